@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/liuchamp/gk/generator"
+)
+
+// gateway_http_addCmd represents the gateway-http add command
+var gateway_http_addCmd = &cobra.Command{
+	Use:   "gateway-http",
+	Short: "Add the http/json gateway of a grpc service",
+	Long: `Add the http/json gateway of a grpc service.
+
+The routes are read from the google.api.http options of the rpcs or, without one, from the
+"gk:http <METHOD> <path> [body=<field>]" lines of their comment. The other rpcs are served
+with POST on their gRPC name. Run it again after changing the proto.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			logrus.Error("You must provide the service name")
+			return
+		}
+		if err := generator.NewProtoCompiler().Refresh(args[0]); err != nil {
+			logrus.Error(err)
+			return
+		}
+		g := generator.NewGatewayHTTPGenerator()
+		err := g.Generate(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
+	},
+}
+
+func init() {
+	addCmd.AddCommand(gateway_http_addCmd)
+}
@@ -4,25 +4,28 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/liuchamp/gk/generator"
-	"time"
 )
 
 // grpcCmd represents the grpc command
 var grpcCmd = &cobra.Command{
 	Use:   "grpc",
-	Short: "Initiates grpc transport after creating the protobuf",
+	Short: "Compiles the protobuf and initiates grpc transport",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			logrus.Error("You must provide the service name")
 			return
 		}
+		err := generator.NewProtoCompiler().Refresh(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
 		g := generator.NewGRPCInitGenerator()
-		err := g.Generate(args[0])
+		err = g.Generate(args[0])
 		if err != nil {
 			logrus.Error(err)
 			return
 		}
-		time.Sleep(time.Second * 5)
 		err = g.GenerateEndpointClient(args[0])
 		if err != nil {
 			logrus.Error(err)
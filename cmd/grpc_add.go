@@ -21,6 +21,7 @@ var grpc_addCmd = &cobra.Command{
 			logrus.Error(err)
 			return
 		}
+		logrus.Infof("Run `gk init grpc %s` to compile the proto and create the grpc transport", args[0])
 	},
 }
 
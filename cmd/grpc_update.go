@@ -15,8 +15,13 @@ var grpcUpdateCmd = &cobra.Command{
 			logrus.Error("You must provide the service name")
 			return
 		}
+		err := generator.NewProtoCompiler().Refresh(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
 		g := generator.NewGRPCUpdateGenerator()
-		err := g.Generate(args[0])
+		err = g.Generate(args[0])
 		if err != nil {
 			logrus.Error(err)
 			return
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Use to create a service from an existing interface definition",
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/liuchamp/gk/generator"
+)
+
+// openapiCmd represents the openapi command
+var openapiCmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Generate the OpenAPI 3 document of the http transport of a service",
+	Long: `Generate the OpenAPI 3 document of the http transport of a service.
+
+The document describes the routes the http transport registers, the schemas of their requests
+and responses, the response envelope, the errors and the JWT security scheme. It is written to
+"openapi.path" and rewritten every time, set "openapi.update" to generate it with gk update.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			logrus.Error("You must provide the service name")
+			return
+		}
+		g := generator.NewOpenAPIGenerator()
+		err := g.Generate(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(openapiCmd)
+}
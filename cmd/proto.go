@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// protoCmd represents the proto command
+var protoCmd = &cobra.Command{
+	Use:   "proto",
+	Short: "Use to work with the protobuf of a service",
+}
+
+func init() {
+	RootCmd.AddCommand(protoCmd)
+}
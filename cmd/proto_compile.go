@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/liuchamp/gk/generator"
+)
+
+// protoCompileCmd represents the proto compile command
+var protoCompileCmd = &cobra.Command{
+	Use:   "compile [serviceName]",
+	Short: "Compile the protobuf of a service with protoc",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			logrus.Error("You must provide the service name")
+			return
+		}
+		err := generator.NewProtoCompiler().Compile(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
+	},
+}
+
+func init() {
+	protoCmd.AddCommand(protoCompileCmd)
+}
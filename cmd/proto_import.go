@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/liuchamp/gk/generator"
+)
+
+// protoImportCmd represents the import proto command
+var protoImportCmd = &cobra.Command{
+	Use:   "proto [serviceName] [protoFile]",
+	Short: "Create the service interface from the service of a .proto file",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			logrus.Error("You must provide the service name")
+			return
+		}
+		if len(args) == 1 {
+			logrus.Error("You must provide the .proto file to import")
+			return
+		}
+		g := generator.NewImportProtoGenerator()
+		err := g.Generate(args[0], args[1])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
+	},
+}
+
+func init() {
+	importCmd.AddCommand(protoImportCmd)
+}
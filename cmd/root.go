@@ -72,10 +72,53 @@ func initViperDefaults() {
 	viper.SetDefault("service.file_name", "service.go")
 	viper.SetDefault("service.interface_name", "{{toUpperFirstCamelCase .ServiceName}}Service")
 	viper.SetDefault("service.struct_name", "stub{{toCamelCase .ServiceName}}Service")
+	viper.SetDefault("service.errors_file_name", "errors.go")
 	viper.SetDefault("middleware.file_name", "middleware.go")
 	viper.SetDefault("endpoints.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"pkg"+afero.FilePathSeparator+"endpoints")
 	viper.SetDefault("endpoints.file_name", "endpoints.go")
+	viper.SetDefault("endpoints.middleware", []string{"auth", "metrics", "logging", "tracing", "validate"})
+	viper.SetDefault("endpoints.rate_limit", 100)
+	viper.SetDefault("endpoints.timeout", "10s")
+	viper.SetDefault("endpoints.circuitbreaker.failures", 5)
+	viper.SetDefault("endpoints.circuitbreaker.max_requests", 1)
+	viper.SetDefault("endpoints.circuitbreaker.interval", "0s")
+	viper.SetDefault("endpoints.circuitbreaker.timeout", "30s")
+	viper.SetDefault("tracing", "zipkin")
+	viper.SetDefault("auth.scheme", "jwt")
+	viper.SetDefault("auth.jwt.algorithm", "HS256")
+	viper.SetDefault("auth.jwt.key", "env:JWT_KEY")
+	viper.SetDefault("auth.api_key.header", "X-API-Key")
+	viper.SetDefault("auth.api_key.keys", "env:API_KEYS")
+	viper.SetDefault("auth.basic.users", "env:BASIC_AUTH_USERS")
 	viper.SetDefault("transport.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"pkg"+afero.FilePathSeparator+"{{.TransportType}}")
 	viper.SetDefault("transport.file_name", "handler.go")
+	viper.SetDefault("httptransport.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"pkg"+afero.FilePathSeparator+"{{toSnakeCase .ServiceName}}transport")
+	viper.SetDefault("httptransport.file_name", "http.go")
+	viper.SetDefault("httptransport.test_file_name", "http_test.go")
+	viper.SetDefault("httptransport.client_file_name", "httpclient.go")
+	viper.SetDefault("httptransport.router", "http")
+	viper.SetDefault("httptransport.envelope", "template")
+	viper.SetDefault("httptransport.codecs", []string{"json", "protobuf"})
+	viper.SetDefault("thrifttransport.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"pkg"+afero.FilePathSeparator+"{{toSnakeCase .ServiceName}}transport")
+	viper.SetDefault("thrifttransport.file_name", "thrift.go")
+	viper.SetDefault("thrifttransport.client_file_name", "thriftclient.go")
+	viper.SetDefault("grpctransport.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"pkg"+afero.FilePathSeparator+"{{toSnakeCase .ServiceName}}transport")
+	viper.SetDefault("grpctransport.file_name", "grpc.go")
+	viper.SetDefault("grpctransport.client_file_name", "grpcclient.go")
+	viper.SetDefault("grpctransport.gateway_file_name", "gateway.go")
+	viper.SetDefault("grpctransport.errors", "status")
+	viper.SetDefault("grpctransport.health", true)
+	viper.SetDefault("grpctransport.reflection", true)
+	viper.SetDefault("grpctransport.interceptors", []string{"recovery", "request_id", "metrics"})
+	viper.SetDefault("pb.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"{{toSnakeCase .ServiceName}}pb")
+	viper.SetDefault("pb.protoc", "protoc")
+	viper.SetDefault("pb.package", "{{toSnakeCase .ServiceName}}pb")
+	viper.SetDefault("thrift.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"{{toSnakeCase .ServiceName}}thrift")
+	viper.SetDefault("openapi.path", "{{toSnakeCase .ServiceName}}"+afero.FilePathSeparator+"api")
+	viper.SetDefault("openapi.file_name", "openapi.json")
+	viper.SetDefault("openapi.version", "1.0.0")
+	viper.SetDefault("openapi.update", false)
+	viper.SetDefault("cmd.path", "{{toSnakeCase .ServiceName}}")
+	viper.SetDefault("cmd.file_name", "main.go")
 	viper.SetDefault("default_transport", "http")
 }
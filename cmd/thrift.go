@@ -20,6 +20,11 @@ var thriftCmd = &cobra.Command{
 			logrus.Error(err)
 			return
 		}
+		err = g.GenerateEndpointClient(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
 	},
 }
 
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/liuchamp/gk/generator"
+)
+
+var thriftUpdateCmd = &cobra.Command{
+	Use:   "thrift",
+	Short: "Update thrift transport after adding methods to the service",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			logrus.Error("You must provide the service name")
+			return
+		}
+		g := generator.NewThriftUpdateGenerator()
+		err := g.Generate(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
+		err = generator.NewThriftInitGenerator().GenerateEndpointClient(args[0])
+		if err != nil {
+			logrus.Error(err)
+			return
+		}
+	},
+}
+
+func init() {
+	updateCmd.AddCommand(thriftUpdateCmd)
+}
@@ -3,9 +3,9 @@ package generator
 import (
 	"errors"
 	"fmt"
-	"runtime"
 	"strings"
 
+	"github.com/emicklei/proto"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/liuchamp/gk/fs"
@@ -138,50 +138,27 @@ func (sg *AddGRPCGenerator) GenerateProtobuf(name string) (err error) {
 		}
 	}
 
-	pbModel := &parser.Proto{PackageName: fmt.Sprintf("%vpb", name), ServiceName: utils.ToUpperFirstCamelCase(name)}
-	pbModel = TransferToPBModel(pbModel, iface)
-
-	protoTmpl, err := te.Execute("proto.pb", pbModel)
+	st, err := LoadServiceTypes(name)
 	if err != nil {
-		fmt.Println("edwin #199 ", err.Error())
 		return err
 	}
-	err = defaultFs.WriteFile(tfile, protoTmpl, false)
+	po, err := loadPBOptions(name)
 	if err != nil {
 		return err
 	}
-	if runtime.GOOS == "windows" {
-		tfile := path + defaultFs.FilePathSeparator() + "compile.bat"
-		cmpTmpl, err := te.Execute("proto_compile.bat", map[string]string{
-			"Name": fname,
-		})
-		if err != nil {
-			return err
-		}
-		logrus.Warn("--------------------------------------------------------------------")
-		logrus.Warn("The service is still not ready!!")
-		logrus.Warn("To create the grpc transport please create your protobuf.")
-		logrus.Warn("Than follow the instructions in compile.bat and compile the .proto file.")
-		logrus.Warnf("After the file is compiled run `gk init grpc %s`.", name)
-		logrus.Warn("--------------------------------------------------------------------")
-		return defaultFs.WriteFile(tfile, cmpTmpl, false)
-	} else {
-		tfile := path + defaultFs.FilePathSeparator() + "compile.sh"
-		cmpTmpl, err := te.Execute("proto_compile.sh", map[string]string{
-			"Name": fname,
-		})
-		if err != nil {
-			return err
-		}
-		logrus.Warn("--------------------------------------------------------------------")
-		logrus.Warn("The service is still not ready!!")
-		logrus.Warn("To create the grpc transport please create your protobuf.")
-		logrus.Warn("Than follow the instructions in compile.sh and compile the .proto file.")
-		logrus.Warnf("After the file is compiled run `gk init grpc %s`.", name)
-		logrus.Warn("--------------------------------------------------------------------")
-		return defaultFs.WriteFile(tfile, cmpTmpl, false)
+	pbModel := parser.NewProto()
+	pbModel.PackageName = po.Package
+	po.applyFile(pbModel)
+	lock := parser.NewProtoLock()
+	if pbModel, err = TransferToPBModel(pbModel, utils.ToUpperFirstCamelCase(name), iface, st, lock, po); err != nil {
+		return err
+	}
+
+	proto, err := pbModel.Render()
+	if err != nil {
+		return err
 	}
-	return nil
+	return writeProto(defaultFs, tfile, proto, lock)
 }
 
 func (sg *AddGRPCGenerator) UpdateProtobuf(name string, iface *parser.Interface, sfile string, defaultFs *fs.DefaultFs, te template.Engine) (err error) {
@@ -200,133 +177,399 @@ func (sg *AddGRPCGenerator) UpdateProtobuf(name string, iface *parser.Interface,
 		return err
 	}
 
-	pbModel = TransferToPBModel(pbModel, iface)
-
-	protoTmpl, err := te.Execute("proto.pb", pbModel)
+	serviceName := utils.ToUpperFirstCamelCase(name)
+	if svc := FindProtoService(pbModel, name); svc != nil {
+		serviceName = svc.Name
+	}
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	lock := parser.NewProtoLock()
+	if b, _ := defaultFs.Exists(pbLockFile(sfile)); b {
+		src, err := defaultFs.ReadFile(pbLockFile(sfile))
+		if err != nil {
+			return err
+		}
+		if lock, err = parser.ParseProtoLock([]byte(src)); err != nil {
+			return err
+		}
+	}
+	po, err := loadPBOptions(name)
+	if err != nil {
+		return err
+	}
+	before, err := pbModel.Render()
+	if err != nil {
+		return err
+	}
+	po.applyFile(pbModel)
+	if pbModel, err = TransferToPBModel(pbModel, serviceName, iface, st, lock, po); err != nil {
+		return err
+	}
+	after, err := pbModel.Render()
 	if err != nil {
-		fmt.Println("edwin #199 ", err.Error())
 		return err
 	}
+	if after != before {
+		return writeProto(defaultFs, sfile, after, lock)
+	}
+	logrus.Infof("The proto already matches the service, `%s` is kept as is", sfile)
+	return defaultFs.WriteFile(pbLockFile(sfile), lock.String(), true)
+}
+
+// writeProto writes the proto, then its lock once the proto is written: declining to overwrite
+// the proto keeps the lock of the numbers it still has.
+func writeProto(defaultFs *fs.DefaultFs, file, proto string, lock parser.ProtoLock) error {
+	if err := defaultFs.WriteFile(file, proto, false); err != nil {
+		return err
+	}
+	if written, err := defaultFs.ReadFile(file); err != nil || written != proto {
+		logrus.Warnf("`%s` was not overwritten, its lock is kept", file)
+		return err
+	}
+	return defaultFs.WriteFile(pbLockFile(file), lock.String(), true)
+}
 
-	err = defaultFs.WriteFile(sfile, protoTmpl, false)
+// grpcStatusErrors tells if the gRPC transport sends the errors of the service as the status of
+// the calls, the `string` mode of `grpctransport.errors` keeps them in a string field of the replies.
+func grpcStatusErrors() bool {
+	return viper.GetString("grpctransport.errors") != "string"
+}
 
-	return nil
+// pbLockFile is the file keeping the field numbers of the proto.
+func pbLockFile(protoFile string) string {
+	return protoFile + ".lock"
 }
 
-func TransferToPBModel(pbModel *parser.Proto, iface *parser.Interface) *parser.Proto {
+// TransferToPBModel adds a rpc with its request and response messages to the service
+// for every interface method the proto does not define yet. The messages of the methods
+// and structures already declared are synced with the interface through the lock, their
+// fields keep their numbers and the removed ones are reserved. The fields added get the
+// options of the `pb.field_options` rules.
+func TransferToPBModel(pbModel *parser.Proto, serviceName string, iface *parser.Interface, st *ServiceTypes, lock parser.ProtoLock, po *pbOptions) (*parser.Proto, error) {
+	pm := newPBMapper(st, pbModel, lock, po)
 	for _, v := range iface.Methods {
-		var isExist bool
-		for _, vv := range pbModel.Methods {
-			if vv.Name == v.Name {
-				isExist = true
-				break
-			}
-		}
-		if isExist {
-			continue
-		}
 		var (
-			msgReq = parser.Struct{Name: fmt.Sprintf("%vReq", utils.ToUpperFirstCamelCase(v.Name))}
-			msgRes = parser.Struct{Name: fmt.Sprintf("%vRes", utils.ToUpperFirstCamelCase(v.Name))}
+			reqName = fmt.Sprintf("%vReq", utils.ToUpperFirstCamelCase(v.Name))
+			resName = fmt.Sprintf("%vRes", utils.ToUpperFirstCamelCase(v.Name))
 		)
-		m := parser.Method{Name: v.Name}
-		for k, kv := range v.Parameters {
+		streamParam, streamResult, err := chanParams(v)
+		if err != nil {
+			return nil, err
+		}
+		if rpc := pbModel.Service(serviceName).RPC(v.Name); rpc != nil {
+			reqName = pbMessageName(pbModel, rpc.RequestType)
+			resName = pbMessageName(pbModel, rpc.ReturnsType)
+		} else {
+			rpc := parser.ProtoRPC{
+				Name:           v.Name,
+				RequestType:    reqName,
+				StreamsRequest: streamParam != nil,
+				ReturnsType:    resName,
+				StreamsReturns: streamResult != nil,
+			}
+			if streamParam != nil {
+				rpc.Comment = []string{fmt.Sprintf(
+					" The first message carries the parameters, the next ones the elements of %s.",
+					utils.ToUpperFirstCamelCase(streamParam.Name),
+				)}
+			}
+			svc := pbModel.Service(serviceName)
+			svc.RPCs = append(svc.RPCs, rpc)
+			addPBMessages(pbModel, []parser.ProtoMessage{{Name: reqName}, {Name: resName}})
+		}
+		var reqFields, resFields []parser.ProtoField
+		for _, kv := range v.Parameters {
 			if kv.Type == "context.Context" {
 				continue
 			}
-
-			var otherMessages []parser.Struct
-			kv.Type, otherMessages = ParseToPBType(kv.Type)
-			if len(otherMessages) > 0 {
-				for _, ov := range otherMessages {
-					if isExist := IsMessageExist(pbModel.Messages, ov); isExist {
-						continue
-					} else {
-						pbModel.Messages = append(pbModel.Messages, parser.NewStruct(ov.Name, nil))
-					}
+			elem, _ := chanElem(kv.Type)
+			field, err := pm.ParseToPBType(elem)
+			if err != nil {
+				return nil, fmt.Errorf("parameter `%s` of method `%s`: %s", kv.Name, v.Name, err)
+			}
+			field.Name = utils.ToUpperFirstCamelCase(kv.Name)
+			reqFields = append(reqFields, field)
+		}
+		for _, kv := range v.Results {
+			field := parser.ProtoField{Type: "string"}
+			if isErrorResult(kv) {
+				// the streams always end with the status of the call
+				if grpcStatusErrors() || streamParam != nil || streamResult != nil {
+					continue
+				}
+			} else {
+				elem, _ := chanElem(kv.Type)
+				if field, err = pm.ParseToPBType(elem); err != nil {
+					return nil, fmt.Errorf("result `%s` of method `%s`: %s", kv.Name, v.Name, err)
 				}
 			}
+			field.Name = utils.ToUpperFirstCamelCase(kv.Name)
+			resFields = append(resFields, field)
+		}
+		if err := pm.sync(reqName, reqFields); err != nil {
+			return nil, err
+		}
+		if err := pm.sync(resName, resFields); err != nil {
+			return nil, err
+		}
+	}
+	return pbModel, nil
+}
 
-			//利用 Method.Value 来传递 protobuf index，下标从 1 开始，由于 ctx 参数不用，则跨过 0 下标
-			kv.Value = fmt.Sprintf("%v", k)
-			kv.Name = utils.ToUpperFirstCamelCase(kv.Name)
-			msgReq.Vars = append(msgReq.Vars, kv)
-		}
-		for k, kv := range v.Results {
-			var otherMessages []parser.Struct
-			kv.Type, otherMessages = ParseToPBType(kv.Type)
-			if len(otherMessages) > 0 {
-				for _, ov := range otherMessages {
-					if isExist := IsMessageExist(pbModel.Messages, ov); isExist {
-						continue
-					} else {
-						pbModel.Messages = append(pbModel.Messages, parser.NewStruct(ov.Name, nil))
-					}
-				}
+// chanParams returns the parameter and the result of the method streamed through a channel,
+// a method streams at most one of each and a streamed result only comes with an error.
+func chanParams(m parser.Method) (param, result *parser.NamedTypeValue, err error) {
+	for k, v := range m.Parameters {
+		if _, ok := chanElem(v.Type); !ok {
+			continue
+		}
+		if param != nil {
+			return nil, nil, fmt.Errorf("method `%s` can only stream one parameter", m.Name)
+		}
+		param = &m.Parameters[k]
+	}
+	for k, v := range m.Results {
+		if _, ok := chanElem(v.Type); !ok {
+			continue
+		}
+		if result != nil {
+			return nil, nil, fmt.Errorf("method `%s` can only stream one result", m.Name)
+		}
+		result = &m.Results[k]
+	}
+	for _, v := range append(append([]parser.NamedTypeValue{}, m.Parameters...), m.Results...) {
+		if strings.HasPrefix(v.Type, "chan<- ") {
+			return nil, nil, fmt.Errorf("`%s %s` of method `%s` can not be streamed, use a receive only or a bidirectional channel", v.Name, v.Type, m.Name)
+		}
+	}
+	if result != nil {
+		for _, v := range m.Results {
+			if v.Name != result.Name && !isErrorResult(v) {
+				return nil, nil, fmt.Errorf("method `%s` streams `%s` and can only return an error next to it", m.Name, result.Name)
 			}
-			//利用 Method.Value 来传递 protobuf index，下标从 1 开始
-			kv.Value = fmt.Sprintf("%v", k+1)
-			kv.Name = utils.ToUpperFirstCamelCase(kv.Name)
-			msgRes.Vars = append(msgRes.Vars, kv)
 		}
-		pbModel.Methods = append(pbModel.Methods, m)
-		pbModel.Messages = append(pbModel.Messages, msgReq, msgRes)
 	}
-	return pbModel
+	return param, result, nil
 }
-func IsMessageExist(messages []parser.Struct, msg parser.Struct) (yes bool) {
-	for _, mv := range messages {
-		if mv.Name == msg.Name {
-			yes = true
-			break
+
+// isStreamMethod tells if the method streams a parameter or a result through a channel.
+func isStreamMethod(m parser.Method) bool {
+	for _, v := range append(append([]parser.NamedTypeValue{}, m.Parameters...), m.Results...) {
+		if _, ok := chanElem(v.Type); ok || strings.HasPrefix(v.Type, "chan<- ") {
+			return true
 		}
 	}
-	return
+	return false
 }
 
-func ParseToPBType(dataType string) (pbDataType string, otherMessages []parser.Struct) {
-	if dataType == "error" {
-		pbDataType = "string"
-		return
-	} else if dataType == "int" {
-		pbDataType = "int32"
-		return
+// chanElem returns the element type of a channel type.
+func chanElem(goType string) (string, bool) {
+	for _, prefix := range []string{"<-chan ", "chan "} {
+		if strings.HasPrefix(goType, prefix) {
+			return strings.TrimPrefix(goType, prefix), true
+		}
 	}
+	return goType, false
+}
 
-	var (
-		mapKeyType string
-		isRepeated bool
-	)
-	if strings.Contains(dataType, "map") {
-		//map[string]string
-		tmp := strings.Split(dataType, "[")
-		tmp = strings.Split(tmp[1], "]")
-		mapKeyType = tmp[0]
-		dataType = tmp[1]
+// pbMessageName is the name of a message of the proto referenced by a rpc, it is empty for
+// the messages of other packages.
+func pbMessageName(pbModel *parser.Proto, ref string) string {
+	ref = strings.TrimPrefix(ref, ".")
+	if pbModel.PackageName != "" {
+		ref = strings.TrimPrefix(ref, pbModel.PackageName+".")
 	}
+	if strings.Contains(ref, ".") {
+		return ""
+	}
+	return ref
+}
 
-	if strings.Contains(dataType, "[]") {
-		isRepeated = true
-		dataType = strings.ReplaceAll(dataType, "[]", "")
+func addPBMessages(pbModel *parser.Proto, messages []parser.ProtoMessage) {
+	for _, v := range messages {
+		if pbModel.Message(v.Name) == nil {
+			pbModel.Messages = append(pbModel.Messages, v)
+		}
+	}
+}
+
+// pbScalarTypes maps the go scalars to the protobuf scalar that holds them, the integers
+// without a protobuf equivalent are widened.
+var pbScalarTypes = map[string]string{
+	"string":  "string",
+	"bool":    "bool",
+	"[]byte":  "bytes",
+	"int":     "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"rune":    "int32",
+	"int64":   "int64",
+	"uint":    "uint64",
+	"uint8":   "uint32",
+	"byte":    "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"float32": "float",
+	"float64": "double",
+}
+
+// pbWrapperTypes are the well known wrappers of the protobuf scalars, they keep the
+// difference between nil and the zero value of a pointer.
+var pbWrapperTypes = map[string]string{
+	"string": "google.protobuf.StringValue",
+	"bool":   "google.protobuf.BoolValue",
+	"bytes":  "google.protobuf.BytesValue",
+	"int32":  "google.protobuf.Int32Value",
+	"int64":  "google.protobuf.Int64Value",
+	"uint32": "google.protobuf.UInt32Value",
+	"uint64": "google.protobuf.UInt64Value",
+	"float":  "google.protobuf.FloatValue",
+	"double": "google.protobuf.DoubleValue",
+}
+
+// pbMapKeyTypes are the protobuf types allowed as map keys.
+var pbMapKeyTypes = map[string]bool{
+	"string": true, "bool": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+}
+
+// pbMapper maps the types of the service to protobuf, the structures of the service become
+// messages declared the first time they are used, the well known types add their import.
+type pbMapper struct {
+	st    *ServiceTypes
+	model *parser.Proto
+	lock  parser.ProtoLock
+	po    *pbOptions
+	done  map[string]bool
+}
+
+func newPBMapper(st *ServiceTypes, model *parser.Proto, lock parser.ProtoLock, po *pbOptions) *pbMapper {
+	return &pbMapper{st: st, model: model, lock: lock, po: po, done: map[string]bool{}}
+}
+
+// ParseToPBType maps a go type to a protobuf field, the messages and imports the field
+// needs are added to the proto.
+func (pm *pbMapper) ParseToPBType(dataType string) (field parser.ProtoField, err error) {
+	switch {
+	case strings.HasPrefix(dataType, "map["):
+		key, value := splitMapType(dataType)
+		k, err := pm.singular(key)
+		if err != nil {
+			return field, err
+		}
+		if !pbMapKeyTypes[k] {
+			return field, fmt.Errorf("the type `%s` can not be a protobuf map key", key)
+		}
+		if strings.HasPrefix(value, "[]") && value != "[]byte" || strings.HasPrefix(value, "map[") {
+			return field, fmt.Errorf("the map `%s` can not be mapped to protobuf, map values can not be repeated or maps, wrap `%s` in a structure", dataType, value)
+		}
+		if field.Type, err = pm.singular(value); err != nil {
+			return field, err
+		}
+		field.KeyType = k
+	case strings.HasPrefix(dataType, "[]") && dataType != "[]byte":
+		elem := strings.TrimPrefix(dataType, "[]")
+		if strings.HasPrefix(elem, "[]") && elem != "[]byte" || strings.HasPrefix(elem, "map[") {
+			return field, fmt.Errorf("the type `%s` can not be mapped to protobuf, repeated fields can not hold lists or maps, wrap `%s` in a structure", dataType, elem)
+		}
+		if field.Type, err = pm.singular(elem); err != nil {
+			return field, err
+		}
+		field.Repeated = true
+	default:
+		field.Type, err = pm.singular(dataType)
+	}
+	return field, err
+}
+
+// singular maps a type that is neither a list nor a map.
+func (pm *pbMapper) singular(dataType string) (string, error) {
+	switch dataType {
+	case "time.Time", "*time.Time":
+		pm.addImport("google/protobuf/timestamp.proto")
+		return "google.protobuf.Timestamp", nil
+	case "time.Duration", "*time.Duration":
+		pm.addImport("google/protobuf/duration.proto")
+		return "google.protobuf.Duration", nil
+	}
+	if s, ok := pbScalarTypes[dataType]; ok {
+		return s, nil
+	}
+	if strings.HasPrefix(dataType, "*") {
+		elem := strings.TrimPrefix(dataType, "*")
+		if s, ok := pbScalarTypes[elem]; ok {
+			pm.addImport("google/protobuf/wrappers.proto")
+			return pbWrapperTypes[s], nil
+		}
+		if underlying, ok := pm.st.Types[elem]; ok && underlying == "struct" {
+			return elem, pm.declareMessage(elem)
+		}
+		return "", fmt.Errorf("the type `%s` can not be mapped to protobuf, only pointers to scalars and structures of the service are supported", dataType)
+	}
+	if underlying, ok := pm.st.Types[dataType]; ok {
+		if underlying == "struct" {
+			return dataType, pm.declareMessage(dataType)
+		}
+		if s, ok := pbScalarTypes[underlying]; ok {
+			return s, nil
+		}
+		return "", fmt.Errorf("the type `%s` (%s) can not be mapped to protobuf, declare it as a structure", dataType, underlying)
 	}
 	if strings.Contains(dataType, ".") {
-		tmp := strings.Split(dataType, ".")
-		dataType = tmp[1]
-		otherMessages = append(otherMessages, parser.NewStruct(dataType, nil))
+		return "", fmt.Errorf("the type `%s` of another package can not be mapped to protobuf, declare a structure in the service file instead", dataType)
+	}
+	return "", fmt.Errorf("the type `%s` can not be mapped to protobuf", dataType)
+}
+
+// declareMessage adds or syncs the message of a service structure with a field for every
+// exported field of the structure, the messages it refers to are declared as well.
+func (pm *pbMapper) declareMessage(name string) error {
+	if pm.done[name] {
+		return nil
+	}
+	pm.done[name] = true
+	var fields []parser.ProtoField
+	for _, f := range pm.st.Structs[name] {
+		if f.Name == "" || f.Name[:1] == strings.ToLower(f.Name[:1]) {
+			logrus.Warnf("The field `%s %s` of `%s` is not exported and will be ignored", f.Name, f.Type, name)
+			continue
+		}
+		field, err := pm.ParseToPBType(f.Type)
+		if err != nil {
+			return fmt.Errorf("field `%s` of `%s`: %s", f.Name, name, err)
+		}
+		field.Name = f.Name
+		fields = append(fields, field)
 	}
+	addPBMessages(pm.model, []parser.ProtoMessage{{Name: name}})
+	return pm.sync(name, fields)
+}
 
-	if mapKeyType != "" && isRepeated {
-		panic(fmt.Errorf("proto does not support map with array value"))
+// sync makes the fields of a top level message match the fields, messages of other
+// packages are left alone.
+func (pm *pbMapper) sync(name string, fields []parser.ProtoField) error {
+	msg := pm.model.Message(name)
+	if msg == nil {
+		return nil
 	}
+	for k, v := range fields {
+		options, err := pm.po.fieldOptions(name, v)
+		if err != nil {
+			return err
+		}
+		fields[k].Options = append(fields[k].Options, options...)
+	}
+	return pm.lock.Sync(msg, fields)
+}
 
-	if mapKeyType != "" {
-		pbDataType = fmt.Sprintf("map<%v,%v> ", mapKeyType, dataType)
-		return
-	} else if isRepeated {
-		pbDataType = fmt.Sprintf("repeated %s ", dataType)
-		return
-	} else {
-		pbDataType = dataType
+func (pm *pbMapper) addImport(filename string) {
+	for _, v := range pm.model.Imports {
+		if v.Filename == filename {
+			return
+		}
 	}
-	return
+	pm.model.Imports = append(pm.model.Imports, &proto.Import{Filename: filename})
 }
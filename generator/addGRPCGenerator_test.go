@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+)
+
+func TestUpdateProtobufKeepsLockOfDeclinedProto(t *testing.T) {
+	testProject(t, nil)
+	testService(t, "acc", testAccMethods, testAccTypes)
+	if err := NewAddGRPCGenerator().GenerateProtobuf("acc"); err != nil {
+		t.Fatal(err)
+	}
+	proto, lock := testRead(t, "acc/accpb/acc.proto"), testRead(t, "acc/accpb/acc.proto.lock")
+
+	// a new parameter, the overwrite of the proto is declined: without an answer the prompt
+	// keeps the file
+	file := "acc/pkg/accservice/service.go"
+	src := strings.Replace(testRead(t, file), "Get(ctx context.Context, id int64)", "Get(ctx context.Context, id int64, name string)", 1)
+	if err := fs.Get().WriteFile(file, src, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAddGRPCGenerator().GenerateProtobuf("acc"); err != nil {
+		t.Fatal(err)
+	}
+	if testRead(t, "acc/accpb/acc.proto") != proto {
+		t.Fatal("the proto was overwritten")
+	}
+	if got := testRead(t, "acc/accpb/acc.proto.lock"); got != lock {
+		t.Errorf("the lock of the kept proto changed from\n%s\nto\n%s", lock, got)
+	}
+
+	// accepted, both follow the service
+	viper.Set("gk_force_override", true)
+	if err := NewAddGRPCGenerator().GenerateProtobuf("acc"); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, testRead(t, "acc/accpb/acc.proto"), "string Name = 2;")
+	if testRead(t, "acc/accpb/acc.proto.lock") == lock {
+		t.Error("the lock did not follow the proto")
+	}
+}
+
+func TestParseToPBType(t *testing.T) {
+	st := &ServiceTypes{
+		Package: "accservice",
+		Types:   map[string]string{"Account": "struct", "Status": "int", "Tags": "[]string"},
+		Structs: map[string][]parser.NamedTypeValue{
+			"Account": {parser.NewNameType("Id", "int64"), parser.NewNameType("owner", "string")},
+		},
+	}
+	for _, c := range []struct {
+		goType, pbType, keyType string
+		repeated                bool
+		imports, err            string
+	}{
+		{goType: "string", pbType: "string"},
+		{goType: "[]byte", pbType: "bytes"},
+		// the integers without a protobuf scalar are widened
+		{goType: "int", pbType: "int64"},
+		{goType: "int8", pbType: "int32"},
+		{goType: "uint", pbType: "uint64"},
+		{goType: "byte", pbType: "uint32"},
+		{goType: "float32", pbType: "float"},
+		{goType: "float64", pbType: "double"},
+		{goType: "time.Time", pbType: "google.protobuf.Timestamp", imports: "google/protobuf/timestamp.proto"},
+		{goType: "*time.Duration", pbType: "google.protobuf.Duration", imports: "google/protobuf/duration.proto"},
+		// the pointers to scalars keep nil apart from the zero value
+		{goType: "*string", pbType: "google.protobuf.StringValue", imports: "google/protobuf/wrappers.proto"},
+		{goType: "*int", pbType: "google.protobuf.Int64Value", imports: "google/protobuf/wrappers.proto"},
+		{goType: "Account", pbType: "Account"},
+		{goType: "*Account", pbType: "Account"},
+		{goType: "Status", pbType: "int64"},
+		{goType: "[]Account", pbType: "Account", repeated: true},
+		{goType: "map[string]*Account", pbType: "Account", keyType: "string"},
+		{goType: "map[int]time.Time", pbType: "google.protobuf.Timestamp", keyType: "int64", imports: "google/protobuf/timestamp.proto"},
+		{goType: "Tags", err: "declare it as a structure"},
+		{goType: "map[float64]string", err: "can not be a protobuf map key"},
+		{goType: "map[string][]string", err: "map values can not be repeated or maps"},
+		{goType: "[][]string", err: "repeated fields can not hold lists or maps"},
+		{goType: "*[]string", err: "only pointers to scalars and structures of the service"},
+		{goType: "http.Header", err: "of another package"},
+		{goType: "chan int", err: "can not be mapped to protobuf"},
+	} {
+		model := parser.NewProto()
+		field, err := newPBMapper(st, model, parser.NewProtoLock(), nil).ParseToPBType(c.goType)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s: got the error %v, want %q", c.goType, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", c.goType, err)
+			continue
+		}
+		if field.Type != c.pbType || field.KeyType != c.keyType || field.Repeated != c.repeated {
+			t.Errorf("%s: got %+v", c.goType, field)
+		}
+		var imports []string
+		for _, v := range model.Imports {
+			imports = append(imports, v.Filename)
+		}
+		if strings.Join(imports, ",") != c.imports {
+			t.Errorf("%s: got the imports %v, want %q", c.goType, imports, c.imports)
+		}
+	}
+
+	// the structures are declared once with their exported fields
+	model := parser.NewProto()
+	pm := newPBMapper(st, model, parser.NewProtoLock(), nil)
+	for _, v := range []string{"Account", "[]Account", "map[string]*Account"} {
+		if _, err := pm.ParseToPBType(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(model.Messages) != 1 {
+		t.Fatalf("got %d messages, want Account only", len(model.Messages))
+	}
+	assertContains(t, model.String(), "message Account { int64 Id = 1; }")
+	assertNotContains(t, model.String(), "owner")
+}
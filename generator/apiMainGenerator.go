@@ -49,7 +49,14 @@ func (sg *ApiMainGenerator) Generate() error {
 	if err != nil {
 		return err
 	}
-	tmpl, err := te.Execute("main_api", nil)
+	tracing, err := tracingMode()
+	if err != nil {
+		return err
+	}
+	tmpl, err := te.Execute("main_api", map[string]string{
+		"APIKeyHeader": authAPIKeyHeader(),
+		"Tracing":      tracing,
+	})
 	if err != nil {
 		return err
 	}
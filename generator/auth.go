@@ -0,0 +1,754 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+)
+
+// authSchemes are the authentications of the endpoints, by their name in `auth.scheme` and in
+// the `gk:auth <scheme>` directives.
+var authSchemes = []string{"none", "jwt", "apikey", "basic"}
+
+// jwtAlgorithms are the signing methods of the JWT scheme with the kind of their keys.
+var jwtAlgorithms = map[string]string{
+	"HS256": "oct", "HS384": "oct", "HS512": "oct",
+	"RS256": "RSA", "RS384": "RSA", "RS512": "RSA",
+	"ES256": "EC", "ES384": "EC", "ES512": "EC",
+}
+
+// authScheme is the authentication of the method, the first of: the `gk:auth <scheme>`
+// directive of the method, `endpoints.methods.<Method>.auth`, the `gk:auth <scheme>` directive
+// of the service interface and `auth.scheme`. It is `none` when the chain of the method has no
+// `auth` middleware.
+func authScheme(iface *parser.Interface, m parser.Method) (string, error) {
+	chain, err := endpointChain(m)
+	if err != nil {
+		return "", err
+	}
+	chained := false
+	for _, v := range chain {
+		chained = chained || v == "auth"
+	}
+	if !chained {
+		return "none", nil
+	}
+	scheme, source := viper.GetString("auth.scheme"), "`auth.scheme`"
+	if d := (parser.Method{Comment: iface.Comment}).Directives("auth"); len(d) > 0 && len(d[0]) > 0 {
+		scheme, source = d[0][0], "the gk:auth directive of "+iface.Name
+	}
+	if key := fmt.Sprintf("endpoints.methods.%s.auth", m.Name); viper.IsSet(key) {
+		scheme, source = viper.GetString(key), "`"+key+"`"
+	}
+	if d := m.Directives("auth"); len(d) > 0 && len(d[0]) > 0 {
+		scheme, source = d[0][0], "the gk:auth directive of "+m.Name
+	}
+	for _, v := range authSchemes {
+		if v == scheme {
+			return scheme, nil
+		}
+	}
+	return "", fmt.Errorf("The authentication `%s` of %s is not supported, use %s", scheme, source, strings.Join(authSchemes, ", "))
+}
+
+// authSchemesOf are the authentications the methods of the service use, `none` excepted.
+func authSchemesOf(iface *parser.Interface) (map[string]bool, error) {
+	schemes := map[string]bool{}
+	for _, m := range iface.Methods {
+		scheme, err := authScheme(iface, m)
+		if err != nil {
+			return nil, err
+		}
+		if scheme != "none" {
+			schemes[scheme] = true
+		}
+	}
+	return schemes, nil
+}
+
+// authSource is a source of credentials of gk.json: `env:<NAME>`, `file:<path>`, or
+// `jwks:<path>` for the keys of the JWT scheme.
+func authSource(key string, jwks bool) (string, error) {
+	source := viper.GetString(key)
+	prefixes := []string{"env:", "file:"}
+	if jwks {
+		prefixes = append(prefixes, "jwks:")
+	}
+	for _, v := range prefixes {
+		if strings.HasPrefix(source, v) && len(source) > len(v) {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("The credentials `%s` of `%s` are not one of %s<...>", source, key, strings.Join(prefixes, "<...>, "))
+}
+
+// authAPIKeyHeader is the header of the API keys.
+func authAPIKeyHeader() string {
+	return viper.GetString("auth.api_key.header")
+}
+
+// catalogHas tells if the error catalog of the service declares the error, the catalogs written
+// before it do not.
+func catalogHas(name, errName string) bool {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return false
+	}
+	fname, err := te.ExecuteString(viper.GetString("service.errors_file_name"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return false
+	}
+	s, err := defaultFs.ReadFile(path + defaultFs.FilePathSeparator() + fname)
+	return err == nil && regexp.MustCompile(`\b`+errName+`\s*=`).MatchString(s)
+}
+
+// generateEndpointsAuth writes the authentication of the endpoints, `auth.go` next to the
+// middleware: the middleware of the schemes the service uses, the errors and the contexts of
+// the credentials. It follows gk.json, it is generated again by each update.
+func generateEndpointsAuth(name string, iface *parser.Interface) error {
+	schemes, err := authSchemesOf(iface)
+	if err != nil || len(schemes) == 0 {
+		return err
+	}
+	logrus.Info("Generating endpoints authentication...")
+	if err = NewErrorCatalogGenerator().Generate(name); err != nil {
+		return err
+	}
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return err
+	}
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	file := parser.NewFile()
+	file.Package = fmt.Sprintf("%sendpoint", name)
+	file.Imports = []parser.NamedTypeValue{
+		parser.NewNameType("", `"context"`),
+		parser.NewNameType("", `"errors"`),
+		parser.NewNameType("", `"fmt"`),
+		parser.NewNameType("", `"io/ioutil"`),
+		parser.NewNameType("", `"os"`),
+		parser.NewNameType("", `"strings"`),
+		parser.NewNameType("", `"sync"`),
+		parser.NewNameType("", `"github.com/go-kit/kit/endpoint"`),
+	}
+
+	unwrap, message := "e.Err", "e.Err.Error()"
+	if catalogHas(name, "ErrUnauthenticated") {
+		file.Imports = append(file.Imports, parser.NewNameType(st.Package, fmt.Sprintf("%q", serviceImport)))
+		unwrap = st.Package + ".ErrUnauthenticated"
+		message = fmt.Sprintf(`e.Err.Error() + ": " + %s.ErrUnauthenticated.Error()`, st.Package)
+	} else {
+		logrus.Warnf("The error catalog of service %s has no ErrUnauthenticated, add it with the code "+
+			"CodeUnauthenticated to send the authentication errors as unauthenticated", name)
+	}
+	authErr := parser.NewStructWithComment(
+		"AuthError",
+		`AuthError is the error of a request failing the authentication, it is an unauthenticated
+		error of the service.`,
+		[]parser.NamedTypeValue{parser.NewNameType("Err", "error")},
+	)
+	authErr.Vars[0].Tag = ""
+	file.Structs = append(file.Structs, authErr)
+	file.Methods = append(file.Methods,
+		parser.NewMethod(
+			"Error",
+			parser.NewNameType("e", "*AuthError"),
+			"return "+message,
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{parser.NewNameType("", "string")},
+		),
+		parser.NewMethod(
+			"Unwrap",
+			parser.NewNameType("e", "*AuthError"),
+			"return "+unwrap,
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{parser.NewNameType("", "error")},
+		),
+		parser.NewMethodWithComment(
+			"readSecret",
+			"readSecret reads the credentials of a source, `env:<NAME>` is an environment variable,\n"+
+				"`file:<path>` and `jwks:<path>` are files.",
+			parser.NamedTypeValue{},
+			`switch {
+			case strings.HasPrefix(source, "env:"):
+				if v, ok := os.LookupEnv(strings.TrimPrefix(source, "env:")); ok {
+					return []byte(v), nil
+				}
+				return nil, fmt.Errorf("the environment variable %s is not set", strings.TrimPrefix(source, "env:"))
+			case strings.HasPrefix(source, "file:"):
+				return ioutil.ReadFile(strings.TrimPrefix(source, "file:"))
+			case strings.HasPrefix(source, "jwks:"):
+				return ioutil.ReadFile(strings.TrimPrefix(source, "jwks:"))
+			}
+			return nil, fmt.Errorf("the credentials %q are not env:<NAME> or file:<path>", source)`,
+			[]parser.NamedTypeValue{parser.NewNameType("source", "string")},
+			[]parser.NamedTypeValue{parser.NewNameType("", "[]byte"), parser.NewNameType("", "error")},
+		),
+	)
+
+	contextKeys := []string{}
+	if schemes["jwt"] {
+		if err := authJWT(&file); err != nil {
+			return err
+		}
+	}
+	if schemes["apikey"] || schemes["basic"] {
+		file.Imports = append(file.Imports, parser.NewNameType("", `"crypto/subtle"`))
+		file.AliasType = append(file.AliasType, parser.NewNameType("authContextKey", "int"))
+		file.Vars = append(file.Vars,
+			parser.NewNameTypeValue("ErrCredentialsMissing", "", `errors.New("credentials missing")`),
+			parser.NewNameTypeValue("ErrCredentialsInvalid", "", `errors.New("credentials invalid")`),
+		)
+		file.Methods = append(file.Methods,
+			parser.NewMethodWithComment(
+				"readCredentials",
+				`readCredentials reads the credentials of a source, separated by commas or new lines.`,
+				parser.NamedTypeValue{},
+				`b, err := readSecret(source)
+				if err != nil {
+					return nil, err
+				}
+				var list []string
+				for _, v := range strings.FieldsFunc(string(b), func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+					if v = strings.TrimSpace(v); v != "" {
+						list = append(list, v)
+					}
+				}
+				if len(list) == 0 {
+					return nil, fmt.Errorf("the credentials %s are empty", source)
+				}
+				return list, nil`,
+				[]parser.NamedTypeValue{parser.NewNameType("source", "string")},
+				[]parser.NamedTypeValue{parser.NewNameType("", "[]string"), parser.NewNameType("", "error")},
+			),
+			parser.NewMethodWithComment(
+				"containsCredential",
+				`containsCredential tells in constant time if the credential is one of the list.`,
+				parser.NamedTypeValue{},
+				`found := false
+				for _, v := range list {
+					found = subtle.ConstantTimeCompare([]byte(v), []byte(credential)) == 1 || found
+				}
+				return found`,
+				[]parser.NamedTypeValue{
+					parser.NewNameType("list", "[]string"),
+					parser.NewNameType("credential", "string"),
+				},
+				[]parser.NamedTypeValue{parser.NewNameType("", "bool")},
+			),
+		)
+	}
+	if schemes["apikey"] {
+		source, err := authSource("auth.api_key.keys", false)
+		if err != nil {
+			return err
+		}
+		contextKeys = append(contextKeys, "apiKeyContextKey")
+		file.Constants = append(file.Constants, parser.NewNameTypeValue("apiKeysSource", "", fmt.Sprintf("%q", source)))
+		file.Methods = append(file.Methods,
+			parser.NewMethodWithComment(
+				"APIKeyMiddleware",
+				fmt.Sprintf(`APIKeyMiddleware returns an endpoint middleware that checks the API key of
+				the context is one of the keys of %s.
+				The failures are AuthError.`, source),
+				parser.NamedTypeValue{},
+				authCheck("keys", "readCredentials(apiKeysSource)", `key, ok := APIKeyFromContext(ctx)`, "key"),
+				[]parser.NamedTypeValue{},
+				[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+			),
+			parser.NewMethodWithComment(
+				"ContextWithAPIKey",
+				fmt.Sprintf(`ContextWithAPIKey returns a context carrying the API key, the clients send it in
+				the %s header.`, authAPIKeyHeader()),
+				parser.NamedTypeValue{},
+				`return context.WithValue(ctx, apiKeyContextKey, key)`,
+				[]parser.NamedTypeValue{
+					parser.NewNameType("ctx", "context.Context"),
+					parser.NewNameType("key", "string"),
+				},
+				[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+			),
+			parser.NewMethodWithComment(
+				"APIKeyFromContext",
+				`APIKeyFromContext returns the API key of the context.`,
+				parser.NamedTypeValue{},
+				`key, ok := ctx.Value(apiKeyContextKey).(string)
+				return key, ok && key != ""`,
+				[]parser.NamedTypeValue{parser.NewNameType("ctx", "context.Context")},
+				[]parser.NamedTypeValue{parser.NewNameType("", "string"), parser.NewNameType("", "bool")},
+			),
+		)
+	}
+	if schemes["basic"] {
+		source, err := authSource("auth.basic.users", false)
+		if err != nil {
+			return err
+		}
+		contextKeys = append(contextKeys, "basicAuthContextKey")
+		file.Constants = append(file.Constants, parser.NewNameTypeValue("basicUsersSource", "", fmt.Sprintf("%q", source)))
+		file.Methods = append(file.Methods,
+			parser.NewMethodWithComment(
+				"BasicAuthMiddleware",
+				fmt.Sprintf(`BasicAuthMiddleware returns an endpoint middleware that checks the user and
+				the password of the context are one of the user:password of %s.
+				The failures are AuthError.`, source),
+				parser.NamedTypeValue{},
+				authCheck("users", "readCredentials(basicUsersSource)", `user, password, ok := BasicAuthFromContext(ctx)`, `user+":"+password`),
+				[]parser.NamedTypeValue{},
+				[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+			),
+			parser.NewMethodWithComment(
+				"ContextWithBasicAuth",
+				`ContextWithBasicAuth returns a context carrying the user and the password, the clients
+				send them in the Authorization header.`,
+				parser.NamedTypeValue{},
+				`return context.WithValue(ctx, basicAuthContextKey, [2]string{user, password})`,
+				[]parser.NamedTypeValue{
+					parser.NewNameType("ctx", "context.Context"),
+					parser.NewNameType("user", "string"),
+					parser.NewNameType("password", "string"),
+				},
+				[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+			),
+			parser.NewMethodWithComment(
+				"BasicAuthFromContext",
+				`BasicAuthFromContext returns the user and the password of the context.`,
+				parser.NamedTypeValue{},
+				`v, ok := ctx.Value(basicAuthContextKey).([2]string)
+				return v[0], v[1], ok`,
+				[]parser.NamedTypeValue{parser.NewNameType("ctx", "context.Context")},
+				[]parser.NamedTypeValue{
+					parser.NewNameType("user", "string"),
+					parser.NewNameType("password", "string"),
+					parser.NewNameType("ok", "bool"),
+				},
+			),
+		)
+	}
+	var keys []parser.NamedTypeValue
+	for k, v := range contextKeys {
+		if k == 0 {
+			keys = append(keys, parser.NewNameTypeValue(v, "authContextKey", "iota"))
+		} else {
+			keys = append(keys, parser.NewNameType(v, ""))
+		}
+	}
+	file.Constants = append(keys, file.Constants...)
+	// the standard library first, then the dependencies
+	var std, deps []parser.NamedTypeValue
+	for _, v := range file.Imports {
+		if strings.Contains(strings.Split(v.Type, "/")[0], ".") {
+			deps = append(deps, v)
+		} else {
+			std = append(std, v)
+		}
+	}
+	std[len(std)-1].Type += "\n"
+	file.Imports = append(std, deps...)
+	return defaultFs.WriteFile(path+defaultFs.FilePathSeparator()+"auth.go", file.String(), true)
+}
+
+// authCheck is the body of a middleware checking the credential of the context is one of the
+// list read once from the source.
+func authCheck(list, read, credential, value string) string {
+	return fmt.Sprintf(`var (
+			once sync.Once
+			%s []string
+			err error
+		)
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				once.Do(func() {
+					%s, err = %s
+				})
+				if err != nil {
+					return nil, err
+				}
+				%s
+				if !ok {
+					return nil, &AuthError{Err: ErrCredentialsMissing}
+				}
+				if !containsCredential(%s, %s) {
+					return nil, &AuthError{Err: ErrCredentialsInvalid}
+				}
+				return next(ctx, request)
+			}
+		}`, list, list, read, credential, list, value)
+}
+
+// authJWT adds the JWT middleware, its keys and the context of the tokens to the file.
+func authJWT(file *parser.File) error {
+	algorithm := viper.GetString("auth.jwt.algorithm")
+	kind, ok := jwtAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("The JWT algorithm `%s` of `auth.jwt.algorithm` is not supported", algorithm)
+	}
+	source, err := authSource("auth.jwt.key", true)
+	if err != nil {
+		return err
+	}
+	file.Imports = append(file.Imports,
+		parser.NewNameType("stdjwt", `"github.com/dgrijalva/jwt-go"`),
+		parser.NewNameType("", `"github.com/go-kit/kit/auth/jwt"`),
+	)
+	file.Constants = append(file.Constants, parser.NewNameTypeValue("jwtKeySource", "", fmt.Sprintf("%q", source)))
+	load := map[string]string{
+		"oct": `return map[string]interface{}{"": []byte(strings.TrimSpace(string(b)))}, nil`,
+		"RSA": `key, err := stdjwt.ParseRSAPublicKeyFromPEM(b)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"": key}, nil`,
+		"EC": `key, err := stdjwt.ParseECPublicKeyFromPEM(b)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"": key}, nil`,
+	}[kind]
+	if strings.HasPrefix(source, "jwks:") {
+		load = "return parseJWKS(b)"
+		file.Methods = append(file.Methods, authJWKS(file, kind))
+	}
+	file.Methods = append(file.Methods,
+		parser.NewMethodWithComment(
+			"JWTMiddleware",
+			fmt.Sprintf(`JWTMiddleware returns an endpoint middleware that checks the bearer token of
+			the context is signed with %s by a key of %s.
+			The claims of the token are added to the context, the failures are AuthError.`, algorithm, source),
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`parser := jwt.NewParser(jwtKeyFunc(), stdjwt.SigningMethod%s, jwt.MapClaimsFactory)
+			return func(next endpoint.Endpoint) endpoint.Endpoint {
+				ep := parser(next)
+				return func(ctx context.Context, request interface{}) (interface{}, error) {
+					response, err := ep(ctx, request)
+					for _, e := range []error{jwt.ErrTokenContextMissing, jwt.ErrTokenInvalid, jwt.ErrTokenExpired,
+						jwt.ErrTokenMalformed, jwt.ErrTokenNotActive, jwt.ErrUnexpectedSigningMethod} {
+						if errors.Is(err, e) {
+							return nil, &AuthError{Err: err}
+						}
+					}
+					return response, err
+				}
+			}`, algorithm),
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+		),
+		parser.NewMethodWithComment(
+			"jwtKeyFunc",
+			`jwtKeyFunc returns the keys verifying the tokens, read once. The key is chosen by the kid
+			of the token, a key without kid verifies any token.`,
+			parser.NamedTypeValue{},
+			`var (
+				once sync.Once
+				keys map[string]interface{}
+				err  error
+			)
+			return func(token *stdjwt.Token) (interface{}, error) {
+				once.Do(func() {
+					keys, err = loadJWTKeys()
+				})
+				if err != nil {
+					return nil, err
+				}
+				kid, _ := token.Header["kid"].(string)
+				if key, ok := keys[kid]; ok {
+					return key, nil
+				}
+				if key, ok := keys[""]; ok {
+					return key, nil
+				}
+				return nil, &AuthError{Err: fmt.Errorf("no key %q verifies the token", kid)}
+			}`,
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{parser.NewNameType("", "stdjwt.Keyfunc")},
+		),
+		parser.NewMethodWithComment(
+			"loadJWTKeys",
+			`loadJWTKeys reads the keys of the tokens by their kid.`,
+			parser.NamedTypeValue{},
+			`b, err := readSecret(jwtKeySource)
+			if err != nil {
+				return nil, err
+			}
+			`+load,
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "map[string]interface{}"),
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"ContextWithToken",
+			`ContextWithToken returns a context carrying the bearer token, the clients send it in the
+			Authorization header.`,
+			parser.NamedTypeValue{},
+			`return context.WithValue(ctx, jwt.JWTContextKey, token)`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("token", "string"),
+			},
+			[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+		),
+	)
+	return nil
+}
+
+// authJWKS is the parser of the JSON Web Key Sets, it keeps the keys of the kind.
+func authJWKS(file *parser.File, kind string) parser.Method {
+	file.Imports = append(file.Imports,
+		parser.NewNameType("", `"encoding/base64"`),
+		parser.NewNameType("", `"encoding/json"`),
+	)
+	key := map[string]string{
+		"oct": "keys[k.Kid] = values[4]",
+		"RSA": "keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(values[0]), E: int(new(big.Int).SetBytes(values[1]).Int64())}",
+		"EC": `curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+			curve, ok := curves[k.Crv]
+			if !ok {
+				return nil, fmt.Errorf("the curve %q of the key %q is not supported", k.Crv, k.Kid)
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(values[2]), Y: new(big.Int).SetBytes(values[3])}`,
+	}[kind]
+	switch kind {
+	case "RSA":
+		file.Imports = append(file.Imports, parser.NewNameType("", `"crypto/rsa"`), parser.NewNameType("", `"math/big"`))
+	case "EC":
+		file.Imports = append(file.Imports,
+			parser.NewNameType("", `"crypto/ecdsa"`),
+			parser.NewNameType("", `"crypto/elliptic"`),
+			parser.NewNameType("", `"math/big"`),
+		)
+	}
+	return parser.NewMethodWithComment(
+		"parseJWKS",
+		fmt.Sprintf(`parseJWKS reads the %s keys of a JSON Web Key Set by their kid.`, kind),
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`var set struct {
+			Keys []struct {
+				Kid string `+"`json:\"kid\"`"+`
+				Kty string `+"`json:\"kty\"`"+`
+				Crv string `+"`json:\"crv\"`"+`
+				N   string `+"`json:\"n\"`"+`
+				E   string `+"`json:\"e\"`"+`
+				X   string `+"`json:\"x\"`"+`
+				Y   string `+"`json:\"y\"`"+`
+				K   string `+"`json:\"k\"`"+`
+			} `+"`json:\"keys\"`"+`
+		}
+		if err := json.Unmarshal(b, &set); err != nil {
+			return nil, err
+		}
+		keys := map[string]interface{}{}
+		for _, k := range set.Keys {
+			if k.Kty != %q {
+				continue
+			}
+			var values [][]byte
+			for _, s := range []string{k.N, k.E, k.X, k.Y, k.K} {
+				v, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+				if err != nil {
+					return nil, fmt.Errorf("the key %%q: %%v", k.Kid, err)
+				}
+				values = append(values, v)
+			}
+			%s
+		}
+		return keys, nil`, kind, key),
+		[]parser.NamedTypeValue{parser.NewNameType("b", "[]byte")},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "map[string]interface{}"),
+			parser.NewNameType("", "error"),
+		},
+	)
+}
+
+// authTransportHelpers are the request functions of the transport moving the credentials of
+// the schemes the service uses between the requests and the contexts: authHTTPToContext and
+// authContextToHTTP for http, authGRPCToContext and authContextToGRPC for grpc.
+func authTransportHelpers(name string, iface *parser.Interface, transport string) ([]parser.Method, error) {
+	schemes, err := authSchemesOf(iface)
+	if err != nil {
+		return nil, err
+	}
+	header, ep := authAPIKeyHeader(), fmt.Sprintf("%sendpoint", name)
+	server, client := "", ""
+	if schemes["jwt"] {
+		if transport == "grpc" {
+			server += "ctx = jwt.GRPCToContext()(ctx, md)\n"
+			client += "ctx = jwt.ContextToGRPC()(ctx, md)\n"
+		} else {
+			server += "ctx = jwt.HTTPToContext()(ctx, r)\n"
+			client += "ctx = jwt.ContextToHTTP()(ctx, r)\n"
+		}
+	}
+	if schemes["apikey"] {
+		if transport == "grpc" {
+			server += fmt.Sprintf(`if v := md[%q]; len(v) > 0 {
+				ctx = %s.ContextWithAPIKey(ctx, v[0])
+			}
+			`, strings.ToLower(header), ep)
+			client += fmt.Sprintf(`if key, ok := %s.APIKeyFromContext(ctx); ok {
+				(*md)[%q] = []string{key}
+			}
+			`, ep, strings.ToLower(header))
+		} else {
+			server += fmt.Sprintf(`if key := r.Header.Get(%q); key != "" {
+				ctx = %s.ContextWithAPIKey(ctx, key)
+			}
+			`, header, ep)
+			client += fmt.Sprintf(`if key, ok := %s.APIKeyFromContext(ctx); ok {
+				r.Header.Set(%q, key)
+			}
+			`, ep, header)
+		}
+	}
+	if schemes["basic"] {
+		if transport == "grpc" {
+			server += fmt.Sprintf(`if v := md["authorization"]; len(v) > 0 && strings.HasPrefix(v[0], "Basic ") {
+				if b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v[0], "Basic ")); err == nil {
+					if s := strings.SplitN(string(b), ":", 2); len(s) == 2 {
+						ctx = %s.ContextWithBasicAuth(ctx, s[0], s[1])
+					}
+				}
+			}
+			`, ep)
+			client += fmt.Sprintf(`if user, password, ok := %s.BasicAuthFromContext(ctx); ok {
+				(*md)["authorization"] = []string{"Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))}
+			}
+			`, ep)
+		} else {
+			server += fmt.Sprintf(`if user, password, ok := r.BasicAuth(); ok {
+				ctx = %s.ContextWithBasicAuth(ctx, user, password)
+			}
+			`, ep)
+			client += fmt.Sprintf(`if user, password, ok := %s.BasicAuthFromContext(ctx); ok {
+				r.SetBasicAuth(user, password)
+			}
+			`, ep)
+		}
+	}
+	if transport == "grpc" {
+		return []parser.Method{
+			parser.NewMethodWithComment(
+				"authGRPCToContext",
+				`authGRPCToContext moves the credentials of the metadata to the context, the endpoint
+				middleware checks them. It follows the auth of gk.json.`,
+				parser.NamedTypeValue{},
+				server+"return ctx",
+				[]parser.NamedTypeValue{
+					parser.NewNameType("ctx", "context.Context"),
+					parser.NewNameType("md", "metadata.MD"),
+				},
+				[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+			),
+			parser.NewMethodWithComment(
+				"authContextToGRPC",
+				`authContextToGRPC moves the credentials of the context to the metadata of the
+				request. It follows the auth of gk.json.`,
+				parser.NamedTypeValue{},
+				client+"return ctx",
+				[]parser.NamedTypeValue{
+					parser.NewNameType("ctx", "context.Context"),
+					parser.NewNameType("md", "*metadata.MD"),
+				},
+				[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+			),
+		}, nil
+	}
+	return []parser.Method{
+		parser.NewMethodWithComment(
+			"authHTTPToContext",
+			`authHTTPToContext moves the credentials of the request headers to the context, the
+			endpoint middleware checks them. It follows the auth of gk.json.`,
+			parser.NamedTypeValue{},
+			server+"return ctx",
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("r", "*http.Request"),
+			},
+			[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+		),
+		parser.NewMethodWithComment(
+			"authContextToHTTP",
+			`authContextToHTTP moves the credentials of the context to the request headers. It
+			follows the auth of gk.json.`,
+			parser.NamedTypeValue{},
+			client+"return ctx",
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("r", "*http.Request"),
+			},
+			[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+		),
+	}, nil
+}
+
+// authTransportImports are the imports of the auth helpers of the transport.
+func authTransportImports(iface *parser.Interface, transport string) ([]parser.NamedTypeValue, error) {
+	schemes, err := authSchemesOf(iface)
+	if err != nil {
+		return nil, err
+	}
+	imports := []parser.NamedTypeValue{}
+	if schemes["jwt"] {
+		imports = append(imports, parser.NewNameType("", `"github.com/go-kit/kit/auth/jwt"`))
+	}
+	if transport == "grpc" {
+		imports = append(imports, parser.NewNameType("", `"google.golang.org/grpc/metadata"`))
+		if schemes["basic"] {
+			imports = append(imports,
+				parser.NewNameType("", `"encoding/base64"`),
+				parser.NewNameType("", `"strings"`),
+			)
+		}
+	}
+	return imports, nil
+}
+
+// authReplaceHelpers replaces the auth helpers of the file, or adds them, and switches the
+// request functions of the transports generated before them to the helpers.
+func authReplaceHelpers(file *parser.File, helpers []parser.Method, imports []parser.NamedTypeValue, options map[string]string) {
+	for k := range file.Methods {
+		for old, v := range options {
+			file.Methods[k].Body = strings.ReplaceAll(file.Methods[k].Body, old, v)
+		}
+	}
+	for _, h := range helpers {
+		if k := methodIndex(file, h.Name); k >= 0 {
+			file.Methods[k] = h
+		} else {
+			file.Methods = append(file.Methods, h)
+		}
+	}
+	for _, v := range imports {
+		exists := false
+		for _, vv := range file.Imports {
+			exists = exists || vv.Type == v.Type
+		}
+		if !exists {
+			file.Imports = append(file.Imports, v)
+		}
+	}
+}
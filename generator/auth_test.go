@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+// testAuthMethods are the methods of the acc service, Watch has no authentication and Upload
+// is authenticated with basic.
+var testAuthMethods = strings.NewReplacer(
+	"Watch(", "// gk:auth none\n\tWatch(",
+	"Upload(", "// gk:auth basic\n\tUpload(",
+).Replace(testAccMethods)
+
+func TestAuthScheme(t *testing.T) {
+	testProject(t, nil)
+	testService(t, "acc", testAuthMethods, testAccTypes)
+	iface, err := LoadServiceInterfaceFromFile("acc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := "acc/pkg/accservice/service.go"
+	src := strings.Replace(testRead(t, file), "type Service interface", "// gk:auth apikey\ntype Service interface", 1)
+	if err := fs.Get().WriteFile(file, src, true); err != nil {
+		t.Fatal(err)
+	}
+	apikey, err := LoadServiceInterfaceFromFile("acc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		settings map[string]interface{}
+		iface    string
+		want     string
+	}{
+		{nil, "", "Get:jwt Watch:none Upload:basic Chat:jwt"},
+		{map[string]interface{}{"auth.scheme": "basic"}, "", "Get:basic Watch:none Upload:basic Chat:basic"},
+		// the directive of the interface comes before auth.scheme
+		{map[string]interface{}{"auth.scheme": "basic"}, "apikey", "Get:apikey Watch:none Upload:basic Chat:apikey"},
+		// the settings of the methods come before the directive of the interface, not before
+		// the directives of the methods
+		{map[string]interface{}{"endpoints.methods.Get.auth": "basic", "endpoints.methods.Upload.auth": "jwt"}, "apikey", "Get:basic Watch:none Upload:basic Chat:apikey"},
+		// the methods without the auth middleware are not authenticated
+		{map[string]interface{}{"endpoints.methods.Get.middleware": []string{"logging"}}, "", "Get:none Watch:none Upload:basic Chat:jwt"},
+		{map[string]interface{}{"endpoints.middleware": []string{"logging"}}, "", "Get:none Watch:none Upload:none Chat:none"},
+	} {
+		viper.Set("auth.scheme", "jwt")
+		viper.Set("endpoints.middleware", []string{"auth", "metrics", "logging", "tracing", "validate"})
+		viper.Set("endpoints.methods", map[string]interface{}{})
+		for k, v := range c.settings {
+			viper.Set(k, v)
+		}
+		in := iface
+		if c.iface != "" {
+			in = apikey
+		}
+		var got []string
+		for _, m := range in.Methods {
+			scheme, err := authScheme(in, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, m.Name+":"+scheme)
+		}
+		if strings.Join(got, " ") != c.want {
+			t.Errorf("%v %s: got %q, want %q", c.settings, c.iface, strings.Join(got, " "), c.want)
+		}
+	}
+
+	viper.Set("endpoints.middleware", []string{"auth"})
+	viper.Set("endpoints.methods", map[string]interface{}{})
+	viper.Set("auth.scheme", "oauth")
+	if _, err := authScheme(iface, iface.Methods[0]); err == nil || !strings.Contains(err.Error(), "The authentication `oauth` of `auth.scheme` is not supported") {
+		t.Errorf("got %v, want the unsupported authentication of auth.scheme", err)
+	}
+	viper.Set("endpoints.methods.Get.auth", "digest")
+	if _, err := authScheme(iface, iface.Methods[0]); err == nil || !strings.Contains(err.Error(), "The authentication `digest` of `endpoints.methods.Get.auth` is not supported") {
+		t.Errorf("got %v, want the unsupported authentication of the method", err)
+	}
+}
+
+func TestAuthInit(t *testing.T) {
+	testProject(t, map[string]interface{}{"gk_transport": "http", "auth.scheme": "apikey"})
+	testService(t, "acc", testAuthMethods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	auth := testRead(t, "acc/pkg/accendpoint/auth.go")
+	assertContains(t, auth, `
+		apiKeysSource = "env:API_KEYS"
+		basicUsersSource = "env:BASIC_AUTH_USERS"`)
+	assertContains(t, auth, `func (e *AuthError) Unwrap() error { return accservice.ErrUnauthenticated }`)
+	assertContains(t, auth, `func APIKeyMiddleware() endpoint.Middleware {`)
+	assertContains(t, auth, `func ContextWithAPIKey(ctx context.Context, key string) context.Context {`)
+	assertContains(t, auth, `func BasicAuthMiddleware() endpoint.Middleware {`)
+	assertContains(t, auth, `if !containsCredential(users, user+":"+password) {`)
+	assertNotContains(t, auth, `JWTMiddleware`)
+
+	set := testRead(t, "acc/pkg/accendpoint/set.go")
+	assertContains(t, set, `
+		apiKeyAuth := APIKeyMiddleware()
+		basicAuth := BasicAuthMiddleware()`)
+	if chain := strings.Join(testEndpointChain(t, set, "Get"), " "); !strings.HasSuffix(chain, "apiKeyAuth") {
+		t.Errorf("the chain of Get is %q, want apiKeyAuth", chain)
+	}
+	if chain := strings.Join(testEndpointChain(t, set, "Watch"), " "); strings.Contains(chain, "Auth") {
+		t.Errorf("the chain of Watch is %q, want no authentication", chain)
+	}
+	if chain := strings.Join(testEndpointChain(t, set, "Upload"), " "); !strings.HasSuffix(chain, "basicAuth") {
+		t.Errorf("the chain of Upload is %q, want basicAuth", chain)
+	}
+
+	http := testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, http, `ops := append(options, httptransport.ServerBefore(authHTTPToContext))`)
+	assertContains(t, http, `
+		if key := r.Header.Get("X-API-Key"); key != "" {
+			ctx = accendpoint.ContextWithAPIKey(ctx, key)
+		}
+		if user, password, ok := r.BasicAuth(); ok {
+			ctx = accendpoint.ContextWithBasicAuth(ctx, user, password)
+		}`)
+	assertContains(t, http, `r.SetBasicAuth(user, password)`)
+
+	// the JWT scheme reads its keys from a JWKS
+	testProject(t, map[string]interface{}{"gk_transport": "http", "auth.jwt.key": "jwks:keys.json"})
+	testService(t, "acc", testAccMethods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	auth = testRead(t, "acc/pkg/accendpoint/auth.go")
+	assertContains(t, auth, `jwtKeySource = "jwks:keys.json"`)
+	assertContains(t, auth, `parser := jwt.NewParser(jwtKeyFunc(), stdjwt.SigningMethodHS256, jwt.MapClaimsFactory)`)
+	assertContains(t, auth, `func parseJWKS(b []byte) (map[string]interface{}, error) {`)
+	assertNotContains(t, auth, `APIKeyMiddleware`)
+	assertNotContains(t, auth, `BasicAuthMiddleware`)
+
+	// no auth.go without authentication
+	testProject(t, map[string]interface{}{"gk_transport": "http", "auth.scheme": "none"})
+	testService(t, "acc", testAccMethods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := fs.Get().Exists("acc/pkg/accendpoint/auth.go"); b {
+		t.Error("auth.go is generated without authentication")
+	}
+	assertNotContains(t, testRead(t, "acc/pkg/accendpoint/set.go"), `Auth`)
+
+	for _, c := range []struct {
+		settings map[string]interface{}
+		err      string
+	}{
+		{map[string]interface{}{"auth.jwt.algorithm": "PS256"}, "The JWT algorithm `PS256` of `auth.jwt.algorithm` is not supported"},
+		{map[string]interface{}{"auth.scheme": "apikey", "auth.api_key.keys": "API_KEYS"}, "The credentials `API_KEYS` of `auth.api_key.keys` are not one of env:<...>, file:<...>"},
+		{map[string]interface{}{"auth.scheme": "basic", "auth.basic.users": "jwks:users.json"}, "The credentials `jwks:users.json` of `auth.basic.users`"},
+	} {
+		c.settings["gk_transport"] = "http"
+		testProject(t, c.settings)
+		testService(t, "acc", testAccMethods, testAccTypes)
+		if err := NewServiceInitGenerator().Generate("acc"); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%v: got %v, want %s", c.settings, err, c.err)
+		}
+	}
+}
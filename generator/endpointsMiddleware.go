@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/parser"
+	"github.com/liuchamp/gk/utils"
+)
+
+// endpointMiddlewareNames are the endpoint middleware `New` can chain, by their name in
+// `endpoints.middleware`.
+var endpointMiddlewareNames = []string{"auth", "metrics", "logging", "tracing", "validate", "ratelimit", "circuitbreaker", "timeout"}
+
+// endpointMiddlewareImports are the imports of the statements of the middleware.
+var endpointMiddlewareImports = map[string][]parser.NamedTypeValue{
+	"circuitbreaker": {parser.NewNameType("", `"github.com/sony/gobreaker"`)},
+}
+
+// endpointChain is the middleware of the endpoint of the method, from the outermost to the
+// innermost: `endpoints.methods.<Method>.middleware` when it is set, `endpoints.middleware`
+// otherwise. The rate limit, the circuit breaker and the timeout the method has its own settings
+// of come first when the chain misses them. The methods streaming their results have no timeout.
+func endpointChain(m parser.Method) ([]string, error) {
+	key := fmt.Sprintf("endpoints.methods.%s.middleware", m.Name)
+	if !viper.IsSet(key) {
+		key = "endpoints.middleware"
+	}
+	chain := viper.GetStringSlice(key)
+	for _, v := range chain {
+		supported := false
+		for _, n := range endpointMiddlewareNames {
+			supported = supported || v == n
+		}
+		if !supported {
+			return nil, fmt.Errorf("The endpoint middleware `%s` of `%s` is not supported, use %s",
+				v, key, strings.Join(endpointMiddlewareNames, ", "))
+		}
+	}
+	r, err := methodResilience(m)
+	if err != nil {
+		return nil, err
+	}
+	if streamsResults(m) {
+		if r.own["timeout"] {
+			return nil, fmt.Errorf("The timeout of %s would cancel the results it streams, it cannot have one", m.Name)
+		}
+		var kept []string
+		for _, v := range chain {
+			if v != "timeout" {
+				kept = append(kept, v)
+			}
+		}
+		chain = kept
+	}
+	own := []string{}
+	for _, n := range resilienceMiddlewareNames {
+		missing := r.own[n]
+		for _, v := range chain {
+			missing = missing && v != n
+		}
+		if missing {
+			own = append(own, n)
+		}
+	}
+	return append(own, chain...), nil
+}
+
+// streamsResults tells if the method returns channels or readers, they are read after the
+// invocation returned so its context must outlive it.
+func streamsResults(m parser.Method) bool {
+	for _, v := range m.Results {
+		if _, ok := chanElem(v.Type); ok || httpReader(v.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// authMiddlewareNames are the instances of the authentication middleware in `New`, by scheme.
+var authMiddlewareNames = map[string]string{"jwt": "jwtAuth", "apikey": "apiKeyAuth", "basic": "basicAuth"}
+
+// authMiddlewareConstructors are the functions of auth.go returning the middleware, by scheme.
+var authMiddlewareConstructors = map[string]string{"jwt": "JWTMiddleware", "apikey": "APIKeyMiddleware", "basic": "BasicAuthMiddleware"}
+
+// endpointMiddlewareStatement is the statement wrapping ep in the middleware, scheme is the
+// authentication of the method and r its resilience settings.
+func endpointMiddlewareStatement(name, scheme string, r resilience) string {
+	switch name {
+	case "auth":
+		if scheme == "none" {
+			return ""
+		}
+		return fmt.Sprintf("ep = %s(ep)", authMiddlewareNames[scheme])
+	case "metrics":
+		return `ep = InstrumentingMiddleware(duration.With("method", method))(ep)`
+	case "logging":
+		return `ep = LoggingMiddleware(log.With(logger, "method", method))(ep)`
+	case "tracing":
+		if mode, _ := tracingMode(); mode == "none" {
+			return ""
+		}
+		return "ep = TraceMiddleware(tracer, method)(ep)"
+	case "validate":
+		return "ep = ValidatingMiddleware()(ep)"
+	case "ratelimit", "circuitbreaker", "timeout":
+		return fmt.Sprintf("ep = %s(ep)", resilienceStatement(name, r))
+	}
+	return ""
+}
+
+// endpointsNew builds the body of `New` of the endpoints, each endpoint of the set is wrapped in
+// its chain of middleware, and returns the imports the chains need.
+func endpointsNew(iface *parser.Interface) (string, []parser.NamedTypeValue, error) {
+	if _, err := tracingMode(); err != nil {
+		return "", nil, err
+	}
+	body := ""
+	used := map[string]bool{}
+	for _, v := range iface.Methods {
+		chain, err := endpointChain(v)
+		if err != nil {
+			return "", nil, err
+		}
+		scheme, err := authScheme(iface, v)
+		if err != nil {
+			return "", nil, err
+		}
+		r, err := methodResilience(v)
+		if err != nil {
+			return "", nil, err
+		}
+		statements := ""
+		for k := len(chain) - 1; k >= 0; k-- {
+			if statement := endpointMiddlewareStatement(chain[k], scheme, r); statement != "" {
+				statements += "\n" + statement
+				used[chain[k]] = true
+			}
+		}
+		used[scheme] = true
+		method := ""
+		if strings.Contains(statements, "method") {
+			method = fmt.Sprintf("\nmethod := %q", utils.ToLowerFirstCamelCase(v.Name))
+		}
+		body += fmt.Sprintf(`
+		{%s
+			ep := Make%sEndpoint(svc)%s
+			set.%sEndpoint = ep
+		}
+		`, method, v.Name, statements, v.Name)
+	}
+	prelude := ""
+	for _, v := range authSchemes {
+		if used[v] && v != "none" {
+			prelude += fmt.Sprintf("\n%s := %s()", authMiddlewareNames[v], authMiddlewareConstructors[v])
+		}
+	}
+	body = prelude + body
+	imports := []parser.NamedTypeValue{}
+	if strings.Contains(body, "time.") {
+		imports = append(imports, parser.NewNameType("", `"time"`))
+	}
+	for _, n := range endpointMiddlewareNames {
+		if used[n] {
+			imports = append(imports, endpointMiddlewareImports[n]...)
+		}
+	}
+	return body + "\n return set", imports, nil
+}
+
+// endpointsTimeout tells if a chain of the service has the timeout middleware.
+func endpointsTimeout(iface *parser.Interface) bool {
+	for _, v := range iface.Methods {
+		chain, _ := endpointChain(v)
+		for _, n := range chain {
+			if n == "timeout" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timeoutMiddleware is the endpoint middleware giving a deadline to the invocations.
+func timeoutMiddleware() parser.Method {
+	return parser.NewMethodWithComment(
+		"TimeoutMiddleware",
+		`TimeoutMiddleware returns an endpoint middleware that cancels the context of
+		each invocation once the timeout elapsed.`,
+		parser.NamedTypeValue{},
+		`return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				return next(ctx, request)
+			}
+		}`,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("timeout", "time.Duration"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "endpoint.Middleware"),
+		},
+	)
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+var testMiddlewareRegexp = regexp.MustCompile(`ep = (\w+)`)
+
+// testEndpointChain returns the middleware wrapping the endpoint of the method in the body of
+// New, from the innermost to the outermost.
+func testEndpointChain(t *testing.T, body, method string) []string {
+	t.Helper()
+	start := strings.Index(body, "ep := Make"+method+"Endpoint(svc)")
+	if start == -1 {
+		t.Fatalf("no endpoint of %s in\n%s", method, body)
+	}
+	block := body[start:]
+	block = block[:strings.Index(block, "set."+method+"Endpoint = ep")]
+	var chain []string
+	for _, v := range testMiddlewareRegexp.FindAllStringSubmatch(block, -1) {
+		chain = append(chain, v[1])
+	}
+	return chain
+}
+
+func TestEndpointChain(t *testing.T) {
+	testProject(t, nil)
+	methods := strings.Replace(testAccMethods, "Upload(", "// gk:ratelimit 5 10\n\tUpload(", 1)
+	testService(t, "acc", methods, testAccTypes)
+	iface, err := LoadServiceInterfaceFromFile("acc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		settings map[string]interface{}
+		method   string
+		want     string
+	}{
+		// endpoints.middleware lists the chain from the outermost
+		{nil, "Get", "ValidatingMiddleware TraceMiddleware LoggingMiddleware InstrumentingMiddleware jwtAuth"},
+		{map[string]interface{}{"endpoints.middleware": []string{"logging", "auth", "timeout"}}, "Get", "TimeoutMiddleware jwtAuth LoggingMiddleware"},
+		// the methods streaming their results have no timeout
+		{map[string]interface{}{"endpoints.middleware": []string{"logging", "auth", "timeout"}}, "Watch", "jwtAuth LoggingMiddleware"},
+		// the methods override the chain
+		{map[string]interface{}{"endpoints.methods.Get.middleware": []string{"auth", "logging"}}, "Get", "LoggingMiddleware jwtAuth"},
+		{map[string]interface{}{"endpoints.methods.Get.middleware": []string{}}, "Get", ""},
+		{map[string]interface{}{"endpoints.methods.Get.middleware": []string{"auth", "logging"}}, "Chat", "ValidatingMiddleware TraceMiddleware LoggingMiddleware InstrumentingMiddleware jwtAuth"},
+		// the rate limit of the method comes first when the chain misses it
+		{nil, "Upload", "ValidatingMiddleware TraceMiddleware LoggingMiddleware InstrumentingMiddleware jwtAuth RateLimitMiddleware"},
+		{map[string]interface{}{"endpoints.middleware": []string{"auth", "ratelimit", "logging"}}, "Upload", "LoggingMiddleware RateLimitMiddleware jwtAuth"},
+	} {
+		viper.Set("endpoints.middleware", []string{"auth", "metrics", "logging", "tracing", "validate"})
+		viper.Set("endpoints.methods", map[string]interface{}{})
+		for k, v := range c.settings {
+			viper.Set(k, v)
+		}
+		body, _, err := endpointsNew(iface)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(testEndpointChain(t, body, c.method), " "); got != c.want {
+			t.Errorf("%v: the chain of %s is %q, want %q", c.settings, c.method, got, c.want)
+		}
+	}
+
+	viper.Set("endpoints.middleware", []string{"auth", "retry"})
+	if _, _, err := endpointsNew(iface); err == nil || !strings.Contains(err.Error(), "The endpoint middleware `retry` of `endpoints.middleware` is not supported") {
+		t.Errorf("got %v, want the unsupported middleware", err)
+	}
+}
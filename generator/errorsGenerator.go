@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	template "github.com/liuchamp/gk/templates"
+)
+
+// ErrorCatalogGenerator writes the error catalog of a service, the errors the transports send
+// with a status code. The catalog belongs to the service once written, it is never overwritten.
+type ErrorCatalogGenerator struct {
+}
+
+func NewErrorCatalogGenerator() *ErrorCatalogGenerator {
+	return &ErrorCatalogGenerator{}
+}
+
+func (sg *ErrorCatalogGenerator) Generate(name string) error {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	fname, err := te.ExecuteString(viper.GetString("service.errors_file_name"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	sfile := path + defaultFs.FilePathSeparator() + fname
+	b, err := defaultFs.Exists(sfile)
+	if err != nil {
+		return err
+	}
+	if b {
+		return nil
+	}
+	pkg := fmt.Sprintf("%sservice", name)
+	if st, err := LoadServiceTypes(name); err == nil {
+		pkg = st.Package
+	}
+	logrus.Info(fmt.Sprintf("Generating the error catalog of service %s", name))
+	tmpl, err := te.Execute("errors", map[string]string{
+		"Package": pkg,
+	})
+	if err != nil {
+		return err
+	}
+	if err = defaultFs.MkdirAll(path); err != nil {
+		return err
+	}
+	return defaultFs.WriteFile(sfile, tmpl, false)
+}
@@ -41,6 +41,9 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 			logrus.Error(err.Error())
 			return err
 		}
+		if err = NewErrorCatalogGenerator().Generate(name); err != nil {
+			return err
+		}
 	}
 
 	gosrc := utils.GetGOPATH() + "/src/"
@@ -54,7 +57,11 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 	}
 	pwd = strings.Replace(pwd, "\\", "/", -1)
 	projectPath := strings.Replace(pwd, gosrc, "", 1)
-	pbImport := projectPath + "/" + path
+	pbPath, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	pbImport := projectPath + "/" + pbPath
 	pbImport = strings.Replace(pbImport, "\\", "/", -1)
 	enpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
 	if err != nil {
@@ -68,19 +75,28 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 	handler.Imports = []parser.NamedTypeValue{
 		parser.NewNameType("", "\"context\""),
 		parser.NewNameType("", "\"errors\"\n"),
-		parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
-		parser.NewNameType("stdopentracing", "\"github.com/opentracing/opentracing-go\""),
-		parser.NewNameType("", "\"google.golang.org/grpc\""),
-		parser.NewNameType("jujuratelimit", "\"github.com/juju/ratelimit\"\n"),
+		parser.NewNameType("", "\"google.golang.org/grpc\"\n"),
 		parser.NewNameType("grpctransport", "\"github.com/go-kit/kit/transport/grpc\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/ratelimit\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/tracing/opentracing\""),
 		parser.NewNameType("", "\"github.com/go-kit/kit/endpoint\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/auth/jwt\""),
 		parser.NewNameType("", "\"github.com/go-kit/kit/log\"\n"),
-		parser.NewNameType("", fmt.Sprintf("\"%s\"", pbImport)),
 		parser.NewNameType("", fmt.Sprintf("\"%s\"", endpointsImport)),
 	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return err
+	}
+	handler.Imports = append(handler.Imports, parser.NewNameType("", fmt.Sprintf("\"%s\"", serviceImport)))
+	pbs := LoadPBService(name)
+	handler.Imports = append(handler.Imports, pbs.Imports(pbImport)...)
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	pc := newPBConverter(name, st, pbs)
 
 	if path, err = te.ExecuteString(viper.GetString("grpctransport.path"), map[string]string{"ServiceName": name}); err != nil {
 		return err
@@ -97,7 +113,7 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 	// If service generated before, go to update
 	{
 		if exist {
-			logrus.Info("exist grpc transport file found: %v ", sfile)
+			logrus.Infof("exist grpc transport file found: %v ", sfile)
 			g := NewGRPCUpdateGenerator()
 			err = g.Generate(name)
 			return nil
@@ -105,6 +121,9 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 	}
 
 	logrus.Info("Init grpc transport for service ", name)
+	if err = defaultFs.MkdirAll(path); err != nil {
+		return err
+	}
 
 	grpcStruct := parser.NewStruct("grpcServer", []parser.NamedTypeValue{})
 	//NewGRPCServer
@@ -113,20 +132,17 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		`NewGRPCServer makes a set of endpoints available as a gRPC server.`,
 		parser.NamedTypeValue{},
 		`
-		zipkinServer := zipkin.GRPCServerTrace(zipkinTracer)
-		options := []grpctransport.ServerOption{
+		options := append([]grpctransport.ServerOption{
 			grpctransport.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
-			zipkinServer,
-		}
+		}, tracingGRPCServerOptions(tracer)...)
 		gs := &grpcServer{}`,
 		[]parser.NamedTypeValue{
 			parser.NewNameType("endpoints", fmt.Sprintf("%sendpoint.Set", name)),
-			//parser.NewNameType("otTracer", "stdopentracing.Tracer"),
-			parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+			tracingParam(name + "endpoint."),
 			parser.NewNameType("logger", "log.Logger"),
 		},
 		[]parser.NamedTypeValue{
-			parser.NewNameType("", fmt.Sprintf("%spb.%sServer", name, utils.ToUpperFirstCamelCase(name))),
+			parser.NewNameType("", pbs.ServerType()),
 		},
 	))
 	//NewGRPCClient
@@ -135,57 +151,60 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		`NewGRPCClient makes a set of endpoints available as a gRPC client.`,
 		parser.NamedTypeValue{},
 		fmt.Sprintf(`
-		zipkinClient := zipkin.GRPCClientTrace(zipkinTracer)
-		options := []grpctransport.ClientOption{
-			zipkinClient,
-		}
+		options := tracingGRPCClientOptions(tracer)
 		set := %sendpoint.Set{}
 		`, name),
 		[]parser.NamedTypeValue{
 			parser.NewNameType("conn", "*grpc.ClientConn"),
-			//parser.NewNameType("otTracer", "stdopentracing.Tracer"),
-			parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+			tracingParam(name + "endpoint."),
 			parser.NewNameType("logger", "log.Logger"),
 		},
 		[]parser.NamedTypeValue{
 			parser.NewNameType("", fmt.Sprintf("%sservice.Service", utils.ToLowerFirstCamelCase(name))),
 		},
 	))
-	handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
-		"str2err",
-		`str2err `,
-		parser.NamedTypeValue{},
-		fmt.Sprintf(`
-		if s == "" {
-			return nil
+	handler.Methods = append(handler.Methods, grpcErrorHelpers(pc)...)
+	handler.Imports = append(handler.Imports, grpcErrorImports()...)
+	auth, err := authTransportHelpers(name, iface, "grpc")
+	if err != nil {
+		return err
+	}
+	authImports, err := authTransportImports(iface, "grpc")
+	if err != nil {
+		return err
+	}
+	tracing, err := tracingTransportHelpers(name, "grpc")
+	if err != nil {
+		return err
+	}
+	tracingImports, err := tracingTransportImports("grpc")
+	if err != nil {
+		return err
+	}
+	handler.Methods = append(handler.Methods, auth...)
+	handler.Imports = append(handler.Imports, authImports...)
+	// the transport already imports some of the packages of the helpers
+	authReplaceHelpers(&handler, tracing, tracingImports, nil)
+	for _, v := range iface.Methods {
+		if isStreamMethod(v) {
+			server, client, err := grpcStream(pc, v)
+			if err != nil {
+				return err
+			}
+			grpcStreamTracer(&grpcStruct, &handler.Methods[0], name)
+			grpcStruct.Vars = append(grpcStruct.Vars, parser.NewNameType(
+				utils.ToLowerFirstCamelCase(v.Name),
+				"endpoint.Endpoint",
+			))
+			handler.Methods = append(handler.Methods, server)
+			handler.Methods[0].Body += "\n" + fmt.Sprintf(`gs.%s = endpoints.%sEndpoint`, utils.ToLowerFirstCamelCase(v.Name), v.Name)
+			handler.Methods[1].Body += "\n" + client
+			continue
 		}
-		return errors.New(s)
-		`),
-		[]parser.NamedTypeValue{
-			parser.NewNameType("s", "string"),
-		},
-		[]parser.NamedTypeValue{
-			parser.NewNameType("", "error"),
-		},
-	))
-	handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
-		"err2str",
-		`err2str `,
-		parser.NamedTypeValue{},
-		fmt.Sprintf(`
-		if err == nil {
-			return ""
+		decodeReq, encodeRes, encodeReq, decodeRes, err := grpcCodecs(pc, v)
+		if err != nil {
+			return err
 		}
-		return err.Error()
-		`),
-		[]parser.NamedTypeValue{
-			parser.NewNameType("err", "error"),
-		},
-		[]parser.NamedTypeValue{
-			parser.NewNameType("", "string"),
-		},
-	))
-	for _, v := range iface.Methods {
 		//add member to grpcServer
 		grpcStruct.Vars = append(grpcStruct.Vars, parser.NewNameType(
 			utils.ToLowerFirstCamelCase(v.Name),
@@ -193,14 +212,6 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		))
 
 		// add server side request decoder
-		var decodeReqParamList string
-		for _, v := range v.Parameters {
-			if v.Type == "context.Context" {
-				continue
-			}
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			decodeReqParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"decodeGRPC"+v.Name+"Req",
 			fmt.Sprintf(
@@ -209,9 +220,7 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := grpcReq.(*%spb.%sReq)
-			req := %sendpoint.%sReq{%s}
-			return req, nil`, name, v.Name, name, v.Name, decodeReqParamList),
+			decodeReq,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("grpcReq", "interface{}"),
@@ -223,15 +232,6 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		))
 
 		// add server side response encoder
-		var encodeResParamList string
-		for _, v := range v.Results {
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			if v.Name == "Err" || v.Name == "err" || v.Type == "error" || v.Type == "Error" {
-				encodeResParamList += fmt.Sprintf("%s:err2str(r.%s),", pname, pname)
-			} else {
-				encodeResParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-			}
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"encodeGRPC"+v.Name+"Res",
 			fmt.Sprintf(
@@ -240,9 +240,7 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := response.(%sendpoint.%sRes)
-			res := &%spb.%sRes{%s}
-			return res, nil`, name, v.Name, name, v.Name, encodeResParamList),
+			encodeRes,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("response", "interface{}"),
@@ -254,25 +252,15 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		))
 
 		// add client side request encoder
-		var encodeReqParamList string
-		for _, v := range v.Parameters {
-			if v.Type == "context.Context" {
-				continue
-			}
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			encodeReqParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"encodeGRPC"+v.Name+"Req",
 			fmt.Sprintf(
-				`encodeGRPC%Req s a transport/grpc.EncodeRequestFunc that converts a
+				`encodeGRPC%sReq is a transport/grpc.EncodeRequestFunc that converts a
 				 user-domain sum request to a gRPC sum request. Primarily useful in a client.`,
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := request.(%sendpoint.%sReq)
-			req :=  &%spb.%sReq{%s}
-			return req, nil`, name, v.Name, name, v.Name, encodeReqParamList),
+			encodeReq,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("request", "interface{}"),
@@ -284,26 +272,15 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		))
 
 		// add client side response decoder
-		var decodeResParamList string
-		for _, v := range v.Results {
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			if v.Name == "Err" || v.Name == "err" || v.Type == "error" || v.Type == "Error" {
-				decodeResParamList += fmt.Sprintf("%s:str2err(r.%s),", pname, pname)
-			} else {
-				decodeResParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-			}
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"decodeGRPC"+v.Name+"Res",
 			fmt.Sprintf(
-				`decodeGRPC%Res is a transport/grpc.DecodeResponseFunc that converts a
+				`decodeGRPC%sRes is a transport/grpc.DecodeResponseFunc that converts a
 				 gRPC sum reply to a user-domain sum response. Primarily useful in a client.`,
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := grpcReply.(*%spb.%sRes)
-			res := %sendpoint.%sRes{%s}
-			return res, nil`, name, utils.ToUpperFirstCamelCase(v.Name), name, v.Name, decodeResParamList),
+			decodeRes,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("grpcReply", "interface{}"),
@@ -320,20 +297,19 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 			fmt.Sprintf(
 				`_, rp, err := s.%s.ServeGRPC(ctx, req)
 					if err != nil {
-						return nil, err
+						return nil, err2status(err)
 					}
-					rep = rp.(*%spb.%sRes)
+					rep = rp.(*%s)
 					return rep, err`,
 				utils.ToLowerFirstCamelCase(v.Name),
-				name,
-				v.Name,
+				pbs.ResponseType(v.Name),
 			),
 			[]parser.NamedTypeValue{
 				parser.NewNameType("ctx", "context.Context"),
-				parser.NewNameType("req", fmt.Sprintf("*%spb.%sReq", name, v.Name)),
+				parser.NewNameType("req", "*"+pbs.RequestType(v.Name)),
 			},
 			[]parser.NamedTypeValue{
-				parser.NewNameType("rep", fmt.Sprintf("*%spb.%sRes", name, v.Name)),
+				parser.NewNameType("rep", "*"+pbs.ResponseType(v.Name)),
 				parser.NewNameType("err", "error"),
 			},
 		))
@@ -341,8 +317,7 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 		//init grpcServer method
 		handler.Methods[0].Body += "\n" + fmt.Sprintf(`
 			{
-				//ops := append(options, grpctransport.ServerBefore(opentracing.GRPCToContext(otTracer, "%s", logger)))
-				ops := append(options, grpctransport.ServerBefore(jwt.GRPCToContext()))
+				ops := append(options, grpctransport.ServerBefore(authGRPCToContext))
 				//ops = append(ops, grpctransport.ServerBefore(header.GRPCToContext()))
 				gs.%s = grpctransport.NewServer(
 					endpoints.%sEndpoint,
@@ -351,29 +326,29 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 					ops...,
 				)
 			}
-		`, v.Name, utils.ToLowerFirstCamelCase(v.Name), v.Name, v.Name, v.Name)
+		`, utils.ToLowerFirstCamelCase(v.Name), v.Name, v.Name, v.Name)
 
 		//init grpc client method
 		lowerName := utils.ToLowerFirstCamelCase(v.Name)
 		upperName := utils.ToUpperFirstCamelCase(v.Name)
 		handler.Methods[1].Body += "\n" + fmt.Sprintf(`
 			{
-				//ops := append(options, grpctransport.ClientBefore(opentracing.ContextToGRPC(otTracer, logger)))
-				ops := append(options, grpctransport.ClientBefore(jwt.ContextToGRPC()))
+				ops := append(options, grpctransport.ClientBefore(authContextToGRPC))
 				//ops = append(ops, grpctransport.ClientBefore(header.ContextToGRPC()))
 				ep := grpctransport.NewClient(
 					conn,
-					"%spb.%s",
+					"%s",
 					"%s",
 					encodeGRPC%sReq,
 					decodeGRPC%sRes,
-					%spb.%sRes{},
+					%s{},
 					ops...,
 				).Endpoint()
-				ep = opentracing.TraceClient(otTracer, "%s")(ep)
+				ep = decodeGRPCStatus(ep)
+				%s
 				set.%sEndpoint = ep
 			}
-		`, name, utils.ToUpperFirstCamelCase(name), upperName, upperName, upperName, name, upperName, lowerName, upperName)
+		`, pbs.FullName(), upperName, upperName, upperName, pbs.ResponseType(v.Name), tracingClientEndpoint(name, lowerName), upperName)
 	}
 	//close NewGRPCServer
 	handler.Methods[0].Body += `
@@ -382,17 +357,15 @@ func (sg *GRPCInitGenerator) Generate(name string) (err error) {
 	handler.Methods[1].Body += `
 	return set`
 
+	handler.Methods = append(handler.Methods, pc.helpers...)
+	handler.Imports = append(handler.Imports, pc.Imports()...)
 	handler.Structs = append(handler.Structs, grpcStruct)
 
 	err = defaultFs.WriteFile(sfile, handler.String(), false)
 	if err != nil {
 		return err
 	}
-	logrus.Warn("---------------------------------------------------------------------------------------")
-	logrus.Warn("The generator does not implement the Decoding and Encoding of the grpc request/response")
-	logrus.Warn("Before using the service don't forget to implement those.")
-	logrus.Warn("---------------------------------------------------------------------------------------")
-	return nil
+	return generateEndpointsTracing(name)
 }
 
 func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
@@ -429,7 +402,11 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 	}
 	pwd = strings.Replace(pwd, "\\", "/", -1)
 	projectPath := strings.Replace(pwd, gosrc, "", 1)
-	pbImport := projectPath + "/" + path
+	pbPath, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	pbImport := projectPath + "/" + pbPath
 	pbImport = strings.Replace(pbImport, "\\", "/", -1)
 	enpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
 	if err != nil {
@@ -444,8 +421,6 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 		parser.NewNameType("", "\"io\""),
 		parser.NewNameType("", "\"time\"\n"),
 
-		parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
-		parser.NewNameType("stdopentracing", "\"github.com/opentracing/opentracing-go\""),
 		parser.NewNameType("", "\"google.golang.org/grpc\"\n"),
 
 		parser.NewNameType("", "\"github.com/go-kit/kit/sd\""),
@@ -501,8 +476,7 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 			parser.NewNameType("retryTimeout", "time.Duration"),
 			parser.NewNameType("logger", "log.Logger"),
 			parser.NewNameType("etcdClient", "ketcd.Client"),
-			//parser.NewNameType("otTracer", "stdopentracing.Tracer"),
-			parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+			tracingParam(name + "endpoint."),
 		},
 		[]parser.NamedTypeValue{
 			parser.NewNameType("set", fmt.Sprintf("%sendpoint.Set", name)),
@@ -519,7 +493,7 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 			if err != nil {
 				return nil, nil, err
 			}
-			service := NewGRPCClient(conn, zipkinTracer, logger)
+			service := NewGRPCClient(conn, tracer, logger)
 			ep := makeEndpoint(service)
 	
 			return ep, conn, nil
@@ -527,8 +501,7 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 		`),
 		[]parser.NamedTypeValue{
 			parser.NewNameType("makeEndpoint", fmt.Sprintf("func(%sservice.Service) endpoint.Endpoint", name)),
-			//parser.NewNameType("otTracer", "stdopentracing.Tracer"),
-			parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+			tracingParam(name + "endpoint."),
 			parser.NewNameType("logger", "log.Logger"),
 		},
 		[]parser.NamedTypeValue{
@@ -544,15 +517,14 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 			if err != nil {
 				return nil
 			}
-			service := NewGRPCClient(conn, zipkinTracer, logger)
+			service := NewGRPCClient(conn, tracer, logger)
 			ep := makeEndpoint(service)
 			return ep
 		`),
 		[]parser.NamedTypeValue{
 			parser.NewNameType("svcName", "string"),
 			parser.NewNameType("makeEndpoint", fmt.Sprintf("func(%sservice.Service) endpoint.Endpoint", name)),
-			//parser.NewNameType("otTracer", "stdopentracing.Tracer"),
-			parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+			tracingParam(name + "endpoint."),
 			parser.NewNameType("logger", "log.Logger"),
 		},
 		[]parser.NamedTypeValue{
@@ -563,13 +535,13 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 	for _, v := range iface.Methods {
 		handler.Methods[0].Body += "\n" + fmt.Sprintf(`
 		{
-			factory := factory(%sendpoint.Make%sEndpoint, zipkinTracer, logger)
+			factory := factory(%sendpoint.Make%sEndpoint, tracer, logger)
 			endpointer := sd.NewEndpointer(instancer, factory, logger)
 			balancer := lb.NewRoundRobin(endpointer)
 			retry := lb.Retry(retryMax, retryTimeout, balancer)
-			set.%sEndpoint = retry
+			%s
 		}
-		`, name, utils.ToUpperFirstCamelCase(v.Name), utils.ToUpperFirstCamelCase(v.Name))
+		`, name, utils.ToUpperFirstCamelCase(v.Name), clientEndpoint(name, v))
 	}
 
 	handler.Methods[0].Body += `
@@ -581,5 +553,1076 @@ func (sg *GRPCInitGenerator) GenerateEndpointClient(name string) (err error) {
 		return err
 	}
 
+	if err = generateEndpointsResilience(name, iface); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
+}
+
+func isErrorResult(v parser.NamedTypeValue) bool {
+	return v.Name == "Err" || v.Name == "err" || v.Type == "error" || v.Type == "Error"
+}
+
+// grpcErrorImports are the packages of the gRPC status errors.
+func grpcErrorImports() []parser.NamedTypeValue {
+	return []parser.NamedTypeValue{
+		parser.NewNameType("", "\"google.golang.org/grpc/codes\""),
+		parser.NewNameType("", "\"google.golang.org/grpc/status\""),
+		parser.NewNameType("", "\"google.golang.org/genproto/googleapis/rpc/errdetails\""),
+	}
+}
+
+// grpcErrorHelpers are the functions turning the errors of the service into gRPC status errors
+// with the codes of the error catalog and back, plus err2str/str2err in the `string` mode.
+func grpcErrorHelpers(pc *pbConverter) []parser.Method {
+	helpers := []parser.Method{
+		parser.NewMethodWithComment(
+			"err2status",
+			`err2status turns an error of the service into a gRPC status error with the code the
+			error catalog gives to it, the field violations of the invalid requests are its details.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`
+			if err == nil {
+				return nil
+			}
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
+			s := status.New(codes.Code(%s.ErrorCode(err)), err.Error())
+			var verr *%sendpoint.ValidationError
+			if errors.As(err, &verr) {
+				br := &errdetails.BadRequest{}
+				for _, v := range verr.Violations {
+					br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+				}
+				if d, err := s.WithDetails(br); err == nil {
+					return d.Err()
+				}
+			}
+			return s.Err()`, pc.st.Package, pc.name),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("err", "error"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"status2err",
+			`status2err turns a gRPC status error back into the error of the catalog with its code
+			and message, the other errors are kept as they are.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`
+			s, ok := status.FromError(err)
+			if !ok {
+				return err
+			}
+			if e := %s.ErrorFromCode(%s.Code(s.Code()), s.Message()); e != nil {
+				return e
+			}
+			return err`, pc.st.Package, pc.st.Package),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("err", "error"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"decodeGRPCStatus",
+			`decodeGRPCStatus is an endpoint.Middleware turning the status errors of a gRPC client
+			endpoint back into the errors of the service.`,
+			parser.NamedTypeValue{},
+			`
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				response, err := next(ctx, request)
+				if err != nil {
+					return nil, status2err(err)
+				}
+				return response, nil
+			}`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("next", "endpoint.Endpoint"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "endpoint.Endpoint"),
+			},
+		),
+	}
+	if grpcStatusErrors() {
+		return helpers
+	}
+	return append(helpers,
+		parser.NewMethodWithComment(
+			"str2err",
+			`str2err `,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`
+		if s == "" {
+			return nil
+		}
+		return errors.New(s)
+		`),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("s", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"err2str",
+			`err2str `,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`
+		if err == nil {
+			return ""
+		}
+		return err.Error()
+		`),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("err", "error"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "string"),
+			},
+		),
+	)
+}
+
+// grpcCodecs returns the bodies of the server request decoder, the server response encoder,
+// the client request encoder and the client response decoder of the method.
+func grpcCodecs(pc *pbConverter, m parser.Method) (decodeReq, encodeRes, encodeReq, decodeRes string, err error) {
+	if decodeReq, err = grpcDecodeReqBody(pc, m); err != nil {
+		return
+	}
+	if encodeRes, err = grpcEncodeResBody(pc, m); err != nil {
+		return
+	}
+	if encodeReq, err = grpcEncodeReqBody(pc, m); err != nil {
+		return
+	}
+	decodeRes, err = grpcDecodeResBody(pc, m)
 	return
 }
+
+// grpcDecodeReqBody converts the gRPC request of the method to the endpoint request.
+func grpcDecodeReqBody(pc *pbConverter, m parser.Method) (string, error) {
+	list, err := pc.fields(pc.message(m.Name, true), m.Name, "r", m.Parameters, false)
+	if err != nil || list == "" {
+		return fmt.Sprintf(`return %sendpoint.%sReq{}, nil`, pc.name, m.Name), err
+	}
+	return fmt.Sprintf(`r := grpcReq.(*%s)
+		req := %sendpoint.%sReq{%s}
+		return req, nil`, pc.pbs.RequestType(m.Name), pc.name, m.Name, list), nil
+}
+
+// grpcEncodeResBody converts the endpoint response of the method to the gRPC reply, the error
+// is returned to be sent as the status of the call unless the `string` mode keeps it in the reply.
+func grpcEncodeResBody(pc *pbConverter, m parser.Method) (string, error) {
+	var errCheck string
+	message := pc.message(m.Name, false)
+	list, err := pc.fields(message, m.Name, "r", m.Results, true)
+	if err != nil {
+		return "", err
+	}
+	oneofs, err := pc.setOneofs(message, "res", "r", m.Results)
+	if err != nil {
+		return "", err
+	}
+	hasErr := pc.pbs.ResponseHasErr(m.Name)
+	for _, v := range m.Results {
+		if !isErrorResult(v) {
+			continue
+		}
+		pname := utils.ToUpperFirstCamelCase(v.Name)
+		if hasErr && !grpcStatusErrors() {
+			list += fmt.Sprintf("%s:err2str(r.%s),", pc.fieldName(message, pname), pname)
+		} else {
+			errCheck = fmt.Sprintf(`
+				if r.%s != nil {
+					return nil, err2status(r.%s)
+				}`, pname, pname)
+		}
+	}
+	return fmt.Sprintf(`r := response.(%sendpoint.%sRes)%s
+		res := &%s{%s}%s
+		return res, nil`, pc.name, m.Name, errCheck, pc.pbs.ResponseType(m.Name), list, oneofs), nil
+}
+
+// grpcEncodeReqBody converts the endpoint request of the method to the gRPC request.
+func grpcEncodeReqBody(pc *pbConverter, m parser.Method) (string, error) {
+	message := pc.message(m.Name, true)
+	list, err := pc.fields(message, m.Name, "r", m.Parameters, true)
+	if err != nil {
+		return "", err
+	}
+	oneofs, err := pc.setOneofs(message, "req", "r", m.Parameters)
+	if err != nil || list == "" && oneofs == "" {
+		return fmt.Sprintf(`return &%s{}, nil`, pc.pbs.RequestType(m.Name)), err
+	}
+	return fmt.Sprintf(`r := request.(%sendpoint.%sReq)
+		req :=  &%s{%s}%s
+		return req, nil`, pc.name, m.Name, pc.pbs.RequestType(m.Name), list, oneofs), nil
+}
+
+// grpcDecodeResBody converts the gRPC reply of the method to the endpoint response.
+func grpcDecodeResBody(pc *pbConverter, m parser.Method) (string, error) {
+	message := pc.message(m.Name, false)
+	list, err := pc.fields(message, m.Name, "r", m.Results, false)
+	if err != nil {
+		return "", err
+	}
+	if pc.pbs.ResponseHasErr(m.Name) && !grpcStatusErrors() {
+		for _, v := range m.Results {
+			if isErrorResult(v) {
+				pname := utils.ToUpperFirstCamelCase(v.Name)
+				list += fmt.Sprintf("%s:str2err(r.%s),", pname, pc.fieldName(message, pname))
+			}
+		}
+	}
+	if list == "" {
+		return fmt.Sprintf(`return %sendpoint.%sRes{}, nil`, pc.name, m.Name), nil
+	}
+	return fmt.Sprintf(`r := grpcReply.(*%s)
+		res := %sendpoint.%sRes{%s}
+		return res, nil`, pc.pbs.ResponseType(m.Name), pc.name, m.Name, list), nil
+}
+
+// grpcStreamHeader is the header the server sends once the call of a method streaming its
+// results succeeded, without it the client receives the status of the call first.
+const grpcStreamHeader = "gk-stream"
+
+// grpcStream returns the server handler and the client block of a method streaming through
+// channels. The streams bypass the grpctransport handlers, they call the endpoints of the set
+// so the endpoint middlewares still apply, the auth and tracing helpers move the metadata like
+// the grpctransport options, and the ctx of the call cancels them. A streamed
+// parameter is sent after a first message carrying the other parameters. A broken parameter
+// stream fails the call on the server, the client returns the error of the call before its
+// results are streamed and logs the errors breaking them afterwards.
+func grpcStream(pc *pbConverter, m parser.Method) (server parser.Method, client string, err error) {
+	param, result, err := chanParams(m)
+	if err != nil {
+		return
+	}
+	pc.imports["stream"] = true
+	var (
+		ep      = pc.name + "endpoint"
+		lower   = utils.ToLowerFirstCamelCase(m.Name)
+		reqMsg  = pc.message(m.Name, true)
+		resMsg  = pc.message(m.Name, false)
+		reqType = pc.pbs.RequestType(m.Name)
+		resType = pc.pbs.ResponseType(m.Name)
+	)
+	fromReq, err := pc.fields(reqMsg, m.Name, "r", m.Parameters, false)
+	if err != nil {
+		return
+	}
+	toReq, err := pc.fields(reqMsg, m.Name, "r", m.Parameters, true)
+	if err != nil {
+		return
+	}
+	reqOneofs, err := pc.setOneofs(reqMsg, "out", "r", m.Parameters)
+	if err != nil {
+		return
+	}
+	toRes, err := pc.fields(resMsg, m.Name, "res", m.Results, true)
+	if err != nil {
+		return
+	}
+	resOneofs, err := pc.setOneofs(resMsg, "out", "res", m.Results)
+	if err != nil {
+		return
+	}
+	fromRes, err := pc.fields(resMsg, m.Name, "res", m.Results, false)
+	if err != nil {
+		return
+	}
+	errCheck := ""
+	for _, v := range m.Results {
+		if isErrorResult(v) {
+			errCheck = fmt.Sprintf(`
+			if res.%s != nil {
+				return err2status(res.%s)
+			}`, utils.ToUpperFirstCamelCase(v.Name), utils.ToUpperFirstCamelCase(v.Name))
+		}
+	}
+
+	// the parameter stream is received on the server and sent by the client
+	var recvParam, sendParam string
+	if param != nil {
+		elem, _ := chanElem(param.Type)
+		t, err := pc.mapType(elem)
+		if err != nil {
+			return server, "", fmt.Errorf("`%s %s` of method `%s`: %s", param.Name, param.Type, m.Name, err)
+		}
+		name := utils.ToUpperFirstCamelCase(param.Name)
+		pbName := pc.fieldName(reqMsg, name)
+		fromReq += fmt.Sprintf("%s: elems,", name)
+		recvParam = fmt.Sprintf(`
+			recvErr := make(chan error, 1)
+			elems := make(chan %s)
+			go func() {
+				defer close(elems)
+				for {
+					msg, err := stream.Recv()
+					if err == io.EOF {
+						return
+					}
+					if err != nil {
+						// a broken stream is not the end of the parameters, the call is canceled
+						recvErr <- err
+						cancel()
+						return
+					}
+					select {
+					case elems <- %s:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()`, pc.st.Qualify(elem), t.From("msg."+pbName))
+		sendParam = fmt.Sprintf(`
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case v, ok := <-r.%s:
+						if !ok {
+							return
+						}
+						if err := stream.Send(&%s{%s: %s}); err != nil {
+							return
+						}
+					}
+				}`, name, reqType, pbName, t.To("v"))
+	}
+
+	// the result stream is sent by the server and received by the client
+	var sendResult, recvResult string
+	if result != nil {
+		elem, _ := chanElem(result.Type)
+		t, err := pc.mapType(elem)
+		if err != nil {
+			return server, "", fmt.Errorf("`%s %s` of method `%s`: %s", result.Name, result.Type, m.Name, err)
+		}
+		name := utils.ToUpperFirstCamelCase(result.Name)
+		pbName := pc.fieldName(resMsg, name)
+		sendResult = fmt.Sprintf(`
+			if err := stream.SendHeader(metadata.Pairs(%q, "true")); err != nil {
+				return err
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case v, ok := <-res.%s:
+					if !ok {
+						return nil
+					}
+					if err := stream.Send(&%s{%s: %s}); err != nil {
+						return err
+					}
+				}
+			}`, grpcStreamHeader, name, resType, pbName, t.To("v"))
+		recvResult = fmt.Sprintf(`
+				header, err := stream.Header()
+				if err != nil {
+					return nil, err
+				}
+				var first *%s
+				if len(header.Get(%q)) == 0 {
+					// without the header the call failed, its status is the error of Recv
+					if first, err = stream.Recv(); err != nil && err != io.EOF {
+						return nil, err
+					}
+				}
+				elems := make(chan %s)
+				go func() {
+					defer close(elems)
+					msg := first
+					for {
+						if msg == nil {
+							var err error
+							if msg, err = stream.Recv(); err != nil {
+								if err != io.EOF {
+									logger.Log("method", %q, "err", err)
+								}
+								return
+							}
+						}
+						select {
+						case elems <- %s:
+						case <-ctx.Done():
+							return
+						}
+						msg = nil
+					}
+				}()
+				return %s.%sRes{%s: elems}, nil`, resType, grpcStreamHeader, pc.st.Qualify(elem), m.Name, t.From("msg."+pbName), ep, m.Name, name)
+	}
+
+	call := fmt.Sprintf(`
+			response, err := s.%s(ctx, %s.%sReq{%s})
+			if err != nil {
+				return err2status(err)
+			}
+			res := response.(%s.%sRes)%s`, lower, ep, m.Name, fromReq, ep, m.Name, errCheck)
+	clientHead := fmt.Sprintf(`
+		{
+			client := %s(conn)
+			ep := func(ctx context.Context, request interface{}) (interface{}, error) {`, pc.pbs.ClientConstructor())
+	if param != nil || toReq != "" || reqOneofs != "" {
+		clientHead += fmt.Sprintf(`
+				r := request.(%s.%sReq)`, ep, m.Name)
+	}
+	clientHead += `
+				md := metadata.MD{}
+				ctx = tracingGRPCStreamClient(authContextToGRPC(ctx, &md), &md)
+				ctx = metadata.NewOutgoingContext(ctx, md)`
+	serverHead := fmt.Sprintf(`md, _ := metadata.FromIncomingContext(stream.Context())
+			ctx, finish := tracingGRPCStreamServer(s.tracer, authGRPCToContext(stream.Context(), md), md, %q)
+			defer finish()`, "/"+pc.pbs.FullName()+"/"+m.Name)
+	clientTail := fmt.Sprintf(`
+			}
+			set.%sEndpoint = %sTraceMiddleware(tracer, "%s")(decodeGRPCStatus(ep))
+		}`, m.Name, pc.name+"endpoint.", lower)
+	switch {
+	case param == nil:
+		server = parser.NewMethod(
+			m.Name,
+			parser.NewNameType("s", "*grpcServer"),
+			serverHead+call+sendResult,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("r", "*"+reqType),
+				parser.NewNameType("stream", pc.pbs.StreamType(m.Name, "Server")),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		)
+		client = clientHead + fmt.Sprintf(`
+				stream, err := client.%s(ctx, %s)
+				if err != nil {
+					return nil, err
+				}`, m.Name, pbMessage(reqType, toReq, reqOneofs)) + recvResult + clientTail
+	default:
+		first := "r"
+		if toReq == "" && reqOneofs == "" {
+			// only the stream is sent, the first message is empty
+			first = "_"
+		}
+		body := serverHead + fmt.Sprintf(`
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			%s, err := stream.Recv()
+			if err != nil {
+				return err
+			}`, first) + recvParam + `
+			err = func() error {` + call
+		if result == nil {
+			body += fmt.Sprintf(`
+			return stream.SendAndClose(%s)`, pbMessage(resType, toRes, resOneofs))
+		} else {
+			body += sendResult
+		}
+		body += `
+			}()
+			select {
+			case rerr := <-recvErr:
+				return rerr
+			default:
+				return err
+			}`
+		server = parser.NewMethod(
+			m.Name,
+			parser.NewNameType("s", "*grpcServer"),
+			body,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("stream", pc.pbs.StreamType(m.Name, "Server")),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		)
+		client = clientHead + fmt.Sprintf(`
+				stream, err := client.%s(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if err := stream.Send(%s); err != nil {
+					return nil, err
+				}`, m.Name, pbMessage(reqType, toReq, reqOneofs))
+		if result == nil {
+			client += fmt.Sprintf(`
+				sent := make(chan struct{})
+				go func() {
+					defer close(sent)%s
+				}()
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-sent:
+				}
+				res, err := stream.CloseAndRecv()
+				if err != nil {
+					return nil, err
+				}
+				return %s.%sRes{%s}, nil`, sendParam, ep, m.Name, fromRes)
+		} else {
+			client += fmt.Sprintf(`
+				go func() {
+					defer stream.CloseSend()%s
+				}()`, sendParam) + recvResult
+		}
+		client += clientTail
+	}
+	return server, client, nil
+}
+
+// grpcStreamTracer gives the stream handlers of the server the tracer of its constructor, once.
+func grpcStreamTracer(server *parser.Struct, constructor *parser.Method, name string) {
+	for _, v := range server.Vars {
+		if v.Name == "tracer" {
+			return
+		}
+	}
+	server.Vars = append(server.Vars, parser.NewNameType("tracer", name+"endpoint.Tracer"))
+	constructor.Body += "\ngs.tracer = tracer"
+}
+
+// pbMessage is the expression creating the message goType from the field list, the oneofs set
+// the remaining members of `out` once it is created.
+func pbMessage(goType, list, oneofs string) string {
+	if oneofs == "" {
+		return "&" + goType + "{" + list + "}"
+	}
+	return fmt.Sprintf(`func() *%s {
+		out := &%s{%s}%s
+		return out
+	}()`, goType, goType, list, oneofs)
+}
+
+// pbGoScalarTypes are the go types protoc-gen-go generates for the protobuf scalars.
+var pbGoScalarTypes = map[string]string{
+	"string": "string",
+	"bool":   "bool",
+	"bytes":  "[]byte",
+	"int32":  "int32",
+	"int64":  "int64",
+	"uint32": "uint32",
+	"uint64": "uint64",
+	"float":  "float32",
+	"double": "float64",
+}
+
+// pbWellKnownImports are the packages of the well known types the conversions use.
+var pbWellKnownImports = map[string]string{
+	"timestamppb": "\"google.golang.org/protobuf/types/known/timestamppb\"",
+	"durationpb":  "\"google.golang.org/protobuf/types/known/durationpb\"",
+	"wrapperspb":  "\"google.golang.org/protobuf/types/known/wrapperspb\"",
+}
+
+// pbType is the go type of a protobuf field and the expressions converting a value of the
+// service type to it and back.
+type pbType struct {
+	Go   string
+	To   func(string) string
+	From func(string) string
+}
+
+func (t *pbType) identity() bool {
+	return t.To("v") == "v" && t.From("v") == "v"
+}
+
+func pbIdentity(expr string) string {
+	return expr
+}
+
+// pbConverter writes the conversions between the types of the service and the compiled
+// protobuf, the structures of the service get a pair of toPB<Type>/fromPB<Type> helpers
+// the first time they are used.
+type pbConverter struct {
+	name    string
+	st      *ServiceTypes
+	pbs     *PBService
+	imports map[string]bool
+	helpers []parser.Method
+	done    map[string]bool
+}
+
+func newPBConverter(name string, st *ServiceTypes, pbs *PBService) *pbConverter {
+	return &pbConverter{name: name, st: st, pbs: pbs, imports: map[string]bool{}, done: map[string]bool{}}
+}
+
+// Imports are the packages of the well known types and the streams used by the conversions
+// so far.
+func (pc *pbConverter) Imports() (list []parser.NamedTypeValue) {
+	if pc.imports["stream"] {
+		list = append(list,
+			parser.NewNameType("", "\"io\""),
+			parser.NewNameType("", "\"google.golang.org/grpc/metadata\""),
+		)
+	}
+	if pc.done["time.Time"] || pc.done["time.Duration"] {
+		list = append(list, parser.NewNameType("", "\"time\""))
+	}
+	for _, k := range []string{"timestamppb", "durationpb", "wrapperspb"} {
+		if pc.imports[k] {
+			list = append(list, parser.NewNameType("", pbWellKnownImports[k]))
+		}
+	}
+	return list
+}
+
+// message is the name of the request or the response message of the method in the proto.
+func (pc *pbConverter) message(method string, request bool) string {
+	if rpc := pc.pbs.rpc(method); rpc != nil && pc.pbs.Proto != nil {
+		if request {
+			return pbMessageName(pc.pbs.Proto, rpc.RequestType)
+		}
+		return pbMessageName(pc.pbs.Proto, rpc.ReturnsType)
+	}
+	if request {
+		return utils.ToUpperFirstCamelCase(method) + "Req"
+	}
+	return utils.ToUpperFirstCamelCase(method) + "Res"
+}
+
+// fieldName is the go name protoc-gen-go gives to the field of the message matching the go
+// name of a parameter or a structure field.
+func (pc *pbConverter) fieldName(message, name string) string {
+	if msg := pc.protoMessage(message); msg != nil {
+		if f := msg.MatchField(name); f != nil {
+			return pbGoName(f.Name)
+		}
+		if _, f := msg.MatchOneofField(name); f != nil {
+			return pbGoName(f.Name)
+		}
+	}
+	return pbGoName(name)
+}
+
+// oneofField returns the oneof of the message holding the field matching the go name, if any.
+func (pc *pbConverter) oneofField(message, name string) (*parser.ProtoOneof, *parser.ProtoField) {
+	if msg := pc.protoMessage(message); msg != nil {
+		return msg.MatchOneofField(name)
+	}
+	return nil, nil
+}
+
+// getter reads the field of the message from src, the members of a oneof are read with their
+// getter which gives the zero value when another member is set.
+func (pc *pbConverter) getter(message, src, name string) string {
+	pbName := pc.fieldName(message, name)
+	if o, _ := pc.oneofField(message, name); o != nil {
+		return src + ".Get" + pbName + "()"
+	}
+	return src + "." + pbName
+}
+
+// protoMessage finds the message of the proto by the go name protoc-gen-go gives it, the one
+// protoGoName derives from its full name.
+func (pc *pbConverter) protoMessage(goName string) *parser.ProtoMessage {
+	if pc.pbs.Proto == nil {
+		return nil
+	}
+	return findProtoMessage(pc.pbs.Proto.Messages, "", goName)
+}
+
+func findProtoMessage(list []parser.ProtoMessage, scope, goName string) *parser.ProtoMessage {
+	for k, v := range list {
+		if protoGoName(scope+v.Name) == goName {
+			return &list[k]
+		}
+		if msg := findProtoMessage(v.Messages, scope+v.Name+".", goName); msg != nil {
+			return msg
+		}
+	}
+	return nil
+}
+
+// protoEnum tells if the go name is the one protoc-gen-go gives to an enum of the proto.
+func (pc *pbConverter) protoEnum(goName string) bool {
+	if pc.pbs.Proto == nil {
+		return false
+	}
+	for _, v := range pc.pbs.Proto.Enums {
+		if v.Name == goName {
+			return true
+		}
+	}
+	return findProtoEnum(pc.pbs.Proto.Messages, "", goName)
+}
+
+func findProtoEnum(list []parser.ProtoMessage, scope, goName string) bool {
+	for _, v := range list {
+		for _, e := range v.Enums {
+			if protoGoName(scope+v.Name+"."+e.Name) == goName {
+				return true
+			}
+		}
+		if findProtoEnum(v.Messages, scope+v.Name+".", goName) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageGoName is the go name of the message of a service structure, the proto one when the
+// proto declares it.
+func (pc *pbConverter) messageGoName(name string) string {
+	if pc.protoMessage(name) != nil {
+		return name
+	}
+	return pbGoName(name)
+}
+
+// isSet is the condition telling the value of the service type is not its zero value, the
+// first member of a oneof that is set is the one sent.
+func (pc *pbConverter) isSet(goType, e string) string {
+	zero := pc.st.Zero(pc.st.Qualify(goType))
+	switch {
+	case goType == "time.Time":
+		return "!" + e + ".IsZero()"
+	case strings.HasSuffix(zero, "{}"):
+		return fmt.Sprintf("%s != (%s)", e, zero)
+	}
+	return e + " != " + zero
+}
+
+// setOneofs returns the statements setting the oneofs of the message dst from the fields of
+// src, the members are left out of the message literal as their wrapper types are unexported.
+func (pc *pbConverter) setOneofs(message, dst, src string, list []parser.NamedTypeValue) (string, error) {
+	msg := pc.protoMessage(message)
+	if msg == nil || len(msg.Oneofs) == 0 {
+		return "", nil
+	}
+	var out string
+	for _, o := range msg.Oneofs {
+		var cases []string
+		for _, v := range list {
+			name := utils.ToUpperFirstCamelCase(v.Name)
+			if oneof, _ := msg.MatchOneofField(name); oneof == nil || oneof.Name != o.Name {
+				continue
+			}
+			t, err := pc.mapType(v.Type)
+			if err != nil {
+				return "", fmt.Errorf("`%s %s` of `%s`: %s", v.Name, v.Type, message, err)
+			}
+			pbName := pc.fieldName(message, name)
+			cases = append(cases, fmt.Sprintf(`if %s {
+				%s.%s = &%s.%s_%s{%s: %s}
+			}`, pc.isSet(v.Type, src+"."+name), dst, pbGoName(o.Name), pc.pbs.Alias, message, pbName, pbName, t.To(src+"."+name)))
+		}
+		if len(cases) > 0 {
+			out += "\n" + strings.Join(cases, " else ")
+		}
+	}
+	return out, nil
+}
+
+// pbGoName is the CamelCase name protoc-gen-go generates for a protobuf name.
+func pbGoName(name string) string {
+	out := ""
+	for i, part := range strings.Split(name, "_") {
+		if part == "" {
+			if i == 0 {
+				out += "X"
+			}
+			continue
+		}
+		out += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return out
+}
+
+func (pc *pbConverter) mapType(goType string) (*pbType, error) {
+	qualified := pc.st.Qualify(goType)
+	switch goType {
+	case "time.Time", "time.Duration":
+		pkg, typ, to, from := "timestamppb", "Timestamp", "timestamppb.New", "fromPBTime"
+		if goType == "time.Duration" {
+			pkg, typ, to, from = "durationpb", "Duration", "durationpb.New", "fromPBDuration"
+		}
+		pc.imports[pkg] = true
+		pc.declareWellKnown(goType)
+		return &pbType{
+			Go:   "*" + pkg + "." + typ,
+			To:   func(e string) string { return to + "(" + e + ")" },
+			From: func(e string) string { return from + "(" + e + ")" },
+		}, nil
+	}
+	if s, ok := pbScalarTypes[goType]; ok {
+		t := &pbType{Go: pbGoScalarTypes[s], To: pbIdentity, From: pbIdentity}
+		if t.Go != goType {
+			t.To = func(e string) string { return t.Go + "(" + e + ")" }
+			t.From = func(e string) string { return goType + "(" + e + ")" }
+		}
+		return t, nil
+	}
+	if underlying, ok := pc.st.Types[goType]; ok {
+		if pc.protoEnum(goType) {
+			t := &pbType{Go: pc.pbs.Alias + "." + goType}
+			t.To = func(e string) string { return t.Go + "(" + e + ")" }
+			t.From = func(e string) string { return qualified + "(" + e + ")" }
+			return t, nil
+		}
+		if underlying == "struct" {
+			if err := pc.declareStruct(goType); err != nil {
+				return nil, err
+			}
+			return &pbType{
+				Go:   "*" + pc.pbs.Alias + "." + pc.messageGoName(goType),
+				To:   func(e string) string { return "toPB" + utils.ToUpperFirst(goType) + "(" + e + ")" },
+				From: func(e string) string { return "fromPB" + utils.ToUpperFirst(goType) + "(" + e + ")" },
+			}, nil
+		}
+		s, ok := pbScalarTypes[underlying]
+		if !ok {
+			return nil, fmt.Errorf("the type `%s` (%s) can not be mapped to protobuf", goType, underlying)
+		}
+		t := &pbType{Go: pbGoScalarTypes[s]}
+		t.To = func(e string) string { return t.Go + "(" + e + ")" }
+		t.From = func(e string) string { return qualified + "(" + e + ")" }
+		return t, nil
+	}
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		elem := goType[1:]
+		q := pc.st.Qualify(elem)
+		t, err := pc.mapType(elem)
+		if err != nil {
+			return nil, err
+		}
+		p := &pbType{Go: t.Go}
+		if s, scalar := pbScalarTypes[elem]; scalar {
+			wrapper := strings.TrimPrefix(pbWrapperTypes[s], "google.protobuf.")
+			pc.imports["wrapperspb"] = true
+			p.Go = "*wrapperspb." + wrapper
+			p.To = func(e string) string {
+				return fmt.Sprintf(`func(in *%s) %s {
+					if in == nil {
+						return nil
+					}
+					return wrapperspb.%s(%s)
+				}(%s)`, q, p.Go, strings.TrimSuffix(wrapper, "Value"), t.To("*in"), e)
+			}
+			p.From = func(e string) string {
+				return fmt.Sprintf(`func(in %s) *%s {
+					if in == nil {
+						return nil
+					}
+					out := %s
+					return &out
+				}(%s)`, p.Go, q, t.From("in.Value"), e)
+			}
+			return p, nil
+		}
+		if _, isStruct := pc.st.Structs[elem]; !isStruct && elem != "time.Time" && elem != "time.Duration" {
+			return nil, fmt.Errorf("the type `%s` can not be mapped to protobuf", goType)
+		}
+		p.To = func(e string) string {
+			return fmt.Sprintf(`func(in *%s) %s {
+				if in == nil {
+					return nil
+				}
+				return %s
+			}(%s)`, q, t.Go, t.To("*in"), e)
+		}
+		p.From = func(e string) string {
+			return fmt.Sprintf(`func(in %s) *%s {
+				if in == nil {
+					return nil
+				}
+				out := %s
+				return &out
+			}(%s)`, t.Go, q, t.From("in"), e)
+		}
+		return p, nil
+	case strings.HasPrefix(goType, "[]"):
+		t, err := pc.mapType(goType[2:])
+		if err != nil {
+			return nil, err
+		}
+		l := &pbType{Go: "[]" + t.Go, To: pbIdentity, From: pbIdentity}
+		if !t.identity() {
+			l.To = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for i, v := range in {
+						out[i] = %s
+					}
+					return out
+				}(%s)`, qualified, l.Go, l.Go, t.To("v"), e)
+			}
+			l.From = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for i, v := range in {
+						out[i] = %s
+					}
+					return out
+				}(%s)`, l.Go, qualified, qualified, t.From("v"), e)
+			}
+		}
+		return l, nil
+	case strings.HasPrefix(goType, "map["):
+		key, value := splitMapType(goType)
+		k, err := pc.mapType(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := pc.mapType(value)
+		if err != nil {
+			return nil, err
+		}
+		m := &pbType{Go: "map[" + k.Go + "]" + v.Go, To: pbIdentity, From: pbIdentity}
+		if !k.identity() || !v.identity() {
+			m.To = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for k, v := range in {
+						out[%s] = %s
+					}
+					return out
+				}(%s)`, qualified, m.Go, m.Go, k.To("k"), v.To("v"), e)
+			}
+			m.From = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for k, v := range in {
+						out[%s] = %s
+					}
+					return out
+				}(%s)`, m.Go, qualified, qualified, k.From("k"), v.From("v"), e)
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("the type `%s` can not be mapped to protobuf", goType)
+}
+
+// declareWellKnown adds the fromPBTime or fromPBDuration helper, AsTime and AsDuration would
+// turn an unset field into the unix epoch instead of the zero value.
+func (pc *pbConverter) declareWellKnown(goType string) {
+	if pc.done[goType] {
+		return
+	}
+	pc.done[goType] = true
+	if goType == "time.Time" {
+		pc.helpers = append(pc.helpers, parser.NewMethodWithComment(
+			"fromPBTime",
+			"fromPBTime converts a protobuf timestamp to a time.Time, nil gives the zero time.",
+			parser.NamedTypeValue{},
+			`if in == nil {
+				return time.Time{}
+			}
+			return in.AsTime()`,
+			[]parser.NamedTypeValue{parser.NewNameType("in", "*timestamppb.Timestamp")},
+			[]parser.NamedTypeValue{parser.NewNameType("", "time.Time")},
+		))
+		return
+	}
+	pc.helpers = append(pc.helpers, parser.NewMethodWithComment(
+		"fromPBDuration",
+		"fromPBDuration converts a protobuf duration to a time.Duration, nil gives 0.",
+		parser.NamedTypeValue{},
+		`if in == nil {
+			return 0
+		}
+		return in.AsDuration()`,
+		[]parser.NamedTypeValue{parser.NewNameType("in", "*durationpb.Duration")},
+		[]parser.NamedTypeValue{parser.NewNameType("", "time.Duration")},
+	))
+}
+
+// declareStruct adds the toPB/fromPB helpers of a service structure, the helpers of the
+// structures it refers to are added as well.
+func (pc *pbConverter) declareStruct(name string) error {
+	if pc.done[name] {
+		return nil
+	}
+	pc.done[name] = true
+	toList, fromList := "", ""
+	var exported []parser.NamedTypeValue
+	for _, f := range pc.st.Structs[name] {
+		if f.Name == "" || f.Name[:1] == strings.ToLower(f.Name[:1]) {
+			continue
+		}
+		exported = append(exported, f)
+		t, err := pc.mapType(f.Type)
+		if err != nil {
+			return fmt.Errorf("field `%s` of `%s`: %s", f.Name, name, err)
+		}
+		fromList += fmt.Sprintf("\n%s: %s,", f.Name, t.From(pc.getter(name, "in", f.Name)))
+		if o, _ := pc.oneofField(name, f.Name); o == nil {
+			toList += fmt.Sprintf("\n%s: %s,", pc.fieldName(name, f.Name), t.To("in."+f.Name))
+		}
+	}
+	message := pc.messageGoName(name)
+	oneofs, err := pc.setOneofs(message, "out", "in", exported)
+	if err != nil {
+		return err
+	}
+	goName := pc.pbs.Alias + "." + message
+	toBody := fmt.Sprintf(`return &%s{%s
+		}`, goName, toList)
+	if oneofs != "" {
+		toBody = fmt.Sprintf(`out := &%s{%s
+		}%s
+		return out`, goName, toList, oneofs)
+	}
+	pc.helpers = append(pc.helpers, parser.NewMethodWithComment(
+		"toPB"+utils.ToUpperFirst(name),
+		fmt.Sprintf(`toPB%s converts a %s to its protobuf message.`, utils.ToUpperFirst(name), name),
+		parser.NamedTypeValue{},
+		toBody,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("in", pc.st.Qualify(name)),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "*"+goName),
+		},
+	), parser.NewMethodWithComment(
+		"fromPB"+utils.ToUpperFirst(name),
+		fmt.Sprintf(`fromPB%s converts a protobuf message to a %s, nil gives the zero value.`,
+			utils.ToUpperFirst(name), name),
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`if in == nil {
+			return out
+		}
+		return %s{%s
+		}`, pc.st.Qualify(name), fromList),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("in", "*"+goName),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("out", pc.st.Qualify(name)),
+		},
+	))
+	return nil
+}
+
+// fields returns the `Field: conversion,` list converting the parameters or the results of a
+// method read from src, toPB tells the direction, the errors and the streams are left to the caller
+// as are the oneofs of the message, see setOneofs.
+func (pc *pbConverter) fields(message, method, src string, list []parser.NamedTypeValue, toPB bool) (string, error) {
+	var out string
+	for _, v := range list {
+		if _, stream := chanElem(v.Type); stream || v.Type == "context.Context" || isErrorResult(v) {
+			continue
+		}
+		t, err := pc.mapType(v.Type)
+		if err != nil {
+			return "", fmt.Errorf("`%s %s` of method `%s`: %s", v.Name, v.Type, method, err)
+		}
+		name := utils.ToUpperFirstCamelCase(v.Name)
+		if !toPB {
+			out += fmt.Sprintf("%s:%s,", name, t.From(pc.getter(message, src, name)))
+		} else if o, _ := pc.oneofField(message, name); o == nil {
+			out += fmt.Sprintf("%s:%s,", pc.fieldName(message, name), t.To(src+"."+name))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+const testAccMethods = `Get(ctx context.Context, id int64) (a *Account, err error)
+	Watch(ctx context.Context, filter string) (events <-chan Event, err error)
+	Upload(ctx context.Context, name string, chunks <-chan []byte) (n int64, err error)
+	Chat(ctx context.Context, room string, in <-chan string) (out <-chan Event, err error)`
+
+const testAccTypes = `
+type Account struct {
+	Id        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type Event struct {
+	Kind string
+	At   time.Time
+}
+`
+
+// testGRPCService initiates the grpc transport of the acc service.
+func testGRPCService(t *testing.T, settings map[string]interface{}) {
+	t.Helper()
+	testProject(t, settings)
+	testService(t, "acc", testAccMethods, testAccTypes)
+	if err := fs.Get().WriteFile("acc/accpb/acc.pb.go", "package accpb\n", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewServiceInitGenerator().generateTransport("acc", nil, "grpc"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGRPCInitUpdate(t *testing.T) {
+	// the updates overwrite the files instead of prompting
+	testGRPCService(t, map[string]interface{}{"gk_force_override": true})
+	file := "acc/pkg/acctransport/grpc.go"
+	initialized := testRead(t, file)
+	assertContains(t, initialized, "func tracingGRPCStreamServer(tracer accendpoint.Tracer, ctx context.Context, md metadata.MD, method string) (context.Context, func())")
+
+	// the stream helpers parse back, the update keeps the declarations of init and a second
+	// update changes nothing
+	if err := NewGRPCUpdateGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	updated := testRead(t, file)
+	assertSameDeclarations(t, initialized, updated)
+	assertNotContains(t, updated, "`json:\"get\"`", "`json:\"tracer\"`")
+	if err := NewGRPCUpdateGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	if again := testRead(t, file); again != updated {
+		t.Errorf("the second update changed grpc.go from\n%s\nto\n%s", updated, again)
+	}
+
+	// a second init updates the existing transport
+	if err := NewServiceInitGenerator().generateTransport("acc", nil, "grpc"); err != nil {
+		t.Fatal(err)
+	}
+	if again := testRead(t, file); again != updated {
+		t.Errorf("the second init changed grpc.go from\n%s\nto\n%s", updated, again)
+	}
+}
+
+func TestGRPCConversions(t *testing.T) {
+	testProject(t, nil)
+	testService(t, "acc", `Save(ctx context.Context, a *Account, byName map[string]User) (saved []Account, err error)`, `
+type Account struct {
+	Id        int64
+	Owner     *User
+	Friends   []User
+	Meta      map[string]User
+	CreatedAt time.Time
+	Ttl       time.Duration
+	Score     *float64
+}
+
+type User struct {
+	Name string
+	Age  int
+}
+`)
+	if err := fs.Get().WriteFile("acc/accpb/acc.pb.go", "package accpb\n", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewServiceInitGenerator().generateTransport("acc", nil, "grpc"); err != nil {
+		t.Fatal(err)
+	}
+	code := testRead(t, "acc/pkg/acctransport/grpc.go")
+	assertContains(t, code,
+		// a helper pair for every structure
+		`func toPBAccount(in accservice.Account) *accpb.Account {`,
+		`func fromPBAccount(in *accpb.Account) (out accservice.Account) {
+			if in == nil {
+				return out
+			}`,
+		`func toPBUser(in accservice.User) *accpb.User {`,
+		`func fromPBUser(in *accpb.User) (out accservice.User) {`,
+		// the widened scalars are converted back
+		`Age: int64(in.Age),`,
+		`Age: int(in.Age),`,
+		// pointers, lists and maps of structures go through the helpers
+		`return toPBUser(*in)`,
+		`out := fromPBUser(in)
+			return &out`,
+		`out[i] = toPBUser(v)`,
+		`out[k] = fromPBUser(v)`,
+		// the well-known types
+		`CreatedAt: timestamppb.New(in.CreatedAt),`,
+		`CreatedAt: fromPBTime(in.CreatedAt),`,
+		`Ttl: durationpb.New(in.Ttl),`,
+		`Ttl: fromPBDuration(in.Ttl),`,
+		`return wrapperspb.Double(*in)`,
+		// the codecs convert the parameters and the results
+		`req := accendpoint.SaveReq{A: func(in *accpb.Account) *accservice.Account {`,
+		`res := &accpb.SaveRes{Saved: func(in []accservice.Account) []*accpb.Account {`,
+	)
+	for _, v := range []string{"func toPBUser(", "func fromPBUser(", "func fromPBTime("} {
+		if n := strings.Count(code, v); n != 1 {
+			t.Errorf("%s is declared %d times", v, n)
+		}
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	testGRPCService(t, nil)
+	proto, code := testRead(t, "acc/accpb/acc.proto"), testRead(t, "acc/pkg/acctransport/grpc.go")
+	// the errors are the status of the calls, with the code of the catalog
+	assertContains(t, code,
+		`s := status.New(codes.Code(accservice.ErrorCode(err)), err.Error())`,
+		`if e := accservice.ErrorFromCode(accservice.Code(s.Code()), s.Message()); e != nil {`,
+		`return nil, err2status(r.Err)`,
+		`ep = decodeGRPCStatus(ep)`,
+	)
+	assertNotContains(t, code, `func err2str(`, `func str2err(`)
+	assertNotContains(t, proto, `string Err =`)
+	assertContains(t, testRead(t, "acc/pkg/accservice/errors.go"),
+		`{Code: CodeNotFound, Err: ErrNotFound},`,
+		`func ErrorCode(err error) Code {`,
+	)
+
+	// the catalog belongs to the service once written
+	catalog := "acc/pkg/accservice/errors.go"
+	if err := fs.Get().WriteFile(catalog, "package accservice\n\n// edited\n", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewErrorCatalogGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	if got := testRead(t, catalog); got != "package accservice\n\n// edited\n" {
+		t.Errorf("the catalog was overwritten with\n%s", got)
+	}
+
+	// the string mode keeps the errors in the replies, the streams still end with a status
+	testGRPCService(t, map[string]interface{}{"grpctransport.errors": "string"})
+	proto, code = testRead(t, "acc/accpb/acc.proto"), testRead(t, "acc/pkg/acctransport/grpc.go")
+	assertContains(t, proto, `message GetRes { Account A = 1; string Err = 2; }`)
+	assertNotContains(t, proto, `message WatchRes { Event Events = 1; string Err`)
+	assertContains(t, code,
+		`Err: err2str(r.Err)`,
+		`Err: str2err(r.Err)`,
+		`return err2status(res.Err)`,
+	)
+}
@@ -38,6 +38,9 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		} else if !yes {
 			return errors.New("Could not find the compiled pb of the service")
 		}
+		if err = NewErrorCatalogGenerator().Generate(name); err != nil {
+			return err
+		}
 	}
 
 	path, err = te.ExecuteString(viper.GetString("grpctransport.path"), map[string]string{
@@ -64,6 +67,28 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 	if err != nil {
 		return err
 	}
+	migrateTracing(handler, name+"endpoint.")
+
+	pbs := LoadPBService(name)
+	if pbs.UsesEmpty() {
+		emptyImport := parser.NewNameType("", "\"github.com/golang/protobuf/ptypes/empty\"")
+		hasEmptyImport := false
+		for _, v := range handler.Imports {
+			if v.Type == emptyImport.Type {
+				hasEmptyImport = true
+				break
+			}
+		}
+		if !hasEmptyImport {
+			handler.Imports = append(handler.Imports, emptyImport)
+		}
+	}
+
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	pc := newPBConverter(name, st, pbs)
 
 	var grpcServer *parser.Struct
 	for k, v := range handler.Structs {
@@ -92,6 +117,25 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		if isExist {
 			continue
 		}
+		if isStreamMethod(v) {
+			server, client, err := grpcStream(pc, v)
+			if err != nil {
+				return err
+			}
+			grpcStreamTracer(grpcServer, &handler.Methods[0], name)
+			grpcServer.Vars = append(grpcServer.Vars, parser.NewNameType(
+				utils.ToLowerFirstCamelCase(v.Name),
+				"endpoint.Endpoint",
+			))
+			handler.Methods = append(handler.Methods, server)
+			handler.Methods[0].Body += "\n" + fmt.Sprintf(`gs.%s = endpoints.%sEndpoint`, utils.ToLowerFirstCamelCase(v.Name), v.Name)
+			handler.Methods[1].Body += "\n" + client
+			continue
+		}
+		decodeReq, encodeRes, encodeReq, decodeRes, err := grpcCodecs(pc, v)
+		if err != nil {
+			return err
+		}
 		//add member to grpcServer
 		grpcServer.Vars = append(grpcServer.Vars, parser.NewNameType(
 			utils.ToLowerFirstCamelCase(v.Name),
@@ -99,14 +143,6 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		))
 
 		// add server side request decoder
-		var decodeReqParamList string
-		for _, v := range v.Parameters {
-			if v.Type == "context.Context" {
-				continue
-			}
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			decodeReqParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"decodeGRPC"+v.Name+"Req",
 			fmt.Sprintf(
@@ -115,9 +151,7 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := grpcReq.(*%spb.%sReq)
-			req := %sendpoint.%sReq{%s}
-			return req, nil`, name, v.Name, name, v.Name, decodeReqParamList),
+			decodeReq,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("grpcReq", "interface{}"),
@@ -129,15 +163,6 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		))
 
 		// add server side response encoder
-		var encodeResParamList string
-		for _, v := range v.Results {
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			if v.Name == "Err" || v.Name == "err" || v.Type == "error" || v.Type == "Error" {
-				encodeResParamList += fmt.Sprintf("%s:err2str(r.%s),", pname, pname)
-			} else {
-				encodeResParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-			}
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"encodeGRPC"+v.Name+"Res",
 			fmt.Sprintf(
@@ -146,9 +171,7 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := response.(%sendpoint.%sRes)
-			res := &%spb.%sRes{%s}
-			return res, nil`, name, v.Name, name, v.Name, encodeResParamList),
+			encodeRes,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("response", "interface{}"),
@@ -160,25 +183,15 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		))
 
 		// add client side request encoder
-		var encodeReqParamList string
-		for _, v := range v.Parameters {
-			if v.Type == "context.Context" {
-				continue
-			}
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			encodeReqParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"encodeGRPC"+v.Name+"Req",
 			fmt.Sprintf(
-				`encodeGRPC%Req s a transport/grpc.EncodeRequestFunc that converts a
+				`encodeGRPC%sReq is a transport/grpc.EncodeRequestFunc that converts a
 				 user-domain sum request to a gRPC sum request. Primarily useful in a client.`,
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := request.(%sendpoint.%sReq)
-			req :=  &%spb.%sReq{%s}
-			return req, nil`, name, v.Name, name, v.Name, encodeReqParamList),
+			encodeReq,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("request", "interface{}"),
@@ -190,26 +203,15 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		))
 
 		// add client side response decoder
-		var decodeResParamList string
-		for _, v := range v.Results {
-			pname := utils.ToUpperFirstCamelCase(v.Name)
-			if v.Name == "Err" || v.Name == "err" || v.Type == "error" || v.Type == "Error" {
-				decodeResParamList += fmt.Sprintf("%s:str2err(r.%s),", pname, pname)
-			} else {
-				decodeResParamList += fmt.Sprintf("%s:r.%s,", pname, pname)
-			}
-		}
 		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
 			"decodeGRPC"+v.Name+"Res",
 			fmt.Sprintf(
-				`decodeGRPC%Res is a transport/grpc.DecodeResponseFunc that converts a
+				`decodeGRPC%sRes is a transport/grpc.DecodeResponseFunc that converts a
 				 gRPC sum reply to a user-domain sum response. Primarily useful in a client.`,
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`r := grpcReply.(*%spb.%sRes)
-			res := %sendpoint.%sRes{%s}
-			return res, nil`, name, utils.ToUpperFirstCamelCase(v.Name), name, v.Name, decodeResParamList),
+			decodeRes,
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("grpcReply", "interface{}"),
@@ -227,20 +229,19 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 			fmt.Sprintf(
 				`_, rp, err := s.%s.ServeGRPC(ctx, req)
 					if err != nil {
-						return nil, err
+						return nil, err2status(err)
 					}
-					rep = rp.(*%spb.%sRes)
+					rep = rp.(*%s)
 					return rep, err`,
 				utils.ToLowerFirstCamelCase(v.Name),
-				name,
-				v.Name,
+				pbs.ResponseType(v.Name),
 			),
 			[]parser.NamedTypeValue{
 				parser.NewNameType("ctx", "context.Context"),
-				parser.NewNameType("req", fmt.Sprintf("*%spb.%sReq", name, v.Name)),
+				parser.NewNameType("req", "*"+pbs.RequestType(v.Name)),
 			},
 			[]parser.NamedTypeValue{
-				parser.NewNameType("rep", fmt.Sprintf("*%spb.%sRes", name, v.Name)),
+				parser.NewNameType("rep", "*"+pbs.ResponseType(v.Name)),
 				parser.NewNameType("err", "error"),
 			},
 		))
@@ -248,8 +249,7 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 		//init grpcServer method
 		handler.Methods[0].Body += "\n" + fmt.Sprintf(`
 			{
-				//ops := append(options, grpctransport.ServerBefore(opentracing.GRPCToContext(otTracer, "%s", logger)))
-				ops := append(options, grpctransport.ServerBefore(jwt.GRPCToContext()))
+				ops := append(options, grpctransport.ServerBefore(authGRPCToContext))
 				//ops = append(ops, grpctransport.ServerBefore(header.GRPCToContext()))
 				gs.%s = grpctransport.NewServer(
 					endpoints.%sEndpoint,
@@ -258,29 +258,29 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 					ops...,
 				)
 			}
-		`, v.Name, utils.ToLowerFirstCamelCase(v.Name), v.Name, v.Name, v.Name)
+		`, utils.ToLowerFirstCamelCase(v.Name), v.Name, v.Name, v.Name)
 
 		//init grpc client method
 		lowerName := utils.ToLowerFirstCamelCase(v.Name)
 		upperName := utils.ToUpperFirstCamelCase(v.Name)
 		handler.Methods[1].Body += "\n" + fmt.Sprintf(`
 			{
-				//ops := append(options, grpctransport.ClientBefore(opentracing.ContextToGRPC(otTracer, logger)))
-				ops := append(options, grpctransport.ClientBefore(jwt.ContextToGRPC()))
+				ops := append(options, grpctransport.ClientBefore(authContextToGRPC))
 				//ops = append(ops, grpctransport.ClientBefore(header.ContextToGRPC()))
 				ep := grpctransport.NewClient(
 					conn,
-					"%spb.%s",
+					"%s",
 					"%s",
 					encodeGRPC%sReq,
 					decodeGRPC%sRes,
-					%spb.%sRes{},
+					%s{},
 					ops...,
 				).Endpoint()
-				ep = opentracing.TraceClient(otTracer, "%s")(ep)
+				ep = decodeGRPCStatus(ep)
+				%s
 				set.%sEndpoint = ep
 			}
-		`, name, utils.ToUpperFirstCamelCase(name), upperName, upperName, upperName, name, upperName, lowerName, upperName)
+		`, pbs.FullName(), upperName, upperName, upperName, pbs.ResponseType(v.Name), tracingClientEndpoint(name, lowerName), upperName)
 	}
 	//close NewGRPCServer
 	handler.Methods[0].Body += `
@@ -288,17 +288,61 @@ func (sg *GRPCUpdateGenerator) Generate(name string) (err error) {
 	//close NewGRPCClient
 	handler.Methods[1].Body += `
 	return set`
+	for _, v := range append(grpcErrorHelpers(pc), pc.helpers...) {
+		if methodIndex(handler, v.Name) == -1 {
+			handler.Methods = append(handler.Methods, v)
+		}
+	}
+	for _, v := range append(grpcErrorImports(), pc.Imports()...) {
+		hasImport := false
+		for _, vv := range handler.Imports {
+			if vv.Type == v.Type {
+				hasImport = true
+				break
+			}
+		}
+		if !hasImport {
+			handler.Imports = append(handler.Imports, v)
+		}
+	}
+
+	// the rate limits of the endpoints are in resilience.go
+	var kept []parser.NamedTypeValue
+	for _, v := range handler.Imports {
+		if v.Type != `"github.com/juju/ratelimit"` && v.Type != `"github.com/go-kit/kit/ratelimit"` {
+			kept = append(kept, v)
+		}
+	}
+	handler.Imports = kept
+
+	auth, err := authTransportHelpers(name, iface, "grpc")
+	if err != nil {
+		return err
+	}
+	authImports, err := authTransportImports(iface, "grpc")
+	if err != nil {
+		return err
+	}
+	authReplaceHelpers(handler, auth, authImports, map[string]string{
+		"grpctransport.ServerBefore(jwt.GRPCToContext())": "grpctransport.ServerBefore(authGRPCToContext)",
+		"grpctransport.ClientBefore(jwt.ContextToGRPC())": "grpctransport.ClientBefore(authContextToGRPC)",
+	})
+	tracing, err := tracingTransportHelpers(name, "grpc")
+	if err != nil {
+		return err
+	}
+	tracingImports, err := tracingTransportImports("grpc")
+	if err != nil {
+		return err
+	}
+	authReplaceHelpers(handler, tracing, tracingImports, nil)
 
 	err = defaultFs.WriteFile(sfile, handler.String(), false)
 	if err != nil {
 		return err
 	}
 
-	logrus.Warn("---------------------------------------------------------------------------------------")
-	logrus.Warn("The generator does not implement the Decoding and Encoding of the grpc request/response")
-	logrus.Warn("Before using the service don't forget to implement those.")
-	logrus.Warn("---------------------------------------------------------------------------------------")
-	return nil
+	return generateEndpointsTracing(name)
 }
 func (sg *GRPCUpdateGenerator) UpdateEndpointClient(name string) (err error) {
 
@@ -353,6 +397,7 @@ func (sg *GRPCUpdateGenerator) UpdateEndpointClient(name string) (err error) {
 		logrus.Error(err.Error())
 		return err
 	}
+	migrateTracing(handler, name+"endpoint.")
 
 	handler.Methods[0].Body = strings.ReplaceAll(handler.Methods[0].Body, "return", "")
 
@@ -363,21 +408,24 @@ func (sg *GRPCUpdateGenerator) UpdateEndpointClient(name string) (err error) {
 		}
 		handler.Methods[0].Body += "\n" + fmt.Sprintf(`
 			{
-				factory := factory(%sendpoint.Make%sEndpoint, zipkinTracer, logger)
+				factory := factory(%sendpoint.Make%sEndpoint, tracer, logger)
 				endpointer := sd.NewEndpointer(instancer, factory, logger)
 				balancer := lb.NewRoundRobin(endpointer)
 				retry := lb.Retry(retryMax, retryTimeout, balancer)
-				set.%sEndpoint = retry
+				%s
 			}
-		`, name, utils.ToUpperFirstCamelCase(v.Name), utils.ToUpperFirstCamelCase(v.Name))
+		`, name, utils.ToUpperFirstCamelCase(v.Name), clientEndpoint(name, v))
 	}
 
-	handler.Methods[0].Body += `
+	handler.Methods[0].Body = wrapClientEndpoints(handler.Methods[0].Body, name, iface) + `
 	return `
 	err = defaultFs.WriteFile(sfile, handler.String(), false)
 	if err != nil {
 		logrus.Error(err.Error())
 		return err
 	}
-	return
+	if err = generateEndpointsResilience(name, iface); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
 }
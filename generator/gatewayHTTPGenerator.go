@@ -0,0 +1,552 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+)
+
+// GatewayHTTPGenerator writes the HTTP/JSON gateway of a gRPC service, the routes of the proto
+// are served with the gRPC client of the service.
+type GatewayHTTPGenerator struct {
+}
+
+func NewGatewayHTTPGenerator() *GatewayHTTPGenerator {
+	return &GatewayHTTPGenerator{}
+}
+
+func (sg *GatewayHTTPGenerator) Generate(name string) error {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+
+	if yes, err := IsProtoCompiled(name); err != nil {
+		return err
+	} else if !yes {
+		return errors.New("Could not find the compiled pb of the service")
+	}
+	pbs := LoadPBService(name)
+	if pbs.Service == nil {
+		return fmt.Errorf("Could not find the service of `%s` in its proto", name)
+	}
+	pbPath, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	pbImport, err := ProjectImport(pbPath)
+	if err != nil {
+		return err
+	}
+	path, err := te.ExecuteString(viper.GetString("grpctransport.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	fname, err := te.ExecuteString(viper.GetString("grpctransport.gateway_file_name"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+
+	handler := parser.NewFile()
+	handler.Package = fmt.Sprintf("%stransport", name)
+	handler.Imports = []parser.NamedTypeValue{
+		parser.NewNameType("", "\"context\""),
+		parser.NewNameType("", "\"encoding/base64\""),
+		parser.NewNameType("", "\"encoding/json\""),
+		parser.NewNameType("", "\"io\""),
+		parser.NewNameType("", "\"io/ioutil\""),
+		parser.NewNameType("", "\"net/http\""),
+		parser.NewNameType("", "\"strconv\""),
+		parser.NewNameType("", "\"strings\"\n"),
+		parser.NewNameType("", "\"github.com/gorilla/mux\""),
+		parser.NewNameType("", "\"google.golang.org/grpc/codes\""),
+		parser.NewNameType("", "\"google.golang.org/grpc/metadata\""),
+		parser.NewNameType("", "\"google.golang.org/grpc/status\""),
+		parser.NewNameType("", "\"google.golang.org/protobuf/encoding/protojson\""),
+		parser.NewNameType("", "\"google.golang.org/protobuf/proto\""),
+		parser.NewNameType("", "\"google.golang.org/protobuf/reflect/protoreflect\"\n"),
+		parser.NewNameType("", "\"github.com/go-kit/kit/log\"\n"),
+	}
+	handler.Imports = append(handler.Imports, pbs.Imports(pbImport)...)
+	handler.Vars = append(handler.Vars, parser.NewNameTypeValue(
+		"gatewayMarshaler", "", "protojson.MarshalOptions{EmitUnpopulated: true}",
+	))
+	handler.Structs = append(handler.Structs, parser.NewStruct("httpGateway", []parser.NamedTypeValue{
+		parser.NewNameType("client", pbs.ClientType()),
+		parser.NewNameType("logger", "log.Logger"),
+	}))
+
+	routes := []string{}
+	for _, rpc := range pbs.Service.RPCs {
+		if rpc.StreamsRequest {
+			logrus.Warnf("The rpc '%s' streams its request and is not served by the gateway", rpc.Name)
+			continue
+		}
+		rules := rpc.HTTPRules
+		if len(rules) == 0 {
+			rules = []parser.ProtoHTTPRule{{Method: "POST", Path: "/" + pbs.FullName() + "/" + rpc.Name, Body: "*"}}
+			logrus.Infof("The rpc '%s' has no http rule, it is served on %s %s", rpc.Name, rules[0].Method, rules[0].Path)
+		}
+		for _, v := range rules {
+			if v.ResponseBody != "" {
+				logrus.Warnf("The response_body of '%s' is not supported, the whole response is written", rpc.Name)
+			}
+			routes = append(routes, fmt.Sprintf(`r.Methods(%q).Path(%q).Handler(g.%s(%q))`,
+				v.Method, muxPath(v.Path), pbGoName(rpc.Name), v.Body))
+		}
+		handler.Methods = append(handler.Methods, gatewayHandler(pbs, rpc))
+	}
+	handler.Methods = append([]parser.Method{parser.NewMethodWithComment(
+		"NewHTTPGateway",
+		fmt.Sprintf(`NewHTTPGateway serves the routes of the %s proto as JSON, the requests are sent
+		with the gRPC client.`, pbs.Service.Name),
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`
+		g := &httpGateway{client: client, logger: logger}
+		r := mux.NewRouter()
+		%s
+		return r`, strings.Join(routes, "\n")),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("client", pbs.ClientType()),
+			parser.NewNameType("logger", "log.Logger"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "http.Handler"),
+		},
+	)}, handler.Methods...)
+	handler.Methods = append(handler.Methods, gatewayHelpers()...)
+
+	if err = defaultFs.MkdirAll(path); err != nil {
+		return err
+	}
+	sfile := path + defaultFs.FilePathSeparator() + fname
+	logrus.Infof("Generating the http gateway of service %s: %s", name, sfile)
+	if err = defaultFs.WriteFile(sfile, handler.String(), true); err != nil {
+		return err
+	}
+	logrus.Infof("Mount it in the gateway main with `%stransport.NewHTTPGateway(%s(conn), logger)`", name, pbs.ClientConstructor())
+	return nil
+}
+
+// gatewayHandler serves an rpc, the body names the request field the JSON body fills.
+func gatewayHandler(pbs *PBService, rpc parser.ProtoRPC) parser.Method {
+	call := `
+		res, err := g.client.%s(gatewayContext(r), req)
+		if err != nil {
+			g.writeError(w, err)
+			return
+		}
+		g.write(w, res)`
+	if rpc.StreamsReturns {
+		call = `
+		stream, err := g.client.%s(gatewayContext(r), req)
+		if err != nil {
+			g.writeError(w, err)
+			return
+		}
+		g.stream(w, func() (proto.Message, error) {
+			return stream.Recv()
+		})`
+	}
+	return parser.NewMethod(
+		pbGoName(rpc.Name),
+		parser.NewNameType("g", "*httpGateway"),
+		fmt.Sprintf(`
+		return func(w http.ResponseWriter, r *http.Request) {
+			req := &%s{}
+			if err := decodeGatewayRequest(r, req, body); err != nil {
+				g.writeError(w, err)
+				return
+			}`+call+`
+		}`, pbs.RequestType(rpc.Name), pbGoName(rpc.Name)),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("body", "string"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "http.HandlerFunc"),
+		},
+	)
+}
+
+// gatewayHelpers decode the requests, write the responses and map the gRPC status codes to the
+// HTTP statuses.
+func gatewayHelpers() []parser.Method {
+	return []parser.Method{
+		parser.NewMethodWithComment(
+			"write",
+			`write writes the response as JSON.`,
+			parser.NewNameType("g", "*httpGateway"),
+			`
+			b, err := gatewayMarshaler.Marshal(res)
+			if err != nil {
+				g.writeError(w, status.Error(codes.Internal, err.Error()))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(b)`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("w", "http.ResponseWriter"),
+				parser.NewNameType("res", "proto.Message"),
+			},
+			[]parser.NamedTypeValue{},
+		),
+		parser.NewMethodWithComment(
+			"stream",
+			`stream writes the messages of a server stream as lines of JSON, an error after the first
+			message ends the stream with an error line.`,
+			parser.NewNameType("g", "*httpGateway"),
+			`
+			flusher, _ := w.(http.Flusher)
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			started := false
+			for {
+				res, err := recv()
+				if err == io.EOF {
+					return
+				}
+				var b []byte
+				if err == nil {
+					b, err = gatewayMarshaler.Marshal(res)
+				}
+				if err != nil {
+					if !started {
+						g.writeError(w, err)
+						return
+					}
+					_ = g.logger.Log("err", err)
+					b, _ = json.Marshal(map[string]interface{}{"error": gatewayError(status.Convert(err))})
+					_, _ = w.Write(append(b, '\n'))
+					return
+				}
+				started = true
+				_, _ = w.Write(append(b, '\n'))
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("w", "http.ResponseWriter"),
+				parser.NewNameType("recv", "func() (proto.Message, error)"),
+			},
+			[]parser.NamedTypeValue{},
+		),
+		parser.NewMethodWithComment(
+			"writeError",
+			`writeError writes the status of a failed call with the HTTP status matching its code.`,
+			parser.NewNameType("g", "*httpGateway"),
+			`
+			s := status.Convert(err)
+			_ = g.logger.Log("code", s.Code(), "err", s.Message())
+			b, _ := json.Marshal(gatewayError(s))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(HTTPStatusFromCode(s.Code()))
+			_, _ = w.Write(b)`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("w", "http.ResponseWriter"),
+				parser.NewNameType("err", "error"),
+			},
+			[]parser.NamedTypeValue{},
+		),
+		parser.NewMethod(
+			"gatewayError",
+			parser.NamedTypeValue{},
+			`
+			return map[string]interface{}{
+				"code":    int(s.Code()),
+				"status":  s.Code().String(),
+				"message": s.Message(),
+			}`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("s", "*status.Status"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "map[string]interface{}"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"HTTPStatusFromCode",
+			`HTTPStatusFromCode is the HTTP status of a gRPC status code.`,
+			parser.NamedTypeValue{},
+			`
+			switch code {
+			case codes.OK:
+				return http.StatusOK
+			case codes.Canceled:
+				return 499
+			case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+				return http.StatusBadRequest
+			case codes.DeadlineExceeded:
+				return http.StatusGatewayTimeout
+			case codes.NotFound:
+				return http.StatusNotFound
+			case codes.AlreadyExists, codes.Aborted:
+				return http.StatusConflict
+			case codes.PermissionDenied:
+				return http.StatusForbidden
+			case codes.Unauthenticated:
+				return http.StatusUnauthorized
+			case codes.ResourceExhausted:
+				return http.StatusTooManyRequests
+			case codes.Unimplemented:
+				return http.StatusNotImplemented
+			case codes.Unavailable:
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusInternalServerError`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("code", "codes.Code"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "int"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"gatewayContext",
+			`gatewayContext forwards the authorization, the API key and the request id of the HTTP
+			request to the gRPC call.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`
+			ctx := r.Context()
+			for _, k := range []string{"Authorization", %q, "X-Request-Id"} {
+				if v := r.Header.Get(k); v != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(k), v)
+				}
+			}
+			return ctx`, authAPIKeyHeader()),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("r", "*http.Request"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "context.Context"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"decodeGatewayRequest",
+			`decodeGatewayRequest fills the request with the JSON body, the path variables and, unless
+			the body is the whole request, the query parameters. The unknown query parameters are ignored.`,
+			parser.NamedTypeValue{},
+			`
+			msg := req.ProtoReflect()
+			if body != "" {
+				b, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					return status.Error(codes.InvalidArgument, err.Error())
+				}
+				if len(b) > 0 {
+					target := req
+					if body != "*" {
+						parent, fd, err := gatewayField(msg, body)
+						if err != nil {
+							return err
+						}
+						if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+							return status.Errorf(codes.Internal, "the body field %s is not a message", body)
+						}
+						target = parent.Mutable(fd).Message().Interface()
+					}
+					if err = protojson.Unmarshal(b, target); err != nil {
+						return status.Errorf(codes.InvalidArgument, "the body is invalid: %s", err)
+					}
+				}
+			}
+			vars := mux.Vars(r)
+			for k, v := range vars {
+				if err := setGatewayField(msg, k, []string{v}); err != nil {
+					return err
+				}
+			}
+			if body == "*" {
+				return nil
+			}
+			for k, v := range r.URL.Query() {
+				if _, ok := vars[k]; ok || (body != "" && (k == body || strings.HasPrefix(k, body+"."))) {
+					continue
+				}
+				if _, _, err := gatewayField(msg, k); err != nil {
+					continue
+				}
+				if err := setGatewayField(msg, k, v); err != nil {
+					return err
+				}
+			}
+			return nil`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("r", "*http.Request"),
+				parser.NewNameType("req", "proto.Message"),
+				parser.NewNameType("body", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"gatewayField",
+			`gatewayField finds the field of a dotted path by its proto or JSON name, the messages on
+			the way are created.`,
+			parser.NamedTypeValue{},
+			`
+			names := strings.Split(path, ".")
+			for i, n := range names {
+				fields := msg.Descriptor().Fields()
+				fd := fields.ByName(protoreflect.Name(n))
+				if fd == nil {
+					fd = fields.ByJSONName(n)
+				}
+				if fd == nil {
+					return nil, nil, status.Errorf(codes.InvalidArgument, "unknown field %s", path)
+				}
+				if i == len(names)-1 {
+					return msg, fd, nil
+				}
+				if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+					return nil, nil, status.Errorf(codes.InvalidArgument, "%s is not a message", n)
+				}
+				msg = msg.Mutable(fd).Message()
+			}
+			return nil, nil, status.Errorf(codes.InvalidArgument, "unknown field %s", path)`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("msg", "protoreflect.Message"),
+				parser.NewNameType("path", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "protoreflect.Message"),
+				parser.NewNameType("", "protoreflect.FieldDescriptor"),
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"setGatewayField",
+			`setGatewayField sets the field of a dotted path from the values of the URL.`,
+			parser.NamedTypeValue{},
+			`
+			parent, fd, err := gatewayField(msg, path)
+			if err != nil {
+				return err
+			}
+			if fd.IsList() {
+				list := parent.Mutable(fd).List()
+				for _, s := range values {
+					v, err := gatewayValue(fd, s)
+					if err != nil {
+						return err
+					}
+					list.Append(v)
+				}
+				return nil
+			}
+			v, err := gatewayValue(fd, values[len(values)-1])
+			if err != nil {
+				return err
+			}
+			parent.Set(fd, v)
+			return nil`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("msg", "protoreflect.Message"),
+				parser.NewNameType("path", "string"),
+				parser.NewNameType("values", "[]string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"gatewayValue",
+			`gatewayValue parses a value of the URL for a scalar or an enum field, the invalid values
+			are InvalidArgument errors.`,
+			parser.NamedTypeValue{},
+			`
+			var (
+				v   protoreflect.Value
+				err error
+			)
+			switch fd.Kind() {
+			case protoreflect.StringKind:
+				v = protoreflect.ValueOfString(s)
+			case protoreflect.BytesKind:
+				var b []byte
+				if b, err = base64.StdEncoding.DecodeString(s); err != nil {
+					b, err = base64.URLEncoding.DecodeString(s)
+				}
+				v = protoreflect.ValueOfBytes(b)
+			case protoreflect.BoolKind:
+				var b bool
+				b, err = strconv.ParseBool(s)
+				v = protoreflect.ValueOfBool(b)
+			case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+				var n int64
+				n, err = strconv.ParseInt(s, 10, 32)
+				v = protoreflect.ValueOfInt32(int32(n))
+			case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+				var n int64
+				n, err = strconv.ParseInt(s, 10, 64)
+				v = protoreflect.ValueOfInt64(n)
+			case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+				var n uint64
+				n, err = strconv.ParseUint(s, 10, 32)
+				v = protoreflect.ValueOfUint32(uint32(n))
+			case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+				var n uint64
+				n, err = strconv.ParseUint(s, 10, 64)
+				v = protoreflect.ValueOfUint64(n)
+			case protoreflect.FloatKind:
+				var f float64
+				f, err = strconv.ParseFloat(s, 32)
+				v = protoreflect.ValueOfFloat32(float32(f))
+			case protoreflect.DoubleKind:
+				var f float64
+				f, err = strconv.ParseFloat(s, 64)
+				v = protoreflect.ValueOfFloat64(f)
+			case protoreflect.EnumKind:
+				if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+					return protoreflect.ValueOfEnum(ev.Number()), nil
+				}
+				var n int64
+				n, err = strconv.ParseInt(s, 10, 32)
+				v = protoreflect.ValueOfEnum(protoreflect.EnumNumber(n))
+			default:
+				return v, status.Errorf(codes.InvalidArgument, "the field %s cannot be set from the URL", fd.Name())
+			}
+			if err != nil {
+				return v, status.Errorf(codes.InvalidArgument, "invalid value %q for the field %s", s, fd.Name())
+			}
+			return v, nil`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("fd", "protoreflect.FieldDescriptor"),
+				parser.NewNameType("s", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "protoreflect.Value"),
+				parser.NewNameType("", "error"),
+			},
+		),
+	}
+}
+
+// muxVariable matches the path variables with a pattern, `{name=messages/*}`.
+var muxVariable = regexp.MustCompile(`\{([^}=]+)=([^}]+)\}`)
+
+// muxPath turns the path template of an http rule into a mux route, `*` matches a segment and
+// `**` the rest of the path.
+func muxPath(path string) string {
+	return muxVariable.ReplaceAllStringFunc(path, func(s string) string {
+		m := muxVariable.FindStringSubmatch(s)
+		segments := strings.Split(m[2], "/")
+		for k, v := range segments {
+			switch v {
+			case "*":
+				segments[k] = "[^/]+"
+			case "**":
+				segments[k] = ".+"
+			default:
+				segments[k] = regexp.QuoteMeta(v)
+			}
+		}
+		return "{" + m[1] + ":" + strings.Join(segments, "/") + "}"
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/liuchamp/gk/utils"
+	"os"
+	"regexp"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -119,3 +121,286 @@ func IsProtoCompiled(name string) (yes bool, err error) {
 	}
 	return true, nil
 }
+
+func IsThriftCompiled(name string) (yes bool, err error) {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("thrift.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return false, err
+	}
+	sfile := path + defaultFs.FilePathSeparator() + "gen-go" + defaultFs.FilePathSeparator() +
+		utils.ToLowerSnakeCase(name) + defaultFs.FilePathSeparator() + utils.ToLowerSnakeCase(name) + ".go"
+	exist, err := defaultFs.Exists(sfile)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		logrus.Error("Not found: ", sfile)
+		return false, errors.New("Could not find the compiled thrift of the service")
+	}
+	return true, nil
+}
+
+// ProjectImport returns the import path of a folder of the project, the project is found
+// from the working directory and the GOPATH.
+func ProjectImport(path string) (string, error) {
+	gosrc := utils.GetGOPATH() + "/src/"
+	gosrc = strings.Replace(gosrc, "\\", "/", -1)
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if viper.GetString("gk_folder") != "" {
+		pwd += "/" + viper.GetString("gk_folder")
+	}
+	pwd = strings.Replace(pwd, "\\", "/", -1)
+	projectPath := strings.Replace(pwd, gosrc, "", 1)
+	return strings.Replace(projectPath+"/"+path, "\\", "/", -1), nil
+}
+
+// ServiceTypes are the types declared in the service file next to the interface, other
+// packages have to qualify them with the service package.
+type ServiceTypes struct {
+	Package string
+	// Types maps the declared types to their underlying type, `struct` for structures.
+	Types map[string]string
+	// Structs keeps the fields of the declared structures.
+	Structs map[string][]parser.NamedTypeValue
+}
+
+func LoadServiceTypes(name string) (*ServiceTypes, error) {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fname, err := te.ExecuteString(viper.GetString("service.file_name"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s, err := defaultFs.ReadFile(path + defaultFs.FilePathSeparator() + fname)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parser.NewFileParser().Parse([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	st := &ServiceTypes{Package: f.Package, Types: map[string]string{}, Structs: map[string][]parser.NamedTypeValue{}}
+	for _, v := range f.Structs {
+		st.Types[v.Name] = "struct"
+		st.Structs[v.Name] = v.Vars
+	}
+	for _, v := range f.AliasType {
+		st.Types[v.Name] = v.Type
+	}
+	return st, nil
+}
+
+var identifierRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_.]*`)
+
+// Qualify prefixes the service types referenced by typeName with the service package.
+func (st *ServiceTypes) Qualify(typeName string) string {
+	if st == nil {
+		return typeName
+	}
+	return identifierRegexp.ReplaceAllStringFunc(typeName, func(s string) string {
+		if _, ok := st.Types[s]; ok {
+			return st.Package + "." + s
+		}
+		return s
+	})
+}
+
+// QualifyAll returns a copy of the list with the service types qualified.
+func (st *ServiceTypes) QualifyAll(list []parser.NamedTypeValue) []parser.NamedTypeValue {
+	qualified := []parser.NamedTypeValue{}
+	for _, v := range list {
+		v.Type = st.Qualify(v.Type)
+		qualified = append(qualified, v)
+	}
+	return qualified
+}
+
+// Zero returns the zero value expression of the type as seen from another package.
+func (st *ServiceTypes) Zero(typeName string) string {
+	if st != nil {
+		if underlying, ok := st.Types[strings.TrimPrefix(typeName, st.Package+".")]; ok && underlying != "struct" {
+			return fmt.Sprintf("%s(%s)", typeName, getEmptyExpOfTypeName(underlying))
+		}
+	}
+	return getEmptyExpOfTypeName(typeName)
+}
+
+// LoadServiceProto parses the proto of the service kept in `pb.path`.
+func LoadServiceProto(name string) (*parser.Proto, error) {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s, err := defaultFs.ReadFile(path + defaultFs.FilePathSeparator() + utils.ToLowerSnakeCase(name) + ".proto")
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewProtoParser().Parse([]byte(s))
+}
+
+// FindProtoService returns the proto service backing the gk service `name`: the one with the
+// same name, the name followed by `Service`, or the only service of the proto.
+func FindProtoService(pbModel *parser.Proto, name string) *parser.ProtoService {
+	for _, n := range []string{utils.ToUpperFirstCamelCase(name), utils.ToUpperFirstCamelCase(name) + "Service"} {
+		for k, v := range pbModel.Services {
+			if v.Name == n {
+				return &pbModel.Services[k]
+			}
+		}
+	}
+	if len(pbModel.Services) == 1 {
+		return &pbModel.Services[0]
+	}
+	return nil
+}
+
+// PBService resolves the go names the compiled protobuf of a service exposes to the grpc
+// transport, falling back to the names `TransferToPBModel` generates when there is no proto.
+type PBService struct {
+	Name    string
+	Alias   string
+	Proto   *parser.Proto
+	Service *parser.ProtoService
+}
+
+func LoadPBService(name string) *PBService {
+	pbs := &PBService{Name: name, Alias: name + "pb"}
+	pbModel, err := LoadServiceProto(name)
+	if err != nil {
+		logrus.Warnf("Could not read the proto of the service, default names are used: %s", err)
+		return pbs
+	}
+	pbs.Proto = pbModel
+	pbs.Service = FindProtoService(pbModel, name)
+	return pbs
+}
+
+// FullName is the service name gRPC clients call.
+func (s *PBService) FullName() string {
+	if s.Service == nil {
+		return fmt.Sprintf("%s.%s", s.Alias, utils.ToUpperFirstCamelCase(s.Name))
+	}
+	if s.Proto.PackageName == "" {
+		return s.Service.Name
+	}
+	return s.Proto.PackageName + "." + s.Service.Name
+}
+
+func (s *PBService) ServerType() string {
+	if s.Service == nil {
+		return fmt.Sprintf("%s.%sServer", s.Alias, utils.ToUpperFirstCamelCase(s.Name))
+	}
+	return fmt.Sprintf("%s.%sServer", s.Alias, s.Service.Name)
+}
+
+// ClientConstructor is the function of the compiled pb making a gRPC client of the service.
+func (s *PBService) ClientConstructor() string {
+	if s.Service == nil {
+		return fmt.Sprintf("%s.New%sClient", s.Alias, utils.ToUpperFirstCamelCase(s.Name))
+	}
+	return fmt.Sprintf("%s.New%sClient", s.Alias, pbGoName(s.Service.Name))
+}
+
+// ClientType is the interface of the gRPC client in the compiled pb.
+func (s *PBService) ClientType() string {
+	if s.Service == nil {
+		return fmt.Sprintf("%s.%sClient", s.Alias, utils.ToUpperFirstCamelCase(s.Name))
+	}
+	return fmt.Sprintf("%s.%sClient", s.Alias, pbGoName(s.Service.Name))
+}
+
+// StreamType is the stream of a streaming method on the `Server` or the `Client` side.
+func (s *PBService) StreamType(method, side string) string {
+	if s.Service == nil {
+		return fmt.Sprintf("%s.%s_%s%s", s.Alias, utils.ToUpperFirstCamelCase(s.Name), method, side)
+	}
+	return fmt.Sprintf("%s.%s_%s%s", s.Alias, pbGoName(s.Service.Name), pbGoName(method), side)
+}
+
+func (s *PBService) rpc(method string) *parser.ProtoRPC {
+	if s.Service == nil {
+		return nil
+	}
+	return s.Service.RPC(method)
+}
+
+// RequestType is the go type of the request message of the method.
+func (s *PBService) RequestType(method string) string {
+	if rpc := s.rpc(method); rpc != nil {
+		return s.goType(rpc.RequestType)
+	}
+	return fmt.Sprintf("%s.%sReq", s.Alias, method)
+}
+
+// ResponseType is the go type of the response message of the method.
+func (s *PBService) ResponseType(method string) string {
+	if rpc := s.rpc(method); rpc != nil {
+		return s.goType(rpc.ReturnsType)
+	}
+	return fmt.Sprintf("%s.%sRes", s.Alias, method)
+}
+
+// ResponseHasErr tells if the response message carries the error as a string field, otherwise the
+// error is returned as the status of the call.
+func (s *PBService) ResponseHasErr(method string) bool {
+	rpc := s.rpc(method)
+	if rpc == nil {
+		return true
+	}
+	msg := s.Proto.Message(strings.TrimPrefix(strings.TrimPrefix(rpc.ReturnsType, "."), s.Proto.PackageName+"."))
+	return msg != nil && (msg.Field("Err") != nil || msg.Field("err") != nil)
+}
+
+// UsesEmpty tells if a method of the service sends or returns google.protobuf.Empty.
+func (s *PBService) UsesEmpty() bool {
+	if s.Service == nil {
+		return false
+	}
+	for _, v := range s.Service.RPCs {
+		if s.goType(v.RequestType) == pbEmptyType || s.goType(v.ReturnsType) == pbEmptyType {
+			return true
+		}
+	}
+	return false
+}
+
+// Imports are the imports of the compiled pb, pbImport is the import path of `pb.path`.
+func (s *PBService) Imports(pbImport string) []parser.NamedTypeValue {
+	list := []parser.NamedTypeValue{parser.NewNameType(s.Alias, fmt.Sprintf("\"%s\"", pbImport))}
+	if s.UsesEmpty() {
+		list = append(list, parser.NewNameType("", "\"github.com/golang/protobuf/ptypes/empty\""))
+	}
+	return list
+}
+
+const pbEmptyType = "empty.Empty"
+
+func (s *PBService) goType(protoType string) string {
+	protoType = strings.TrimPrefix(protoType, ".")
+	if protoType == "google.protobuf.Empty" {
+		return pbEmptyType
+	}
+	if s.Proto.PackageName != "" {
+		protoType = strings.TrimPrefix(protoType, s.Proto.PackageName+".")
+	}
+	return s.Alias + "." + strings.Replace(protoType, ".", "_", -1)
+}
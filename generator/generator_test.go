@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+)
+
+// testProject starts a project on an in-memory fs with the gk.json of `gk init`, the settings
+// override its keys.
+func testProject(t *testing.T, settings map[string]interface{}) {
+	t.Helper()
+	viper.Reset()
+	viper.Set("gk_testing", true)
+	viper.SetFs(fs.NewDefaultFs("").Fs)
+	config, err := template.NewEngine().Execute("gk.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Get().WriteFile("gk.json", config, true); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile("gk.json")
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	// protoc is never run, the tests write the compiled pb
+	viper.Set("pb.protoc", "gk-test-no-protoc")
+	for k, v := range settings {
+		viper.Set(k, v)
+	}
+}
+
+// testService writes the service of `gk new service` with the methods in its interface and
+// the declarations after it.
+func testService(t *testing.T, name, methods, decls string) {
+	t.Helper()
+	if err := NewServiceGenerator().Generate(name); err != nil {
+		t.Fatal(err)
+	}
+	file := name + "/pkg/" + name + "service/service.go"
+	src, err := fs.Get().ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src = strings.Replace(src, "FooToo(ctx context.Context, inParam string) (outParam string, err error)", methods, 1)
+	if err := fs.Get().WriteFile(file, src+"\n"+decls, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testRead returns the content of the generated file.
+func testRead(t *testing.T, file string) string {
+	t.Helper()
+	src, err := fs.Get().ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+// compact is the code without its spaces, the tests compare it without depending on gofmt.
+func compact(code string) string {
+	return strings.Join(strings.Fields(code), "")
+}
+
+// assertContains fails the test for each snippet missing in the code, spaces apart.
+func assertContains(t *testing.T, code string, snippets ...string) {
+	t.Helper()
+	for _, v := range snippets {
+		if !strings.Contains(compact(code), compact(v)) {
+			t.Errorf("missing %q in\n%s", v, code)
+		}
+	}
+}
+
+// assertNotContains fails the test for each snippet found in the code, spaces apart.
+func assertNotContains(t *testing.T, code string, snippets ...string) {
+	t.Helper()
+	for _, v := range snippets {
+		if strings.Contains(compact(code), compact(v)) {
+			t.Errorf("unexpected %q in\n%s", v, code)
+		}
+	}
+}
+
+// declarations are the signatures of the functions and the methods and the struct fields of
+// the code.
+func declarations(t *testing.T, code string) []string {
+	t.Helper()
+	file, err := parser.NewFileParser().Parse([]byte(code))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []string
+	types := func(list []parser.NamedTypeValue) string {
+		var out []string
+		for _, v := range list {
+			out = append(out, v.Type)
+		}
+		return strings.Join(out, ", ")
+	}
+	for _, m := range file.Methods {
+		list = append(list, fmt.Sprintf("%s %s(%s) (%s)", m.Struct.Type, m.Name, types(m.Parameters), types(m.Results)))
+	}
+	for _, s := range file.Structs {
+		for _, v := range s.Vars {
+			list = append(list, s.Name+"."+v.Name+" "+v.Type)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// assertSameDeclarations fails the test when the update lost or added declarations.
+func assertSameDeclarations(t *testing.T, initialized, updated string) {
+	t.Helper()
+	before, after := declarations(t, initialized), declarations(t, updated)
+	if strings.Join(before, "\n") != strings.Join(after, "\n") {
+		t.Errorf("the declarations changed from\n%s\nto\n%s", strings.Join(before, "\n"), strings.Join(after, "\n"))
+	}
+}
@@ -0,0 +1,378 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+)
+
+// httpTransportFile returns the path of the http transport file, `file_name` or `client_file_name`.
+func httpTransportFile(name, file string) (string, error) {
+	te := template.NewEngine()
+	path, err := te.ExecuteString(viper.GetString("httptransport.path"), map[string]string{
+		"ServiceName":   name,
+		"TransportType": "http",
+	})
+	if err != nil {
+		return "", err
+	}
+	fname, err := te.ExecuteString(viper.GetString("httptransport."+file), map[string]string{
+		"ServiceName":   name,
+		"TransportType": "http",
+	})
+	if err != nil {
+		return "", err
+	}
+	return path + fs.Get().FilePathSeparator() + fname, nil
+}
+
+// httpFormat is the conversion of a parameter to the string the server parses.
+func httpFormat(typeName, underlying, expr string) string {
+	cast := func(t string) string {
+		if typeName == t {
+			return expr
+		}
+		return t + "(" + expr + ")"
+	}
+	switch {
+	case underlying == "bool":
+		return "strconv.FormatBool(" + cast("bool") + ")"
+	case strings.HasPrefix(underlying, "int"):
+		return "strconv.FormatInt(" + cast("int64") + ", 10)"
+	case strings.HasPrefix(underlying, "uint"):
+		return "strconv.FormatUint(" + cast("uint64") + ", 10)"
+	case strings.HasPrefix(underlying, "float"):
+		return fmt.Sprintf("strconv.FormatFloat(%s, 'g', -1, %s)", cast("float64"), strings.TrimPrefix(underlying, "float"))
+	case underlying == "time.Duration":
+		return cast("time.Duration") + ".String()"
+	case underlying == "time.Time":
+		return cast("time.Time") + ".Format(time.RFC3339Nano)"
+	}
+	return cast("string")
+}
+
+// httpEncoding is the code setting a parameter in the path, the query or the headers of the
+// request.
+func httpEncoding(p httpParam, route httpRoute, st *ServiceTypes) string {
+	if strings.HasPrefix(p.Type, "[]") {
+		elem := strings.TrimPrefix(p.Type, "[]")
+		underlying, _ := httpScalar(elem, st)
+		return fmt.Sprintf(`for _, v := range req.%s {
+				q.Add(%q, %s)
+			}`, p.Field, p.Key, httpFormat(elem, underlying, "v"))
+	}
+	underlying, _ := httpScalar(p.Type, st)
+	value := httpFormat(p.Type, underlying, "req."+p.Field)
+	switch p.In {
+	case "path":
+		variable := "{" + p.Key + "}"
+		for _, v := range httpPathVariable.FindAllStringSubmatch(route.Path, -1) {
+			if v[1] == p.Key {
+				variable = v[0]
+			}
+		}
+		return fmt.Sprintf("r.URL.Path = strings.Replace(r.URL.Path, %q, %s, 1)", variable, value)
+	case "header":
+		return fmt.Sprintf("r.Header.Set(%q, %s)", p.Key, value)
+	}
+	return fmt.Sprintf("q.Set(%q, %s)", p.Key, value)
+}
+
+// httpURLParams are the parameters of the route read from the path, the query or the headers.
+func httpURLParams(route httpRoute) []httpParam {
+	var params []httpParam
+	for _, p := range route.Params {
+		if p.In == "path" || p.In == "query" || p.In == "header" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// httpEncodeReqName is the encoder of the method request, the requests only made of the JSON
+// body use encodeHTTPGenericRequest.
+func httpEncodeReqName(m parser.Method, route httpRoute) string {
+	if route.Body && len(httpURLParams(route)) == 0 {
+		return "encodeHTTPGenericRequest"
+	}
+	return fmt.Sprintf("encodeHTTP%sReq", m.Name)
+}
+
+// httpClientCodecs are the encoder of the method request and the decoder of its response.
+func httpClientCodecs(name string, m parser.Method, route httpRoute, st *ServiceTypes, codecs *httpCodecs) []parser.Method {
+	var methods []parser.Method
+	if httpEncodeReqName(m, route) != "encodeHTTPGenericRequest" {
+		params := httpURLParams(route)
+		stream := httpEncodeReqStream(m, route)
+		body := ""
+		if len(params) > 0 || stream != "" {
+			body = fmt.Sprintf("req := request.(%sendpoint.%sReq)", name, m.Name)
+		}
+		query := false
+		for _, p := range params {
+			if p.In == "query" {
+				query = true
+			}
+		}
+		if query {
+			body += "\nq := r.URL.Query()"
+		}
+		for _, p := range params {
+			body += "\n" + httpEncoding(p, route, st)
+		}
+		if query {
+			body += "\nr.URL.RawQuery = q.Encode()"
+		}
+		switch {
+		case route.Body:
+			body += "\nreturn encodeHTTPGenericRequest(ctx, r, req)"
+		case stream != "":
+			body += stream
+		default:
+			body += "\nreturn nil"
+		}
+		methods = append(methods, parser.NewMethodWithComment(
+			fmt.Sprintf("encodeHTTP%sReq", m.Name),
+			fmt.Sprintf(`encodeHTTP%sReq is a transport/http.EncodeRequestFunc that sets the parameters
+					of the %s %s request in its path, query, headers and body. Primarily useful in a client.`,
+				m.Name, route.Verb, route.Path),
+			parser.NamedTypeValue{},
+			body,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("r", "*http.Request"),
+				parser.NewNameType("request", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		))
+	}
+	if route.Multipart {
+		methods = append(methods, httpWriteForm(name, m, route, st))
+	}
+	decode := codecs.decodeResProto(m) + fmt.Sprintf(`res := %sendpoint.%sRes{}
+			if err := decodeHTTPGenericResponse(r, &res); err != nil {
+				return nil, err
+			}
+			return res, nil`, name, m.Name)
+	if route.Download != nil {
+		decode = httpDecodeDownload(name, m, route, st)
+	}
+	return append(methods, parser.NewMethodWithComment(
+		fmt.Sprintf("decodeHTTP%sRes", m.Name),
+		fmt.Sprintf(`decodeHTTP%sRes is a transport/http.DecodeResponseFunc that decodes the
+				response of %s. Primarily useful in a client.`, m.Name, m.Name),
+		parser.NamedTypeValue{},
+		decode,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("ctx", "context.Context"),
+			parser.NewNameType("r", "*http.Response"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "interface{}"),
+			parser.NewNameType("", "error"),
+		},
+	))
+}
+
+// httpClientBlock is the block of NewHTTPClient making the endpoint of the method, the bodies
+// of the downloads are left open for the caller.
+func httpClientBlock(name string, m parser.Method, route httpRoute) string {
+	buffered := ""
+	if route.Download != nil {
+		buffered = "\nops = append(ops, httptransport.BufferedStream(true))"
+	}
+	return fmt.Sprintf(`
+			{
+				ops := append(options, httptransport.ClientBefore(authContextToHTTP))%s
+				ep := httptransport.NewClient(
+					%q,
+					routeURL(u, %q),
+					%s,
+					decodeHTTP%sRes,
+					ops...,
+				).Endpoint()
+				%s
+				set.%sEndpoint = ep
+			}`, buffered, route.Verb, route.Path, httpEncodeReqName(m, route), m.Name,
+		tracingClientEndpoint(name, utils.ToLowerFirstCamelCase(m.Name)), m.Name)
+}
+
+// httpNewClient is NewHTTPClient, the blocks of the methods are added to its body.
+func httpNewClient(name string, iface *parser.Interface, st *ServiceTypes) parser.Method {
+	return parser.NewMethodWithComment(
+		"NewHTTPClient",
+		`NewHTTPClient returns a service backed by an HTTP server living at the remote
+		instance. We expect instance to come from a service discovery system, so likely
+		of the form "host:port".`,
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`
+		if !strings.HasPrefix(instance, "http") {
+			instance = "http://" + instance
+		}
+		u, err := url.Parse(instance)
+		if err != nil {
+			return nil, err
+		}
+		options := append([]httptransport.ClientOption{
+			httptransport.ClientBefore(contextToAccept),
+		}, tracingHTTPClientOptions(tracer)...)
+		set := %sendpoint.Set{}
+		`, name),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("instance", "string"),
+			tracingParam(name + "endpoint."),
+			parser.NewNameType("logger", "log.Logger"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", st.Package+"."+iface.Name),
+			parser.NewNameType("", "error"),
+		},
+	)
+}
+
+// httpClientHelpers give the urls of the routes and encode the bodies.
+func httpClientHelpers(codecs *httpCodecs) []parser.Method {
+	return []parser.Method{
+		parser.NewMethodWithComment(
+			"routeURL",
+			`routeURL is the url of the route on the instance.`,
+			parser.NamedTypeValue{},
+			`route := *u
+			route.Path = strings.TrimSuffix(route.Path, "/") + path
+			return &route`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("u", "*url.URL"),
+				parser.NewNameType("path", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "*url.URL"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"encodeHTTPGenericRequest",
+			`encodeHTTPGenericRequest is a transport/http.EncodeRequestFunc that encodes any
+			request to the request body in the format of WithFormat. Primarily useful in a client.`,
+			parser.NamedTypeValue{},
+			codecs.encodeRequest(),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("r", "*http.Request"),
+				parser.NewNameType("request", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+	}
+}
+
+// httpEndpointClientBlock is the block of NewHTTPEndpointClientSet balancing the endpoint of
+// the method over the instances.
+func httpEndpointClientBlock(name string, v parser.Method) string {
+	return "\n" + fmt.Sprintf(`
+		{
+			factory := httpFactory(%sendpoint.Make%sEndpoint, tracer, logger)
+			endpointer := sd.NewEndpointer(instancer, factory, logger)
+			balancer := lb.NewRoundRobin(endpointer)
+			retry := lb.Retry(retryMax, retryTimeout, balancer)
+			%s
+		}`, name, utils.ToUpperFirstCamelCase(v.Name), clientEndpoint(name, v))
+}
+
+// httpEndpointClient is the file of NewHTTPEndpointClientSet.
+func httpEndpointClient(name string, iface *parser.Interface) (*parser.File, error) {
+	te := template.NewEngine()
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return nil, err
+	}
+	endpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return nil, err
+	}
+	endpointsImport, err := ProjectImport(endpointsPath)
+	if err != nil {
+		return nil, err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return nil, err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return nil, err
+	}
+	handler := parser.NewFile()
+	handler.Package = fmt.Sprintf("%stransport", name)
+	handler.Imports = []parser.NamedTypeValue{
+		parser.NewNameType("", "\"io\""),
+		parser.NewNameType("", "\"time\"\n"),
+
+		parser.NewNameType("", "\"github.com/go-kit/kit/sd\""),
+		parser.NewNameType("ketcd", "\"github.com/go-kit/kit/sd/etcdv3\""),
+		parser.NewNameType("", "\"github.com/go-kit/kit/endpoint\""),
+		parser.NewNameType("", "\"github.com/go-kit/kit/sd/lb\""),
+		parser.NewNameType("", "\"github.com/go-kit/kit/log\"\n"),
+
+		parser.NewNameType("", fmt.Sprintf("\"%s\"", endpointsImport)),
+		parser.NewNameType("", fmt.Sprintf("\"%s\"", serviceImport)),
+	}
+	handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
+		"NewHTTPEndpointClientSet",
+		`NewHTTPEndpointClientSet makes a set of endpoints available for an HTTP client,
+		the instances of the service are discovered in etcd.`,
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`
+		var instancer *ketcd.Instancer
+		if instancer, err = ketcd.NewInstancer(etcdClient, svcName, logger); err != nil {
+			return set, err
+		}
+		set = %sendpoint.Set{}
+		`, name),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("svcName", "string"),
+			parser.NewNameType("retryMax", "int"),
+			parser.NewNameType("retryTimeout", "time.Duration"),
+			parser.NewNameType("logger", "log.Logger"),
+			parser.NewNameType("etcdClient", "ketcd.Client"),
+			tracingParam(name + "endpoint."),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("set", fmt.Sprintf("%sendpoint.Set", name)),
+			parser.NewNameType("err", "error"),
+		},
+	), parser.NewMethod(
+		"httpFactory",
+		parser.NamedTypeValue{},
+		`
+		return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+			service, err := NewHTTPClient(instance, tracer, logger)
+			if err != nil {
+				return nil, nil, err
+			}
+			return makeEndpoint(service), nil, nil
+		}`,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("makeEndpoint", fmt.Sprintf("func(%s.%s) endpoint.Endpoint", st.Package, iface.Name)),
+			tracingParam(name + "endpoint."),
+			parser.NewNameType("logger", "log.Logger"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "sd.Factory"),
+		},
+	))
+	for _, v := range iface.Methods {
+		handler.Methods[0].Body += httpEndpointClientBlock(name, v)
+	}
+	handler.Methods[0].Body += `
+	return set, nil`
+	return &handler, nil
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+const testItemMethods = `// gk:http GET /items/{id}
+	// gk:header token Authorization
+	GetItem(ctx context.Context, id int64, token string) (item Account, err error)
+	// gk:http PUT /items/{id} body=none
+	Touch(ctx context.Context, id int64) (err error)
+	CreateItem(ctx context.Context, a Account) (id int64, err error)`
+
+func TestHTTPClient(t *testing.T) {
+	testProject(t, map[string]interface{}{"gk_transport": "http", "gk_force_override": true})
+	testService(t, "acc", testItemMethods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	file := "acc/pkg/acctransport/http.go"
+	code := testRead(t, file)
+	assertContains(t, code,
+		// the client is the service
+		`func NewHTTPClient(instance string, tracer accendpoint.Tracer, logger log.Logger) (accservice.Service, error) {`,
+		`set := accendpoint.Set{}`,
+		`return set, nil`,
+		// the parameters go where the server reads them
+		`"GET", routeURL(u, "/items/{id}"), encodeHTTPGetItemReq, decodeHTTPGetItemRes,`,
+		`r.URL.Path = strings.Replace(r.URL.Path, "{id}", strconv.FormatInt(req.Id, 10), 1)
+		r.Header.Set("Authorization", req.Token)`,
+		`"PUT", routeURL(u, "/items/{id}"), encodeHTTPTouchReq, decodeHTTPTouchRes,`,
+		// the requests only made of the body share the generic encoder
+		`"POST", routeURL(u, "/create-item"), encodeHTTPGenericRequest, decodeHTTPCreateItemRes,`,
+		`set.CreateItemEndpoint = ep`,
+	)
+	assertNotContains(t, code, `func encodeHTTPCreateItemReq(`)
+	assertContains(t, testRead(t, "acc/pkg/acctransport/httpclient.go"),
+		`func NewHTTPEndpointClientSet(svcName string, retryMax int, retryTimeout time.Duration, logger log.Logger, etcdClient ketcd.Client, tracer accendpoint.Tracer) (set accendpoint.Set, err error) {`,
+		`factory := httpFactory(accendpoint.MakeTouchEndpoint, tracer, logger)`,
+		`set.TouchEndpoint = accendpoint.ClientMiddleware("touch")(retry)`,
+		`service, err := NewHTTPClient(instance, tracer, logger)`,
+	)
+
+	// the update adds the client of the new methods
+	svc := "acc/pkg/accservice/service.go"
+	src := strings.Replace(testRead(t, svc), "CreateItem(ctx context.Context, a Account) (id int64, err error)",
+		"CreateItem(ctx context.Context, a Account) (id int64, err error)\n\tRemoveItem(ctx context.Context, id int64) (err error)", 1)
+	if err := fs.Get().WriteFile(svc, src, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewServiceUpdateGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, testRead(t, file),
+		`"DELETE", routeURL(u, "/remove-item"), encodeHTTPRemoveItemReq, decodeHTTPRemoveItemRes,`,
+		`q.Set("id", strconv.FormatInt(req.Id, 10))`,
+		`set.RemoveItemEndpoint = ep`,
+	)
+	assertContains(t, testRead(t, "acc/pkg/acctransport/httpclient.go"),
+		`factory := httpFactory(accendpoint.MakeRemoveItemEndpoint, tracer, logger)`,
+	)
+
+	// the client file follows `client_file_name`
+	viper.Set("httptransport.client_file_name", "balancer.go")
+	if err := NewServiceUpdateGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, testRead(t, "acc/pkg/acctransport/balancer.go"), `func NewHTTPEndpointClientSet(`)
+}
@@ -0,0 +1,610 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+)
+
+// httpCodecNames are the formats of the bodies of `httptransport.codecs`, JSON is always
+// served and the errors are always sent in JSON.
+var httpCodecNames = []string{"json", "protobuf", "msgpack"}
+
+// httpFormats are the formats and the content types the codecs negotiate, the first content
+// type of a format is the one sent.
+var httpFormats = []struct {
+	Codec        string
+	Constant     string
+	ContentTypes []string
+}{
+	{"json", "FormatJSON", []string{"application/json"}},
+	{"protobuf", "FormatProtobuf", []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"}},
+	{"msgpack", "FormatMsgpack", []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}},
+}
+
+// httpUnmarshalReq is the statement of the decoders unmarshalling the body in the negotiated
+// format, the protobuf bodies are decoded before it.
+const httpUnmarshalReq = "if err = unmarshal(format, body, &req); err != nil {"
+
+// httpCodecs are the formats the http transport negotiates on the Content-Type and Accept
+// headers.
+type httpCodecs struct {
+	Protobuf bool
+	Msgpack  bool
+	name     string
+	pbs      *PBService
+	pbImport string
+	// pb are the methods with the gRPC codecs in the transport package, their protobuf bodies
+	// are converted by them.
+	pb map[string]bool
+}
+
+func loadHTTPCodecs(name string) (*httpCodecs, error) {
+	c := &httpCodecs{name: name, pb: map[string]bool{}}
+	for _, v := range viper.GetStringSlice("httptransport.codecs") {
+		supported := false
+		for _, n := range httpCodecNames {
+			if v == n {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("The http codec `%s` is not supported, use %s", v, strings.Join(httpCodecNames, ", "))
+		}
+		switch v {
+		case "protobuf":
+			c.Protobuf = true
+		case "msgpack":
+			c.Msgpack = true
+		}
+	}
+	if c.Protobuf {
+		if err := c.loadPB(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// loadPB finds the methods converted by the gRPC transport of the package, the protobuf
+// bodies are only negotiated when the service has it.
+func (c *httpCodecs) loadPB() error {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	data := map[string]string{"ServiceName": c.name, "TransportType": "http"}
+	httpPath, err := te.ExecuteString(viper.GetString("httptransport.path"), data)
+	if err != nil {
+		return err
+	}
+	grpcPath, err := te.ExecuteString(viper.GetString("grpctransport.path"), data)
+	if err != nil {
+		return err
+	}
+	fname, err := te.ExecuteString(viper.GetString("grpctransport.file_name"), data)
+	if err != nil {
+		return err
+	}
+	sfile := grpcPath + defaultFs.FilePathSeparator() + fname
+	exist, err := defaultFs.Exists(sfile)
+	if err != nil {
+		return err
+	}
+	if !exist || grpcPath != httpPath {
+		logrus.Info("The service has no gRPC transport next to the http transport, the protobuf bodies are not negotiated")
+		c.Protobuf = false
+		return nil
+	}
+	s, err := defaultFs.ReadFile(sfile)
+	if err != nil {
+		return err
+	}
+	f, err := parser.NewFileParser().Parse([]byte(s))
+	if err != nil {
+		return err
+	}
+	for _, v := range f.Methods {
+		if strings.HasPrefix(v.Name, "decodeGRPC") && strings.HasSuffix(v.Name, "Req") {
+			c.pb[strings.TrimSuffix(strings.TrimPrefix(v.Name, "decodeGRPC"), "Req")] = true
+		}
+	}
+	pbPath, err := te.ExecuteString(viper.GetString("pb.path"), data)
+	if err != nil {
+		return err
+	}
+	if c.pbImport, err = ProjectImport(pbPath); err != nil {
+		return err
+	}
+	c.pbs = LoadPBService(c.name)
+	return nil
+}
+
+// proto tells if the bodies of the method are negotiated in protobuf.
+func (c *httpCodecs) proto(m parser.Method) bool {
+	return c.Protobuf && c.pb[m.Name]
+}
+
+func (c *httpCodecs) enabled(codec string) bool {
+	switch codec {
+	case "protobuf":
+		return c.Protobuf
+	case "msgpack":
+		return c.Msgpack
+	}
+	return true
+}
+
+// imports are the packages of the codecs.
+func (c *httpCodecs) imports() []parser.NamedTypeValue {
+	var list []parser.NamedTypeValue
+	if c.Protobuf {
+		list = append(list, parser.NewNameType("", "\"google.golang.org/protobuf/proto\""))
+		list = append(list, c.pbs.Imports(c.pbImport)...)
+	}
+	if c.Msgpack {
+		list = append(list, parser.NewNameType("", "\"github.com/vmihailenco/msgpack/v5\""))
+	}
+	return list
+}
+
+// constants are the formats callers pick with WithFormat.
+func (c *httpCodecs) constants() []parser.NamedTypeValue {
+	var list []parser.NamedTypeValue
+	for _, v := range httpFormats {
+		if c.enabled(v.Codec) {
+			list = append(list, parser.NewNameTypeValue(v.Constant, "string", fmt.Sprintf("%q", v.ContentTypes[0])))
+		}
+	}
+	return list
+}
+
+// structs are the key of the format in the contexts of the client.
+func (c *httpCodecs) structs() []parser.Struct {
+	return []parser.Struct{parser.NewStructWithComment(
+		"formatKey",
+		`formatKey is the key of the format of WithFormat in the context.`,
+		[]parser.NamedTypeValue{},
+	)}
+}
+
+// decodeReq is the code of the decoders unmarshalling the request body of the method in the
+// format of its Content-Type.
+func (c *httpCodecs) decodeReq(m parser.Method) string {
+	return c.decodeReqFormat(m) + httpUnmarshalReq + `
+			return nil, badRequest("body", err)
+		}`
+}
+
+// decodeReqFormat reads the format of the request body and decodes the protobuf bodies.
+func (c *httpCodecs) decodeReqFormat(m parser.Method) string {
+	return `format := contentFormat(r.Header.Get("Content-Type"))
+		if format == "" {
+			return nil, badRequest("Content-Type", fmt.Errorf("%q is not supported", r.Header.Get("Content-Type")))
+		}
+		` + c.decodeReqProto(m)
+}
+
+// decodeReqProto converts the protobuf request body of the method with its gRPC decoder.
+func (c *httpCodecs) decodeReqProto(m parser.Method) string {
+	if !c.proto(m) {
+		return ""
+	}
+	return fmt.Sprintf(`if format == FormatProtobuf {
+			pbReq := &%s{}
+			if err = proto.Unmarshal(body, pbReq); err != nil {
+				return nil, badRequest("body", err)
+			}
+			v, err := decodeGRPC%sReq(ctx, pbReq)
+			if err != nil {
+				return nil, badRequest("body", err)
+			}
+			req = v.(%sendpoint.%sReq)
+		} else `, c.pbs.RequestType(m.Name), m.Name, c.name, m.Name)
+}
+
+// decodeResProto converts the protobuf response of the method with its gRPC decoder.
+func (c *httpCodecs) decodeResProto(m parser.Method) string {
+	if !c.proto(m) {
+		return ""
+	}
+	return fmt.Sprintf(`if contentFormat(r.Header.Get("Content-Type")) == FormatProtobuf {
+			reply := &%s{}
+			if err := decodeHTTPGenericResponse(r, reply); err != nil {
+				return nil, err
+			}
+			return decodeGRPC%sRes(ctx, reply)
+		}
+		`, c.pbs.ResponseType(m.Name), m.Name)
+}
+
+// encodeResponse is the code of encodeHTTPGenericResponse sending the response in the format
+// of the Accept header, `json` is the code sending it in JSON.
+func (c *httpCodecs) encodeResponse(json string) string {
+	body := "format := responseFormat(ctx)\n"
+	if c.Protobuf {
+		body += `if format == FormatProtobuf {
+				reply, err := protoResponse(ctx, response)
+				if err != nil {
+					return err
+				}
+				response = reply
+			}
+			`
+	}
+	return body + `if format != FormatJSON {
+				b, err := marshal(format, response)
+				if err != nil {
+					return err
+				}
+				w.Header().Set("Content-Type", format)
+				_, err = w.Write(b)
+				return err
+			}
+			` + json
+}
+
+// encodeRequest is the body of encodeHTTPGenericRequest.
+func (c *httpCodecs) encodeRequest() string {
+	body := "format := contextFormat(ctx)\n"
+	if c.Protobuf {
+		body += `if format == FormatProtobuf {
+				pbReq, err := protoRequest(ctx, request)
+				if err != nil {
+					return err
+				}
+				request = pbReq
+			}
+			`
+	}
+	return body + `b, err := marshal(format, request)
+			if err != nil {
+				return err
+			}
+			if format == FormatJSON {
+				format += "; charset=utf-8"
+			}
+			r.Header.Set("Content-Type", format)
+			r.ContentLength = int64(len(b))
+			r.Body = ioutil.NopCloser(bytes.NewReader(b))
+			return nil`
+}
+
+// decodeResponse is the code of decodeHTTPGenericResponse unmarshalling the successful
+// responses that are not in JSON.
+func (c *httpCodecs) decodeResponse() string {
+	return `if format := contentFormat(r.Header.Get("Content-Type")); format != "" && format != FormatJSON && r.StatusCode >= 200 && r.StatusCode <= 299 {
+				return unmarshal(format, body, response)
+			}
+			`
+}
+
+// helpers negotiate the formats and marshal the bodies in them, they are generated again on
+// update as they follow the codecs and the methods.
+func (c *httpCodecs) helpers(iface *parser.Interface) []parser.Method {
+	formats := ""
+	for _, v := range httpFormats {
+		if !c.enabled(v.Codec) {
+			continue
+		}
+		contentTypes := []string{}
+		for _, t := range v.ContentTypes {
+			contentTypes = append(contentTypes, fmt.Sprintf("%q", t))
+		}
+		if v.Codec == "json" {
+			contentTypes = append([]string{`""`}, contentTypes...)
+		}
+		formats += fmt.Sprintf("\ncase %s:\nreturn %s", strings.Join(contentTypes, ", "), v.Constant)
+	}
+	marshal, unmarshal := "", ""
+	if c.Protobuf {
+		marshal += `
+			case FormatProtobuf:
+				m, ok := v.(proto.Message)
+				if !ok {
+					return nil, fmt.Errorf("%T is not a protobuf message", v)
+				}
+				return proto.Marshal(m)`
+		unmarshal += `
+			case FormatProtobuf:
+				m, ok := v.(proto.Message)
+				if !ok {
+					return fmt.Errorf("%T is not a protobuf message", v)
+				}
+				return proto.Unmarshal(data, m)`
+	}
+	if c.Msgpack {
+		marshal += `
+			case FormatMsgpack:
+				var buf bytes.Buffer
+				enc := msgpack.NewEncoder(&buf)
+				enc.SetCustomStructTag("json")
+				if err := enc.Encode(v); err != nil {
+					return nil, err
+				}
+				return buf.Bytes(), nil`
+		unmarshal += `
+			case FormatMsgpack:
+				dec := msgpack.NewDecoder(bytes.NewReader(data))
+				dec.SetCustomStructTag("json")
+				return dec.Decode(v)`
+	}
+	if marshal != "" {
+		marshal = "switch format {" + marshal + "\n}\n"
+		unmarshal = "switch format {" + unmarshal + "\n}\n"
+	}
+	helpers := []parser.Method{
+		parser.NewMethodWithComment(
+			"contentFormat",
+			`contentFormat is the format of a Content-Type, or of a media range of an Accept header,
+			the empty string when it is not negotiated. The bodies without a Content-Type are JSON.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {%s
+			}
+			return ""`, formats),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("contentType", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "string"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"responseFormat",
+			`responseFormat is the first format of the Accept header of the request, JSON when none
+			is negotiated. Primarily useful in a server.`,
+			parser.NamedTypeValue{},
+			`accept, _ := ctx.Value(httptransport.ContextKeyRequestAccept).(string)
+			for _, v := range strings.Split(accept, ",") {
+				if format := contentFormat(v); format != "" {
+					return format
+				}
+			}
+			return FormatJSON`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "string"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"marshal",
+			`marshal encodes v in the format, the msgpack bodies use the names of the JSON fields.`,
+			parser.NamedTypeValue{},
+			marshal+"return json.Marshal(v)",
+			[]parser.NamedTypeValue{
+				parser.NewNameType("format", "string"),
+				parser.NewNameType("v", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "[]byte"),
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"unmarshal",
+			`unmarshal decodes the data in the format into v.`,
+			parser.NamedTypeValue{},
+			unmarshal+"return json.Unmarshal(data, v)",
+			[]parser.NamedTypeValue{
+				parser.NewNameType("format", "string"),
+				parser.NewNameType("data", "[]byte"),
+				parser.NewNameType("v", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"WithFormat",
+			`WithFormat makes the HTTP client send the requests made with the context in the format,
+			and ask for the responses in it. The errors are always sent in JSON.`,
+			parser.NamedTypeValue{},
+			`return context.WithValue(ctx, formatKey{}, format)`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("format", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "context.Context"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"contextFormat",
+			`contextFormat is the format of WithFormat, JSON by default. Primarily useful in a client.`,
+			parser.NamedTypeValue{},
+			`if format, ok := ctx.Value(formatKey{}).(string); ok {
+				if format = contentFormat(format); format != "" {
+					return format
+				}
+			}
+			return FormatJSON`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "string"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"contextToAccept",
+			`contextToAccept asks for the responses in the format of the context. Primarily useful
+			in a client.`,
+			parser.NamedTypeValue{},
+			`r.Header.Set("Accept", contextFormat(ctx))
+			return ctx`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("r", "*http.Request"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "context.Context"),
+			},
+		),
+	}
+	if !c.Protobuf {
+		return helpers
+	}
+	responses, requests := "", ""
+	for _, m := range iface.Methods {
+		if !c.proto(m) {
+			continue
+		}
+		responses += fmt.Sprintf("\ncase %sendpoint.%sRes:\nreturn encodeGRPC%sRes(ctx, response)", c.name, m.Name, m.Name)
+		requests += fmt.Sprintf("\ncase %sendpoint.%sReq:\nreturn encodeGRPC%sReq(ctx, request)", c.name, m.Name, m.Name)
+	}
+	return append(helpers,
+		parser.NewMethodWithComment(
+			"protoResponse",
+			`protoResponse converts the response to its protobuf message with the codecs of the gRPC
+			transport. Primarily useful in a server.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`switch response.(type) {%s
+			}
+			return nil, fmt.Errorf("%%T has no protobuf message", response)`, responses),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("response", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "interface{}"),
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"protoRequest",
+			`protoRequest converts the request to its protobuf message with the codecs of the gRPC
+			transport. Primarily useful in a client.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`switch request.(type) {%s
+			}
+			return nil, fmt.Errorf("%%T has no protobuf message", request)`, requests),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("request", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "interface{}"),
+				parser.NewNameType("", "error"),
+			},
+		),
+	)
+}
+
+// update makes the codecs of a transport generated before them, or before the gRPC transport,
+// negotiate the formats. The helpers follow the codecs and the methods, they are generated
+// again.
+func (c *httpCodecs) update(f *parser.File, iface *parser.Interface, envelope *httpEnvelope) {
+	methods := map[string]parser.Method{}
+	for _, m := range iface.Methods {
+		methods[m.Name] = m
+	}
+	for k, v := range f.Methods {
+		if v.Struct.Type != "" || !strings.HasPrefix(v.Name, "decodeHTTP") {
+			continue
+		}
+		body := v.Body
+		if m, ok := methods[strings.TrimSuffix(strings.TrimPrefix(v.Name, "decodeHTTP"), "Req")]; ok {
+			if strings.Contains(body, "json.Unmarshal(body, &req)") {
+				body = strings.Replace(body, "if err = json.Unmarshal(body, &req); err != nil {", c.decodeReqFormat(m)+httpUnmarshalReq, 1)
+			} else if c.proto(m) && strings.Contains(body, httpUnmarshalReq) && !strings.Contains(body, "FormatProtobuf") {
+				body = strings.Replace(body, httpUnmarshalReq, c.decodeReqProto(m)+httpUnmarshalReq, 1)
+			}
+		}
+		if m, ok := methods[strings.TrimSuffix(strings.TrimPrefix(v.Name, "decodeHTTP"), "Res")]; ok {
+			if c.proto(m) && !strings.Contains(body, "FormatProtobuf") {
+				body = c.decodeResProto(m) + body
+			}
+		}
+		if body != v.Body {
+			logrus.Infof("Negotiating the formats in %s", v.Name)
+			f.Methods[k].Body = body
+			if len(v.Parameters) > 0 && v.Parameters[0].Name == "_" {
+				f.Methods[k].Parameters[0].Name = "ctx"
+			}
+		}
+	}
+	stale := func(body, negotiate, proto string) bool {
+		return !strings.Contains(body, negotiate) || strings.Contains(body, proto) != c.Protobuf
+	}
+	for k, v := range f.Methods {
+		if v.Struct.Type != "" {
+			continue
+		}
+		switch {
+		case v.Name == "encodeHTTPGenericResponse" && stale(v.Body, "responseFormat(", "protoResponse("):
+			f.Methods[k].Body = envelope.encodeResponse(c.name, c)
+		case v.Name == "encodeHTTPGenericRequest" && stale(v.Body, "contextFormat(", "protoRequest("):
+			f.Methods[k] = httpClientHelpers(c)[1]
+		case v.Name == "decodeHTTPGenericResponse" && !strings.Contains(v.Body, "contentFormat("):
+			f.Methods[k] = envelope.clientHelpers(c)[0]
+		case v.Name == "NewHTTPHandler" && !strings.Contains(v.Body, "PopulateRequestContext"):
+			f.Methods[k].Body = strings.Replace(v.Body, "options := []httptransport.ServerOption{",
+				"options := []httptransport.ServerOption{httptransport.ServerBefore(httptransport.PopulateRequestContext), ", 1)
+		case v.Name == "NewHTTPClient" && !strings.Contains(v.Body, "contextToAccept"):
+			f.Methods[k].Body = strings.Replace(v.Body, "options := []httptransport.ClientOption{",
+				"options := []httptransport.ClientOption{httptransport.ClientBefore(contextToAccept), ", 1)
+		}
+	}
+
+	helpers := c.helpers(iface)
+	names := map[string]bool{}
+	for _, v := range helpers {
+		names[v.Name] = true
+	}
+	list := []parser.Method{}
+	for _, v := range f.Methods {
+		if v.Struct.Type == "" && names[v.Name] {
+			continue
+		}
+		list = append(list, v)
+	}
+	f.Methods = append(list, helpers...)
+	constants := []parser.NamedTypeValue{}
+	for _, v := range f.Constants {
+		if !strings.HasPrefix(v.Name, "Format") {
+			constants = append(constants, v)
+		}
+	}
+	f.Constants = append(constants, c.constants()...)
+	for _, s := range c.structs() {
+		hasStruct := false
+		for _, v := range f.Structs {
+			if v.Name == s.Name {
+				hasStruct = true
+				break
+			}
+		}
+		if !hasStruct {
+			f.Structs = append(f.Structs, s)
+		}
+	}
+	// goimports keeps the versioned msgpack import once its codec is turned off
+	if !c.Msgpack {
+		imports := []parser.NamedTypeValue{}
+		for _, v := range f.Imports {
+			if v.Type != "\"github.com/vmihailenco/msgpack/v5\"" {
+				imports = append(imports, v)
+			}
+		}
+		f.Imports = imports
+	}
+	for _, v := range c.imports() {
+		hasImport := false
+		for _, vv := range f.Imports {
+			if vv.Type == v.Type {
+				hasImport = true
+				break
+			}
+		}
+		if !hasImport {
+			f.Imports = append(f.Imports, v)
+		}
+	}
+}
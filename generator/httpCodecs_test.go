@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+func TestHTTPCodecsLeftovers(t *testing.T) {
+	file := "acc/pkg/acctransport/http.go"
+	for _, codecs := range [][]string{{"json"}, {"json", "msgpack"}, {"json", "protobuf", "msgpack"}} {
+		testHTTPService(t, map[string]interface{}{"httptransport.codecs": codecs, "gk_force_override": true})
+		code := testRead(t, file)
+		assertNotContains(t, code, "header.HTTPToContext")
+		for _, v := range []string{"encodeHTTPGenericResponse", "errorEncoder"} {
+			if strings.Contains(code, "// "+v+"\n") {
+				t.Errorf("%s has no doc comment in\n%s", v, code)
+			}
+		}
+
+		// the update drops the commented out option of the routes generated before, the bodies
+		// are printed without their comments
+		legacy := strings.Replace(code, "ops := append(options, httptransport.ServerBefore(authHTTPToContext))",
+			"ops := append(options, httptransport.ServerBefore(authHTTPToContext))\n//ops = append(ops, httptransport.ServerBefore(header.HTTPToContext()))", -1)
+		if err := fs.Get().WriteFile(file, legacy, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewServiceUpdateGenerator().Generate("acc"); err != nil {
+			t.Fatal(err)
+		}
+		assertNotContains(t, testRead(t, file), "header.HTTPToContext")
+	}
+}
+
+func TestHTTPCodecs(t *testing.T) {
+	file := "acc/pkg/acctransport/http.go"
+	testHTTPService(t, map[string]interface{}{"httptransport.codecs": []string{"json"}})
+	code := testRead(t, file)
+	assertContains(t, code,
+		`FormatJSON string = "application/json"`,
+		`switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+			case "", "application/json":
+				return FormatJSON
+			}`,
+		`func marshal(format string, v interface{}) ([]byte, error) { return json.Marshal(v) }`,
+	)
+	assertNotContains(t, code, `FormatMsgpack`, `FormatProtobuf`, `msgpack/v5`, `protobuf/proto"`)
+
+	testHTTPService(t, map[string]interface{}{"httptransport.codecs": []string{"json", "msgpack"}})
+	code = testRead(t, file)
+	assertContains(t, code,
+		`FormatMsgpack string = "application/msgpack"`,
+		`case "application/msgpack", "application/x-msgpack", "application/vnd.msgpack":
+				return FormatMsgpack`,
+		`enc.SetCustomStructTag("json")`,
+		`dec.SetCustomStructTag("json")`,
+		`"github.com/vmihailenco/msgpack/v5"`,
+		// the responses follow the Accept header and the client asks for its format
+		`format := responseFormat(ctx)`,
+		`httptransport.ClientBefore(contextToAccept)`,
+	)
+
+	// the protobuf bodies need the gRPC codecs of the methods next to the http transport
+	testHTTPService(t, map[string]interface{}{"httptransport.codecs": []string{"json", "protobuf"}})
+	assertNotContains(t, testRead(t, file), `FormatProtobuf`)
+	testGRPCService(t, map[string]interface{}{"httptransport.codecs": []string{"json", "protobuf"}})
+	if err := NewAddHttpGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	code = testRead(t, file)
+	assertContains(t, code,
+		`FormatProtobuf string = "application/x-protobuf"`,
+		// the client converts the protobuf replies with the gRPC decoders
+		`if contentFormat(r.Header.Get("Content-Type")) == FormatProtobuf {
+			reply := &accpb.GetRes{}`,
+		`return decodeGRPCGetRes(ctx, reply)`,
+		`reply, err := protoResponse(ctx, response)`,
+		`return proto.Unmarshal(data, m)`,
+	)
+
+	testProject(t, map[string]interface{}{"httptransport.codecs": []string{"json", "yaml"}})
+	if _, err := loadHTTPCodecs("acc"); err == nil || !strings.Contains(err.Error(), "The http codec `yaml` is not supported") {
+		t.Errorf("got %v, want the unsupported codec", err)
+	}
+}
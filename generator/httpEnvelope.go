@@ -0,0 +1,416 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/parser"
+)
+
+// httpEnvelopes are the styles of the responses of the http transport: `template` wraps the
+// responses and the errors in the `httptransport.envelope_template` object, `none` sends the
+// responses as they are and the errors as `{"code","msg"}`, `problem` sends the errors as
+// RFC 7807 problem details.
+var httpEnvelopes = []string{"template", "none", "problem"}
+
+// httpEnvelopeField is a field of the envelope template, the value is one of the placeholders
+// of httpEnvelopePlaceholders or a constant.
+type httpEnvelopeField struct {
+	Name  string      `mapstructure:"name"`
+	Value interface{} `mapstructure:"value"`
+}
+
+// httpEnvelopePlaceholders are the values of the envelope and the variables holding them.
+var httpEnvelopePlaceholders = map[string]string{
+	"{{.Status}}": "status",
+	"{{.Code}}":   "code",
+	"{{.Msg}}":    "msg",
+	"{{.Data}}":   "data",
+}
+
+// httpEnvelope is the response style of `httptransport.envelope`.
+type httpEnvelope struct {
+	Style  string
+	Fields []httpEnvelopeField
+	// svc is the package of the service, its error catalog gives the codes of the errors.
+	svc string
+}
+
+func loadHTTPEnvelope(st *ServiceTypes) (*httpEnvelope, error) {
+	e := &httpEnvelope{Style: viper.GetString("httptransport.envelope"), svc: st.Package}
+	if e.Style == "" {
+		e.Style = "template"
+	}
+	supported := false
+	for _, v := range httpEnvelopes {
+		if v == e.Style {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("The http envelope `%s` is not supported, use %s", e.Style, strings.Join(httpEnvelopes, ", "))
+	}
+	if e.Style != "template" {
+		return e, nil
+	}
+	if err := viper.UnmarshalKey("httptransport.envelope_template", &e.Fields); err != nil {
+		return nil, fmt.Errorf("`httptransport.envelope_template` is invalid: %s", err)
+	}
+	if len(e.Fields) == 0 {
+		e.Fields = []httpEnvelopeField{
+			{Name: "code", Value: "{{.Code}}"},
+			{Name: "msg", Value: "{{.Msg}}"},
+			{Name: "data", Value: "{{.Data}}"},
+		}
+	}
+	if e.field("{{.Data}}") == "" {
+		return nil, fmt.Errorf("`httptransport.envelope_template` has no `{{.Data}}` field")
+	}
+	return e, nil
+}
+
+// field is the name of the field of the template holding the placeholder.
+func (e *httpEnvelope) field(placeholder string) string {
+	for _, v := range e.Fields {
+		if s, ok := v.Value.(string); ok && s == placeholder {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// httpLiteral is the Go constant of a value of gk.json.
+func httpLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		if v == math.Trunc(v) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// contentType is the content type of the errors.
+func (e *httpEnvelope) contentType() string {
+	if e.Style == "problem" {
+		return "application/problem+json"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// encodeResponse is the body of encodeHTTPGenericResponse, only the JSON responses are
+// wrapped in the envelope.
+func (e *httpEnvelope) encodeResponse(name string, codecs *httpCodecs) string {
+	response := "response"
+	if e.Style == "template" {
+		response = `envelope(http.StatusOK, 0, "success", response)`
+	}
+	return fmt.Sprintf(`if f, ok := response.(%sendpoint.Failer); ok && f.Failed() != nil {
+			errorEncoder(ctx, f.Failed(), w)
+			return nil
+		}
+		`, name) + codecs.encodeResponse(fmt.Sprintf(`w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		return json.NewEncoder(w).Encode(%s)`, response))
+}
+
+// errorEncoder is the body of errorEncoder, the errors are sent with the status of their code
+// and the invalid requests with their field violations.
+func (e *httpEnvelope) errorEncoder(name string) string {
+	body := "errorWrapper{Code: int(code), Msg: err.Error(), Details: details}"
+	switch e.Style {
+	case "template":
+		body = "envelope(status, int(code), err.Error(), details)"
+	case "problem":
+		body = `problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Detail: err.Error(), Code: int(code), Errors: details}`
+	}
+	return fmt.Sprintf(`code := errorCode(err)
+		status := httpStatusFromCode(code)
+		var details []%sendpoint.FieldViolation
+		var verr *%sendpoint.ValidationError
+		if errors.As(err, &verr) {
+			details = verr.Violations
+		}
+		w.Header().Set("Content-Type", %q)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(%s)`, name, name, e.contentType(), body)
+}
+
+// httpAuthErrors are the errors of the token checks, the unauthenticated and permission
+// denied errors of the error catalog when it has them.
+func httpAuthErrors(name string, st *ServiceTypes) []parser.NamedTypeValue {
+	var vars []parser.NamedTypeValue
+	for _, v := range []struct{ name, msg, catalog string }{
+		{"ErrJWTTokenIsExpired", "JWT Token is expired", "ErrUnauthenticated"},
+		{"ErrJWTTokenNotPassParse", "token up for parsing was not passed through the context", "ErrUnauthenticated"},
+		{"ErrJWTTokenIsMalformed", "JWT Token is malformed", "ErrUnauthenticated"},
+		{"ErrAccessRestricted", "access restricted", "ErrPermissionDenied"},
+	} {
+		value := fmt.Sprintf("errors.New(%q)", v.msg)
+		if catalogHas(name, v.catalog) {
+			value = fmt.Sprintf("fmt.Errorf(\"%s: %%w\", %s.%s)", v.msg, st.Package, v.catalog)
+		}
+		vars = append(vars, parser.NewNameTypeValue(v.name, "", value))
+	}
+	return vars
+}
+
+// structs are the bodies of the errors, and of the responses for the client.
+func (e *httpEnvelope) structs(name string) []parser.Struct {
+	errors := parser.NewNameType("Errors", fmt.Sprintf("[]%sendpoint.FieldViolation", name))
+	errors.Tag = "`json:\"errors,omitempty\"`"
+	switch e.Style {
+	case "none":
+		details := parser.NewNameType("Details", fmt.Sprintf("[]%sendpoint.FieldViolation", name))
+		details.Tag = "`json:\"details,omitempty\"`"
+		return []parser.Struct{parser.NewStructWithComment(
+			"errorWrapper",
+			`errorWrapper is the body of the errors.`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("Code", "int"),
+				parser.NewNameType("Msg", "string"),
+				details,
+			}),
+		}
+	case "problem":
+		return []parser.Struct{parser.NewStructWithComment(
+			"problem",
+			`problem is an RFC 7807 problem detail, the code of the error catalog is an extension.`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("Type", "string"),
+				parser.NewNameType("Title", "string"),
+				parser.NewNameType("Status", "int"),
+				parser.NewNameType("Detail", "string"),
+				parser.NewNameType("Code", "int"),
+				errors,
+			}),
+		}
+	case "template":
+		vars := []parser.NamedTypeValue{}
+		for _, v := range []struct{ placeholder, name, tp string }{
+			{"{{.Code}}", "Code", "int"},
+			{"{{.Msg}}", "Msg", "string"},
+			{"{{.Data}}", "Data", "json.RawMessage"},
+		} {
+			if field := e.field(v.placeholder); field != "" {
+				f := parser.NewNameType(v.name, v.tp)
+				f.Tag = fmt.Sprintf("`json:\"%s\"`", field)
+				vars = append(vars, f)
+			}
+		}
+		return []parser.Struct{parser.NewStructWithComment(
+			"responseWrapper",
+			`responseWrapper is the envelope of the responses and the errors.`,
+			vars,
+		)}
+	}
+	return nil
+}
+
+// helpers give the codes and the statuses of the errors, and wrap the responses in the
+// envelope template.
+func (e *httpEnvelope) helpers() []parser.Method {
+	svc := e.svc
+	helpers := []parser.Method{
+		parser.NewMethodWithComment(
+			"errorCode",
+			`errorCode is the code of the error in the error catalog of the service, the errors
+			of the token checks are unauthenticated or permission denied.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`switch {
+			case errors.Is(err, jwt.ErrTokenContextMissing), errors.Is(err, jwt.ErrTokenInvalid), errors.Is(err, jwt.ErrTokenExpired),
+				errors.Is(err, jwt.ErrTokenMalformed), errors.Is(err, jwt.ErrTokenNotActive), errors.Is(err, jwt.ErrUnexpectedSigningMethod),
+				errors.Is(err, ErrJWTTokenIsExpired), errors.Is(err, ErrJWTTokenNotPassParse), errors.Is(err, ErrJWTTokenIsMalformed):
+				return %s.CodeUnauthenticated
+			case errors.Is(err, ErrAccessRestricted):
+				return %s.CodePermissionDenied
+			}
+			return %s.ErrorCode(err)`, svc, svc, svc),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("err", "error"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", svc+".Code"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"httpStatusFromCode",
+			`httpStatusFromCode is the HTTP status of a code of the error catalog.`,
+			parser.NamedTypeValue{},
+			strings.NewReplacer("svc.", svc+".").Replace(`switch code {
+			case svc.CodeCanceled:
+				return 499
+			case svc.CodeInvalidArgument, svc.CodeFailedPrecondition, svc.CodeOutOfRange:
+				return http.StatusBadRequest
+			case svc.CodeDeadlineExceeded:
+				return http.StatusGatewayTimeout
+			case svc.CodeNotFound:
+				return http.StatusNotFound
+			case svc.CodeAlreadyExists, svc.CodeAborted:
+				return http.StatusConflict
+			case svc.CodePermissionDenied:
+				return http.StatusForbidden
+			case svc.CodeUnauthenticated:
+				return http.StatusUnauthorized
+			case svc.CodeResourceExhausted:
+				return http.StatusTooManyRequests
+			case svc.CodeUnimplemented:
+				return http.StatusNotImplemented
+			case svc.CodeUnavailable:
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusInternalServerError`),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("code", svc+".Code"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "int"),
+			},
+		),
+	}
+	if e.Style != "template" {
+		return helpers
+	}
+	fields := ""
+	for _, v := range e.Fields {
+		value := httpLiteral(v.Value)
+		if s, ok := v.Value.(string); ok && httpEnvelopePlaceholders[s] != "" {
+			value = httpEnvelopePlaceholders[s]
+		}
+		fields += fmt.Sprintf("\n%q: %s,", v.Name, value)
+	}
+	return append(helpers, parser.NewMethodWithComment(
+		"envelope",
+		`envelope wraps the responses and the errors in the envelope of the service.`,
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`return map[string]interface{}{%s
+			}`, fields),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("status", "int"),
+			parser.NewNameType("code", "int"),
+			parser.NewNameType("msg", "string"),
+			parser.NewNameType("data", "interface{}"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "interface{}"),
+		},
+	))
+}
+
+// clientHelpers decode the responses of encodeHTTPGenericResponse and turn the errors of
+// errorEncoder back into the errors of the service.
+func (e *httpEnvelope) clientHelpers(codecs *httpCodecs) []parser.Method {
+	svc := e.svc
+	decode := `if r.StatusCode < 200 || r.StatusCode > 299 {
+				return decodeHTTPError(r, body)
+			}
+			if len(body) == 0 {
+				return nil
+			}
+			return json.Unmarshal(body, response)`
+	decodeError := `res := errorWrapper{}
+			if err := json.Unmarshal(body, &res); err != nil {
+				return fmt.Errorf("%s: %s", r.Status, bytes.TrimSpace(body))
+			}
+			return errorFromCode(` + svc + `.Code(res.Code), res.Msg)`
+	switch e.Style {
+	case "problem":
+		decodeError = `res := problem{}
+			if err := json.Unmarshal(body, &res); err != nil {
+				return fmt.Errorf("%s: %s", r.Status, bytes.TrimSpace(body))
+			}
+			return errorFromCode(` + svc + `.Code(res.Code), res.Detail)`
+	case "template":
+		code, msg, failed := svc+".CodeUnknown", "r.Status", "r.StatusCode < 200 || r.StatusCode > 299"
+		if e.field("{{.Code}}") != "" {
+			code = svc + ".Code(res.Code)"
+			// the transports generated before the statuses send the errors with 200
+			failed += " || res.Code != 0"
+		}
+		if e.field("{{.Msg}}") != "" {
+			msg = "res.Msg"
+		}
+		decode = fmt.Sprintf(`res := responseWrapper{}
+			if err := json.Unmarshal(body, &res); err != nil {
+				return decodeHTTPError(r, body)
+			}
+			if %s {
+				return decodeHTTPError(r, body)
+			}
+			if len(res.Data) == 0 {
+				return nil
+			}
+			return json.Unmarshal(res.Data, response)`, failed)
+		decodeError = fmt.Sprintf(`res := responseWrapper{}
+			if err := json.Unmarshal(body, &res); err != nil {
+				return fmt.Errorf("%%s: %%s", r.Status, bytes.TrimSpace(body))
+			}
+			return errorFromCode(%s, %s)`, code, msg)
+	}
+	return []parser.Method{
+		parser.NewMethodWithComment(
+			"decodeHTTPGenericResponse",
+			`decodeHTTPGenericResponse decodes the response sent by encodeHTTPGenericResponse in
+			its format, the errors sent by errorEncoder are returned as errors.`,
+			parser.NamedTypeValue{},
+			`body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return err
+			}
+			`+codecs.decodeResponse()+decode,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("r", "*http.Response"),
+				parser.NewNameType("response", "interface{}"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"decodeHTTPError",
+			`decodeHTTPError is the error of a response sent by errorEncoder.`,
+			parser.NamedTypeValue{},
+			decodeError,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("r", "*http.Response"),
+				parser.NewNameType("body", "[]byte"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"errorFromCode",
+			`errorFromCode is the error of the token checks with the message, the error of the
+			catalog of the service with the code and the message or else a new error.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`for _, err := range []error{ErrJWTTokenIsExpired, ErrJWTTokenNotPassParse, ErrJWTTokenIsMalformed, ErrAccessRestricted} {
+				if msg == err.Error() {
+					return err
+				}
+			}
+			if err := %s.ErrorFromCode(code, msg); err != nil {
+				return err
+			}
+			return errors.New(msg)`, svc),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("code", svc+".Code"),
+				parser.NewNameType("msg", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+	}
+}
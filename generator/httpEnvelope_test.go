@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTTPEnvelopeErrors(t *testing.T) {
+	for _, style := range httpEnvelopes {
+		testHTTPService(t, map[string]interface{}{"httptransport.envelope": style})
+		code := testRead(t, "acc/pkg/acctransport/http.go")
+		assertContains(t, code,
+			`ErrJWTTokenIsExpired = fmt.Errorf("JWT Token is expired: %w", accservice.ErrUnauthenticated)`,
+			`ErrAccessRestricted = errors.New("access restricted")`,
+		)
+		assertNotContains(t, code, "rpc error")
+		// the body of the errors without an envelope
+		if style == "none" {
+			assertContains(t, code, "type errorWrapper struct", "res := errorWrapper{}")
+		} else {
+			assertNotContains(t, code, "errorWrapper")
+		}
+	}
+}
+
+func TestHTTPEnvelope(t *testing.T) {
+	// the default template
+	testHTTPService(t, map[string]interface{}{})
+	code := testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, code,
+		`return json.NewEncoder(w).Encode(envelope(http.StatusOK, 0, "success", response))`,
+		`json.NewEncoder(w).Encode(envelope(status, int(code), err.Error(), details))`,
+		`return map[string]interface{}{"code": code, "msg": msg, "data": data,}`,
+		"Code int `json:\"code\"`",
+		"Data json.RawMessage `json:\"data\"`",
+		`if r.StatusCode < 200 || r.StatusCode > 299 || res.Code != 0 {`,
+		`return errorFromCode(accservice.Code(res.Code), res.Msg)`,
+	)
+
+	// a template without code nor message, the constants are kept
+	testHTTPService(t, map[string]interface{}{
+		"httptransport.envelope_template": []map[string]interface{}{
+			{"name": "status", "value": "{{.Status}}"},
+			{"name": "ok", "value": true},
+			{"name": "version", "value": 2},
+			{"name": "payload", "value": "{{.Data}}"},
+		},
+	})
+	code = testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, code,
+		`return map[string]interface{}{"status": status, "ok": true, "version": 2, "payload": data,}`,
+		"type responseWrapper struct { Data json.RawMessage `json:\"payload\"` }",
+		`if r.StatusCode < 200 || r.StatusCode > 299 {`,
+		`return errorFromCode(accservice.CodeUnknown, r.Status)`,
+	)
+
+	testHTTPService(t, map[string]interface{}{"httptransport.envelope": "none"})
+	code = testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, code,
+		`return json.NewEncoder(w).Encode(response)`,
+		`json.NewEncoder(w).Encode(errorWrapper{Code: int(code), Msg: err.Error(), Details: details})`,
+	)
+	assertNotContains(t, code, `func envelope(`, `responseWrapper`)
+
+	testHTTPService(t, map[string]interface{}{"httptransport.envelope": "problem"})
+	code = testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, code,
+		`w.Header().Set("Content-Type", "application/problem+json")`,
+		`Title: http.StatusText(status), Status: status, Detail: err.Error(), Code: int(code), Errors: details}`,
+		`return errorFromCode(accservice.Code(res.Code), res.Detail)`,
+	)
+	assertNotContains(t, code, `func envelope(`)
+
+	// the errors of the settings
+	st := &ServiceTypes{Package: "accservice"}
+	testProject(t, map[string]interface{}{"httptransport.envelope": "wrapped"})
+	if _, err := loadHTTPEnvelope(st); err == nil || !strings.Contains(err.Error(), "use template, none, problem") {
+		t.Errorf("got %v, want the unsupported envelope", err)
+	}
+	testProject(t, map[string]interface{}{
+		"httptransport.envelope_template": []map[string]interface{}{{"name": "code", "value": "{{.Code}}"}},
+	})
+	if _, err := loadHTTPEnvelope(st); err == nil || !strings.Contains(err.Error(), "has no `{{.Data}}` field") {
+		t.Errorf("got %v, want the missing data", err)
+	}
+}
+
+func TestHTTPLiteral(t *testing.T) {
+	for _, c := range []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "nil"},
+		{"ok", `"ok"`},
+		{false, "false"},
+		{float64(200), "200"},
+		{0.5, "0.5"},
+	} {
+		if got := httpLiteral(c.value); got != c.want {
+			t.Errorf("httpLiteral(%#v) = %s, want %s", c.value, got, c.want)
+		}
+	}
+}
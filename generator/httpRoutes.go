@@ -0,0 +1,487 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	"github.com/liuchamp/gk/utils"
+)
+
+// httpRouters are the routers the http transport registers its routes on, `http` is the
+// ServeMux of net/http with the patterns of go 1.22.
+var httpRouters = []string{"http", "mux", "chi"}
+
+// httpVerbs give the verb of the methods without a `gk:http` directive by the first word of
+// their name, the other methods are served with POST.
+var httpVerbs = []struct {
+	Verb     string
+	Prefixes []string
+}{
+	{"GET", []string{"Get", "Find", "List", "Query", "Search", "Count", "Fetch"}},
+	{"PUT", []string{"Update", "Put", "Set", "Replace"}},
+	{"PATCH", []string{"Patch", "Modify"}},
+	{"DELETE", []string{"Delete", "Remove", "Del"}},
+}
+
+var goModVersion = regexp.MustCompile(`(?m)^go\s+(\d+)\.(\d+)`)
+
+// httpOldGo is the go version of the go.mod of the project when it is older than 1.22, the
+// ServeMux of net/http matches the verbs and the path variables of the routes since 1.22 and
+// serves every route with 404 before. The projects without a go.mod are built by a recent go.
+func httpOldGo() string {
+	gomod, err := fs.Get().ReadFile("go.mod")
+	if err != nil {
+		return ""
+	}
+	v := goModVersion.FindStringSubmatch(gomod)
+	if v == nil {
+		return ""
+	}
+	major, _ := strconv.Atoi(v[1])
+	minor, _ := strconv.Atoi(v[2])
+	if major > 1 || (major == 1 && minor >= 22) {
+		return ""
+	}
+	return v[1] + "." + v[2]
+}
+
+// httpRouter is the router of `httptransport.router`, the routes of a project older than go
+// 1.22 are registered on gorilla/mux instead of the ServeMux of net/http.
+func httpRouter() (string, error) {
+	router := viper.GetString("httptransport.router")
+	for _, v := range httpRouters {
+		if v != router {
+			continue
+		}
+		if old := httpOldGo(); router == "http" && old != "" {
+			logrus.Warnf("The go.mod of the project requires go %s, the ServeMux of net/http matches the routes since go 1.22: the routes are registered on gorilla/mux", old)
+			return "mux", nil
+		}
+		return router, nil
+	}
+	return "", fmt.Errorf("The http router `%s` is not supported, use %s", router, strings.Join(httpRouters, ", "))
+}
+
+// httpHandlerComment is the comment of NewHTTPHandler, it tells the go version the ServeMux
+// of net/http needs.
+func httpHandlerComment(router string) string {
+	comment := `NewHTTPHandler returns a handler that makes a set of endpoints available on
+			 predefined paths.`
+	if router == "http" {
+		comment += `
+			 The routes use the patterns of the ServeMux of go 1.22, the go.mod of the module
+			 needs go 1.22 or later or every route is served with 404.`
+	}
+	return comment
+}
+
+// httpFileRouter is the router an existing http transport registers its routes on.
+func httpFileRouter(handler parser.Method) string {
+	switch {
+	case strings.Contains(handler.Body, "mux.NewRouter()"):
+		return "mux"
+	case strings.Contains(handler.Body, "chi.NewRouter()"):
+		return "chi"
+	}
+	return "http"
+}
+
+// httpRouterImports are the packages of the routers.
+var httpRouterImports = map[string]string{
+	"mux": "\"github.com/gorilla/mux\"",
+	"chi": "\"github.com/go-chi/chi/v5\"",
+}
+
+// httpNewRouter is the expression making the router.
+func httpNewRouter(router string) string {
+	switch router {
+	case "mux":
+		return "mux.NewRouter()"
+	case "chi":
+		return "chi.NewRouter()"
+	}
+	return "http.NewServeMux()"
+}
+
+// httpParam is a parameter of a method and where the request carries it.
+type httpParam struct {
+	// Field is the field of the endpoint request.
+	Field string
+	Type  string
+	// In is path, query, header or body.
+	In  string
+	Key string
+}
+
+// httpRoute is the verb and the path template of a method, `{name}` segments are the path
+// parameters.
+type httpRoute struct {
+	Verb string
+	Path string
+	// Body is true when the parameters are read from the body in the negotiated format, the
+	// multipart forms and the streamed bodies are not.
+	Body      bool
+	Multipart bool
+	Params    []httpParam
+	// Download is the streamed response of the method, nil for the encoded ones.
+	Download *httpDownload
+}
+
+var httpPathVariable = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(\.\.\.)?\}`)
+
+// newHTTPRoute is the route of the first `gk:http` directive of the method, or the verb its
+// name gives on `/<kebab-name>`. The path variables and the `gk:header <param> <Header>`
+// directives bind their parameters, the others are read from the JSON body or, for the verbs
+// without a body, from the query. The methods with an `io.Reader` parameter stream it as the
+// request body, the `gk:http multipart` ones read their parameters from a multipart form.
+func newHTTPRoute(m parser.Method, st *ServiceTypes) httpRoute {
+	route := httpRoute{Verb: "POST", Path: "/" + utils.ToLowerHyphenCase(m.Name)}
+	rules := m.HTTPRules()
+	if len(rules) > 0 {
+		route.Verb, route.Path = rules[0].Method, rules[0].Path
+	} else {
+		for _, v := range httpVerbs {
+			for _, p := range v.Prefixes {
+				if strings.HasPrefix(m.Name, p) && (len(m.Name) == len(p) || strings.ToUpper(m.Name[len(p):len(p)+1]) == m.Name[len(p):len(p)+1]) {
+					route.Verb = v.Verb
+				}
+			}
+		}
+	}
+	route.Body = route.Verb == "POST" || route.Verb == "PUT" || route.Verb == "PATCH"
+	if len(rules) > 0 && rules[0].Body != "" {
+		route.Body = rules[0].Body != "none"
+	}
+	route.Multipart = httpMultipart(m)
+	stream := route.Multipart
+	for _, p := range m.Parameters {
+		stream = stream || httpReader(p.Type)
+	}
+	if stream {
+		if !route.Body {
+			logrus.Warnf("The files of '%s' are sent in the request body, the method is served with POST", m.Name)
+			route.Verb = "POST"
+		}
+		route.Body = false
+	}
+
+	headers := map[string]string{}
+	for _, v := range m.Directives("header") {
+		if len(v) == 2 {
+			headers[v[0]] = v[1]
+		}
+	}
+	route.Download = newHTTPDownload(m, st, headers)
+	streamed := false
+	vars := map[string]bool{}
+	for _, v := range httpPathVariable.FindAllStringSubmatch(route.Path, -1) {
+		vars[v[1]] = true
+	}
+	for _, p := range m.Parameters {
+		if p.Type == "context.Context" {
+			continue
+		}
+		param := httpParam{Field: utils.ToUpperFirstCamelCase(p.Name), Type: st.Qualify(p.Type), In: "body", Key: utils.ToLowerSnakeCase(p.Name)}
+		if httpReader(p.Type) {
+			// the reader is the rest of the body, a request has one
+			if streamed {
+				logrus.Warnf("The parameter '%s' of '%s' can not be streamed after another one and is left empty", p.Name, m.Name)
+				continue
+			}
+			streamed, param.In = true, "stream"
+			if route.Multipart {
+				param.In = "file"
+			}
+			route.Params = append(route.Params, param)
+			continue
+		}
+		if route.Multipart && p.Type == "[]byte" {
+			param.In = "file"
+			route.Params = append(route.Params, param)
+			continue
+		}
+		for v := range vars {
+			if v == p.Name || v == param.Key || strings.EqualFold(v, p.Name) {
+				param.In, param.Key = "path", v
+				delete(vars, v)
+				break
+			}
+		}
+		if h, ok := headers[p.Name]; ok && param.In == "body" {
+			param.In, param.Key = "header", h
+		}
+		if param.In == "body" && route.Multipart {
+			param.In = "form"
+		}
+		if param.In == "body" && !route.Body {
+			param.In = "query"
+		}
+		if param.In != "body" && param.In != "form" && !httpBindable(param, st) {
+			if len(rules) == 0 && param.In == "query" && !stream {
+				logrus.Infof("The parameter '%s' of '%s' can not be read from the query, the method is served with POST", p.Name, m.Name)
+				m.Comment = "// gk:http POST " + route.Path + "\n" + m.Comment
+				return newHTTPRoute(m, st)
+			}
+			logrus.Warnf("The parameter '%s' of '%s' can not be read from the %s and is left empty", p.Name, m.Name, param.In)
+			continue
+		}
+		route.Params = append(route.Params, param)
+	}
+	for v := range vars {
+		logrus.Warnf("The path variable '%s' of '%s' does not match a parameter", v, m.Name)
+	}
+	return route
+}
+
+// httpScalar is the conversion of a string to the underlying type of a parameter.
+func httpScalar(typeName string, st *ServiceTypes) (underlying string, ok bool) {
+	underlying = typeName
+	if st != nil {
+		if t, found := st.Types[strings.TrimPrefix(typeName, st.Package+".")]; found {
+			underlying = t
+		}
+	}
+	switch underlying {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "time.Duration", "time.Time":
+		return underlying, true
+	}
+	return underlying, false
+}
+
+func httpBindable(p httpParam, st *ServiceTypes) bool {
+	if strings.HasPrefix(p.Type, "[]") && p.Type != "[]byte" {
+		if p.In != "query" {
+			return false
+		}
+		_, ok := httpScalar(strings.TrimPrefix(p.Type, "[]"), st)
+		return ok
+	}
+	_, ok := httpScalar(p.Type, st)
+	return ok
+}
+
+// httpParse is the statement parsing `s` into `v`, the errors are bad requests.
+func httpParse(underlying, key string) string {
+	return httpParseOr(underlying, fmt.Sprintf("badRequest(%q, err)", key))
+}
+
+// httpParseOr is the statement parsing `s` into `v`, returning the error expression when it
+// fails.
+func httpParseOr(underlying, fail string) string {
+	parse := ""
+	switch underlying {
+	case "bool":
+		parse = "strconv.ParseBool(s)"
+	case "int", "int64":
+		parse = "strconv.ParseInt(s, 10, 64)"
+	case "int8", "int16", "int32":
+		parse = fmt.Sprintf("strconv.ParseInt(s, 10, %s)", strings.TrimPrefix(underlying, "int"))
+	case "uint", "uint64":
+		parse = "strconv.ParseUint(s, 10, 64)"
+	case "uint8", "uint16", "uint32":
+		parse = fmt.Sprintf("strconv.ParseUint(s, 10, %s)", strings.TrimPrefix(underlying, "uint"))
+	case "float32", "float64":
+		parse = fmt.Sprintf("strconv.ParseFloat(s, %s)", strings.TrimPrefix(underlying, "float"))
+	case "time.Duration":
+		parse = "time.ParseDuration(s)"
+	case "time.Time":
+		parse = "time.Parse(time.RFC3339, s)"
+	}
+	return fmt.Sprintf(`v, err := %s
+		if err != nil {
+			return nil, %s
+		}`, parse, fail)
+}
+
+// httpConvert converts the parsed value to the type of the parameter when they differ.
+func httpConvert(typeName, underlying, expr string) string {
+	parsed := underlying
+	switch {
+	case strings.HasPrefix(underlying, "int"):
+		parsed = "int64"
+	case strings.HasPrefix(underlying, "uint"):
+		parsed = "uint64"
+	case strings.HasPrefix(underlying, "float"):
+		parsed = "float64"
+	}
+	if typeName == parsed {
+		return expr
+	}
+	return typeName + "(" + expr + ")"
+}
+
+// httpBinding is the code setting a parameter from the path, the query, the headers or the
+// values of a multipart form, the form values of the other types are JSON.
+func httpBinding(p httpParam, st *ServiceTypes) string {
+	value, values := "", "q"
+	switch p.In {
+	case "path":
+		value = fmt.Sprintf("pathVar(r, %q)", p.Key)
+	case "query":
+		value = fmt.Sprintf("q.Get(%q)", p.Key)
+	case "header":
+		value = fmt.Sprintf("r.Header.Get(%q)", p.Key)
+	case "form":
+		value, values = fmt.Sprintf("form.Get(%q)", p.Key), "form"
+		if !httpBindable(httpParam{Type: p.Type, In: "query"}, st) {
+			return fmt.Sprintf(`if s := %s; s != "" {
+				if err := json.Unmarshal([]byte(s), &req.%s); err != nil {
+					return nil, badRequest(%q, err)
+				}
+			}`, value, p.Field, p.Key)
+		}
+	}
+	if strings.HasPrefix(p.Type, "[]") {
+		elem := strings.TrimPrefix(p.Type, "[]")
+		underlying, _ := httpScalar(elem, st)
+		if underlying == "string" {
+			return fmt.Sprintf(`for _, s := range %s[%q] {
+				req.%s = append(req.%s, %s)
+			}`, values, p.Key, p.Field, p.Field, httpConvert(elem, underlying, "s"))
+		}
+		return fmt.Sprintf(`for _, s := range %s[%q] {
+			%s
+			req.%s = append(req.%s, %s)
+		}`, values, p.Key, httpParse(underlying, p.Key), p.Field, p.Field, httpConvert(elem, underlying, "v"))
+	}
+	underlying, _ := httpScalar(p.Type, st)
+	if underlying == "string" {
+		return fmt.Sprintf(`if s := %s; s != "" {
+			req.%s = %s
+		}`, value, p.Field, httpConvert(p.Type, underlying, "s"))
+	}
+	return fmt.Sprintf(`if s := %s; s != "" {
+		%s
+		req.%s = %s
+	}`, value, httpParse(underlying, p.Key), p.Field, httpConvert(p.Type, underlying, "v"))
+}
+
+// httpDecodeReqBody is the body of the decoder of the method request.
+func httpDecodeReqBody(name string, m parser.Method, route httpRoute, st *ServiceTypes, codecs *httpCodecs) string {
+	body := fmt.Sprintf("req := %sendpoint.%sReq{}", name, m.Name)
+	if route.Body {
+		body += `
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, badRequest("body", err)
+		}
+		if len(body) > 0 {
+			` + codecs.decodeReq(m) + `
+		}`
+	}
+	for _, p := range route.Params {
+		if p.In == "query" {
+			body += "\nq := r.URL.Query()"
+			break
+		}
+	}
+	for _, p := range httpURLParams(route) {
+		body += "\n" + httpBinding(p, st)
+	}
+	return body + httpDecodeReqStream(route, st) + "\nreturn req, nil"
+}
+
+// httpRegister registers the handler of the route on the router `m`.
+func httpRegister(router string, route httpRoute, handler string) string {
+	switch router {
+	case "mux":
+		return fmt.Sprintf("m.Methods(%q).Path(%q).Handler(%s)", route.Verb, route.Path, handler)
+	case "chi":
+		return fmt.Sprintf("m.Method(%q, %q, %s)", route.Verb, route.Path, handler)
+	}
+	return fmt.Sprintf("m.Handle(%q, %s)", route.Verb+" "+route.Path, handler)
+}
+
+// httpDecodeReq is the decoder of the method request.
+func httpDecodeReq(name string, m parser.Method, route httpRoute, st *ServiceTypes, codecs *httpCodecs) parser.Method {
+	return parser.NewMethodWithComment(
+		fmt.Sprintf("decodeHTTP%sReq", m.Name),
+		fmt.Sprintf(`decodeHTTP%sReq is a transport/http.DecodeRequestFunc that decodes the %s %s
+				request from its path, query, headers and body. Primarily useful in a server.`,
+			m.Name, route.Verb, route.Path),
+		parser.NamedTypeValue{},
+		httpDecodeReqBody(name, m, route, st, codecs),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("ctx", "context.Context"),
+			parser.NewNameType("r", "*http.Request"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "interface{}"),
+			parser.NewNameType("", "error"),
+		},
+	)
+}
+
+// httpRouteHandler is the block registering the server of the method.
+func httpRouteHandler(router string, m parser.Method, route httpRoute) string {
+	return fmt.Sprintf(`
+			{
+				ops := append(options, httptransport.ServerBefore(authHTTPToContext))
+				%s
+			}`, httpRegister(router, route, fmt.Sprintf(`httptransport.NewServer(
+				endpoints.%sEndpoint,
+				decodeHTTP%sReq,
+				%s,
+				ops...,
+				)`, m.Name, m.Name, httpEncodeResName(m, route))))
+}
+
+// httpErrorCheck sends the errors of the requests the transport could not decode with the
+// 400 status, it starts the error encoder.
+func httpErrorCheck(st *ServiceTypes) string {
+	return fmt.Sprintf(`if %s.ErrorCode(err) == %s.CodeInvalidArgument {
+				w.WriteHeader(http.StatusBadRequest)
+				s, _ := json.Marshal(errorWrapper{Code: http.StatusBadRequest, Msg: err.Error()})
+				w.Write(s)
+				return
+			}
+			`, st.Package, st.Package)
+}
+
+// httpHelpers read the path variables of the router and make the bad request errors.
+func httpHelpers(router string, st *ServiceTypes) []parser.Method {
+	pathVar := "return r.PathValue(name)"
+	switch router {
+	case "mux":
+		pathVar = "return mux.Vars(r)[name]"
+	case "chi":
+		pathVar = "return chi.URLParam(r, name)"
+	}
+	return []parser.Method{
+		parser.NewMethodWithComment(
+			"pathVar",
+			`pathVar is the value of a path variable of the route.`,
+			parser.NamedTypeValue{},
+			pathVar,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("r", "*http.Request"),
+				parser.NewNameType("name", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "string"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"badRequest",
+			`badRequest is the error of a request parameter that could not be decoded, it is
+			sent with the 400 status.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`return fmt.Errorf("%%s: %%v: %%w", param, err, %s.ErrInvalidArgument)`, st.Package),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("param", "string"),
+				parser.NewNameType("err", "error"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+	}
+}
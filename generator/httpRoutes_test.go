@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+// testHTTPService initiates the http transport of the acc service.
+func testHTTPService(t *testing.T, settings map[string]interface{}) {
+	t.Helper()
+	settings["gk_transport"] = "http"
+	testProject(t, settings)
+	testService(t, "acc", testAccMethods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHTTPRouterOfGoVersion(t *testing.T) {
+	for _, v := range []struct {
+		gomod  string
+		router string
+	}{
+		{"", "http"},
+		{"module acc\n\ngo 1.22\n", "http"},
+		{"module acc\n\ngo 1.23.1\n", "http"},
+		{"module acc\n\ngo 1.21\n", "mux"},
+		{"module acc\n\ngo 1.16\n", "mux"},
+	} {
+		testProject(t, nil)
+		if v.gomod != "" {
+			if err := fs.Get().WriteFile("go.mod", v.gomod, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		router, err := httpRouter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if router != v.router {
+			t.Errorf("the router of %q is %s, want %s", v.gomod, router, v.router)
+		}
+	}
+
+	// the ServeMux of net/http tells the go version it needs
+	testHTTPService(t, map[string]interface{}{})
+	assertContains(t, testRead(t, "acc/pkg/acctransport/http.go"), "needs go 1.22 or later", "m := http.NewServeMux()", `m.Handle("GET /get"`)
+}
+
+func TestHTTPInitUpdate(t *testing.T) {
+	// the updates overwrite the files instead of prompting
+	testHTTPService(t, map[string]interface{}{"gk_force_override": true})
+	files := []string{
+		"acc/pkg/acctransport/http.go",
+		"acc/pkg/acctransport/httpclient.go",
+		"acc/pkg/accendpoint/set.go",
+		"acc/pkg/accendpoint/middleware.go",
+	}
+	initialized := map[string]string{}
+	for _, v := range files {
+		initialized[v] = testRead(t, v)
+	}
+
+	// the update keeps the declarations of init and a second update changes nothing
+	if err := NewServiceUpdateGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	updated := map[string]string{}
+	for _, v := range files {
+		updated[v] = testRead(t, v)
+		assertSameDeclarations(t, initialized[v], updated[v])
+	}
+	if err := NewServiceUpdateGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range files {
+		if again := testRead(t, v); again != updated[v] {
+			t.Errorf("the second update changed %s from\n%s\nto\n%s", v, updated[v], again)
+		}
+	}
+
+	// a second init updates the existing transport
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range files {
+		if again := testRead(t, v); again != updated[v] {
+			t.Errorf("the second init changed %s from\n%s\nto\n%s", v, updated[v], again)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/liuchamp/gk/parser"
+	"github.com/liuchamp/gk/utils"
+)
+
+// httpReader tells if the parameter or the result is streamed in the body.
+func httpReader(typeName string) bool {
+	return typeName == "io.Reader" || typeName == "io.ReadCloser"
+}
+
+// httpMultipart tells if the method has a `gk:http multipart` directive, its parameters are
+// read from a multipart form.
+func httpMultipart(m parser.Method) bool {
+	for _, v := range m.Directives("http") {
+		for _, w := range v {
+			if w == "multipart" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// httpDownload is the response of a method returning an `io.Reader`, the reader is the body
+// and the other results are headers.
+type httpDownload struct {
+	// Field is the field of the endpoint response streamed in the body.
+	Field string
+	Type  string
+	// Headers are the results sent in the headers, `Content-Disposition` carries the filename.
+	Headers []httpParam
+}
+
+// newHTTPDownload is the download of the method, nil when it returns no reader. The `contentType`
+// and `filename` results are the Content-Type and the Content-Disposition of the body, the
+// `gk:header <result> <Header>` directives send the others.
+func newHTTPDownload(m parser.Method, st *ServiceTypes, headers map[string]string) *httpDownload {
+	var download *httpDownload
+	for _, v := range m.Results {
+		if httpReader(v.Type) {
+			download = &httpDownload{Field: utils.ToUpperFirstCamelCase(v.Name), Type: v.Type}
+			break
+		}
+	}
+	if download == nil {
+		return nil
+	}
+	for _, v := range m.Results {
+		field := utils.ToUpperFirstCamelCase(v.Name)
+		if isErrorResult(v) || field == download.Field {
+			continue
+		}
+		param := httpParam{Field: field, Type: st.Qualify(v.Type), In: "header", Key: headers[v.Name]}
+		if param.Key == "" {
+			switch utils.ToLowerSnakeCase(v.Name) {
+			case "content_type":
+				param.Key = "Content-Type"
+			case "filename", "file_name":
+				param.Key = "Content-Disposition"
+			}
+		}
+		underlying, _ := httpScalar(param.Type, st)
+		if param.Key == "" || httpReader(v.Type) || !httpBindable(param, st) ||
+			(param.Key == "Content-Disposition" && underlying != "string") {
+			logrus.Warnf("The result '%s' of '%s' can not be sent in the headers of the download and is left empty", v.Name, m.Name)
+			continue
+		}
+		download.Headers = append(download.Headers, param)
+	}
+	return download
+}
+
+// httpDecodeReqStream is the end of the decoder of the requests streaming their body or sending
+// a multipart form. The streamed file is the last part of the form, the parts after it are not
+// read.
+func httpDecodeReqStream(route httpRoute, st *ServiceTypes) string {
+	body, cases, forms := "", "", []httpParam{}
+	for _, p := range route.Params {
+		switch {
+		case p.In == "stream":
+			body += fmt.Sprintf("\nreq.%s = r.Body", p.Field)
+		case p.In == "file" && httpReader(p.Type):
+			body = fmt.Sprintf(`
+				if part.FormName() == %q {
+					req.%s = part
+					break
+				}`, p.Key, p.Field)
+		case p.In == "file":
+			cases += fmt.Sprintf(`
+				case %q:
+					b, err := ioutil.ReadAll(part)
+					if err != nil {
+						return nil, badRequest(%q, err)
+					}
+					req.%s = b`, p.Key, p.Key, p.Field)
+		case p.In == "form":
+			forms = append(forms, p)
+		}
+	}
+	if !route.Multipart {
+		return body
+	}
+	if len(forms) > 0 {
+		cases += `
+			default:
+				b, err := ioutil.ReadAll(part)
+				if err != nil {
+					return nil, badRequest(part.FormName(), err)
+				}
+				form.Add(part.FormName(), string(b))`
+	}
+	if cases != "" {
+		body += "\nswitch part.FormName() {" + cases + "\n}"
+	}
+	decode := `
+		mr, err := r.MultipartReader()
+		if err != nil {
+			return nil, badRequest("body", err)
+		}`
+	if len(forms) > 0 {
+		decode += "\nform := url.Values{}"
+	}
+	decode += `
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, badRequest("body", err)
+			}` + body + `
+		}`
+	for _, p := range forms {
+		decode += "\n" + httpBinding(p, st)
+	}
+	return decode
+}
+
+// httpEncodeResName is the encoder of the method response, the responses of the encoded results
+// use encodeHTTPGenericResponse.
+func httpEncodeResName(m parser.Method, route httpRoute) string {
+	if route.Download == nil {
+		return "encodeHTTPGenericResponse"
+	}
+	return fmt.Sprintf("encodeHTTP%sRes", m.Name)
+}
+
+// httpEncodeRes is the encoder of the download of the method, it copies the reader to the body.
+func httpEncodeRes(name string, m parser.Method, route httpRoute, st *ServiceTypes) parser.Method {
+	headers := ""
+	for _, p := range route.Download.Headers {
+		switch p.Key {
+		case "Content-Disposition":
+			headers += fmt.Sprintf(`
+				if res.%s != "" {
+					w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": %s}))
+				}`, p.Field, httpFormat(p.Type, "string", "res."+p.Field))
+		case "Content-Type":
+			headers += fmt.Sprintf(`
+				if res.%s != "" {
+					w.Header().Set("Content-Type", %s)
+				}`, p.Field, httpFormat(p.Type, "string", "res."+p.Field))
+		default:
+			underlying, _ := httpScalar(p.Type, st)
+			headers += fmt.Sprintf("\nw.Header().Set(%q, %s)", p.Key, httpFormat(p.Type, underlying, "res."+p.Field))
+		}
+	}
+	return parser.NewMethodWithComment(
+		fmt.Sprintf("encodeHTTP%sRes", m.Name),
+		fmt.Sprintf(`encodeHTTP%sRes is a transport/http.EncodeResponseFunc that streams the %s
+				of %s in the response body and closes it. Primarily useful in a server.`,
+			m.Name, utils.ToLowerFirstCamelCase(route.Download.Field), m.Name),
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`if f, ok := response.(%sendpoint.Failer); ok && f.Failed() != nil {
+				errorEncoder(ctx, f.Failed(), w)
+				return nil
+			}
+			res := response.(%sendpoint.%sRes)
+			if c, ok := res.%s.(io.Closer); ok {
+				defer c.Close()
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")%s
+			if res.%s == nil {
+				return nil
+			}
+			_, err := io.Copy(w, res.%s)
+			return err`, name, name, m.Name, route.Download.Field, headers, route.Download.Field, route.Download.Field),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("ctx", "context.Context"),
+			parser.NewNameType("w", "http.ResponseWriter"),
+			parser.NewNameType("response", "interface{}"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "error"),
+		},
+	)
+}
+
+// httpEncodeReqStream is the end of the encoder of the client requests streaming their body or
+// sending a multipart form, the readers are not closed.
+func httpEncodeReqStream(m parser.Method, route httpRoute) string {
+	if route.Multipart {
+		return fmt.Sprintf(`
+			pr, pw := io.Pipe()
+			form := multipart.NewWriter(pw)
+			go func() {
+				pw.CloseWithError(writeHTTP%sForm(form, req))
+			}()
+			r.Header.Set("Content-Type", form.FormDataContentType())
+			r.Body = pr
+			return nil`, m.Name)
+	}
+	for _, p := range route.Params {
+		if p.In == "stream" {
+			return fmt.Sprintf(`
+				r.Header.Set("Content-Type", "application/octet-stream")
+				if req.%s != nil {
+					r.Body = ioutil.NopCloser(req.%s)
+				}
+				return nil`, p.Field, p.Field)
+		}
+	}
+	return ""
+}
+
+// httpWriteForm writes the parameters of the multipart request, the values first and the
+// streamed file last.
+func httpWriteForm(name string, m parser.Method, route httpRoute, st *ServiceTypes) parser.Method {
+	check := func(call string) string {
+		return fmt.Sprintf(`
+			if err := %s; err != nil {
+				return err
+			}`, call)
+	}
+	body, stream := "", ""
+	for _, p := range route.Params {
+		switch {
+		case p.In == "form" && !httpBindable(httpParam{Type: p.Type, In: "query"}, st):
+			body += fmt.Sprintf(`
+				if b, err := json.Marshal(req.%s); err != nil {
+					return err
+				} else if err := form.WriteField(%q, string(b)); err != nil {
+					return err
+				}`, p.Field, p.Key)
+		case p.In == "form" && strings.HasPrefix(p.Type, "[]"):
+			elem := strings.TrimPrefix(p.Type, "[]")
+			underlying, _ := httpScalar(elem, st)
+			body += fmt.Sprintf(`
+				for _, v := range req.%s {%s
+				}`, p.Field, check(fmt.Sprintf("form.WriteField(%q, %s)", p.Key, httpFormat(elem, underlying, "v"))))
+		case p.In == "form":
+			underlying, _ := httpScalar(p.Type, st)
+			body += check(fmt.Sprintf("form.WriteField(%q, %s)", p.Key, httpFormat(p.Type, underlying, "req."+p.Field)))
+		case p.In == "file" && httpReader(p.Type):
+			stream = fmt.Sprintf(`
+				if req.%s != nil {%s
+				}`, p.Field, check(fmt.Sprintf("writeFormFile(form, %q, req.%s)", p.Key, p.Field)))
+		case p.In == "file":
+			body += check(fmt.Sprintf("writeFormFile(form, %q, bytes.NewReader(req.%s))", p.Key, p.Field))
+		}
+	}
+	return parser.NewMethodWithComment(
+		fmt.Sprintf("writeHTTP%sForm", m.Name),
+		fmt.Sprintf(`writeHTTP%sForm writes the parameters of %s in the multipart form, the
+				values first and the files last. Primarily useful in a client.`, m.Name, m.Name),
+		parser.NamedTypeValue{},
+		body+stream+"\nreturn form.Close()",
+		[]parser.NamedTypeValue{
+			parser.NewNameType("form", "*multipart.Writer"),
+			parser.NewNameType("req", fmt.Sprintf("%sendpoint.%sReq", name, m.Name)),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "error"),
+		},
+	)
+}
+
+// httpDecodeDownload is the body of the client decoder of the download of the method, the caller
+// closes the reader.
+func httpDecodeDownload(name string, m parser.Method, route httpRoute, st *ServiceTypes) string {
+	headers := ""
+	for _, p := range route.Download.Headers {
+		switch p.Key {
+		case "Content-Disposition":
+			headers += fmt.Sprintf(`
+				if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil {
+					res.%s = %s
+				}`, p.Field, httpConvert(p.Type, "string", `params["filename"]`))
+		default:
+			underlying, _ := httpScalar(p.Type, st)
+			if underlying == "string" {
+				headers += fmt.Sprintf(`
+					res.%s = %s`, p.Field, httpConvert(p.Type, underlying, fmt.Sprintf("r.Header.Get(%q)", p.Key)))
+				continue
+			}
+			headers += fmt.Sprintf(`
+				if s := r.Header.Get(%q); s != "" {
+					%s
+					res.%s = %s
+				}`, p.Key, httpParseOr(underlying, "err"), p.Field, httpConvert(p.Type, underlying, "v"))
+		}
+	}
+	return fmt.Sprintf(`if r.StatusCode < 200 || r.StatusCode > 299 {
+			defer r.Body.Close()
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return nil, err
+			}
+			return nil, decodeHTTPError(r, body)
+		}
+		res := %sendpoint.%sRes{%s: r.Body}%s
+		return res, nil`, name, m.Name, route.Download.Field, headers)
+}
+
+// httpStreamHelpers write the files of the multipart forms, nil when no method sends one.
+func httpStreamHelpers(iface *parser.Interface, st *ServiceTypes) []parser.Method {
+	for _, m := range iface.Methods {
+		if !newHTTPRoute(m, st).Multipart {
+			continue
+		}
+		return []parser.Method{
+			parser.NewMethodWithComment(
+				"writeFormFile",
+				`writeFormFile copies the file in a part of the multipart form.`,
+				parser.NamedTypeValue{},
+				`w, err := form.CreateFormFile(key, key)
+				if err != nil {
+					return err
+				}
+				_, err = io.Copy(w, file)
+				return err`,
+				[]parser.NamedTypeValue{
+					parser.NewNameType("form", "*multipart.Writer"),
+					parser.NewNameType("key", "string"),
+					parser.NewNameType("file", "io.Reader"),
+				},
+				[]parser.NamedTypeValue{
+					parser.NewNameType("", "error"),
+				},
+			),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"testing"
+)
+
+const testFileMethods = `// gk:http multipart
+	Upload(ctx context.Context, name string, meta Account, file io.Reader) (n int64, err error)
+	// gk:http GET /files/{id}
+	// gk:header size X-Size
+	Download(ctx context.Context, id int64) (body io.ReadCloser, contentType string, filename string, size int64, err error)
+	Put(ctx context.Context, id int64, body io.Reader) (err error)
+	// gk:http GET /raw
+	Raw(ctx context.Context, body io.Reader) (err error)`
+
+func TestHTTPStreams(t *testing.T) {
+	testProject(t, map[string]interface{}{"gk_transport": "http"})
+	testService(t, "acc", testFileMethods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	code := testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, code,
+		// the multipart forms are read part by part, the file is the last part
+		`mr, err := r.MultipartReader()`,
+		`if part.FormName() == "file" {
+				req.File = part
+				break
+			}`,
+		`if s := form.Get("name"); s != "" {
+			req.Name = s
+		}`,
+		`if err := json.Unmarshal([]byte(s), &req.Meta); err != nil {
+			return nil, badRequest("meta", err)
+		}`,
+		// the client writes the form in a pipe
+		`pr, pw := io.Pipe()`,
+		`pw.CloseWithError(writeHTTPUploadForm(form, req))`,
+		`if err := writeFormFile(form, "file", req.File); err != nil {`,
+		// the downloads stream the body and send the other results in the headers
+		`"GET /files/{id}", httptransport.NewServer(endpoints.DownloadEndpoint, decodeHTTPDownloadReq, encodeHTTPDownloadRes,`,
+		`if c, ok := res.Body.(io.Closer); ok {
+			defer c.Close()
+		}`,
+		`w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": res.Filename}))`,
+		`w.Header().Set("X-Size", strconv.FormatInt(res.Size, 10))`,
+		`_, err := io.Copy(w, res.Body)`,
+		// the client leaves the body of the downloads open
+		`ops = append(ops, httptransport.BufferedStream(true))`,
+		`res := accendpoint.DownloadRes{Body: r.Body}`,
+		`res.Filename = params["filename"]`,
+		// the streamed bodies are not read in memory
+		`req.Body = r.Body`,
+		`r.Header.Set("Content-Type", "application/octet-stream")
+		if req.Body != nil {
+			r.Body = ioutil.NopCloser(req.Body)
+		}`,
+		// a streamed body needs a method with a body
+		`"POST /raw"`,
+	)
+	assertNotContains(t, code, `"GET /raw"`, `ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, badRequest("body", err)`)
+}
@@ -0,0 +1,540 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+	"github.com/spf13/viper"
+)
+
+// httpExampleDepth is how deep the structures of the service get examples of their fields,
+// the deeper ones are left empty.
+const httpExampleDepth = 3
+
+var httpArrayType = regexp.MustCompile(`^\[[0-9]+\]`)
+
+// httpExample is an example value of the type for the tests, ok is false when the type has
+// none and the tests use its zero value. The types of the service are qualified.
+func httpExample(typeName string, st *ServiceTypes, depth int) (expr string, ok bool) {
+	switch typeName {
+	case "string":
+		return `"example"`, true
+	case "bool":
+		return "true", true
+	case "int":
+		return "1", true
+	case "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		return typeName + "(1)", true
+	case "float64":
+		return "1.5", true
+	case "float32":
+		return "float32(1.5)", true
+	case "time.Time":
+		return "time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)", true
+	case "time.Duration":
+		return "time.Second", true
+	case "[]byte":
+		return `[]byte("example")`, true
+	case "interface{}", "any":
+		return `"example"`, true
+	case "io.Reader":
+		return `strings.NewReader("example")`, true
+	case "io.ReadCloser":
+		return `ioutil.NopCloser(strings.NewReader("example"))`, true
+	}
+	switch {
+	case strings.HasPrefix(typeName, "*"):
+		elem := strings.TrimPrefix(typeName, "*")
+		v, ok := httpExample(elem, st, depth)
+		if !ok {
+			return "", false
+		}
+		if strings.HasSuffix(v, "}") && !strings.HasPrefix(elem, "[]") && !strings.HasPrefix(elem, "map[") {
+			return "&" + v, true
+		}
+		return fmt.Sprintf("func(v %s) *%s { return &v }(%s)", elem, elem, v), true
+	case strings.HasPrefix(typeName, "[]"):
+		v, ok := httpExample(strings.TrimPrefix(typeName, "[]"), st, depth)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%s{%s}", typeName, v), true
+	case strings.HasPrefix(typeName, "map["):
+		key, value := mapKeyValue(typeName)
+		k, ok := httpExample(key, st, depth)
+		if !ok {
+			return "", false
+		}
+		v, ok := httpExample(value, st, depth)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%s{%s: %s}", typeName, k, v), true
+	case httpArrayType.MatchString(typeName), strings.Contains(typeName, "chan "), strings.HasPrefix(typeName, "func"):
+		return "", false
+	}
+	if st == nil || !strings.HasPrefix(typeName, st.Package+".") {
+		return "", false
+	}
+	name := strings.TrimPrefix(typeName, st.Package+".")
+	underlying, found := st.Types[name]
+	if !found {
+		return "", false
+	}
+	if underlying != "struct" {
+		v, ok := httpExample(st.Qualify(underlying), st, depth)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%s(%s)", typeName, v), true
+	}
+	if depth >= httpExampleDepth {
+		return typeName + "{}", true
+	}
+	fields := ""
+	for _, v := range st.Structs[name] {
+		if v.Name == "" || !ast.IsExported(v.Name) || strings.Contains(v.Tag, `json:"-"`) {
+			continue
+		}
+		if e, ok := httpExample(st.Qualify(v.Type), st, depth+1); ok {
+			fields += fmt.Sprintf("%s: %s, ", v.Name, e)
+		}
+	}
+	return fmt.Sprintf("%s{%s}", typeName, strings.TrimSuffix(fields, ", ")), true
+}
+
+// mapKeyValue splits a map type in its key and value types.
+func mapKeyValue(typeName string) (key, value string) {
+	depth := 0
+	for i := len("map["); i < len(typeName); i++ {
+		switch typeName[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				return typeName[len("map["):i], typeName[i+1:]
+			}
+			depth--
+		}
+	}
+	return "", ""
+}
+
+// httpTestNames are the names of the tests the locals holding the examples do not take.
+var httpTestNames = map[string]bool{"t": true, "tt": true, "svc": true, "err": true, "want": true}
+
+// httpTestParams are the names of the parameters of the method in the fake service and in the
+// tests, the context excepted.
+func httpTestParams(m parser.Method) []string {
+	var names []string
+	for k, v := range m.Parameters {
+		if v.Type == "context.Context" {
+			continue
+		}
+		name := v.Name
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("p%d", k)
+		}
+		if httpTestNames[name] {
+			name += "Param"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// httpTestStructs is the fake service of the tests.
+func httpTestStructs() []parser.Struct {
+	s := parser.NewStructWithComment(
+		"fakeService",
+		`fakeService is the service the tests serve, it keeps the parameters of the last call
+		and returns the results and the error of the test case.`,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("params", "[]interface{}"),
+			parser.NewNameType("results", "[]interface{}"),
+			parser.NewNameType("err", "error"),
+		},
+	)
+	for k := range s.Vars {
+		s.Vars[k].Tag = ""
+	}
+	return []parser.Struct{s}
+}
+
+// httpFakeMethod is the method of the fake service.
+func httpFakeMethod(m parser.Method, st *ServiceTypes) parser.Method {
+	params := []parser.NamedTypeValue{}
+	names := httpTestParams(m)
+	k := 0
+	for _, v := range m.Parameters {
+		if v.Type == "context.Context" {
+			params = append(params, parser.NewNameType("ctx", v.Type))
+			continue
+		}
+		params = append(params, parser.NewNameType(names[k], st.Qualify(v.Type)))
+		k++
+	}
+	results := []parser.NamedTypeValue{}
+	returns := []string{}
+	k = 0
+	for _, v := range m.Results {
+		results = append(results, parser.NewNameType("", st.Qualify(v.Type)))
+		switch {
+		case isErrorResult(v):
+			returns = append(returns, "s.err")
+		case isStreamMethod(m):
+			returns = append(returns, st.Zero(st.Qualify(v.Type)))
+		default:
+			returns = append(returns, fmt.Sprintf("s.results[%d].(%s)", k, st.Qualify(v.Type)))
+			k++
+		}
+	}
+	recorded := []string{}
+	k = 0
+	for _, v := range m.Parameters {
+		if v.Type == "context.Context" {
+			continue
+		}
+		// the readers are read during the call, their content is kept
+		if httpReader(v.Type) {
+			recorded = append(recorded, fmt.Sprintf("readString(%s)", names[k]))
+		} else {
+			recorded = append(recorded, names[k])
+		}
+		k++
+	}
+	body := fmt.Sprintf("s.params = []interface{}{%s}", strings.Join(recorded, ", "))
+	if len(returns) > 0 {
+		body += "\nreturn " + strings.Join(returns, ", ")
+	}
+	return parser.NewMethod(m.Name, parser.NewNameType("s", "*fakeService"), body, params, results)
+}
+
+// httpTestMethod is the table test of the method, the client sends examples of the parameters
+// through the handler to the fake service and decodes the results or the errors it returns.
+func httpTestMethod(m parser.Method, route httpRoute, st *ServiceTypes) parser.Method {
+	bound := map[string]bool{}
+	for _, p := range route.Params {
+		bound[p.Field] = true
+	}
+	body := ""
+	names := httpTestParams(m)
+	args := []string{"context.Background()"}
+	params := []string{}
+	k := 0
+	for _, v := range m.Parameters {
+		if v.Type == "context.Context" {
+			continue
+		}
+		name, typeName := names[k], st.Qualify(v.Type)
+		k++
+		e, ok := httpExample(typeName, st, 0)
+		// the parameters the transport can not carry reach the service empty
+		ok = ok && bound[utils.ToUpperFirstCamelCase(v.Name)]
+		switch {
+		case httpReader(typeName) && ok:
+			// the readers are read once, each case sends a new one
+			args, params = append(args, e), append(params, `"example"`)
+			continue
+		case httpReader(typeName):
+			args, params = append(args, "nil"), append(params, `""`)
+			continue
+		case ok:
+			body += fmt.Sprintf("%s := %s\n", name, e)
+		default:
+			body += fmt.Sprintf("var %s %s\n", name, typeName)
+		}
+		args, params = append(args, name), append(params, name)
+	}
+	carried := func(field string) bool {
+		if route.Download == nil || field == route.Download.Field {
+			return true
+		}
+		for _, p := range route.Download.Headers {
+			if p.Field == field {
+				return true
+			}
+		}
+		return false
+	}
+	var results, gots, checks []string
+	for k, v := range m.Results {
+		if isErrorResult(v) {
+			if contains(gots, "err") {
+				gots = append(gots, "_")
+			} else {
+				gots = append(gots, "err")
+			}
+			continue
+		}
+		name := utils.ToUpperFirstCamelCase(v.Name)
+		if v.Name == "" || v.Name == "_" {
+			name = fmt.Sprintf("Res%d", k)
+		}
+		typeName := st.Qualify(v.Type)
+		gots = append(gots, "got"+name)
+		e, ok := httpExample(typeName, st, 0)
+		switch {
+		case ok && httpReader(typeName):
+			results = append(results, e)
+			checks = append(checks, fmt.Sprintf(`if tt.err == nil {
+					if s := readString(got%s); s != "example" {
+						t.Errorf("%s() %s = %%q, want %%q", s, "example")
+					}
+				}`, name, m.Name, utils.ToLowerFirstCamelCase(name)))
+			continue
+		case ok && carried(utils.ToUpperFirstCamelCase(v.Name)):
+			body += fmt.Sprintf("want%s := %s\n", name, e)
+		default:
+			body += fmt.Sprintf("var want%s %s\n", name, typeName)
+		}
+		results = append(results, "want"+name)
+		checks = append(checks, fmt.Sprintf(`if tt.err == nil && !reflect.DeepEqual(got%s, want%s) {
+				t.Errorf("%s() %s = %%#v, want %%#v", got%s, want%s)
+			}`, name, name, m.Name, utils.ToLowerFirstCamelCase(name), name, name))
+	}
+	// the methods without an error only succeed
+	cases := fmt.Sprintf(`{"success", nil},
+			{"not found", %s.ErrNotFound},
+			{"failure", errors.New("failure")},`, st.Package)
+	call := fmt.Sprintf("%s := newTestClient(t, svc).%s", strings.Join(gots, ", "), m.Name)
+	if !contains(gots, "err") {
+		cases = `{"success", nil},`
+		call = "var err error\n" + call
+		if len(gots) == 0 {
+			call = "var err error\nnewTestClient(t, svc)." + m.Name
+		}
+	}
+	body += fmt.Sprintf(`for _, tt := range []struct {
+			name string
+			err  error
+		}{
+			%s
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				svc := &fakeService{results: []interface{}{%s}, err: tt.err}
+				%s(%s)
+				if !sameError(err, tt.err) {
+					t.Fatalf("%s() error = %%v, want %%v", err, tt.err)
+				}
+				if want := []interface{}{%s}; !reflect.DeepEqual(svc.params, want) {
+					t.Errorf("%s() params = %%#v, want %%#v", svc.params, want)
+				}
+				%s
+			})
+		}`, cases, strings.Join(results, ", "), call, strings.Join(args, ", "),
+		m.Name, strings.Join(params, ", "), m.Name, strings.Join(checks, "\n"))
+	return parser.NewMethodWithComment(
+		"TestHTTP"+m.Name,
+		fmt.Sprintf(`TestHTTP%s serves %s %s and calls it with the HTTP client.`, m.Name, route.Verb, route.Path),
+		parser.NamedTypeValue{},
+		body,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("t", "*testing.T"),
+		},
+		[]parser.NamedTypeValue{},
+	)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// httpTestBadRequest is the table test of the bodies the handler refuses, nil when no route
+// reads a body.
+func httpTestBadRequest(iface *parser.Interface, st *ServiceTypes) *parser.Method {
+	cases := ""
+	for _, m := range iface.Methods {
+		route := newHTTPRoute(m, st)
+		if !route.Body || isStreamMethod(m) {
+			continue
+		}
+		path := httpPathVariable.ReplaceAllString(route.Path, "1")
+		cases += fmt.Sprintf("\n{%q, %q, %q, %q, %q},", m.Name+" malformed", route.Verb, path, "application/json", "{")
+		cases += fmt.Sprintf("\n{%q, %q, %q, %q, %q},", m.Name+" unsupported", route.Verb, path, "text/plain", "{}")
+	}
+	if cases == "" {
+		return nil
+	}
+	m := parser.NewMethodWithComment(
+		"TestHTTPBadRequest",
+		`TestHTTPBadRequest sends the bodies the handler can not decode, they are bad requests.`,
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`srv := newTestServer(t, &fakeService{})
+			for _, tt := range []struct {
+				name, method, path, contentType, body string
+			}{%s
+			} {
+				t.Run(tt.name, func(t *testing.T) {
+					r, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+					if err != nil {
+						t.Fatal(err)
+					}
+					r.Header.Set("Content-Type", tt.contentType)
+					resp, err := http.DefaultClient.Do(r)
+					if err != nil {
+						t.Fatal(err)
+					}
+					defer resp.Body.Close()
+					if resp.StatusCode != http.StatusBadRequest {
+						t.Errorf("%%s %%s status = %%d, want %%d", tt.method, tt.path, resp.StatusCode, http.StatusBadRequest)
+					}
+				})
+			}`, cases),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("t", "*testing.T"),
+		},
+		[]parser.NamedTypeValue{},
+	)
+	return &m
+}
+
+// httpTestHelpers serve the fake service on a test server, compare the errors and read the
+// streams.
+func httpTestHelpers(name string, iface *parser.Interface, st *ServiceTypes) []parser.Method {
+	set := ""
+	for _, m := range iface.Methods {
+		set += fmt.Sprintf("\n%sEndpoint: %sendpoint.Make%sEndpoint(svc),", m.Name, name, m.Name)
+	}
+	helpers := []parser.Method{
+		parser.NewMethodWithComment(
+			"newTestServer",
+			`newTestServer serves the endpoints of the service with NewHTTPHandler on a test server.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`set := %sendpoint.Set{%s
+				}
+				srv := httptest.NewServer(NewHTTPHandler(set, %sendpoint.NewNopTracer(), log.NewNopLogger()))
+				t.Cleanup(srv.Close)
+				return srv`, name, set, name),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("t", "*testing.T"),
+				parser.NewNameType("svc", st.Package+"."+iface.Name),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "*httptest.Server"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"newTestClient",
+			`newTestClient is the HTTP client of a test server serving the service.`,
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`client, err := NewHTTPClient(newTestServer(t, svc).URL, %sendpoint.NewNopTracer(), log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return client`, name),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("t", "*testing.T"),
+				parser.NewNameType("svc", st.Package+"."+iface.Name),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", st.Package+"."+iface.Name),
+			},
+		),
+		parser.NewMethodWithComment(
+			"sameError",
+			`sameError tells if the client returned the error of the service, the errors out of its
+			catalog only keep their message.`,
+			parser.NamedTypeValue{},
+			`if err == nil || want == nil {
+				return err == want
+			}
+			return errors.Is(err, want) || err.Error() == want.Error()`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("err", "error"),
+				parser.NewNameType("want", "error"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "bool"),
+			},
+		),
+	}
+	readers := false
+	for _, m := range iface.Methods {
+		for _, v := range m.Parameters {
+			readers = readers || httpReader(v.Type)
+		}
+		for _, v := range m.Results {
+			readers = readers || httpReader(v.Type)
+		}
+	}
+	if !readers {
+		return helpers
+	}
+	return append(helpers, parser.NewMethodWithComment(
+		"readString",
+		`readString reads the stream to the end and closes it, the empty string when it is nil.`,
+		parser.NamedTypeValue{},
+		`if r == nil {
+			return ""
+		}
+		if c, ok := r.(io.Closer); ok {
+			defer c.Close()
+		}
+		b, _ := ioutil.ReadAll(r)
+		return string(b)`,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("r", "io.Reader"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "string"),
+		},
+	))
+}
+
+// httpTesting is the file of the tests of the http transport.
+func httpTesting(name string, iface *parser.Interface, st *ServiceTypes, imports []parser.NamedTypeValue) *parser.File {
+	f := parser.NewFile()
+	f.Package = fmt.Sprintf("%stransport", name)
+	// errors.Is is newer than the standard library goimports resolves, it is imported here
+	f.Imports = append([]parser.NamedTypeValue{
+		parser.NewNameType("", "\"errors\"\n"),
+		parser.NewNameType("", "\"github.com/go-kit/kit/log\"\n"),
+	}, imports...)
+	f.Structs = httpTestStructs()
+	for _, m := range iface.Methods {
+		f.Methods = append(f.Methods, httpFakeMethod(m, st))
+	}
+	f.Methods = append(f.Methods, httpTestHelpers(name, iface, st)...)
+	for _, m := range iface.Methods {
+		if isStreamMethod(m) {
+			continue
+		}
+		f.Methods = append(f.Methods, httpTestMethod(m, newHTTPRoute(m, st), st))
+	}
+	if m := httpTestBadRequest(iface, st); m != nil {
+		f.Methods = append(f.Methods, *m)
+	}
+	return &f
+}
+
+// httpTestingImports are the endpoints and the service packages the tests build the handler with,
+// named as goimports can not always load them.
+func httpTestingImports(name string, st *ServiceTypes) ([]parser.NamedTypeValue, error) {
+	te := template.NewEngine()
+	imports := []parser.NamedTypeValue{}
+	for _, v := range [][2]string{{name + "endpoint", "endpoints.path"}, {st.Package, "service.path"}} {
+		path, err := te.ExecuteString(viper.GetString(v[1]), map[string]string{
+			"ServiceName": name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		path, err = ProjectImport(path)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, parser.NewNameType(v[0], "\""+path+"\""))
+	}
+	return imports, nil
+}
@@ -0,0 +1,358 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+)
+
+// protoWellKnownGoTypes maps the well known protobuf types to plain go types.
+var protoWellKnownGoTypes = map[string]string{
+	"google.protobuf.Timestamp":   "time.Time",
+	"google.protobuf.Duration":    "time.Duration",
+	"google.protobuf.Empty":       "struct{}",
+	"google.protobuf.Any":         "interface{}",
+	"google.protobuf.Value":       "interface{}",
+	"google.protobuf.Struct":      "map[string]interface{}",
+	"google.protobuf.ListValue":   "[]interface{}",
+	"google.protobuf.DoubleValue": "*float64",
+	"google.protobuf.FloatValue":  "*float32",
+	"google.protobuf.Int64Value":  "*int64",
+	"google.protobuf.UInt64Value": "*uint64",
+	"google.protobuf.Int32Value":  "*int32",
+	"google.protobuf.UInt32Value": "*uint32",
+	"google.protobuf.BoolValue":   "*bool",
+	"google.protobuf.StringValue": "*string",
+	"google.protobuf.BytesValue":  "[]byte",
+}
+
+type ImportProtoGenerator struct {
+	proto    *parser.Proto
+	messages map[string]*parser.ProtoMessage
+	enums    map[string]*parser.ProtoEnum
+	// used keeps the full names of the messages and enums the service refers to, in order.
+	used []string
+}
+
+func NewImportProtoGenerator() *ImportProtoGenerator {
+	return &ImportProtoGenerator{
+		messages: map[string]*parser.ProtoMessage{},
+		enums:    map[string]*parser.ProtoEnum{},
+	}
+}
+
+// Generate creates the service `name` from the proto service in protoFile. The proto is copied
+// to `pb.path` so the grpc transport is generated against it.
+func (ig *ImportProtoGenerator) Generate(name string, protoFile string) error {
+	src, err := ioutil.ReadFile(protoFile)
+	if err != nil {
+		return err
+	}
+	ig.proto, err = parser.NewProtoParser().Parse(src)
+	if err != nil {
+		return err
+	}
+	svc := FindProtoService(ig.proto, name)
+	if svc == nil {
+		var names []string
+		for _, v := range ig.proto.Services {
+			names = append(names, v.Name)
+		}
+		if len(names) == 0 {
+			return errors.New(fmt.Sprintf("No service found in `%s`", protoFile))
+		}
+		return errors.New(fmt.Sprintf(
+			"`%s` defines the services %s, name the service after one of them",
+			protoFile, strings.Join(names, ", "),
+		))
+	}
+	for k := range ig.proto.Messages {
+		ig.indexMessage("", &ig.proto.Messages[k])
+	}
+	for k, v := range ig.proto.Enums {
+		ig.enums[v.Name] = &ig.proto.Enums[k]
+	}
+	logrus.Info(fmt.Sprintf("Importing service %s of %s as: %s", svc.Name, protoFile, name))
+
+	te := template.NewEngine()
+	iname, err := te.ExecuteString(viper.GetString("service.interface_name"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	var methods []parser.Method
+	for _, v := range svc.RPCs {
+		m, err := ig.method(v)
+		if err != nil {
+			logrus.Warnf("The rpc '%s' will be ignored: %s", v.Name, err)
+			continue
+		}
+		methods = append(methods, m)
+	}
+	if len(methods) == 0 {
+		return errors.New(fmt.Sprintf("The service %s has no rpc that can be imported", svc.Name))
+	}
+
+	f := parser.NewFile()
+	f.Package = fmt.Sprintf("%sservice", name)
+	f.AliasType = []parser.NamedTypeValue{parser.NewNameType("Middleware", fmt.Sprintf("func(%s) %s", iname, iname))}
+	comment := fmt.Sprintf("%s is imported from the service %s of %s.", iname, svc.Name, protoFile)
+	if len(svc.Comment) > 0 {
+		comment = strings.Join(svc.Comment, "\n")
+	}
+	f.Interfaces = []parser.Interface{parser.NewInterfaceWithComment(iname, comment, methods)}
+	// messages the structs refer to are added to used while they are generated
+	for i := 0; i < len(ig.used); i++ {
+		fullName := ig.used[i]
+		if msg, ok := ig.messages[fullName]; ok {
+			f.Structs = append(f.Structs, ig.structOf(fullName, msg))
+			continue
+		}
+		enum := ig.enums[fullName]
+		f.AliasType = append(f.AliasType, parser.NewNameType(protoGoName(fullName), "int32"))
+		for _, v := range enum.Values {
+			f.Constants = append(f.Constants, parser.NewNameTypeValue(
+				protoGoName(fullName)+"_"+v.Name, protoGoName(fullName), strconv.Itoa(v.Number),
+			))
+		}
+	}
+
+	{
+		apiGen := NewApiMainGenerator()
+		if err := apiGen.Generate(); err != nil {
+			return err
+		}
+	}
+	{
+		mainGen := NewServiceMainGenerator()
+		if err := mainGen.Generate(name); err != nil {
+			return err
+		}
+	}
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	fname, err := te.ExecuteString(viper.GetString("service.file_name"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	if err = defaultFs.MkdirAll(path); err != nil {
+		return err
+	}
+	if err = defaultFs.WriteFile(path+defaultFs.FilePathSeparator()+fname, f.String(), false); err != nil {
+		return err
+	}
+
+	pbPath, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	if err = defaultFs.MkdirAll(pbPath); err != nil {
+		return err
+	}
+	tfile := pbPath + defaultFs.FilePathSeparator() + utils.ToLowerSnakeCase(name) + ".proto"
+	if err = defaultFs.WriteFile(tfile, string(src), false); err != nil {
+		return err
+	}
+	logrus.Infof("The proto was copied to `%s`, run `gk init -t grpc %s` to continue.", tfile, name)
+	return nil
+}
+
+func (ig *ImportProtoGenerator) indexMessage(scope string, msg *parser.ProtoMessage) {
+	fullName := msg.Name
+	if scope != "" {
+		fullName = scope + "." + msg.Name
+	}
+	ig.messages[fullName] = msg
+	for k, v := range msg.Enums {
+		ig.enums[fullName+"."+v.Name] = &msg.Enums[k]
+	}
+	for k := range msg.Messages {
+		ig.indexMessage(fullName, &msg.Messages[k])
+	}
+}
+
+// method maps the rpc to a service method, the request fields become the parameters and the
+// response fields the results followed by the error. A streamed response is a channel of its
+// only field, the way the gRPC transport streams the results. The streamed requests can not be
+// mapped, the gRPC transport sends the parameters in a first message the clients of the proto
+// would not send.
+func (ig *ImportProtoGenerator) method(rpc parser.ProtoRPC) (parser.Method, error) {
+	reqFields := ig.messageFields(rpc.RequestType)
+	var resFields []scopedField
+	for _, v := range ig.messageFields(rpc.ReturnsType) {
+		// the error of the messages gk generates is the error result
+		if strings.ToLower(v.field.Name) != "err" {
+			resFields = append(resFields, v)
+		}
+	}
+	if rpc.StreamsRequest {
+		return parser.Method{}, fmt.Errorf("it streams `%s`, gk sends a first message of parameters before the streamed requests", rpc.RequestType)
+	}
+	if rpc.StreamsReturns && len(resFields) != 1 {
+		return parser.Method{}, fmt.Errorf("it streams `%s` of %d fields, the streams of gk carry the messages of one field", rpc.ReturnsType, len(resFields))
+	}
+	params := []parser.NamedTypeValue{parser.NewNameType("ctx", "context.Context")}
+	for _, v := range reqFields {
+		params = append(params, parser.NewNameType(protoParamName(v.field.Name), ig.fieldGoType(v.scope, v.field)))
+	}
+	var results []parser.NamedTypeValue
+	for _, v := range resFields {
+		tp := ig.fieldGoType(v.scope, v.field)
+		if rpc.StreamsReturns {
+			tp = "<-chan " + tp
+		}
+		results = append(results, parser.NewNameType(protoParamName(v.field.Name), tp))
+	}
+	results = append(results, parser.NewNameType("err", "error"))
+	m := parser.NewMethod(rpc.Name, parser.NamedTypeValue{}, "", params, results)
+	if len(rpc.Comment) > 0 {
+		m = parser.NewMethodWithComment(rpc.Name, strings.Join(rpc.Comment, "\n"), parser.NamedTypeValue{}, "", params, results)
+	}
+	return m, nil
+}
+
+type scopedField struct {
+	scope string
+	field parser.ProtoField
+}
+
+// messageFields lists the fields of the top level message, oneof fields included.
+func (ig *ImportProtoGenerator) messageFields(typeName string) (fields []scopedField) {
+	fullName := ig.resolve("", typeName)
+	msg, ok := ig.messages[fullName]
+	if !ok {
+		if _, wellKnown := protoWellKnownGoTypes[strings.TrimPrefix(typeName, ".")]; !wellKnown {
+			logrus.Warnf("The message `%s` is not declared in the proto and its fields are ignored", typeName)
+		}
+		return nil
+	}
+	for _, v := range msg.Fields {
+		fields = append(fields, scopedField{fullName, v})
+	}
+	for _, o := range msg.Oneofs {
+		for _, v := range o.Fields {
+			fields = append(fields, scopedField{fullName, v})
+		}
+	}
+	return fields
+}
+
+func (ig *ImportProtoGenerator) structOf(fullName string, msg *parser.ProtoMessage) parser.Struct {
+	var vars []parser.NamedTypeValue
+	for _, v := range ig.messageFields(fullName) {
+		vars = append(vars, parser.NewNameType(utils.ToUpperFirstCamelCase(v.field.Name), ig.fieldGoType(v.scope, v.field)))
+	}
+	if vars == nil {
+		vars = []parser.NamedTypeValue{}
+	}
+	if len(msg.Comment) > 0 {
+		return parser.NewStructWithComment(protoGoName(fullName), strings.Join(msg.Comment, "\n"), vars)
+	}
+	return parser.NewStruct(protoGoName(fullName), vars)
+}
+
+func (ig *ImportProtoGenerator) fieldGoType(scope string, field parser.ProtoField) string {
+	typeName := ig.goType(scope, field.Type)
+	switch {
+	case field.KeyType != "":
+		return fmt.Sprintf("map[%s]%s", ig.goType(scope, field.KeyType), typeName)
+	case field.Repeated:
+		return "[]" + typeName
+	case field.Optional && parser.ProtoScalarGoTypes[field.Type] != "" && field.Type != "bytes":
+		return "*" + typeName
+	}
+	return typeName
+}
+
+func (ig *ImportProtoGenerator) goType(scope string, typeName string) string {
+	if v, ok := parser.ProtoScalarGoTypes[typeName]; ok {
+		return v
+	}
+	if v, ok := protoWellKnownGoTypes[strings.TrimPrefix(typeName, ".")]; ok {
+		return v
+	}
+	fullName := ig.resolve(scope, typeName)
+	if _, ok := ig.messages[fullName]; ok {
+		ig.use(fullName)
+		return "*" + protoGoName(fullName)
+	}
+	if _, ok := ig.enums[fullName]; ok {
+		ig.use(fullName)
+		return protoGoName(fullName)
+	}
+	logrus.Warnf("The type `%s` is not declared in the proto and will be mapped to interface{}", typeName)
+	return "interface{}"
+}
+
+// resolve finds the full name of a message or enum referenced from the scope following the
+// protobuf scoping rules, the package of the proto is left out.
+func (ig *ImportProtoGenerator) resolve(scope string, typeName string) string {
+	if strings.HasPrefix(typeName, ".") || (ig.proto.PackageName != "" && strings.HasPrefix(typeName, ig.proto.PackageName+".")) {
+		return strings.TrimPrefix(strings.TrimPrefix(typeName, "."), ig.proto.PackageName+".")
+	}
+	for {
+		fullName := typeName
+		if scope != "" {
+			fullName = scope + "." + typeName
+		}
+		if _, ok := ig.messages[fullName]; ok {
+			return fullName
+		}
+		if _, ok := ig.enums[fullName]; ok {
+			return fullName
+		}
+		if scope == "" {
+			return typeName
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+func (ig *ImportProtoGenerator) use(fullName string) {
+	for _, v := range ig.used {
+		if v == fullName {
+			return
+		}
+	}
+	ig.used = append(ig.used, fullName)
+}
+
+// protoGoName is the go name protoc-gen-go gives to a nested message or enum.
+func protoGoName(fullName string) string {
+	return strings.Replace(fullName, ".", "_", -1)
+}
+
+// protoParamName is the go parameter name of a field, the endpoint field derived from it with
+// utils.ToUpperFirstCamelCase is the one protoc-gen-go generates, a trailing `_` included.
+func protoParamName(field string) string {
+	name := utils.ToLowerFirstCamelCase(field)
+	if token.Lookup(name).IsKeyword() || name == "ctx" || name == "err" {
+		name += "_"
+	}
+	return name
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+)
+
+func TestImportProtoGRPCConversions(t *testing.T) {
+	testProject(t, nil)
+	src := `syntax = "proto3";
+
+package profile.v1;
+
+import "google/protobuf/timestamp.proto";
+
+service Profile {
+    rpc Get (GetReq) returns (GetRes) {}
+    rpc Find (FindReq) returns (FindRes) {}
+}
+
+enum Role {
+    ROLE_UNKNOWN = 0;
+    ROLE_ADMIN = 1;
+}
+
+message User {
+    message Address {
+        string street = 1;
+    }
+    string id = 1;
+    Role role = 2;
+    Address address = 3;
+    oneof contact {
+        string email = 4;
+        Address postal = 5;
+    }
+    google.protobuf.Timestamp created = 6;
+}
+
+message GetReq {
+    string id = 1;
+}
+
+message GetRes {
+    User user = 1;
+}
+
+message FindReq {
+    Role role = 1;
+    oneof by {
+        string email = 2;
+        string phone = 3;
+    }
+}
+
+message FindRes {
+    repeated User users = 1;
+}
+`
+	f, err := ioutil.TempFile("", "profile*.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(src); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := NewImportProtoGenerator().Generate("profile", f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := LoadServiceTypes("profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := fs.Get().ReadFile("profile/pkg/profileservice/service.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.NewFileParser().Parse([]byte(svc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := newPBConverter("profile", st, LoadPBService("profile"))
+	var out string
+	for _, m := range file.Interfaces[0].Methods {
+		decodeReq, encodeRes, encodeReq, decodeRes, err := grpcCodecs(pc, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out += decodeReq + encodeRes + encodeReq + decodeRes
+	}
+	for _, v := range pc.helpers {
+		out += v.Body
+	}
+	// the code is compared without its spaces, gofmt is left to the generator
+	compact := strings.Join(strings.Fields(out), "")
+	for _, want := range []string{
+		// nested messages keep the name protoc-gen-go gives them
+		`toPBUser_Address(*in)`,
+		`&profilepb.User_Address{`,
+		// enums are converted to the pb enum
+		`Role: profilepb.Role(in.Role)`,
+		`Role: profileservice.Role(in.Role)`,
+		`req := &profilepb.FindReq{Role: profilepb.Role(r.Role),}`,
+		// oneofs are set through their wrappers and read with their getters
+		`out.Contact = &profilepb.User_Email{Email: in.Email}`,
+		`out.Contact = &profilepb.User_Postal{Postal: func(in *profileservice.User_Address)`,
+		`Email: in.GetEmail()`,
+		`req.By = &profilepb.FindReq_Email{Email: r.Email}`,
+		`} else if r.Phone != "" {`,
+		`Phone: r.GetPhone(),`,
+		// unset timestamps give the zero time
+		`Created: fromPBTime(in.Created),`,
+	} {
+		if !strings.Contains(compact, strings.Join(strings.Fields(want), "")) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{`UserAddress`, `int32(in.Role)`, `Email: in.Email,`, `in.Created.AsTime()`} {
+		if strings.Contains(compact, strings.Join(strings.Fields(unwanted), "")) {
+			t.Errorf("unexpected %q in\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestImportProtoStreams(t *testing.T) {
+	testProject(t, map[string]interface{}{"service.interface_name": "{{toUpperFirst .ServiceName}}API"})
+	src := `syntax = "proto3";
+
+package feed.v1;
+
+service Feed {
+    rpc Get (GetReq) returns (Item) {}
+    rpc Watch (GetReq) returns (stream Item) {}
+    rpc Tail (GetReq) returns (stream Page) {}
+    rpc Push (stream Item) returns (GetReq) {}
+}
+
+message GetReq {
+    string id = 1;
+}
+
+message Item {
+    string body = 1;
+}
+
+message Page {
+    repeated string bodies = 1;
+    string cursor = 2;
+}
+`
+	f, err := ioutil.TempFile("", "feed*.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(src); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := NewImportProtoGenerator().Generate("feed", f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	svc := testRead(t, "feed/pkg/feedservice/service.go")
+	assertContains(t, svc,
+		// the middleware wraps the configured interface
+		`type Middleware func(FeedAPI) FeedAPI`,
+		`type FeedAPI interface {`,
+		`Get(ctx context.Context, id string) (body string, err error)`,
+		// a streamed response of one field is a channel of it
+		`Watch(ctx context.Context, id string) (body <-chan string, err error)`,
+	)
+	assertNotContains(t, svc,
+		`func(Service) Service`,
+		// the streams of several fields and the streamed requests are skipped
+		`Tail(`,
+		`Push(`,
+	)
+}
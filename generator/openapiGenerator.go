@@ -0,0 +1,553 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+)
+
+// OpenAPIGenerator writes the OpenAPI 3 document of the routes the http transport registers,
+// the document is generated from the service interface and rewritten every time.
+type OpenAPIGenerator struct {
+}
+
+func NewOpenAPIGenerator() *OpenAPIGenerator {
+	return &OpenAPIGenerator{}
+}
+
+// openAPIDocument is the part of the OpenAPI 3.0 object model gk writes.
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Servers    []openAPIServer                         `json:"servers,omitempty"`
+	Tags       []openAPITag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    *[]map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Ref         string                      `json:"$ref,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Headers     map[string]openAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	Responses       map[string]*openAPIResponse       `json:"responses"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// openAPISecuritySchemes are the security schemes of the authentications, by scheme.
+var openAPISecuritySchemes = map[string]string{"jwt": "bearerAuth", "apikey": "apiKeyAuth", "basic": "basicAuth"}
+
+type openAPISchema struct {
+	Ref                  string            `json:"$ref,omitempty"`
+	Type                 string            `json:"type,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Nullable             bool              `json:"nullable,omitempty"`
+	Enum                 []interface{}     `json:"enum,omitempty"`
+	Items                *openAPISchema    `json:"items,omitempty"`
+	Properties           openAPIProperties `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema    `json:"additionalProperties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+}
+
+// openAPIProperty is a property of an object schema, the properties keep the order of the
+// fields of the structure.
+type openAPIProperty struct {
+	Name   string
+	Schema *openAPISchema
+}
+
+type openAPIProperties []openAPIProperty
+
+func (p openAPIProperties) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for k, v := range p {
+		if k > 0 {
+			buf.WriteString(",")
+		}
+		name, err := json.Marshal(v.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(v.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(schema)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// openAPIFormats are the schemas of the Go scalars.
+var openAPIFormats = map[string][2]string{
+	"bool":          {"boolean", ""},
+	"string":        {"string", ""},
+	"int":           {"integer", "int64"},
+	"int8":          {"integer", "int32"},
+	"int16":         {"integer", "int32"},
+	"int32":         {"integer", "int32"},
+	"rune":          {"integer", "int32"},
+	"int64":         {"integer", "int64"},
+	"uint":          {"integer", "int64"},
+	"uint8":         {"integer", "int32"},
+	"byte":          {"integer", "int32"},
+	"uint16":        {"integer", "int32"},
+	"uint32":        {"integer", "int64"},
+	"uint64":        {"integer", "int64"},
+	"float32":       {"number", "float"},
+	"float64":       {"number", "double"},
+	"time.Time":     {"string", "date-time"},
+	"time.Duration": {"integer", "int64"},
+	"[]byte":        {"string", "byte"},
+}
+
+// openAPISchemas builds the schemas of the service types, the structures of the service
+// file become components.
+type openAPISchemas struct {
+	st         *ServiceTypes
+	components map[string]*openAPISchema
+}
+
+// schema is the schema of a Go type as encoding/json writes it.
+func (s *openAPISchemas) schema(typeName string) *openAPISchema {
+	typeName = strings.TrimPrefix(strings.TrimSpace(typeName), s.st.Package+".")
+	if v, ok := openAPIFormats[typeName]; ok {
+		schema := &openAPISchema{Type: v[0], Format: v[1]}
+		if typeName == "time.Duration" {
+			schema.Description = "nanoseconds"
+		}
+		return schema
+	}
+	switch {
+	case strings.HasPrefix(typeName, "*"):
+		schema := s.schema(typeName[1:])
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case strings.HasPrefix(typeName, "[]"):
+		return &openAPISchema{Type: "array", Items: s.schema(typeName[2:])}
+	case strings.HasPrefix(typeName, "["):
+		return &openAPISchema{Type: "array", Items: s.schema(typeName[strings.Index(typeName, "]")+1:])}
+	case strings.HasPrefix(typeName, "map["):
+		depth := 0
+		for k, c := range typeName {
+			switch c {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					return &openAPISchema{Type: "object", AdditionalProperties: s.schema(typeName[k+1:])}
+				}
+			}
+		}
+	case typeName == "interface{}":
+		return &openAPISchema{}
+	}
+	underlying, ok := s.st.Types[typeName]
+	switch {
+	case !ok:
+		return &openAPISchema{Type: "object", Description: typeName}
+	case underlying != "struct":
+		return s.schema(underlying)
+	}
+	if _, ok := s.components[typeName]; !ok {
+		s.components[typeName] = nil
+		s.components[typeName] = s.object(s.st.Structs[typeName])
+	}
+	return &openAPISchema{Ref: "#/components/schemas/" + typeName}
+}
+
+// object is the schema of a structure, the json tags give the names of the properties and the
+// fields without `omitempty` are required.
+func (s *openAPISchemas) object(fields []parser.NamedTypeValue) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: openAPIProperties{}}
+	for _, v := range fields {
+		if v.Name == "" || v.Type == "error" || strings.ToUpper(v.Name[:1]) != v.Name[:1] {
+			continue
+		}
+		name, omitempty := v.Name, false
+		if tag, ok := reflect.StructTag(strings.Trim(v.Tag, "`")).Lookup("json"); ok {
+			options := strings.Split(tag, ",")
+			if options[0] == "-" && len(options) == 1 {
+				continue
+			}
+			if options[0] != "" {
+				name = options[0]
+			}
+			for _, o := range options[1:] {
+				omitempty = omitempty || o == "omitempty"
+			}
+		}
+		schema.Properties = append(schema.Properties, openAPIProperty{Name: name, Schema: s.schema(v.Type)})
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// openAPIConstant is the schema of a constant of the envelope template.
+func openAPIConstant(value interface{}) *openAPISchema {
+	schema := &openAPISchema{Enum: []interface{}{value}}
+	switch value.(type) {
+	case string:
+		schema.Type = "string"
+	case bool:
+		schema.Type = "boolean"
+	case float64, int:
+		schema.Type = "number"
+	}
+	return schema
+}
+
+// openAPISchema is the schema of a response of the envelope, `data` is the schema of the
+// {{.Data}} field.
+func (e *httpEnvelope) openAPISchema(data *openAPISchema) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: openAPIProperties{}}
+	for _, v := range e.Fields {
+		var property *openAPISchema
+		switch v.Value {
+		case "{{.Data}}":
+			property = data
+		case "{{.Status}}", "{{.Code}}":
+			property = &openAPISchema{Type: "integer", Format: "int32"}
+		case "{{.Msg}}":
+			property = &openAPISchema{Type: "string"}
+		default:
+			property = openAPIConstant(v.Value)
+		}
+		schema.Properties = append(schema.Properties, openAPIProperty{Name: v.Name, Schema: property})
+		schema.Required = append(schema.Required, v.Name)
+	}
+	return schema
+}
+
+// openAPIError is the schema of the errors, the invalid requests list their field violations.
+func (e *httpEnvelope) openAPIError() *openAPISchema {
+	code := &openAPISchema{Type: "integer", Format: "int32", Description: "the code of the error catalog of the service"}
+	details := &openAPISchema{
+		Type:        "array",
+		Items:       &openAPISchema{Ref: "#/components/schemas/FieldViolation"},
+		Description: "the field violations of an invalid request",
+	}
+	switch e.Style {
+	case "template":
+		details.Nullable = true
+		return e.openAPISchema(details)
+	case "problem":
+		return &openAPISchema{
+			Type: "object",
+			Properties: openAPIProperties{
+				{"type", &openAPISchema{Type: "string"}},
+				{"title", &openAPISchema{Type: "string"}},
+				{"status", &openAPISchema{Type: "integer", Format: "int32"}},
+				{"detail", &openAPISchema{Type: "string"}},
+				{"code", code},
+				{"errors", details},
+			},
+			Required: []string{"type", "title", "status", "code"},
+		}
+	}
+	return &openAPISchema{
+		Type: "object",
+		Properties: openAPIProperties{
+			{"code", code},
+			{"msg", &openAPISchema{Type: "string"}},
+			{"details", details},
+		},
+		Required: []string{"code", "msg"},
+	}
+}
+
+// openAPIResponse is a response of the error schema.
+func (e *httpEnvelope) openAPIResponse(description string) *openAPIResponse {
+	return &openAPIResponse{
+		Description: description,
+		Content: map[string]openAPIMediaType{
+			strings.Split(e.contentType(), ";")[0]: {Schema: &openAPISchema{Ref: "#/components/schemas/Error"}},
+		},
+	}
+}
+
+// newOpenAPIOperation is the operation of the route of a method.
+func newOpenAPIOperation(name string, m parser.Method, route httpRoute, schemas *openAPISchemas, envelope *httpEnvelope) *openAPIOperation {
+	op := &openAPIOperation{
+		OperationID: m.Name,
+		Description: m.Description(),
+		Tags:        []string{name},
+		Responses:   map[string]*openAPIResponse{},
+	}
+	op.Summary = strings.Split(op.Description, "\n")[0]
+	if op.Summary == op.Description {
+		op.Description = ""
+	}
+	var body, form []parser.NamedTypeValue
+	files := map[string]bool{}
+	for _, p := range route.Params {
+		switch p.In {
+		case "body":
+			body = append(body, parser.NewNameType(p.Field, p.Type))
+			continue
+		case "stream":
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content: map[string]openAPIMediaType{
+					"application/octet-stream": {Schema: openAPIBinary()},
+				},
+			}
+			continue
+		case "file", "form":
+			v := parser.NewNameType(p.Field, p.Type)
+			v.Tag = fmt.Sprintf("`json:\"%s,omitempty\"`", p.Key)
+			form = append(form, v)
+			files[p.Key] = p.In == "file"
+			continue
+		}
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:     p.Key,
+			In:       p.In,
+			Required: p.In == "path",
+			Schema:   schemas.schema(p.Type),
+		})
+	}
+	if route.Body && len(body) > 0 {
+		schemas.components[m.Name+"Req"] = schemas.object(parser.NewStruct(m.Name+"Req", body).Vars)
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: &openAPISchema{Ref: "#/components/schemas/" + m.Name + "Req"}},
+			},
+		}
+	}
+	if route.Multipart {
+		// the files are binary strings, the other structured values are JSON
+		schema := schemas.object(form)
+		for k, v := range schema.Properties {
+			if files[v.Name] {
+				schema.Properties[k].Schema = openAPIBinary()
+			}
+		}
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  map[string]openAPIMediaType{"multipart/form-data": {Schema: schema}},
+		}
+	}
+	if route.Download != nil {
+		op.Responses["200"] = &openAPIResponse{
+			Description: "OK",
+			Content:     map[string]openAPIMediaType{"application/octet-stream": {Schema: openAPIBinary()}},
+		}
+		for _, p := range route.Download.Headers {
+			if p.Key == "Content-Type" {
+				// the spec ignores a Content-Type header, the content describes it
+				continue
+			}
+			if op.Responses["200"].Headers == nil {
+				op.Responses["200"].Headers = map[string]openAPIHeader{}
+			}
+			op.Responses["200"].Headers[p.Key] = openAPIHeader{Schema: schemas.schema(p.Type)}
+		}
+	}
+	op.Responses["400"] = &openAPIResponse{Ref: "#/components/responses/BadRequest"}
+	op.Responses["401"] = &openAPIResponse{Ref: "#/components/responses/Unauthorized"}
+	op.Responses["default"] = &openAPIResponse{Ref: "#/components/responses/Error"}
+	if route.Download != nil {
+		return op
+	}
+	var results []parser.NamedTypeValue
+	for _, p := range m.Results {
+		results = append(results, parser.NewNameType(utils.ToUpperFirstCamelCase(p.Name), p.Type))
+	}
+	schemas.components[m.Name+"Res"] = schemas.object(parser.NewStruct(m.Name+"Res", results).Vars)
+	data := &openAPISchema{Ref: "#/components/schemas/" + m.Name + "Res"}
+	if envelope.Style == "template" {
+		data = envelope.openAPISchema(data)
+	}
+	op.Responses["200"] = &openAPIResponse{
+		Description: "OK",
+		Content:     map[string]openAPIMediaType{"application/json": {Schema: data}},
+	}
+	return op
+}
+
+// openAPIBinary is the schema of a streamed body or of a file of a form.
+func openAPIBinary() *openAPISchema {
+	return &openAPISchema{Type: "string", Format: "binary"}
+}
+
+func (sg *OpenAPIGenerator) Generate(name string) error {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	iface, err := LoadServiceInterfaceFromFile(name)
+	if err != nil {
+		return err
+	}
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	envelope, err := loadHTTPEnvelope(st)
+	if err != nil {
+		return err
+	}
+
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       name,
+			Description: parser.Method{Comment: iface.Comment}.Description(),
+			Version:     viper.GetString("openapi.version"),
+		},
+		Tags:  []openAPITag{{Name: name}},
+		Paths: map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas: map[string]*openAPISchema{},
+			Responses: map[string]*openAPIResponse{
+				"BadRequest":   envelope.openAPIResponse("The request could not be decoded or is invalid."),
+				"Unauthorized": envelope.openAPIResponse("The credentials are missing or invalid."),
+				"Error":        envelope.openAPIResponse("The error of the service, the status is given by its code."),
+			},
+			SecuritySchemes: map[string]*openAPISecurityScheme{},
+		},
+	}
+	schemes, err := authSchemesOf(iface)
+	if err != nil {
+		return err
+	}
+	if schemes["jwt"] {
+		doc.Components.SecuritySchemes["bearerAuth"] = &openAPISecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+	}
+	if schemes["apikey"] {
+		doc.Components.SecuritySchemes["apiKeyAuth"] = &openAPISecurityScheme{Type: "apiKey", In: "header", Name: authAPIKeyHeader()}
+	}
+	if schemes["basic"] {
+		doc.Components.SecuritySchemes["basicAuth"] = &openAPISecurityScheme{Type: "http", Scheme: "basic"}
+	}
+	for _, v := range viper.GetStringSlice("openapi.servers") {
+		doc.Servers = append(doc.Servers, openAPIServer{URL: v})
+	}
+	schemas := &openAPISchemas{st: st, components: doc.Components.Schemas}
+	for _, m := range iface.Methods {
+		route := newHTTPRoute(m, st)
+		if doc.Paths[route.Path] == nil {
+			doc.Paths[route.Path] = map[string]*openAPIOperation{}
+		}
+		op := newOpenAPIOperation(name, m, route, schemas, envelope)
+		// the operations without authentication have no security and no 401 response
+		scheme, err := authScheme(iface, m)
+		if err != nil {
+			return err
+		}
+		op.Security = &[]map[string][]string{}
+		if scheme == "none" {
+			delete(op.Responses, "401")
+		} else {
+			*op.Security = append(*op.Security, map[string][]string{openAPISecuritySchemes[scheme]: {}})
+		}
+		doc.Paths[route.Path][strings.ToLower(route.Verb)] = op
+	}
+	doc.Components.Schemas["Error"] = envelope.openAPIError()
+	doc.Components.Schemas["FieldViolation"] = &openAPISchema{
+		Type: "object",
+		Properties: openAPIProperties{
+			{"field", &openAPISchema{Type: "string"}},
+			{"description", &openAPISchema{Type: "string"}},
+		},
+		Required: []string{"field", "description"},
+	}
+
+	d, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	path, err := te.ExecuteString(viper.GetString("openapi.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	fname, err := te.ExecuteString(viper.GetString("openapi.file_name"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	if err = defaultFs.MkdirAll(path); err != nil {
+		return err
+	}
+	logrus.Info(fmt.Sprintf("Generating the OpenAPI document of service %s", name))
+	return defaultFs.WriteFile(path+defaultFs.FilePathSeparator()+fname, string(d)+"\n", true)
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testJSON is the value at the path of the keys in the JSON document, nil when it is missing.
+func testJSON(doc interface{}, keys ...string) interface{} {
+	for _, k := range keys {
+		m, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		doc = m[k]
+	}
+	return doc
+}
+
+func TestOpenAPI(t *testing.T) {
+	methods := strings.Replace(testItemMethods, "CreateItem(", "// CreateItem adds an item.\n\t// gk:auth none\n\tCreateItem(", 1)
+	testProject(t, map[string]interface{}{"gk_transport": "http", "openapi.servers": []string{"https://acc.example.com"}})
+	testService(t, "acc", methods, testAccTypes)
+	if err := NewOpenAPIGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(testRead(t, "acc/api/openapi.json")), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		path []string
+		want interface{}
+	}{
+		{[]string{"openapi"}, "3.0.3"},
+		{[]string{"info", "version"}, "1.0.0"},
+		// the routes of the http transport with the parameters of the path, query and headers
+		{[]string{"paths", "/items/{id}", "get", "operationId"}, "GetItem"},
+		{[]string{"paths", "/items/{id}", "put", "operationId"}, "Touch"},
+		{[]string{"paths", "/items/{id}", "get", "parameters"}, []interface{}{
+			map[string]interface{}{"name": "id", "in": "path", "required": true, "schema": map[string]interface{}{"type": "integer", "format": "int64"}},
+			map[string]interface{}{"name": "Authorization", "in": "header", "schema": map[string]interface{}{"type": "string"}},
+		}},
+		{[]string{"paths", "/items/{id}", "put", "requestBody"}, nil},
+		// the comment without its directives is the summary
+		{[]string{"paths", "/create-item", "post", "summary"}, "CreateItem adds an item."},
+		{[]string{"paths", "/create-item", "post", "requestBody", "content", "application/json", "schema", "$ref"}, "#/components/schemas/CreateItemReq"},
+		// the responses are wrapped in the envelope
+		{[]string{"paths", "/items/{id}", "get", "responses", "200", "content", "application/json", "schema", "properties", "data", "$ref"}, "#/components/schemas/GetItemRes"},
+		{[]string{"paths", "/items/{id}", "get", "responses", "401", "$ref"}, "#/components/responses/Unauthorized"},
+		{[]string{"paths", "/items/{id}", "get", "security"}, []interface{}{map[string]interface{}{"bearerAuth": []interface{}{}}}},
+		// the methods without authentication
+		{[]string{"paths", "/create-item", "post", "responses", "401"}, nil},
+		{[]string{"paths", "/create-item", "post", "security"}, []interface{}{}},
+		// the structures are components with the names of their json fields
+		{[]string{"components", "schemas", "Account", "properties", "created_at"}, map[string]interface{}{"type": "string", "format": "date-time"}},
+		{[]string{"components", "schemas", "GetItemRes", "properties", "item", "$ref"}, "#/components/schemas/Account"},
+		{[]string{"components", "schemas", "Error", "properties", "data", "items", "$ref"}, "#/components/schemas/FieldViolation"},
+		{[]string{"components", "securitySchemes", "bearerAuth", "scheme"}, "bearer"},
+	} {
+		if got := testJSON(doc, c.path...); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s is %v, want %v", strings.Join(c.path, "."), got, c.want)
+		}
+	}
+	if got := testJSON(doc, "servers"); !reflect.DeepEqual(got, []interface{}{map[string]interface{}{"url": "https://acc.example.com"}}) {
+		t.Errorf("the servers are %v", got)
+	}
+
+	// the problem details replace the envelope
+	testProject(t, map[string]interface{}{"gk_transport": "http", "httptransport.envelope": "problem"})
+	testService(t, "acc", methods, testAccTypes)
+	if err := NewOpenAPIGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	doc = nil
+	if err := json.Unmarshal([]byte(testRead(t, "acc/api/openapi.json")), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := testJSON(doc, "paths", "/items/{id}", "get", "responses", "200", "content", "application/json", "schema", "$ref"); got != "#/components/schemas/GetItemRes" {
+		t.Errorf("the response is %v, want GetItemRes", got)
+	}
+	if got := testJSON(doc, "components", "responses", "Error", "content", "application/problem+json", "schema", "$ref"); got != "#/components/schemas/Error" {
+		t.Errorf("the error is %v, want a problem", got)
+	}
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+)
+
+// ProtoCompiler runs protoc on the proto of a service, the go messages and the gRPC service
+// are written next to the proto.
+type ProtoCompiler struct {
+}
+
+func NewProtoCompiler() *ProtoCompiler {
+	return &ProtoCompiler{}
+}
+
+func (pc *ProtoCompiler) Compile(name string) error {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	fname := utils.ToLowerSnakeCase(name) + ".proto"
+	exist, err := defaultFs.Exists(path + defaultFs.FilePathSeparator() + fname)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("The proto of service `%s` was not found, create it with `gk add grpc %s`", name, name)
+	}
+	protoc, err := lookupTool(viper.GetString("pb.protoc"))
+	if err != nil {
+		return errors.New("protoc was not found, install it from https://github.com/protocolbuffers/protobuf/releases " +
+			"and add it to the PATH or set `pb.protoc` in gk.json to its path")
+	}
+	goPlugin, err := lookupTool("protoc-gen-go")
+	if err != nil {
+		return errors.New("protoc-gen-go was not found in the PATH, GOBIN or GOPATH/bin, install it with " +
+			"`go install google.golang.org/protobuf/cmd/protoc-gen-go@latest`")
+	}
+
+	// the proto folder first, then the well known types shipped with protoc and the includes of gk.json
+	dir := filepath.Join(viper.GetString("gk_folder"), path)
+	args := []string{"-I", "."}
+	if include := filepath.Join(filepath.Dir(filepath.Dir(protoc)), "include"); isDir(include) {
+		args = append(args, "-I", include)
+	}
+	for _, v := range viper.GetStringSlice("pb.includes") {
+		include, err := filepath.Abs(filepath.Join(viper.GetString("gk_folder"), v))
+		if err != nil {
+			return err
+		}
+		args = append(args, "-I", include)
+	}
+
+	opts := []string{"paths=source_relative"}
+	if pbModel, err := LoadServiceProto(name); err == nil && pbModel.GoPackage() == "" {
+		pbImport, err := ProjectImport(path)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, fmt.Sprintf("M%s=%s;%spb", fname, pbImport, utils.ToLowerSnakeCase(name)))
+	}
+	args = append(args, "--plugin=protoc-gen-go="+goPlugin)
+	if grpcPlugin, err := lookupTool("protoc-gen-go-grpc"); err == nil {
+		args = append(args,
+			fmt.Sprintf("--go_out=%s:.", strings.Join(opts, ",")),
+			"--plugin=protoc-gen-go-grpc="+grpcPlugin,
+			fmt.Sprintf("--go-grpc_out=%s:.", strings.Join(append(opts, "require_unimplemented_servers=false"), ",")),
+		)
+	} else {
+		logrus.Warn("protoc-gen-go-grpc was not found, the service is compiled with the grpc plugin of protoc-gen-go " +
+			"that only older versions have. Install it with `go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest`")
+		args = append(args, fmt.Sprintf("--go_out=%s:.", strings.Join(append([]string{"plugins=grpc"}, opts...), ",")))
+	}
+	args = append(args, fname)
+
+	logrus.Infof("Compiling %s", filepath.Join(dir, fname))
+	logrus.Debugf("%s %s", protoc, strings.Join(args, " "))
+	cmd := exec.Command(protoc, args...)
+	cmd.Dir = dir
+	out := bytes.NewBufferString("")
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err = cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("protoc could not compile `%s`:\n%s", fname, msg)
+		}
+		return fmt.Errorf("protoc could not compile `%s`: %s", fname, err)
+	}
+	return nil
+}
+
+// Refresh compiles the proto of the service, when protoc fails the compiled pb found is kept
+// so the transports can still be generated from it.
+func (pc *ProtoCompiler) Refresh(name string) error {
+	err := pc.Compile(name)
+	if err == nil {
+		return nil
+	}
+	if yes, _ := IsProtoCompiled(name); !yes {
+		return err
+	}
+	logrus.Warn(err)
+	logrus.Warn("The proto could not be compiled, the compiled pb found is used")
+	return nil
+}
+
+// lookupTool finds a program in the PATH, then in the folders `go install` writes to.
+func lookupTool(name string) (string, error) {
+	if p, err := exec.LookPath(name); err == nil {
+		return filepath.Abs(p)
+	}
+	if runtime.GOOS == "windows" && !strings.HasSuffix(name, ".exe") {
+		name += ".exe"
+	}
+	dirs := []string{os.Getenv("GOBIN")}
+	for _, v := range filepath.SplitList(utils.GetGOPATH()) {
+		dirs = append(dirs, filepath.Join(v, "bin"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "go", "bin"))
+	}
+	for _, v := range dirs {
+		if v == "" {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(v, name)); err == nil && !info.IsDir() {
+			return filepath.Join(v, name), nil
+		}
+	}
+	return "", fmt.Errorf("%s not found", name)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
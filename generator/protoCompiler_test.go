@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+// testTool writes a shell script standing for a tool in dir.
+func testTool(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestProtoCompile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the tools are shell scripts")
+	}
+	testProject(t, nil)
+	err := NewProtoCompiler().Compile("acc")
+	if err == nil || !strings.Contains(err.Error(), "gk add grpc acc") {
+		t.Fatalf("got %v, want the proto not found", err)
+	}
+	if err := fs.Get().WriteFile("acc/accpb/acc.proto", "syntax = \"proto3\";\n\npackage accpb;\n", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewProtoCompiler().Compile("acc"); err == nil || !strings.Contains(err.Error(), "protoc was not found") {
+		t.Fatalf("got %v, want protoc not found", err)
+	}
+	// without a compiled pb the refresh fails like the compilation, with one it is kept
+	if err := NewProtoCompiler().Refresh("acc"); err == nil {
+		t.Fatal("the refresh without protoc nor pb succeeded")
+	}
+	if err := fs.Get().WriteFile("acc/accpb/acc.pb.go", "package accpb\n", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewProtoCompiler().Refresh("acc"); err != nil {
+		t.Fatalf("the compiled pb was not kept: %s", err)
+	}
+
+	// protoc runs in the folder of the proto with the plugins found in GOBIN
+	dir, err := ioutil.TempDir("", "gk-protoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bin, project := filepath.Join(dir, "bin"), filepath.Join(dir, "project")
+	for _, v := range []string{bin, filepath.Join(project, "acc", "accpb")} {
+		if err := os.MkdirAll(v, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gobin := os.Getenv("GOBIN")
+	defer os.Setenv("GOBIN", gobin)
+	os.Setenv("GOBIN", bin)
+	testTool(t, bin, "protoc-gen-go", "")
+	testTool(t, bin, "protoc-gen-go-grpc", "")
+	viper.Set("gk_folder", project)
+	viper.Set("pb.protoc", testTool(t, dir, "protoc", `pwd > args.txt; echo "$@" >> args.txt`))
+	if err := NewProtoCompiler().Compile("acc"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(project, "acc", "accpb", "args.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		filepath.Join("acc", "accpb") + "\n-I .",
+		"--plugin=protoc-gen-go=" + filepath.Join(bin, "protoc-gen-go"),
+		"--go-grpc_out=paths=source_relative,Macc.proto=",
+		"require_unimplemented_servers=false:.",
+		" acc.proto",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in the call\n%s", want, got)
+		}
+	}
+
+	// the output of a failing protoc is the error
+	viper.Set("pb.protoc", testTool(t, dir, "protoc", "echo 'acc.proto:3:1: Expected \";\".'; exit 1"))
+	err = NewProtoCompiler().Compile("acc")
+	if err == nil || !strings.Contains(err.Error(), `acc.proto:3:1: Expected ";".`) {
+		t.Errorf("got %v, want the output of protoc", err)
+	}
+}
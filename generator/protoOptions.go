@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/proto"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+)
+
+// pbOptions are the settings of gk.json for the protos gk writes: `pb.package` names the proto
+// package, `pb.imports` and `pb.options` are added to the file and the `pb.field_options`
+// rules give their options to the fields gk adds. Existing declarations are never changed.
+type pbOptions struct {
+	Package string
+	Imports []string
+	Options []parser.ProtoOption
+	Fields  []pbFieldRule
+}
+
+// pbFieldRule is an option given to every field gk adds, the value is a template of the
+// field `Name`, `Type` and `Message`.
+type pbFieldRule struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
+// protoEnumConstant matches the enum constants, the other string values are quoted.
+var protoEnumConstant = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+func loadPBOptions(name string) (*pbOptions, error) {
+	te := template.NewEngine()
+	pkg, err := te.ExecuteString(viper.GetString("pb.package"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return nil, err
+	}
+	if pkg == "" {
+		pkg = utils.ToLowerSnakeCase(name) + "pb"
+	}
+	path, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return nil, err
+	}
+	pbImport, err := ProjectImport(path)
+	if err != nil {
+		return nil, err
+	}
+	po := &pbOptions{Package: pkg, Imports: viper.GetStringSlice("pb.imports")}
+	if err = viper.UnmarshalKey("pb.field_options", &po.Fields); err != nil {
+		return nil, fmt.Errorf("`pb.field_options` is invalid: %s", err)
+	}
+	options := viper.GetStringMap("pb.options")
+	keys := []string{}
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := options[k]
+		if s, ok := value.(string); ok {
+			if value, err = te.ExecuteString(s, map[string]string{
+				"ServiceName": name,
+				"Package":     pkg,
+				"Import":      pbImport,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		po.Options = append(po.Options, parser.ProtoOption{Name: k, Value: protoLiteral(value)})
+	}
+	return po, nil
+}
+
+// applyFile adds the imports and the file options the proto does not declare yet.
+func (po *pbOptions) applyFile(pbModel *parser.Proto) {
+	if po == nil {
+		return
+	}
+	for _, v := range po.Imports {
+		hasImport := false
+		for _, vv := range pbModel.Imports {
+			if vv.Filename == v {
+				hasImport = true
+				break
+			}
+		}
+		if !hasImport {
+			pbModel.Imports = append(pbModel.Imports, &proto.Import{Filename: v})
+		}
+	}
+	for _, v := range po.Options {
+		hasOption := false
+		for _, vv := range pbModel.Options {
+			if vv.Name == v.Name {
+				hasOption = true
+				break
+			}
+		}
+		if !hasOption {
+			pbModel.Options = append(pbModel.Options, v)
+		}
+	}
+}
+
+// fieldOptions are the options the rules give to a field of the message.
+func (po *pbOptions) fieldOptions(message string, f parser.ProtoField) ([]parser.ProtoOption, error) {
+	if po == nil {
+		return nil, nil
+	}
+	te := template.NewEngine()
+	options := []parser.ProtoOption{}
+	for _, v := range po.Fields {
+		value, err := te.ExecuteString(v.Value, map[string]string{
+			"Name":    f.Name,
+			"Type":    f.FullType(),
+			"Message": message,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("the field option `%s`: %s", v.Name, err)
+		}
+		options = append(options, parser.ProtoOption{Name: v.Name, Value: protoLiteral(value)})
+	}
+	return options, nil
+}
+
+// protoLiteral writes a value of gk.json as a proto constant, strings are quoted unless they
+// are enum constants or quoted already.
+func protoLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		quoted := len(v) > 1 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0]
+		if quoted || protoEnumConstant.MatchString(v) {
+			return v
+		}
+		return strconv.Quote(v)
+	}
+	return strconv.Quote(strings.TrimSpace(fmt.Sprint(value)))
+}
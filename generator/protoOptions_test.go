@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+func TestProtoOptions(t *testing.T) {
+	testProject(t, map[string]interface{}{
+		"pb.package": "acc.v1",
+		"pb.imports": []string{"github.com/gogo/protobuf/gogoproto/gogo.proto"},
+		"pb.options": map[string]interface{}{
+			"go_package":                      "{{.Import}};{{.ServiceName}}pb",
+			"java_package":                    "com.example.acc",
+			"(gogoproto.goproto_getters_all)": false,
+		},
+		"pb.field_options": []map[string]interface{}{
+			{"name": "(gogoproto.jsontag)", "value": "{{toSnakeCase .Name}}"},
+			{"name": "(gogoproto.moretags)", "value": `'xorm:"{{toSnakeCase .Name}}"'`},
+		},
+	})
+	testService(t, "acc", testAccMethods, testAccTypes)
+	if err := NewAddGRPCGenerator().GenerateProtobuf("acc"); err != nil {
+		t.Fatal(err)
+	}
+	proto := testRead(t, "acc/accpb/acc.proto")
+	assertContains(t, proto,
+		`package acc.v1;`,
+		`import "github.com/gogo/protobuf/gogoproto/gogo.proto";`,
+		`option (gogoproto.goproto_getters_all) = false;`,
+		`option java_package = "com.example.acc";`,
+		`/acc/accpb;accpb";`,
+		// the rules give their options to the fields of the requests, responses and structures
+		`int64 Id = 1 [(gogoproto.jsontag) = "id", (gogoproto.moretags) = 'xorm:"id"'];`,
+		`google.protobuf.Timestamp CreatedAt = 3 [(gogoproto.jsontag) = "created_at", (gogoproto.moretags) = 'xorm:"created_at"'];`,
+	)
+
+	// the declarations of the proto are kept, the new fields get the options
+	proto = strings.Replace(proto, `option java_package = "com.example.acc";`, `option java_package = "org.example.acc";`, 1)
+	if err := fs.Get().WriteFile("acc/accpb/acc.proto", proto, true); err != nil {
+		t.Fatal(err)
+	}
+	file := "acc/pkg/accservice/service.go"
+	src := strings.Replace(testRead(t, file), "Get(ctx context.Context, id int64)", "Get(ctx context.Context, id int64, name string)", 1)
+	if err := fs.Get().WriteFile(file, src, true); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("gk_force_override", true)
+	if err := NewAddGRPCGenerator().GenerateProtobuf("acc"); err != nil {
+		t.Fatal(err)
+	}
+	proto = testRead(t, "acc/accpb/acc.proto")
+	assertContains(t, proto,
+		`option java_package = "org.example.acc";`,
+		`string Name = 2 [(gogoproto.jsontag) = "name", (gogoproto.moretags) = 'xorm:"name"'];`,
+	)
+	assertNotContains(t, proto, `com.example.acc`)
+}
+
+func TestProtoLiteral(t *testing.T) {
+	for _, c := range []struct {
+		value interface{}
+		want  string
+	}{
+		{true, "true"},
+		{1.5, "1.5"},
+		{float64(3), "3"},
+		{7, "7"},
+		{"SPEED", "SPEED"},
+		{"com.example", `"com.example"`},
+		{`"quoted"`, `"quoted"`},
+		{`'xorm:"id"'`, `'xorm:"id"'`},
+		{`say "hi"`, `"say \"hi\""`},
+	} {
+		if got := protoLiteral(c.value); got != c.want {
+			t.Errorf("protoLiteral(%#v) = %s, want %s", c.value, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
+)
+
+// resilienceMiddlewareNames are the middleware a method gets, even out of its chain, once it has
+// its own settings.
+var resilienceMiddlewareNames = []string{"ratelimit", "circuitbreaker", "timeout"}
+
+// resilience are the settings of the rate limit, the circuit breaker and the timeout of the
+// endpoint of a method.
+type resilience struct {
+	RateLimit float64
+	Burst     int
+	Timeout   time.Duration
+	// the circuit breaker opens after Failures consecutive failures, stays open for
+	// BreakerTimeout, then lets MaxRequests requests through; the counts are cleared every
+	// Interval while it is closed, never when it is zero.
+	Failures       uint32
+	MaxRequests    uint32
+	Interval       time.Duration
+	BreakerTimeout time.Duration
+	// own are the middleware of the settings of the method, by name.
+	own map[string]bool
+}
+
+// methodResilience are the settings of the method, the first of: its `gk:ratelimit <rate> [burst]`,
+// `gk:circuitbreaker <key>=<value>...` and `gk:timeout <duration>` directives,
+// `endpoints.methods.<Method>` and `endpoints`.
+func methodResilience(m parser.Method) (resilience, error) {
+	r := resilience{own: map[string]bool{}}
+	mkey := fmt.Sprintf("endpoints.methods.%s", m.Name)
+	first := func(key string) string {
+		if viper.IsSet(mkey + "." + key) {
+			return mkey + "." + key
+		}
+		return "endpoints." + key
+	}
+	for k, v := range map[string]string{"rate_limit": "ratelimit", "burst": "ratelimit", "timeout": "timeout",
+		"circuitbreaker": "circuitbreaker"} {
+		r.own[v] = r.own[v] || viper.IsSet(mkey+"."+k)
+	}
+
+	r.RateLimit, r.Burst = viper.GetFloat64(first("rate_limit")), viper.GetInt(first("burst"))
+	if d := m.Directives("ratelimit"); len(d) > 0 {
+		r.own["ratelimit"] = true
+		if len(d[0]) == 0 || len(d[0]) > 2 {
+			return r, fmt.Errorf("The gk:ratelimit directive of %s is not `gk:ratelimit <rate> [burst]`", m.Name)
+		}
+		var err error
+		if r.RateLimit, err = strconv.ParseFloat(d[0][0], 64); err != nil {
+			return r, fmt.Errorf("The rate `%s` of the gk:ratelimit directive of %s is not a number", d[0][0], m.Name)
+		}
+		r.Burst = 0
+		if len(d[0]) == 2 {
+			if r.Burst, err = strconv.Atoi(d[0][1]); err != nil {
+				return r, fmt.Errorf("The burst `%s` of the gk:ratelimit directive of %s is not a number", d[0][1], m.Name)
+			}
+		}
+	}
+	if r.RateLimit <= 0 {
+		return r, fmt.Errorf("The rate limit of %s is not a positive number of requests per second", m.Name)
+	}
+	if r.Burst <= 0 {
+		r.Burst = int(math.Max(1, math.Ceil(r.RateLimit)))
+	}
+
+	r.Timeout = viper.GetDuration(first("timeout"))
+	if d := m.Directives("timeout"); len(d) > 0 {
+		r.own["timeout"] = true
+		if len(d[0]) != 1 {
+			return r, fmt.Errorf("The gk:timeout directive of %s is not `gk:timeout <duration>`", m.Name)
+		}
+		var err error
+		if r.Timeout, err = time.ParseDuration(d[0][0]); err != nil {
+			return r, fmt.Errorf("The timeout `%s` of the gk:timeout directive of %s is not a duration", d[0][0], m.Name)
+		}
+	}
+	if r.Timeout <= 0 {
+		return r, fmt.Errorf("The timeout of %s is not a positive duration", m.Name)
+	}
+
+	breaker := map[string]string{}
+	for _, v := range []string{"failures", "max_requests", "interval", "timeout"} {
+		breaker[v] = viper.GetString(first("circuitbreaker." + v))
+	}
+	if d := m.Directives("circuitbreaker"); len(d) > 0 {
+		r.own["circuitbreaker"] = true
+		for _, v := range d[0] {
+			kv := strings.SplitN(v, "=", 2)
+			if _, ok := breaker[kv[0]]; !ok || len(kv) != 2 {
+				return r, fmt.Errorf("The setting `%s` of the gk:circuitbreaker directive of %s is not one of "+
+					"failures=<n>, max_requests=<n>, interval=<duration>, timeout=<duration>", v, m.Name)
+			}
+			breaker[kv[0]] = kv[1]
+		}
+	}
+	for _, v := range []string{"failures", "max_requests"} {
+		n, err := strconv.ParseUint(breaker[v], 10, 32)
+		if err != nil || n == 0 {
+			return r, fmt.Errorf("The circuit breaker %s `%s` of %s is not a positive number", v, breaker[v], m.Name)
+		}
+		if v == "failures" {
+			r.Failures = uint32(n)
+		} else {
+			r.MaxRequests = uint32(n)
+		}
+	}
+	for _, v := range []string{"interval", "timeout"} {
+		d, err := time.ParseDuration(breaker[v])
+		if err != nil || d < 0 {
+			return r, fmt.Errorf("The circuit breaker %s `%s` of %s is not a duration", v, breaker[v], m.Name)
+		}
+		if v == "interval" {
+			r.Interval = d
+		} else {
+			r.BreakerTimeout = d
+		}
+	}
+	return r, nil
+}
+
+// durationLiteral is the Go expression of the duration.
+func durationLiteral(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	for _, u := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	} {
+		if d == u.d {
+			return u.name
+		}
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
+// resilienceStatement is the middleware of the settings, ratelimit, circuitbreaker or timeout.
+func resilienceStatement(name string, r resilience) string {
+	switch name {
+	case "ratelimit":
+		return fmt.Sprintf("RateLimitMiddleware(method, %v, %d)", r.RateLimit, r.Burst)
+	case "circuitbreaker":
+		settings := fmt.Sprintf("MaxRequests: %d", r.MaxRequests)
+		if r.Interval > 0 {
+			settings += ", Interval: " + durationLiteral(r.Interval)
+		}
+		if r.BreakerTimeout > 0 {
+			settings += ", Timeout: " + durationLiteral(r.BreakerTimeout)
+		}
+		return fmt.Sprintf("CircuitBreakerMiddleware(method, %d, gobreaker.Settings{%s})", r.Failures, settings)
+	case "timeout":
+		return fmt.Sprintf("TimeoutMiddleware(%s)", durationLiteral(r.Timeout))
+	}
+	return ""
+}
+
+// clientEndpoint is the statement of the client sets giving the endpoint of the method its
+// rate limit, circuit breaker and timeout.
+func clientEndpoint(name string, m parser.Method) string {
+	return fmt.Sprintf("set.%sEndpoint = %sendpoint.ClientMiddleware(%q)(retry)",
+		utils.ToUpperFirstCamelCase(m.Name), name, utils.ToLowerFirstCamelCase(m.Name))
+}
+
+var clientEndpointRegexp = regexp.MustCompile(`set\.(\w+)Endpoint = retry\b`)
+
+// wrapClientEndpoints wraps the endpoints of the client sets generated before ClientMiddleware.
+func wrapClientEndpoints(body, name string, iface *parser.Interface) string {
+	return clientEndpointRegexp.ReplaceAllStringFunc(body, func(s string) string {
+		for _, m := range iface.Methods {
+			if utils.ToUpperFirstCamelCase(m.Name) == clientEndpointRegexp.FindStringSubmatch(s)[1] {
+				return clientEndpoint(name, m)
+			}
+		}
+		return s
+	})
+}
+
+// generateEndpointsResilience writes the rate limit and the circuit breaker of the endpoints,
+// their metrics and ClientMiddleware, `resilience.go` next to the middleware. It follows gk.json,
+// it is generated again by each update.
+func generateEndpointsResilience(name string, iface *parser.Interface) error {
+	logrus.Info("Generating endpoints resilience...")
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return err
+	}
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	if err = NewErrorCatalogGenerator().Generate(name); err != nil {
+		return err
+	}
+	file := parser.NewFile()
+	file.Package = fmt.Sprintf("%sendpoint", name)
+	file.Imports = []parser.NamedTypeValue{
+		parser.NewNameType("", `"context"`),
+		parser.NewNameType("", `"errors"`),
+		parser.NewNameType("", "\"time\"\n"),
+		parser.NewNameType("", `"github.com/go-kit/kit/endpoint"`),
+		parser.NewNameType("", `"github.com/go-kit/kit/metrics"`),
+		parser.NewNameType("", `"github.com/go-kit/kit/metrics/discard"`),
+	}
+	file.Vars = []parser.NamedTypeValue{
+		parser.NewNameTypeValue("Rejections", "metrics.Counter", "discard.NewCounter()"),
+		parser.NewNameTypeValue("OpenCircuitBreakers", "metrics.Gauge", "discard.NewGauge()"),
+	}
+
+	used := map[string]bool{}
+	cases := ""
+	for _, m := range iface.Methods {
+		chain, err := endpointChain(m)
+		if err != nil {
+			return err
+		}
+		r, err := methodResilience(m)
+		if err != nil {
+			return err
+		}
+		var statements []string
+		for _, n := range chain {
+			if s := resilienceStatement(n, r); s != "" {
+				statements = append(statements, s+",")
+				used[n] = true
+			}
+		}
+		if len(statements) > 0 {
+			cases += fmt.Sprintf("\ncase %q:\nreturn endpoint.Chain(\n%s\n)", utils.ToLowerFirstCamelCase(m.Name),
+				strings.Join(statements, "\n"))
+		}
+	}
+	body := "return func(next endpoint.Endpoint) endpoint.Endpoint { return next }"
+	if cases != "" {
+		body = "switch method {" + cases + "\n}\n" + body
+	}
+
+	rejected, codes := map[string]string{"ratelimit": "ErrRateLimited", "circuitbreaker": "ErrCircuitOpen"}, ""
+	if used["ratelimit"] || used["circuitbreaker"] {
+		if catalogHas(name, "ErrResourceExhausted") && catalogHas(name, "ErrUnavailable") {
+			codes = st.Package
+		} else {
+			logrus.Warnf("The error catalog of service %s has no ErrResourceExhausted or ErrUnavailable, add them "+
+				"with the codes CodeResourceExhausted and CodeUnavailable to send the rejected requests with these codes", name)
+		}
+		file.Structs = append(file.Structs, parser.NewStructWithComment(
+			"RejectedError",
+			`RejectedError is the error of a request the rate limit or the open circuit breaker of its
+			endpoint rejected, a resource exhausted or an unavailable error of the service.`,
+			[]parser.NamedTypeValue{parser.NewNameType("Err", "error")},
+		))
+		file.Structs[0].Vars[0].Tag = ""
+		unwrap, message := "return e.Err", "return e.Err.Error()"
+		if codes != "" {
+			unwrap = fmt.Sprintf(`if e.Err == ErrRateLimited {
+				return %s.ErrResourceExhausted
+			}
+			return %s.ErrUnavailable`, codes, codes)
+			message = "return e.Err.Error() + \": \" + e.Unwrap().Error()"
+		}
+		file.Methods = append(file.Methods,
+			parser.NewMethod(
+				"Error",
+				parser.NewNameType("e", "*RejectedError"),
+				message,
+				[]parser.NamedTypeValue{},
+				[]parser.NamedTypeValue{parser.NewNameType("", "string")},
+			),
+			parser.NewMethod(
+				"Unwrap",
+				parser.NewNameType("e", "*RejectedError"),
+				unwrap,
+				[]parser.NamedTypeValue{},
+				[]parser.NamedTypeValue{parser.NewNameType("", "error")},
+			),
+			parser.NewMethod(
+				"Is",
+				parser.NewNameType("e", "*RejectedError"),
+				"return target == e.Err",
+				[]parser.NamedTypeValue{parser.NewNameType("target", "error")},
+				[]parser.NamedTypeValue{parser.NewNameType("", "bool")},
+			),
+		)
+	}
+	if used["ratelimit"] {
+		file.Imports = append(file.Imports, parser.NewNameType("", `"golang.org/x/time/rate"`))
+		file.Vars = append(file.Vars, parser.NewNameTypeValue(rejected["ratelimit"], "", `errors.New("rate limit exceeded")`))
+		file.Methods = append(file.Methods, parser.NewMethodWithComment(
+			"RateLimitMiddleware",
+			`RateLimitMiddleware returns an endpoint middleware that lets limit requests per second
+			through, in bursts of burst requests at most. The others fail with a RejectedError.`,
+			parser.NamedTypeValue{},
+			`limiter := rate.NewLimiter(limit, burst)
+			return func(next endpoint.Endpoint) endpoint.Endpoint {
+				return func(ctx context.Context, request interface{}) (interface{}, error) {
+					if !limiter.Allow() {
+						Rejections.With("method", method, "reason", "ratelimit").Add(1)
+						return nil, &RejectedError{Err: ErrRateLimited}
+					}
+					return next(ctx, request)
+				}
+			}`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("method", "string"),
+				parser.NewNameType("limit", "rate.Limit"),
+				parser.NewNameType("burst", "int"),
+			},
+			[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+		))
+	}
+	if used["circuitbreaker"] {
+		file.Imports = append(file.Imports, parser.NewNameType("", `"github.com/sony/gobreaker"`))
+		file.Vars = append(file.Vars, parser.NewNameTypeValue(rejected["circuitbreaker"], "", `errors.New("circuit breaker is open")`))
+		file.Methods = append(file.Methods,
+			parser.NewMethodWithComment(
+				"CircuitBreakerMiddleware",
+				`CircuitBreakerMiddleware returns an endpoint middleware that opens the circuit after
+				failures consecutive failures, the requests fail with a RejectedError while it is open.
+				The failures are the errors of breakerFailure, returned or in the Failer responses.`,
+				parser.NamedTypeValue{},
+				`settings.Name = method
+				settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+					return counts.ConsecutiveFailures >= failures
+				}
+				settings.OnStateChange = func(_ string, _, to gobreaker.State) {
+					open := 0.0
+					if to == gobreaker.StateOpen {
+						open = 1
+					}
+					OpenCircuitBreakers.With("method", method).Set(open)
+				}
+				cb := gobreaker.NewCircuitBreaker(settings)
+				return func(next endpoint.Endpoint) endpoint.Endpoint {
+					return func(ctx context.Context, request interface{}) (interface{}, error) {
+						var (
+							response interface{}
+							err      error
+						)
+						_, cbErr := cb.Execute(func() (interface{}, error) {
+							response, err = next(ctx, request)
+							failure := err
+							if f, ok := response.(Failer); ok && err == nil {
+								failure = f.Failed()
+							}
+							if failure != nil && breakerFailure(failure) {
+								return nil, failure
+							}
+							return nil, nil
+						})
+						if cbErr == gobreaker.ErrOpenState || cbErr == gobreaker.ErrTooManyRequests {
+							Rejections.With("method", method, "reason", "circuitbreaker").Add(1)
+							return nil, &RejectedError{Err: ErrCircuitOpen}
+						}
+						return response, err
+					}
+				}`,
+				[]parser.NamedTypeValue{
+					parser.NewNameType("method", "string"),
+					parser.NewNameType("failures", "uint32"),
+					parser.NewNameType("settings", "gobreaker.Settings"),
+				},
+				[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+			),
+			parser.NewMethodWithComment(
+				"breakerFailure",
+				`breakerFailure tells if the error is a failure for the circuit breakers: the errors out
+				of the error catalog, internal, unavailable or past their deadline.`,
+				parser.NamedTypeValue{},
+				fmt.Sprintf(`switch %s.ErrorCode(err) {
+				case %s.CodeUnknown, %s.CodeInternal, %s.CodeUnavailable, %s.CodeDeadlineExceeded:
+					return true
+				}
+				return false`, st.Package, st.Package, st.Package, st.Package, st.Package),
+				[]parser.NamedTypeValue{parser.NewNameType("err", "error")},
+				[]parser.NamedTypeValue{parser.NewNameType("", "bool")},
+			),
+		)
+	}
+	if codes != "" || used["circuitbreaker"] {
+		file.Imports = append(file.Imports, parser.NewNameType(st.Package, fmt.Sprintf("%q", serviceImport)))
+	}
+	file.Methods = append(file.Methods, parser.NewMethodWithComment(
+		"ClientMiddleware",
+		`ClientMiddleware returns the rate limit, the circuit breaker and the timeout of the endpoint
+		of the method, in the order of New, for the endpoint client sets.`,
+		parser.NamedTypeValue{},
+		body,
+		[]parser.NamedTypeValue{parser.NewNameType("method", "string")},
+		[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+	))
+	return defaultFs.WriteFile(path+defaultFs.FilePathSeparator()+"resilience.go", file.String(), true)
+}
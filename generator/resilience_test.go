@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liuchamp/gk/parser"
+)
+
+func TestMethodResilience(t *testing.T) {
+	for _, c := range []struct {
+		settings map[string]interface{}
+		comment  string
+		want     string
+		own      string
+	}{
+		// the settings of gk.json
+		{nil, "", "100 100 10s 5 1 0s 30s", ""},
+		{map[string]interface{}{"endpoints.rate_limit": 0.5, "endpoints.circuitbreaker.interval": "1m"}, "", "0.5 1 10s 5 1 1m0s 30s", ""},
+		{map[string]interface{}{"endpoints.methods.Get.rate_limit": 20, "endpoints.methods.Get.burst": 40}, "", "20 40 10s 5 1 0s 30s", "ratelimit"},
+		{map[string]interface{}{"endpoints.methods.Get.timeout": "2s", "endpoints.methods.Get.circuitbreaker.failures": 9}, "", "100 100 2s 9 1 0s 30s", "circuitbreaker timeout"},
+		// the directives come before the settings
+		{map[string]interface{}{"endpoints.methods.Get.rate_limit": 20, "endpoints.methods.Get.burst": 40}, "// gk:ratelimit 5\n", "5 5 10s 5 1 0s 30s", "ratelimit"},
+		{nil, "// gk:ratelimit 5 10\n// gk:circuitbreaker failures=3 timeout=1m\n// gk:timeout 500ms\n", "5 10 500ms 3 1 0s 1m0s", "circuitbreaker ratelimit timeout"},
+	} {
+		testProject(t, c.settings)
+		r, err := methodResilience(parser.Method{Name: "Get", Comment: c.comment})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := fmt.Sprintf("%v %d %v %d %d %v %v", r.RateLimit, r.Burst, r.Timeout, r.Failures, r.MaxRequests, r.Interval, r.BreakerTimeout)
+		if got != c.want {
+			t.Errorf("%v %q: got %s, want %s", c.settings, c.comment, got, c.want)
+		}
+		var own []string
+		for _, v := range []string{"circuitbreaker", "ratelimit", "timeout"} {
+			if r.own[v] {
+				own = append(own, v)
+			}
+		}
+		if strings.Join(own, " ") != c.own {
+			t.Errorf("%v %q: the own middleware are %v, want %s", c.settings, c.comment, own, c.own)
+		}
+	}
+
+	for _, c := range []struct {
+		settings map[string]interface{}
+		comment  string
+		err      string
+	}{
+		{nil, "// gk:ratelimit\n", "The gk:ratelimit directive of Get is not `gk:ratelimit <rate> [burst]`"},
+		{nil, "// gk:ratelimit fast\n", "The rate `fast` of the gk:ratelimit directive of Get is not a number"},
+		{nil, "// gk:ratelimit 5 many\n", "The burst `many` of the gk:ratelimit directive of Get is not a number"},
+		{map[string]interface{}{"endpoints.rate_limit": 0}, "", "The rate limit of Get is not a positive number of requests per second"},
+		{nil, "// gk:timeout 1s 2s\n", "The gk:timeout directive of Get is not `gk:timeout <duration>`"},
+		{nil, "// gk:timeout soon\n", "The timeout `soon` of the gk:timeout directive of Get is not a duration"},
+		{map[string]interface{}{"endpoints.methods.Get.timeout": "0s"}, "", "The timeout of Get is not a positive duration"},
+		{nil, "// gk:circuitbreaker retries=3\n", "The setting `retries=3` of the gk:circuitbreaker directive of Get is not one of"},
+		{nil, "// gk:circuitbreaker failures=0\n", "The circuit breaker failures `0` of Get is not a positive number"},
+		{nil, "// gk:circuitbreaker interval=-1s\n", "The circuit breaker interval `-1s` of Get is not a duration"},
+	} {
+		testProject(t, c.settings)
+		if _, err := methodResilience(parser.Method{Name: "Get", Comment: c.comment}); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%v %q: got %v, want %s", c.settings, c.comment, err, c.err)
+		}
+	}
+}
+
+func TestDurationLiteral(t *testing.T) {
+	for _, c := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0"},
+		{time.Hour, "time.Hour"},
+		{90 * time.Minute, "90 * time.Minute"},
+		{30 * time.Second, "30 * time.Second"},
+		{500 * time.Millisecond, "500 * time.Millisecond"},
+		{1500 * time.Microsecond, "1500 * time.Microsecond"},
+		{7, "time.Duration(7)"},
+	} {
+		if got := durationLiteral(c.d); got != c.want {
+			t.Errorf("durationLiteral(%v) = %s, want %s", c.d, got, c.want)
+		}
+	}
+}
+
+func TestResilienceInit(t *testing.T) {
+	testProject(t, map[string]interface{}{
+		"gk_transport":         "http",
+		"endpoints.middleware": []string{"auth", "logging", "circuitbreaker", "timeout"},
+	})
+	methods := strings.Replace(testAccMethods, "Get(", "// gk:ratelimit 5 10\n\t// gk:circuitbreaker failures=3 timeout=1m\n\t// gk:timeout 500ms\n\tGet(", 1)
+	testService(t, "acc", methods, testAccTypes)
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	resilience := testRead(t, "acc/pkg/accendpoint/resilience.go")
+	assertContains(t, resilience, `func RateLimitMiddleware(method string, limit rate.Limit, burst int) endpoint.Middleware {`)
+	assertContains(t, resilience, `func CircuitBreakerMiddleware(method string, failures uint32, settings gobreaker.Settings) endpoint.Middleware {`)
+	assertContains(t, resilience, `
+		if e.Err == ErrRateLimited {
+			return accservice.ErrResourceExhausted
+		}
+		return accservice.ErrUnavailable`)
+	assertContains(t, resilience, `case accservice.CodeUnknown, accservice.CodeInternal, accservice.CodeUnavailable, accservice.CodeDeadlineExceeded:`)
+	// the client gets the middleware of the server, from the outermost
+	assertContains(t, resilience, `
+		case "get":
+			return endpoint.Chain(
+				RateLimitMiddleware(method, 5, 10),
+				CircuitBreakerMiddleware(method, 3, gobreaker.Settings{MaxRequests: 1, Timeout: time.Minute}),
+				TimeoutMiddleware(500*time.Millisecond),
+			)`)
+	// the methods streaming their results have no timeout
+	assertContains(t, resilience, `
+		case "watch":
+			return endpoint.Chain(
+				CircuitBreakerMiddleware(method, 5, gobreaker.Settings{MaxRequests: 1, Timeout: 30 * time.Second}),
+			)
+		case "upload":
+			return endpoint.Chain(
+				CircuitBreakerMiddleware(method, 5, gobreaker.Settings{MaxRequests: 1, Timeout: 30 * time.Second}),
+				TimeoutMiddleware(10*time.Second),
+			)`)
+
+	set := testRead(t, "acc/pkg/accendpoint/set.go")
+	assertContains(t, set, `
+		ep := MakeGetEndpoint(svc)
+		ep = TimeoutMiddleware(500 * time.Millisecond)(ep)
+		ep = CircuitBreakerMiddleware(method, 3, gobreaker.Settings{MaxRequests: 1, Timeout: time.Minute})(ep)
+		ep = LoggingMiddleware(log.With(logger, "method", method))(ep)
+		ep = jwtAuth(ep)
+		ep = RateLimitMiddleware(method, 5, 10)(ep)
+		set.GetEndpoint = ep`)
+
+	client := testRead(t, "acc/pkg/acctransport/httpclient.go")
+	for _, v := range []string{"Get", "Watch", "Upload", "Chat"} {
+		assertContains(t, client, fmt.Sprintf(`set.%sEndpoint = accendpoint.ClientMiddleware(%q)(retry)`, v, strings.ToLower(v)))
+	}
+}
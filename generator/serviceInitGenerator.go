@@ -234,11 +234,21 @@ func (sg *ServiceInitGenerator) generateTransport(name string, iface *parser.Int
 		if err := sg.generateHttpTransportTesting(name, iface); err != nil {
 			return err
 		}
-		return nil
+		return sg.generateHttpEndpointClient(name, iface)
 	case "grpc":
 		logrus.Info("Selected grpc transport.")
 		addsg := NewAddGRPCGenerator()
-		return addsg.GenerateProtobuf(name)
+		if err := addsg.GenerateProtobuf(name); err != nil {
+			return err
+		}
+		if err := NewProtoCompiler().Refresh(name); err != nil {
+			return err
+		}
+		g := NewGRPCInitGenerator()
+		if err := g.Generate(name); err != nil {
+			return err
+		}
+		return g.GenerateEndpointClient(name)
 	case "thrift":
 		logrus.Info("Selected thrift transport.")
 		return sg.generateThriftTransport(name, iface)
@@ -271,52 +281,66 @@ func (sg *ServiceInitGenerator) generateHttpTransport(name string, iface *parser
 	}
 	enpointsPath = strings.Replace(enpointsPath, "\\", "/", -1)
 	endpointsImport := projectPath + "/" + enpointsPath
+	router, err := httpRouter()
+	if err != nil {
+		return err
+	}
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	if err = NewErrorCatalogGenerator().Generate(name); err != nil {
+		return err
+	}
+	envelope, err := loadHTTPEnvelope(st)
+	if err != nil {
+		return err
+	}
+	codecs, err := loadHTTPCodecs(name)
+	if err != nil {
+		return err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return err
+	}
 	handlerFile.Imports = []parser.NamedTypeValue{
-		parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
-		parser.NewNameType("stdopentracing", "\"github.com/opentracing/opentracing-go\"\n"),
 		parser.NewNameType("", "\"github.com/go-kit/kit/log\""),
 		parser.NewNameType("", "\"github.com/go-kit/kit/auth/jwt\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/tracing/opentracing\""),
 		parser.NewNameType("httptransport", "\"github.com/go-kit/kit/transport/http\"\n"),
 		parser.NewNameType("", "\""+endpointsImport+"\""),
+		parser.NewNameType("", "\""+serviceImport+"\""),
 	}
-
-	handlerFile.Vars = []parser.NamedTypeValue{
-		parser.NewNameTypeValue("ErrJWTTokenIsExpired", "", `errors.New("rpc error: code = Unknown desc = JWT Token is expired")`),
-		parser.NewNameTypeValue("ErrJWTTokenNotPassParse", "", `errors.New("rpc error: code = Unknown desc = token up for parsing was not passed through the context")`),
-		parser.NewNameTypeValue("ErrJWTTokenIsMalformed", "", `errors.New("rpc error: code = Unknown desc = JWT Token is malformed")`),
-		parser.NewNameTypeValue("ErrAccessRestricted", "", `errors.New("rpc error: code = Unknown desc = access restricted")`),
+	if v, ok := httpRouterImports[router]; ok {
+		handlerFile.Imports = append(handlerFile.Imports, parser.NewNameType("", v))
 	}
+	handlerFile.Imports = append(handlerFile.Imports, codecs.imports()...)
+	handlerFile.Constants = codecs.constants()
 
-	handlerFile.Structs = []parser.Struct{
-		parser.NewStructWithComment(
-			"errorWrapper",
-			``,
-			[]parser.NamedTypeValue{
-				parser.NewNameType("Code", "int"),
-				parser.NewNameType("Msg", "string"),
-			}),
-	}
+	handlerFile.Vars = httpAuthErrors(name, st)
 
 	handlerFile.Methods = append(handlerFile.Methods,
 		parser.NewMethodWithComment(
 			"NewHTTPHandler",
-			`NewHTTPHandler returns a handler that makes a set of endpoints available on
-			 predefined paths.`,
+			httpHandlerComment(router),
 			parser.NamedTypeValue{},
 			`
-			zipkinServer := zipkin.HTTPServerTrace(zipkinTracer)
-			options := []httptransport.ServerOption{
+			options := append([]httptransport.ServerOption{
+				httptransport.ServerBefore(httptransport.PopulateRequestContext),
 				httptransport.ServerErrorEncoder(errorEncoder),
 				httptransport.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
-				zipkinServer,
-			}
+			}, tracingHTTPServerOptions(tracer)...)
 
-			m := http.NewServeMux()`,
+			m := `+httpNewRouter(router),
 			[]parser.NamedTypeValue{
 				parser.NewNameType("endpoints", fmt.Sprintf("%sendpoint", name)+".Set"),
-				//parser.NewNameType("otTracer", "stdopentracing.Tracer"),
-				parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+				tracingParam(name + "endpoint."),
 				parser.NewNameType("logger", "log.Logger"),
 			},
 			[]parser.NamedTypeValue{
@@ -325,16 +349,10 @@ func (sg *ServiceInitGenerator) generateHttpTransport(name string, iface *parser
 		),
 		parser.NewMethodWithComment(
 			"encodeHTTPGenericResponse",
-			`encodeHTTPGenericResponse`,
+			`encodeHTTPGenericResponse is a transport/http.EncodeResponseFunc that encodes the
+			response in the format of the Accept header, the failed responses are sent by errorEncoder.`,
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`if f, ok := response.(%sendpoint.Failer); ok && f.Failed() != nil {
-				errorEncoder(ctx, f.Failed(), w)
-				return nil
-			}
-			res := map[string]interface{}{"msg": "success", "code": 0, "data":response}
-			s, err := json.Marshal(res)
-			w.Write(s)
-			return err`, name),
+			envelope.encodeResponse(name, codecs),
 			[]parser.NamedTypeValue{
 				parser.NewNameType("ctx", "context.Context"),
 				parser.NewNameType("w", "http.ResponseWriter"),
@@ -362,21 +380,9 @@ func (sg *ServiceInitGenerator) generateHttpTransport(name string, iface *parser
 		//),
 		parser.NewMethodWithComment(
 			"errorEncoder",
-			`errorEncoder`,
+			`errorEncoder sends the error with the HTTP status of its code in the error catalog.`,
 			parser.NamedTypeValue{},
-			`code := http.StatusInternalServerError
-			 msg := err.Error()
-			 switch msg {
-			 case ErrJWTTokenIsExpired.Error(), ErrJWTTokenNotPassParse.Error(), ErrJWTTokenIsMalformed.Error():
-				code = 103
-             case ErrAccessRestricted.Error():
-				code = 104
-			 default:
-			 	code = http.StatusInternalServerError
-			 }
-			 w.WriteHeader(http.StatusOK)
-			 s, err := json.Marshal(errorWrapper{Code: code, Msg: msg})
-			 w.Write(s)`,
+			envelope.errorEncoder(name),
 			[]parser.NamedTypeValue{
 				parser.NewNameType("_", "context.Context"),
 				parser.NewNameType("err", "error"),
@@ -385,61 +391,45 @@ func (sg *ServiceInitGenerator) generateHttpTransport(name string, iface *parser
 			[]parser.NamedTypeValue{},
 		),
 	)
+	client := httpNewClient(name, iface, st)
+	var clientCodecs []parser.Method
 	for _, m := range iface.Methods {
-		handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-			fmt.Sprintf("decodeHTTP%sReq", m.Name),
-			fmt.Sprintf(`decodeHTTP%sReq is a transport/http.DecodeRequestFunc that decodes a
-					 JSON-encoded request from the HTTP request body. Primarily useful in a server.`,
-				m.Name),
-			parser.NamedTypeValue{},
-			fmt.Sprintf(`req := %sendpoint.%sReq{}
-			body, _ := ioutil.ReadAll(r.Body)
-			if len(body) == 0 {
-				return req, nil
-			}
-			err := json.Unmarshal(body, &req)
-			return req, err`, name, m.Name),
-			[]parser.NamedTypeValue{
-				parser.NewNameType("_", "context.Context"),
-				parser.NewNameType("r", "*http.Request"),
-			},
-			[]parser.NamedTypeValue{
-				parser.NewNameType("", "interface{}"),
-				parser.NewNameType("", "error"),
-			},
-		))
-		//handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-		//	fmt.Sprintf("encodeHTTP%sRes", m.Name),
-		//	fmt.Sprintf(`encodeHTTP%sRes is a transport/http.EncodeResponseFunc that encodes
-		//		the response as JSON to the response writer. Primarily useful in a server.`, m.Name),
-		//	parser.NamedTypeValue{},
-		//	` w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		//	err = json.NewEncoder(w).Encode(response)
-		//	return err`,
-		//	[]parser.NamedTypeValue{
-		//		parser.NewNameType("_", "context.Context"),
-		//		parser.NewNameType("w", "http.ResponseWriter"),
-		//		parser.NewNameType("response", "interface{}"),
-		//	},
-		//	[]parser.NamedTypeValue{
-		//		parser.NewNameType("err", "error"),
-		//	},
-		//))
-		handlerFile.Methods[0].Body += "\n" + fmt.Sprintf(`
-			{
-				//ops := append(options, httptransport.ServerBefore(opentracing.HTTPToContext(otTracer, "%s", logger)))
-				ops := append(options, httptransport.ServerBefore(jwt.HTTPToContext()))
-				//ops = append(ops, httptransport.ServerBefore(header.HTTPToContext()))
-				m.Handle("/%s", httptransport.NewServer(
-				endpoints.%sEndpoint,
-				decodeHTTP%sReq,
-				encodeHTTPGenericResponse,
-				ops...,
-				))
-			}
-			`, m.Name, utils.ToLowerHyphenCase(m.Name), m.Name, m.Name)
+		route := newHTTPRoute(m, st)
+		handlerFile.Methods = append(handlerFile.Methods, httpDecodeReq(name, m, route, st, codecs))
+		if route.Download != nil {
+			handlerFile.Methods = append(handlerFile.Methods, httpEncodeRes(name, m, route, st))
+		}
+		handlerFile.Methods[0].Body += "\n" + httpRouteHandler(router, m, route) + "\n"
+		client.Body += "\n" + httpClientBlock(name, m, route) + "\n"
+		clientCodecs = append(clientCodecs, httpClientCodecs(name, m, route, st, codecs)...)
 	}
 	handlerFile.Methods[0].Body += "\n" + "return m"
+	client.Body += "\n" + "return set, nil"
+	handlerFile.Methods = append(handlerFile.Methods, httpHelpers(router, st)...)
+	handlerFile.Methods = append(handlerFile.Methods, envelope.helpers()...)
+	auth, err := authTransportHelpers(name, iface, "http")
+	if err != nil {
+		return err
+	}
+	tracing, err := tracingTransportHelpers(name, "http")
+	if err != nil {
+		return err
+	}
+	tracingImports, err := tracingTransportImports("http")
+	if err != nil {
+		return err
+	}
+	handlerFile.Methods = append(handlerFile.Methods, auth...)
+	// the transport already imports some of the packages of the helpers
+	authReplaceHelpers(&handlerFile, tracing, tracingImports, nil)
+	handlerFile.Methods = append(handlerFile.Methods, client)
+	handlerFile.Methods = append(handlerFile.Methods, clientCodecs...)
+	handlerFile.Methods = append(handlerFile.Methods, httpClientHelpers(codecs)...)
+	handlerFile.Methods = append(handlerFile.Methods, httpStreamHelpers(iface, st)...)
+	handlerFile.Methods = append(handlerFile.Methods, envelope.clientHelpers(codecs)...)
+	handlerFile.Methods = append(handlerFile.Methods, codecs.helpers(iface)...)
+	handlerFile.Structs = append(handlerFile.Structs, envelope.structs(name)...)
+	handlerFile.Structs = append(handlerFile.Structs, codecs.structs()...)
 	path, err := te.ExecuteString(viper.GetString("httptransport.path"), map[string]string{
 		"ServiceName":   name,
 		"TransportType": "http",
@@ -477,206 +467,40 @@ func (sg *ServiceInitGenerator) generateHttpTransport(name string, iface *parser
 	}
 	return defaultFs.WriteFile(tfile, handlerFile.String(), false)
 }
-func (sg *ServiceInitGenerator) generateHttpTransportTesting(name string, iface *parser.Interface) error {
-	logrus.Info("Generating http transport testing...")
-	te := template.NewEngine()
+func (sg *ServiceInitGenerator) generateHttpEndpointClient(name string, iface *parser.Interface) error {
 	defaultFs := fs.Get()
-	handlerFile := parser.NewFile()
-	handlerFile.Package = fmt.Sprintf("%stransport", name)
-	gosrc := utils.GetGOPATH() + "/src/"
-	gosrc = strings.Replace(gosrc, "\\", "/", -1)
-	pwd, err := os.Getwd()
+	sfile, err := httpTransportFile(name, "client_file_name")
 	if err != nil {
 		return err
 	}
-	if viper.GetString("gk_folder") != "" {
-		pwd += "/" + viper.GetString("gk_folder")
-	}
-	pwd = strings.Replace(pwd, "\\", "/", -1)
-	projectPath := strings.Replace(pwd, gosrc, "", 1)
-	enpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{
-		"ServiceName": name,
-	})
+	exist, err := defaultFs.Exists(sfile)
 	if err != nil {
 		return err
 	}
-	enpointsPath = strings.Replace(enpointsPath, "\\", "/", -1)
-	endpointsImport := projectPath + "/" + enpointsPath
-	handlerFile.Imports = []parser.NamedTypeValue{
-		parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
-		parser.NewNameType("stdopentracing", "\"github.com/opentracing/opentracing-go\"\n"),
-		parser.NewNameType("", "\"github.com/go-kit/kit/log\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/auth/jwt\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/tracing/opentracing\""),
-		parser.NewNameType("httptransport", "\"github.com/go-kit/kit/transport/http\"\n"),
-		parser.NewNameType("", "\""+endpointsImport+"\""),
+	if exist {
+		logrus.Errorf("Client of http endpoint for service `%s` exist", name)
+		return nil
 	}
-
-	handlerFile.Constants = []parser.NamedTypeValue{
-		parser.NewNameTypeValue("Host", "string", `"http://10.72.17.30"`),
-		parser.NewNameTypeValue("Uid", "int64", `999999999999`),
+	logrus.Info("Generating client of http endpoint...")
+	handler, err := httpEndpointClient(name, iface)
+	if err != nil {
+		return err
 	}
-
-	handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-		"NewJWTToken",
-		`NewJWTToken accept a user id as input parameter, return a string of jwt token`,
-		parser.NamedTypeValue{},
-		`return jwttoken.NewJWTTokenString("############################",userId)`,
-		[]parser.NamedTypeValue{
-			parser.NewNameType("userId", "int64"),
-		},
-		[]parser.NamedTypeValue{
-			parser.NewNameType("", "string"),
-		},
-	))
-
-	handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-		"HTTPPostJSON",
-		`HTTPPostJSON submit content of JSON format string by HTTP POST`,
-		parser.NamedTypeValue{},
-		`
-			reqUrl := host + path
-			fmt.Println("URL: ", reqUrl)
-			var (
-				req  *http.Request
-				resp *http.Response
-				err  error
-			)
-			req, err = http.NewRequest("POST", reqUrl, bytes.NewBuffer(content))
-			if err != nil {
-				fmt.Println("err", err.Error())
-				os.Exit(0)
-			}
-			req.Header.Set("Content-Type", "text/plain")
-			if jwt != "" {
-				req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwt))
-			}
-			client := &http.Client{}
-			resp, err = client.Do(req)
-			if err != nil {
-				fmt.Println("err", err.Error())
-				os.Exit(0)
-			}
-			fmt.Println("Request Header: ", req.Header)
-		
-			defer resp.Body.Close()
-		
-			fmt.Println("response Status:", resp.Status)
-			body, _ := ioutil.ReadAll(resp.Body)
-			return body`,
-		[]parser.NamedTypeValue{
-			parser.NewNameType("host", "string"),
-			parser.NewNameType("path", "string"),
-			parser.NewNameType("content", "[]byte"),
-			parser.NewNameType("jwt", "string"),
-		},
-		[]parser.NamedTypeValue{
-			parser.NewNameType("", "[]byte"),
-		},
-	))
-
-	handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-		"HTTPSPostJSON",
-		`HTTPSPostJSON submit content of JSON format string by HTTPS POST`,
-		parser.NamedTypeValue{},
-		`
-			reqUrl := host + path
-			fmt.Println("URL: ", reqUrl)
-			var (
-				req  *http.Request
-				resp *http.Response
-				err  error
-			)
-			req, err = http.NewRequest("POST", reqUrl, bytes.NewBuffer(content))
-			if err != nil {
-				fmt.Println("err", err.Error())
-				os.Exit(0)
-			}
-			req.Header.Set("Content-Type", "text/plain")
-			if jwt != "" {
-				req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwt))
-			}
-			tr := &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
-			client := &http.Client{Transport: tr, Timeout: time.Second * 5}
-			resp, err = client.Do(req)
-			if err != nil {
-				fmt.Println("err", err.Error())
-				os.Exit(0)
-			}
-			fmt.Println("Request Header: ", req.Header)
-		
-			defer resp.Body.Close()
-		
-			fmt.Println("response Status:", resp.Status)
-			body, _ := ioutil.ReadAll(resp.Body)
-			return body`,
-		[]parser.NamedTypeValue{
-			parser.NewNameType("host", "string"),
-			parser.NewNameType("path", "string"),
-			parser.NewNameType("content", "[]byte"),
-			parser.NewNameType("jwt", "string"),
-		},
-		[]parser.NamedTypeValue{
-			parser.NewNameType("", "[]byte"),
-		},
-	))
-
-	handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-		"HTTPPostForm",
-		`HTTPPostForm submit content of FORM format string by HTTPS POST`,
-		parser.NamedTypeValue{},
-		`
-			reqUrl := host + path
-			resp, err := http.PostForm(reqUrl, form)
-			if err != nil {
-				fmt.Println("http do request err ", err.Error())
-				return nil
-			}
-			defer resp.Body.Close()
-			fmt.Println("response Status:", resp.Status)
-			body, _ := ioutil.ReadAll(resp.Body)
-			return body`,
-		[]parser.NamedTypeValue{
-			parser.NewNameType("host", "string"),
-			parser.NewNameType("path", "string"),
-			parser.NewNameType("form", "url.Values"),
-		},
-		[]parser.NamedTypeValue{
-			parser.NewNameType("", "[]byte"),
-		},
-	))
-
-	for _, m := range iface.Methods {
-		jsonContent := "`{"
-		for _, v := range m.Parameters {
-			if v.Name == "ctx" {
-				continue
-			}
-			jsonContent += fmt.Sprintf(`"%v":xxxxx,`, utils.ToLowerSnakeCase(v.Name))
-		}
-		jsonContent = strings.Trim(jsonContent, ",")
-		jsonContent = strings.TrimSpace(jsonContent)
-		jsonContent += "}`"
-		handlerFile.Methods = append(handlerFile.Methods,
-			parser.NewMethod(
-				fmt.Sprintf("Test%v", m.Name),
-				parser.NamedTypeValue{},
-				fmt.Sprintf(`start := time.Now()
-					path := "/app/user/%s"
-					content := []byte(%s)
-					r := HTTPPostJSON(Host, path, content, NewJWTToken(Uid))
-					fmt.Println("response Body:", string(r))
-					fmt.Println(time.Now().Sub(start))`, utils.ToLowerHyphenCase(m.Name), jsonContent),
-				[]parser.NamedTypeValue{
-					parser.NewNameType("t", "*testing.T"),
-				},
-				[]parser.NamedTypeValue{},
-			),
-		)
+	return defaultFs.WriteFile(sfile, handler.String(), false)
+}
+func (sg *ServiceInitGenerator) generateHttpTransportTesting(name string, iface *parser.Interface) error {
+	logrus.Info("Generating http transport testing...")
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
 	}
-
+	imports, err := httpTestingImports(name, st)
+	if err != nil {
+		return err
+	}
+	handlerFile := httpTesting(name, iface, st, imports)
 	path, err := te.ExecuteString(viper.GetString("httptransport.path"), map[string]string{
 		"ServiceName":   name,
 		"TransportType": "http",
@@ -719,22 +543,12 @@ func (sg *ServiceInitGenerator) generateThriftTransport(name string, iface *pars
 	logrus.Info("Generating thrift transport...")
 	te := template.NewEngine()
 	defaultFs := fs.Get()
-	model := map[string]interface{}{
-		"Name":    utils.ToUpperFirstCamelCase(name),
-		"Methods": []map[string]string{},
-	}
-	mthds := []map[string]string{}
-	for _, v := range iface.Methods {
-		mthds = append(mthds, map[string]string{
-			"Name":    v.Name,
-			"Request": v.Name + "Request",
-			"Reply":   v.Name + "Reply",
-		})
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
 	}
-	model["Methods"] = mthds
-	path, err := te.ExecuteString(viper.GetString("transport.path"), map[string]string{
-		"ServiceName":   name,
-		"TransportType": "thrift",
+	path, err := te.ExecuteString(viper.GetString("thrift.path"), map[string]string{
+		"ServiceName": name,
 	})
 	if err != nil {
 		return err
@@ -745,14 +559,21 @@ func (sg *ServiceInitGenerator) generateThriftTransport(name string, iface *pars
 	}
 	fname := utils.ToLowerSnakeCase(name)
 	tfile := path + defaultFs.FilePathSeparator() + fname + ".thrift"
+	thriftModel := parser.NewThrift()
 	if b {
 		fex, err := defaultFs.Exists(tfile)
 		if err != nil {
 			return err
 		}
 		if fex {
-			logrus.Errorf("Thrift for service `%s` exist", name)
-			return nil
+			src, err := defaultFs.ReadFile(tfile)
+			if err != nil {
+				return err
+			}
+			thriftModel, err = parser.NewThriftParser().Parse([]byte(src))
+			if err != nil {
+				return err
+			}
 		}
 	} else {
 		err = defaultFs.MkdirAll(path)
@@ -760,11 +581,16 @@ func (sg *ServiceInitGenerator) generateThriftTransport(name string, iface *pars
 			return err
 		}
 	}
-	protoTmpl, err := te.Execute("svc.thrift", model)
+	old := thriftModel.String()
+	err = TransferToThriftModel(thriftModel, name, iface, st)
 	if err != nil {
 		return err
 	}
-	err = defaultFs.WriteFile(tfile, protoTmpl, false)
+	if thriftModel.String() == old {
+		logrus.Infof("The thrift of service `%s` already declares every method", name)
+		return nil
+	}
+	err = defaultFs.WriteFile(tfile, thriftModel.String(), true)
 	if err != nil {
 		return err
 	}
@@ -839,6 +665,10 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 		return err
 	}
 	eFile := enpointsPath + defaultFs.FilePathSeparator() + endpointsFileName
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
 	if b {
 		fex, err := defaultFs.Exists(eFile)
 		if err != nil {
@@ -889,13 +719,8 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 
 	//add import
 	file.Imports = []parser.NamedTypeValue{
-		parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
-		parser.NewNameType("stdopentracing", "\"github.com/opentracing/opentracing-go\""),
-		parser.NewNameType("stdjwt", "\"github.com/dgrijalva/jwt-go\"\n"),
 		parser.NewNameType("", "\"github.com/go-kit/kit/log\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/tracing/opentracing\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/metrics\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/auth/jwt\"\n"),
+		parser.NewNameType("", "\"github.com/go-kit/kit/metrics\"\n"),
 		parser.NewNameType("", "\""+serviceImport+"\"\n"),
 	}
 
@@ -915,18 +740,12 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 		parser.NewMethod(
 			"New",
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`
-			kf := func(token *stdjwt.Token) (interface{}, error) {
-				return []byte(GlobalJWTToken), nil
-			}
-			claimsFactory := func() stdjwt.Claims {
-				return &stdjwt.MapClaims{}
-			}`),
+			"",
 			[]parser.NamedTypeValue{
 				parser.NewNameType("svc", fmt.Sprintf("%sservice", name)+"."+iface.Name),
 				parser.NewNameType("logger", "log.Logger"),
 				parser.NewNameType("duration", "metrics.Histogram"),
-				parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+				tracingParam(""),
 			},
 			[]parser.NamedTypeValue{
 				parser.NewNameType("set", "Set"),
@@ -935,18 +754,20 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 	}
 
 	for _, v := range iface.Methods {
+		// the service types are declared in the service package
+		v.Parameters, v.Results = st.QualifyAll(v.Parameters), st.QualifyAll(v.Results)
 		file.Structs[0].Vars = append(file.Structs[0].Vars, parser.NewNameType(v.Name+"Endpoint", "endpoint.Endpoint"))
 		reqPrams := []parser.NamedTypeValue{}
 		for _, p := range v.Parameters {
 			if p.Type != "context.Context" {
-				n := strings.ToUpper(string(p.Name[0])) + p.Name[1:]
+				n := utils.ToUpperFirstCamelCase(p.Name)
 				reqPrams = append(reqPrams, parser.NewNameType(n, p.Type))
 			}
 		}
 
 		resultPrams := []parser.NamedTypeValue{}
 		for _, p := range v.Results {
-			n := strings.ToUpper(string(p.Name[0])) + p.Name[1:]
+			n := utils.ToUpperFirstCamelCase(p.Name)
 			resultPrams = append(resultPrams, parser.NewNameType(n, p.Type))
 		}
 
@@ -954,6 +775,7 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 		res := parser.NewStruct(v.Name+"Res", resultPrams)
 		file.Structs = append(file.Structs, req)
 		file.Structs = append(file.Structs, res)
+		file.Methods = append(file.Methods, validateMethod(v, st))
 
 		//add Failer interface method for response
 		file.Methods = append(file.Methods, parser.NewMethod(
@@ -1004,34 +826,21 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 			response := resp.(%sRes)
 			return %s 
 			`, v.Name,
-				ToReqList(reqPrams),
+				ToReqList(v.Parameters),
 				utils.ToUpperFirstCamelCase(v.Name),
-				ToErrResList(resultPrams),
+				ToErrResList(resultPrams, st),
 				utils.ToUpperFirstCamelCase(v.Name),
 				ToResList(resultPrams)),
 			v.Parameters,
 			v.Results,
 		))
-
-		lowerName := utils.ToLowerFirstCamelCase(v.Name)
-		upperName := utils.ToUpperFirstCamelCase(v.Name)
-
-		file.Methods[0].Body += fmt.Sprintf(`
-		{
-			method := "%s"
-			ep := Make%sEndpoint(svc)
-			//ep = opentracing.TraceServer(otTracer, method)(ep)
-			ep = zipkin.TraceEndpoint(zipkinTracer,  method)(ep)
-			ep = LoggingMiddleware(log.With(logger, "method", method))(ep)
-			ep = InstrumentingMiddleware(duration.With("method", method))(ep)
-			ep = jwt.NewParser(kf, stdjwt.SigningMethodHS256, claimsFactory)(ep)
-			set.%sEndpoint = ep
-		}
-		`, lowerName,
-			upperName,
-			upperName)
 	}
-	file.Methods[0].Body += "\n return set"
+	body, imports, err := endpointsNew(iface)
+	if err != nil {
+		return err
+	}
+	file.Methods[0].Body = body
+	file.Imports = append(file.Imports, imports...)
 
 	err = defaultFs.WriteFile(eFile, file.String(), false)
 	if err != nil {
@@ -1042,7 +851,13 @@ func (sg *ServiceInitGenerator) generateEndpoints(name string, iface *parser.Int
 	if err != nil {
 		return err
 	}
-	return nil
+	if err = generateEndpointsAuth(name, iface); err != nil {
+		return err
+	}
+	if err = generateEndpointsResilience(name, iface); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
 }
 
 func (sg *ServiceInitGenerator) generateEndpointsMiddleware(name string, iface *parser.Interface) error {
@@ -1137,6 +952,15 @@ func (sg *ServiceInitGenerator) generateEndpointsMiddleware(name string, iface *
 			parser.NewNameType("", "endpoint.Middleware"),
 		},
 	))
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	file.Structs = append(file.Structs, validateStructs()...)
+	file.Methods = append(file.Methods, validateMethods(st)...)
+	if endpointsTimeout(iface) {
+		file.Methods = append(file.Methods, timeoutMiddleware())
+	}
 	//file.Methods = append(file.Methods, parser.NewMethodWithComment(
 	//	"AuthenticationMiddleware",
 	//	fmt.Sprintf(`
@@ -1371,7 +1195,10 @@ func (sg *ServiceInitGenerator) generateServiceLoggingMiddleware(name string, if
 
 func ToReqList(params []parser.NamedTypeValue) (list string) {
 	for _, v := range params {
-		list += fmt.Sprintf("%v:%v,", v.Name, utils.ToLowerFirstCamelCase(v.Name))
+		if v.Type == "context.Context" {
+			continue
+		}
+		list += fmt.Sprintf("%v:%v,", utils.ToUpperFirstCamelCase(v.Name), v.Name)
 	}
 	return
 }
@@ -1384,9 +1211,9 @@ func ToResList(params []parser.NamedTypeValue) (list string) {
 	return
 }
 
-func ToErrResList(params []parser.NamedTypeValue) (list string) {
+func ToErrResList(params []parser.NamedTypeValue, st *ServiceTypes) (list string) {
 	for _, v := range params {
-		list += fmt.Sprintf("%v,", getEmptyExpOfTypeName(v.Type))
+		list += fmt.Sprintf("%v,", st.Zero(v.Type))
 	}
 	list = strings.TrimSpace(list)
 	list = strings.TrimRight(list, ",")
@@ -1398,13 +1225,17 @@ func getEmptyExpOfTypeName(typeName string) string {
 	if typeName == "error" {
 		return "err"
 	}
-	if strings.Contains(typeName, "[]") || strings.Contains(typeName, "map") || strings.Contains(typeName, "chan") {
+	if strings.HasPrefix(typeName, "*") || strings.Contains(typeName, "[]") || strings.Contains(typeName, "map") ||
+		strings.Contains(typeName, "chan") || strings.HasPrefix(typeName, "func") || typeName == "interface{}" || httpReader(typeName) {
 		return "nil"
 	}
 	switch typeName {
 	case "string":
 		return `""`
-	case "byte", "uint8", "uint16", "uint", "uint32", "uint64", "int8", "int16", "int", "int32", "int64", "float32":
+	case "bool":
+		return "false"
+	case "byte", "rune", "uint8", "uint16", "uint", "uint32", "uint64", "uintptr", "int8", "int16", "int", "int32", "int64",
+		"float32", "float64", "complex64", "complex128", "time.Duration":
 		return "0"
 	}
 	return typeName + "{}"
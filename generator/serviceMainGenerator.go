@@ -2,14 +2,26 @@ package generator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	"github.com/liuchamp/gk/fs"
 	template "github.com/liuchamp/gk/templates"
+	"github.com/liuchamp/gk/utils"
 )
 
+// grpcInterceptors are the interceptors the service main can chain, by their name in
+// `grpctransport.interceptors`, with the stem of their functions.
+var grpcInterceptors = map[string]string{
+	"recovery":   "recovery",
+	"request_id": "requestID",
+	"metrics":    "metrics",
+}
+
+var grpcInterceptorNames = []string{"recovery", "request_id", "metrics"}
+
 type ServiceMainGenerator struct {
 }
 
@@ -43,7 +55,32 @@ func (sg *ServiceMainGenerator) Generate(name string) error {
 	if err != nil {
 		return err
 	}
-	tmpl, err := te.Execute("main_svc", model)
+	po, err := loadPBOptions(name)
+	if err != nil {
+		return err
+	}
+	tracing, err := tracingMode()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"ServiceName":   name,
+		"Tracing":       tracing,
+		"Health":        viper.GetBool("grpctransport.health"),
+		"HealthService": po.Package + "." + utils.ToUpperFirstCamelCase(name),
+		"Reflection":    viper.GetBool("grpctransport.reflection"),
+	}
+	interceptors := []string{}
+	for _, v := range viper.GetStringSlice("grpctransport.interceptors") {
+		stem, ok := grpcInterceptors[v]
+		if !ok {
+			return fmt.Errorf("The gRPC interceptor `%s` is not supported, use %s", v, strings.Join(grpcInterceptorNames, ", "))
+		}
+		interceptors = append(interceptors, stem)
+		data[utils.ToUpperFirst(stem)] = true
+	}
+	data["Interceptors"] = interceptors
+	tmpl, err := te.Execute("main_svc", data)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+)
+
+func TestServiceMainGRPCServer(t *testing.T) {
+	testProject(t, map[string]interface{}{"pb.package": "acc.v1"})
+	// the service writes its main
+	testService(t, "acc", testAccMethods, testAccTypes)
+	main := testRead(t, "acc/main.go")
+	assertContains(t, main,
+		`grpc.ChainUnaryInterceptor(
+			recoveryUnaryInterceptor(grpcLogger),
+			requestIDUnaryInterceptor(),
+			metricsUnaryInterceptor(grpcRequests, grpcDuration),
+		),`,
+		`grpc.ChainStreamInterceptor(
+			recoveryStreamInterceptor(grpcLogger),
+			requestIDStreamInterceptor(),
+			metricsStreamInterceptor(grpcRequests, grpcDuration),
+		),`,
+		// the health status is the one of the service of the proto package
+		`healthServer.SetServingStatus("acc.v1.Acc", healthpb.HealthCheckResponse_SERVING)`,
+		`reflection.Register(grpcServer)`,
+		`"google.golang.org/grpc/codes"`,
+		`"google.golang.org/grpc/metadata"`,
+	)
+
+	// the main is written once, it follows the settings of the project when it is created
+	testProject(t, map[string]interface{}{
+		"grpctransport.health":       false,
+		"grpctransport.reflection":   false,
+		"grpctransport.interceptors": []string{"request_id"},
+	})
+	testService(t, "acc", testAccMethods, testAccTypes)
+	main = testRead(t, "acc/main.go")
+	assertContains(t, main, `grpc.ChainUnaryInterceptor(requestIDUnaryInterceptor(),),`)
+	assertNotContains(t, main,
+		`recoveryUnaryInterceptor(`,
+		`metricsStreamInterceptor(`,
+		`healthpb`,
+		`reflection`,
+		`"google.golang.org/grpc/codes"`,
+	)
+
+	testProject(t, map[string]interface{}{"grpctransport.interceptors": []string{}})
+	testService(t, "acc", testAccMethods, testAccTypes)
+	assertNotContains(t, testRead(t, "acc/main.go"), `ChainUnaryInterceptor`, `ChainStreamInterceptor`)
+
+	if err := fs.Get().Fs.Remove("acc/main.go"); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("grpctransport.interceptors", []string{"recovery", "auth"})
+	err := NewServiceMainGenerator().Generate("acc")
+	if err == nil || !strings.Contains(err.Error(), "The gRPC interceptor `auth` is not supported") {
+		t.Errorf("got %v, want the unsupported interceptor", err)
+	}
+}
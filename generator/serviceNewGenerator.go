@@ -83,12 +83,15 @@ func (sg *ServiceGenerator) Generate(name string) error {
 	}
 	if b {
 		logrus.Debug("Service folder already exists")
-		return fs.NewDefaultFs(path).WriteFile(fname, f.String(), false)
+	} else {
+		err = defaultFs.MkdirAll(path)
+		logrus.Debug(fmt.Sprintf("Creating folder structure : %s", path))
+		if err != nil {
+			return err
+		}
 	}
-	err = defaultFs.MkdirAll(path)
-	logrus.Debug(fmt.Sprintf("Creating folder structure : %s", path))
-	if err != nil {
+	if err = defaultFs.WriteFile(path+defaultFs.FilePathSeparator()+fname, f.String(), false); err != nil {
 		return err
 	}
-	return fs.NewDefaultFs(path).WriteFile(fname, f.String(), false)
+	return NewErrorCatalogGenerator().Generate(name)
 }
@@ -3,7 +3,6 @@ package generator
 import (
 	"errors"
 	"fmt"
-	"runtime"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -219,6 +218,13 @@ func (sg *ServiceUpdateGenerator) Generate(name string) error {
 		logrus.Error(err)
 		return err
 	}
+	if transport == "http" && viper.GetBool("openapi.update") {
+		err = NewOpenAPIGenerator().Generate(name)
+		if err != nil {
+			logrus.Error(err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -403,6 +409,10 @@ func (sg *ServiceUpdateGenerator) generateEndpoints(name string, iface *parser.I
 		return err
 	}
 	eFile := enpointsPath + defaultFs.FilePathSeparator() + endpointsFileName
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
 
 	s, err := defaultFs.ReadFile(eFile)
 	if err != nil {
@@ -438,8 +448,6 @@ func (sg *ServiceUpdateGenerator) generateEndpoints(name string, iface *parser.I
 	}
 	newMethodIndex := getNewMethodIndex()
 
-	file.Methods[newMethodIndex].Body = strings.ReplaceAll(file.Methods[newMethodIndex].Body, "return set", "")
-
 	for _, v := range iface.Methods {
 		var isExist bool
 		for _, vv := range file.Methods {
@@ -454,6 +462,20 @@ func (sg *ServiceUpdateGenerator) generateEndpoints(name string, iface *parser.I
 	}
 
 	for _, v := range iface.Methods {
+		// the service types are declared in the service package
+		v.Parameters, v.Results = st.QualifyAll(v.Parameters), st.QualifyAll(v.Results)
+		// the rules of the directives may have changed, Validate is always generated again
+		{
+			var methods []parser.Method
+			for _, vv := range file.Methods {
+				if vv.Name != "Validate" || strings.TrimPrefix(vv.Struct.Type, "*") != v.Name+"Req" {
+					methods = append(methods, vv)
+				}
+			}
+			file.Methods = methods
+			newMethodIndex = getNewMethodIndex()
+			file.Methods = append(file.Methods, validateMethod(v, st))
+		}
 		existCheck := MethodNotExist
 		for _, vv := range file.Methods {
 			if vv.Name == v.Name {
@@ -510,13 +532,13 @@ func (sg *ServiceUpdateGenerator) generateEndpoints(name string, iface *parser.I
 		reqPrams := []parser.NamedTypeValue{}
 		for _, p := range v.Parameters {
 			if p.Type != "context.Context" {
-				n := strings.ToUpper(string(p.Name[0])) + p.Name[1:]
+				n := utils.ToUpperFirstCamelCase(p.Name)
 				reqPrams = append(reqPrams, parser.NewNameType(n, p.Type))
 			}
 		}
 		resultPrams := []parser.NamedTypeValue{}
 		for _, p := range v.Results {
-			n := strings.ToUpper(string(p.Name[0])) + p.Name[1:]
+			n := utils.ToUpperFirstCamelCase(p.Name)
 			resultPrams = append(resultPrams, parser.NewNameType(n, p.Type))
 		}
 
@@ -577,45 +599,140 @@ func (sg *ServiceUpdateGenerator) generateEndpoints(name string, iface *parser.I
 			response := resp.(%sRes)
 			return %s 
 			`, v.Name,
-				ToReqList(reqPrams),
+				ToReqList(v.Parameters),
 				utils.ToUpperFirstCamelCase(v.Name),
-				ToErrResList(resultPrams),
+				ToErrResList(resultPrams, st),
 				utils.ToUpperFirstCamelCase(v.Name),
 				ToResList(resultPrams)),
 			v.Parameters,
 			v.Results,
 		))
 
-		lowerName := utils.ToLowerFirstCamelCase(v.Name)
-		upperName := utils.ToUpperFirstCamelCase(v.Name)
+	}
 
-		if existCheck == MethodNotExist {
-			file.Methods[newMethodIndex].Body += fmt.Sprintf(`
-			{
-				method := "%s"
-				ep := Make%sEndpoint(svc)
-				ep = opentracing.TraceServer(otTracer, method)(ep)
-				ep = zipkin.TraceEndpoint(zipkinTracer,  method)(ep)
-				ep = LoggingMiddleware(log.With(logger, "method", method))(ep)
-				ep = InstrumentingMiddleware(duration.With("method", method))(ep)
-				ep = jwt.NewParser(kf, stdjwt.SigningMethodHS256, claimsFactory)(ep)
-				set.%sEndpoint = ep
+	// the chains follow `endpoints.middleware`, New is always generated again
+	body, imports, err := endpointsNew(iface)
+	if err != nil {
+		return err
+	}
+	file.Methods[newMethodIndex].Body = body
+	migrateTracing(file, "")
+	{
+		// the imports of the middleware out of the chains are dropped, and so are the JWT parser
+		// which moved to auth.go and the kit rate limiter and circuit breaker replaced by
+		// resilience.go
+		unused := map[string]bool{`"github.com/dgrijalva/jwt-go"`: true, `"github.com/go-kit/kit/auth/jwt"`: true,
+			`"github.com/go-kit/kit/ratelimit"`: true, `"golang.org/x/time/rate"`: true,
+			`"github.com/go-kit/kit/circuitbreaker"`: true}
+		for _, l := range endpointMiddlewareImports {
+			for _, v := range l {
+				unused[v.Type] = v.Type != `"time"`
 			}
-			`, lowerName,
-				upperName,
-				upperName)
 		}
-
+		for _, v := range imports {
+			unused[v.Type] = false
+		}
+		var kept []parser.NamedTypeValue
+		for _, v := range file.Imports {
+			if !unused[v.Type] {
+				kept = append(kept, v)
+			}
+		}
+		file.Imports = kept
+	}
+	for _, v := range imports {
+		exists := false
+		for _, vv := range file.Imports {
+			exists = exists || vv.Type == v.Type
+		}
+		if !exists {
+			file.Imports = append(file.Imports, v)
+		}
 	}
-
-	file.Methods[newMethodIndex].Body += "\n return set"
 
 	err = defaultFs.WriteFile(eFile, file.String(), false)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err = sg.generateEndpointsMiddleware(name, iface, st); err != nil {
+		return err
+	}
+	if err = generateEndpointsAuth(name, iface); err != nil {
+		return err
+	}
+	if err = generateEndpointsResilience(name, iface); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
+}
+
+// generateEndpointsMiddleware adds the validation middleware and its errors, and the timeout
+// middleware when a chain needs it, to the endpoint middleware of the services generated before them.
+func (sg *ServiceUpdateGenerator) generateEndpointsMiddleware(name string, iface *parser.Interface, st *ServiceTypes) error {
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	enpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	eFile := enpointsPath + defaultFs.FilePathSeparator() + "middleware.go"
+	s, err := defaultFs.ReadFile(eFile)
+	if err != nil {
+		return err
+	}
+	file, err := parser.NewFileParser().Parse([]byte(s))
+	if err != nil {
+		return err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, v := range validateStructs() {
+		exists := false
+		for _, vv := range file.Structs {
+			exists = exists || vv.Name == v.Name
+		}
+		if !exists {
+			file.Structs = append(file.Structs, v)
+			changed = true
+		}
+	}
+	methods := validateMethods(st)
+	if endpointsTimeout(iface) {
+		methods = append(methods, timeoutMiddleware())
+	}
+	for _, v := range methods {
+		exists := false
+		for _, vv := range file.Methods {
+			exists = exists || (vv.Name == v.Name && vv.Struct.Type == v.Struct.Type)
+		}
+		if !exists {
+			file.Methods = append(file.Methods, v)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	hasImport := false
+	for _, v := range file.Imports {
+		hasImport = hasImport || v.Type == "\""+serviceImport+"\""
+	}
+	if !hasImport {
+		file.Imports = append(file.Imports, parser.NewNameType(st.Package, "\""+serviceImport+"\""))
+	}
+	return defaultFs.WriteFile(eFile, file.String(), false)
 }
 
 func (sg *ServiceUpdateGenerator) generateTransport(name string, iface *parser.Interface, transport string) error {
@@ -627,7 +744,7 @@ func (sg *ServiceUpdateGenerator) generateTransport(name string, iface *parser.I
 		if err := sg.generateHttpTransportTesting(name, iface); err != nil {
 			return err
 		}
-		return nil
+		return sg.generateHttpEndpointClient(name, iface)
 	default:
 		return errors.New(fmt.Sprintf("Transport `%s` not supported", transport))
 	}
@@ -663,6 +780,53 @@ func (sg *ServiceUpdateGenerator) generateHttpTransport(name string, iface *pars
 	if err != nil {
 		return err
 	}
+	migrateTracing(handlerFile, name+"endpoint.")
+
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	if err = NewErrorCatalogGenerator().Generate(name); err != nil {
+		return err
+	}
+	envelope, err := loadHTTPEnvelope(st)
+	if err != nil {
+		return err
+	}
+	codecs, err := loadHTTPCodecs(name)
+	if err != nil {
+		return err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
+		"ServiceName": name,
+	})
+	if err != nil {
+		return err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return err
+	}
+	router := httpFileRouter(handlerFile.Methods[0])
+	if old := httpOldGo(); router == "http" && old != "" {
+		logrus.Warnf("The go.mod of the project requires go %s, the ServeMux of net/http serves the routes of `%s` with 404 before go 1.22", old, tfile)
+	}
+	imports := []string{"\"" + serviceImport + "\""}
+	if v, ok := httpRouterImports[router]; ok {
+		imports = append(imports, v)
+	}
+	for _, v := range imports {
+		hasImport := false
+		for _, vv := range handlerFile.Imports {
+			if vv.Type == v {
+				hasImport = true
+				break
+			}
+		}
+		if !hasImport {
+			handlerFile.Imports = append(handlerFile.Imports, parser.NewNameType("", v))
+		}
+	}
 
 	handlerFile.Methods[0].Body = strings.ReplaceAll(handlerFile.Methods[0].Body, "return m", "")
 
@@ -677,48 +841,142 @@ func (sg *ServiceUpdateGenerator) generateHttpTransport(name string, iface *pars
 		if isExist {
 			continue
 		}
-		handlerFile.Methods = append(handlerFile.Methods, parser.NewMethodWithComment(
-			fmt.Sprintf("decodeHTTP%sReq", m.Name),
-			fmt.Sprintf(`decodeHTTP%sReq is a transport/http.DecodeRequestFunc that decodes a
-					 JSON-encoded request from the HTTP request body. Primarily useful in a server.`,
-				m.Name),
-			parser.NamedTypeValue{},
-			fmt.Sprintf(`req := %sendpoint.%sReq{}
-			body, _ := ioutil.ReadAll(r.Body)
-			if len(body) == 0 {
-				return req, nil
-			}
-			err := json.Unmarshal(body, &req)
-			return req, err`, name, m.Name),
-			[]parser.NamedTypeValue{
-				parser.NewNameType("_", "context.Context"),
-				parser.NewNameType("r", "*http.Request"),
-			},
-			[]parser.NamedTypeValue{
-				parser.NewNameType("", "interface{}"),
-				parser.NewNameType("", "error"),
-			},
-		))
-		handlerFile.Methods[0].Body += "\n" + fmt.Sprintf(`
-			{
-				//ops := append(options, httptransport.ServerBefore(opentracing.HTTPToContext(otTracer, "%s", logger)))
-				ops := append(options, httptransport.ServerBefore(jwt.HTTPToContext()))
-				//ops = append(ops, httptransport.ServerBefore(header.HTTPToContext()))
-				m.Handle("/%s", httptransport.NewServer(
-				endpoints.%sEndpoint,
-				decodeHTTP%sReq,
-				encodeHTTPGenericResponse,
-				ops...,
-				))
-			}`, m.Name, utils.ToLowerHyphenCase(m.Name), m.Name, m.Name)
+		route := newHTTPRoute(m, st)
+		handlerFile.Methods = append(handlerFile.Methods, httpDecodeReq(name, m, route, st, codecs))
+		if route.Download != nil {
+			handlerFile.Methods = append(handlerFile.Methods, httpEncodeRes(name, m, route, st))
+		}
+		handlerFile.Methods[0].Body += "\n" + httpRouteHandler(router, m, route)
 	}
 	handlerFile.Methods[0].Body += "\n" + "return m"
 
+	// the transports generated before the bad requests have the 400 status
+	for k, v := range handlerFile.Methods {
+		if v.Name == "errorEncoder" && !strings.Contains(v.Body, "CodeInvalidArgument") && !strings.Contains(v.Body, "errorCode(err)") {
+			handlerFile.Methods[k].Body = httpErrorCheck(st) + v.Body
+		}
+	}
+	for _, h := range httpHelpers(router, st) {
+		hasHelper := false
+		for _, v := range handlerFile.Methods {
+			if v.Name == h.Name {
+				hasHelper = true
+				break
+			}
+		}
+		if !hasHelper {
+			handlerFile.Methods = append(handlerFile.Methods, h)
+		}
+	}
+
+	// the client endpoints of the new methods, the transports generated before the client get
+	// the whole client
+	k := methodIndex(handlerFile, "NewHTTPClient")
+	if k < 0 {
+		handlerFile.Methods = append(handlerFile.Methods, httpNewClient(name, iface, st))
+		k = len(handlerFile.Methods) - 1
+	}
+	handlerFile.Methods[k].Body = trimReturn(handlerFile.Methods[k].Body, "return set, nil")
+	for _, m := range iface.Methods {
+		if methodIndex(handlerFile, fmt.Sprintf("decodeHTTP%sRes", m.Name)) >= 0 {
+			continue
+		}
+		route := newHTTPRoute(m, st)
+		handlerFile.Methods[k].Body += "\n" + httpClientBlock(name, m, route) + "\n"
+		handlerFile.Methods = append(handlerFile.Methods, httpClientCodecs(name, m, route, st, codecs)...)
+	}
+	handlerFile.Methods[k].Body += "\n" + "return set, nil"
+	helpers := append(envelope.helpers(), httpClientHelpers(codecs)...)
+	helpers = append(helpers, httpStreamHelpers(iface, st)...)
+	for _, h := range append(helpers, envelope.clientHelpers(codecs)...) {
+		if methodIndex(handlerFile, h.Name) < 0 {
+			handlerFile.Methods = append(handlerFile.Methods, h)
+		}
+	}
+	for _, h := range envelope.structs(name) {
+		hasStruct := false
+		for _, v := range handlerFile.Structs {
+			if v.Name == h.Name {
+				hasStruct = true
+				break
+			}
+		}
+		if !hasStruct {
+			handlerFile.Structs = append(handlerFile.Structs, h)
+		}
+	}
+	codecs.update(handlerFile, iface, envelope)
+	auth, err := authTransportHelpers(name, iface, "http")
+	if err != nil {
+		return err
+	}
+	authImports, err := authTransportImports(iface, "http")
+	if err != nil {
+		return err
+	}
+	authReplaceHelpers(handlerFile, auth, authImports, map[string]string{
+		"httptransport.ServerBefore(jwt.HTTPToContext())": "httptransport.ServerBefore(authHTTPToContext)",
+		"httptransport.ClientBefore(jwt.ContextToHTTP())": "httptransport.ClientBefore(authContextToHTTP)",
+	})
+	tracing, err := tracingTransportHelpers(name, "http")
+	if err != nil {
+		return err
+	}
+	tracingImports, err := tracingTransportImports("http")
+	if err != nil {
+		return err
+	}
+	authReplaceHelpers(handlerFile, tracing, tracingImports, nil)
+
 	return defaultFs.WriteFile(tfile, handlerFile.String(), false)
 }
 
+// generateHttpEndpointClient balances the endpoints of the new methods in
+// NewHTTPEndpointClientSet, it makes the client of the transports generated before it.
+func (sg *ServiceUpdateGenerator) generateHttpEndpointClient(name string, iface *parser.Interface) error {
+	defaultFs := fs.Get()
+	sfile, err := httpTransportFile(name, "client_file_name")
+	if err != nil {
+		return err
+	}
+	exist, err := defaultFs.Exists(sfile)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		handler, err := httpEndpointClient(name, iface)
+		if err != nil {
+			return err
+		}
+		return defaultFs.WriteFile(sfile, handler.String(), false)
+	}
+	fileContent, err := defaultFs.ReadFile(sfile)
+	if err != nil {
+		return err
+	}
+	handler, err := parser.NewFileParser().Parse([]byte(fileContent))
+	if err != nil {
+		return err
+	}
+	migrateTracing(handler, name+"endpoint.")
+	k := methodIndex(handler, "NewHTTPEndpointClientSet")
+	if k < 0 {
+		return errors.New("Could not find NewHTTPEndpointClientSet")
+	}
+	body := trimReturn(handler.Methods[k].Body, "return set, nil")
+	for _, v := range iface.Methods {
+		if strings.Contains(body, "Make"+utils.ToUpperFirstCamelCase(v.Name)+"Endpoint") {
+			continue
+		}
+		body += httpEndpointClientBlock(name, v)
+	}
+	handler.Methods[k].Body = wrapClientEndpoints(body, name, iface) + `
+	return set, nil`
+	return defaultFs.WriteFile(sfile, handler.String(), false)
+}
+
 func (sg *ServiceUpdateGenerator) generateHttpTransportTesting(name string, iface *parser.Interface) error {
-	logrus.Info("Updating http transport...")
+	logrus.Info("Updating http transport testing...")
 	te := template.NewEngine()
 	defaultFs := fs.Get()
 	path, err := te.ExecuteString(viper.GetString("httptransport.path"), map[string]string{
@@ -737,6 +995,15 @@ func (sg *ServiceUpdateGenerator) generateHttpTransportTesting(name string, ifac
 	}
 	tfile := path + defaultFs.FilePathSeparator() + fname
 
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return err
+	}
+	imports, err := httpTestingImports(name, st)
+	if err != nil {
+		return err
+	}
+	tests := httpTesting(name, iface, st, imports)
 	s, err := defaultFs.ReadFile(tfile)
 	if err != nil {
 		return err
@@ -747,45 +1014,48 @@ func (sg *ServiceUpdateGenerator) generateHttpTransportTesting(name string, ifac
 	if err != nil {
 		return err
 	}
-
-	for _, m := range iface.Methods {
-		testFunName := fmt.Sprintf("Test%v", m.Name)
-		var isExist bool
-		for _, v := range handlerFile.Methods {
-			if v.Name == testFunName {
-				isExist = true
-			}
+	migrateTracing(handlerFile, name+"endpoint.")
+	// the tests posting to a live host are replaced by the tests over the fake service
+	if methodIndex(handlerFile, "HTTPPostJSON") != -1 {
+		logrus.Infof("Replacing the http transport tests of `%s` posting to a live host", name)
+		return defaultFs.WriteFile(tfile, tests.String(), false)
+	}
+	for _, v := range tests.Imports {
+		hasImport := false
+		for _, i := range handlerFile.Imports {
+			hasImport = hasImport || strings.TrimSpace(i.Type) == strings.TrimSpace(v.Type)
 		}
-		if isExist {
-			continue
+		if !hasImport {
+			handlerFile.Imports = append(handlerFile.Imports, v)
 		}
-
-		jsonContent := "`{"
-		for _, v := range m.Parameters {
-			if v.Name == "ctx" {
-				continue
+	}
+	// the parser tags the fields of the parsed fake service, it is generated again
+	structs := []parser.Struct{}
+	for _, v := range handlerFile.Structs {
+		if v.Name != "fakeService" {
+			structs = append(structs, v)
+		}
+	}
+	handlerFile.Structs = append(structs, tests.Structs...)
+	for _, m := range tests.Methods {
+		k := -1
+		for i, v := range handlerFile.Methods {
+			if v.Name == m.Name && v.Struct.Type == m.Struct.Type {
+				k = i
 			}
-			jsonContent += fmt.Sprintf(`"%v":xxxxx,`, utils.ToLowerSnakeCase(v.Name))
 		}
-		jsonContent = strings.Trim(jsonContent, ",")
-		jsonContent = strings.TrimSpace(jsonContent)
-		jsonContent += "}`"
-		handlerFile.Methods = append(handlerFile.Methods,
-			parser.NewMethod(
-				fmt.Sprintf("Test%v", m.Name),
-				parser.NamedTypeValue{},
-				fmt.Sprintf(`start := time.Now()
-					path := "/app/user/%s"
-					content := []byte(%s)
-					r := HTTPPostJSON(Host, path, content, NewJWTToken(Uid))
-					fmt.Println("response Body:", string(r))
-					fmt.Println(time.Now().Sub(start))`, utils.ToLowerHyphenCase(m.Name), jsonContent),
-				[]parser.NamedTypeValue{
-					parser.NewNameType("t", "*testing.T"),
-				},
-				[]parser.NamedTypeValue{},
-			),
-		)
+		switch {
+		case k == -1:
+			handlerFile.Methods = append(handlerFile.Methods, m)
+		// the set, the tracer and the bad bodies follow the endpoints, the other tests are kept as edited
+		case m.Name == "newTestServer" || m.Name == "newTestClient" || m.Name == "TestHTTPBadRequest":
+			handlerFile.Methods[k] = m
+		}
+	}
+	if methodIndex(tests, "TestHTTPBadRequest") == -1 {
+		if k := methodIndex(handlerFile, "TestHTTPBadRequest"); k != -1 {
+			handlerFile.Methods = append(handlerFile.Methods[:k], handlerFile.Methods[k+1:]...)
+		}
 	}
 
 	return defaultFs.WriteFile(tfile, handlerFile.String(), false)
@@ -793,136 +1063,5 @@ func (sg *ServiceUpdateGenerator) generateHttpTransportTesting(name string, ifac
 
 func (sg *ServiceUpdateGenerator) generateGRPCTransport(name string, iface *parser.Interface) error {
 	logrus.Info("Updating grpc transport...")
-	te := template.NewEngine()
-	defaultFs := fs.Get()
-
-	path, err := te.ExecuteString(viper.GetString("pb.path"), map[string]string{
-		"ServiceName": name,
-		//"TransportType": "grpc",
-	})
-
-	//path += defaultFs.FilePathSeparator() + "pb"
-	//if err != nil {
-	//	return err
-	//}
-	b, err := defaultFs.Exists(path)
-	if err != nil {
-		return err
-	}
-	fname := utils.ToLowerSnakeCase(name)
-	tfile := path + defaultFs.FilePathSeparator() + fname + ".proto"
-	if b {
-		fex, err := defaultFs.Exists(tfile)
-		if err != nil {
-			return err
-		}
-		if fex {
-			logrus.Errorf("Proto for service `%s` exist", name)
-			return nil
-		}
-	} else {
-		err = defaultFs.MkdirAll(path)
-		if err != nil {
-			return err
-		}
-	}
-
-	type ProtobufModel struct {
-		Name    string
-		Methods []parser.Method
-		Structs []parser.Struct
-	}
-	pbModel := ProtobufModel{Name: utils.ToUpperFirstCamelCase(name)}
-	for _, v := range iface.Methods {
-		m := parser.Method{Name: v.Name}
-		for k, kv := range v.Parameters {
-			if kv.Type == "context.Context" {
-				continue
-			} else if kv.Type == "int" {
-				kv.Type = "int32"
-			}
-			kv.Type = strings.ReplaceAll(kv.Type, "[]", "repeated ")
-			//利用 Method.Value 来传递 protobuf index，下标从 1 开始，由于 ctx 参数不用，则跨过 0 下标
-			kv.Value = fmt.Sprintf("%v", k)
-			kv.Name = utils.ToUpperFirstCamelCase(kv.Name)
-			m.Parameters = append(m.Parameters, kv)
-		}
-		for k, kv := range v.Results {
-			if kv.Type == "error" {
-				kv.Type = "string"
-			} else if kv.Type == "int" {
-				kv.Type = "int32"
-			}
-
-			if strings.Contains(kv.Type, "map") {
-				//map[string]string
-				tmp := strings.Split(kv.Type, "[")
-				tmp = strings.Split(tmp[1], "]")
-				mapKeyType := tmp[0]
-				mapValueType := tmp[1]
-				if strings.Contains(mapValueType, ".") {
-					tmp = strings.Split(mapValueType, ".")
-					mapValueType = tmp[1]
-					pbModel.Structs = append(pbModel.Structs, parser.NewStruct(mapValueType, nil))
-				}
-				kv.Type = fmt.Sprintf("map<%v,%v> ", mapKeyType, mapValueType)
-			} else if strings.Contains(kv.Type, "[]") {
-				var elementType string
-				if strings.Contains(kv.Type, ".") {
-					tmp := strings.Split(kv.Type, ".")
-					elementType = tmp[1]
-					pbModel.Structs = append(pbModel.Structs, parser.NewStruct(elementType, nil))
-					kv.Type = fmt.Sprintf("repeated %s ", elementType)
-				} else {
-					kv.Type = strings.ReplaceAll(kv.Type, "[]", "repeated ")
-				}
-			}
-
-			//利用 Method.Value 来传递 protobuf index，下标从 1 开始
-			kv.Value = fmt.Sprintf("%v", k+1)
-			kv.Name = utils.ToUpperFirstCamelCase(kv.Name)
-			m.Results = append(m.Results, kv)
-		}
-		pbModel.Methods = append(pbModel.Methods, m)
-	}
-
-	protoTmpl, err := te.Execute("proto.pb", pbModel)
-	if err != nil {
-		return err
-	}
-	err = defaultFs.WriteFile(tfile, protoTmpl, false)
-	if err != nil {
-		return err
-	}
-	if runtime.GOOS == "windows" {
-		tfile := path + defaultFs.FilePathSeparator() + "compile.bat"
-		cmpTmpl, err := te.Execute("proto_compile.bat", map[string]string{
-			"Name": fname,
-		})
-		if err != nil {
-			return err
-		}
-		logrus.Warn("--------------------------------------------------------------------")
-		logrus.Warn("The service is still not ready!!")
-		logrus.Warn("To create the grpc transport please create your protobuf.")
-		logrus.Warn("Than follow the instructions in compile.bat and compile the .proto file.")
-		logrus.Warnf("After the file is compiled run `gk init grpc %s`.", name)
-		logrus.Warn("--------------------------------------------------------------------")
-		return defaultFs.WriteFile(tfile, cmpTmpl, false)
-	} else {
-		tfile := path + defaultFs.FilePathSeparator() + "compile.sh"
-		cmpTmpl, err := te.Execute("proto_compile.sh", map[string]string{
-			"Name": fname,
-		})
-		if err != nil {
-			return err
-		}
-		logrus.Warn("--------------------------------------------------------------------")
-		logrus.Warn("The service is still not ready!!")
-		logrus.Warn("To create the grpc transport please create your protobuf.")
-		logrus.Warn("Than follow the instructions in compile.sh and compile the .proto file.")
-		logrus.Warnf("After the file is compiled run `gk init grpc %s`.", name)
-		logrus.Warn("--------------------------------------------------------------------")
-		return defaultFs.WriteFile(tfile, cmpTmpl, false)
-	}
+	return NewAddGRPCGenerator().GenerateProtobuf(name)
 }
@@ -9,7 +9,6 @@ import (
 	"github.com/liuchamp/gk/parser"
 	template "github.com/liuchamp/gk/templates"
 	"github.com/liuchamp/gk/utils"
-	"os"
 	"strings"
 )
 
@@ -20,246 +19,907 @@ func NewThriftInitGenerator() *ThriftInitGenerator {
 	return &ThriftInitGenerator{}
 }
 
-func (sg *ThriftInitGenerator) Generate(name string) error {
+// thriftTransport keeps what the thrift transport files of a service need to know.
+type thriftTransport struct {
+	name string
+	// alias is the import alias of the compiled thrift package.
+	alias string
+	// service is the thrift service interface in the compiled thrift package.
+	service string
+	// iface is the qualified service interface.
+	iface   string
+	imports []parser.NamedTypeValue
+	tm      *thriftMapper
+}
+
+func loadThriftTransport(name string) (*thriftTransport, error) {
 	te := template.NewEngine()
-	defaultFs := fs.Get()
-	path, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{
-		"ServiceName": name,
-	})
+	thriftPath, err := te.ExecuteString(viper.GetString("thrift.path"), map[string]string{"ServiceName": name})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	fname, err := te.ExecuteString(viper.GetString("service.file_name"), map[string]string{
-		"ServiceName": name,
-	})
+	thriftImport, err := ProjectImport(thriftPath + "/gen-go/" + utils.ToLowerSnakeCase(name))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	sfile := path + defaultFs.FilePathSeparator() + fname
-	b, err := defaultFs.Exists(sfile)
+	enpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	endpointsImport, err := ProjectImport(enpointsPath)
+	if err != nil {
+		return nil, err
+	}
+	servicePath, err := te.ExecuteString(viper.GetString("service.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return nil, err
+	}
+	serviceImport, err := ProjectImport(servicePath)
+	if err != nil {
+		return nil, err
+	}
+	iname, err := te.ExecuteString(viper.GetString("service.interface_name"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return nil, err
+	}
+	st, err := LoadServiceTypes(name)
+	if err != nil {
+		return nil, err
 	}
-	iname, err := te.ExecuteString(viper.GetString("service.interface_name"), map[string]string{
-		"ServiceName": name,
-	})
+	tt := &thriftTransport{
+		name:    name,
+		alias:   fmt.Sprintf("thrift%s", utils.ToUpperFirstCamelCase(name)),
+		service: thriftGoName(utils.ToUpperFirstCamelCase(name) + "Service"),
+		iface:   st.Package + "." + iname,
+	}
+	tt.tm = newThriftMapper(st, tt.alias, nil)
+	tt.imports = []parser.NamedTypeValue{
+		parser.NewNameType("", "\"context\""),
+		parser.NewNameType("", "\"errors\""),
+		parser.NewNameType("", "\"io\""),
+		parser.NewNameType("", "\"time\"\n"),
+		parser.NewNameType("", "\"github.com/apache/thrift/lib/go/thrift\"\n"),
+		parser.NewNameType("", "\"github.com/go-kit/kit/endpoint\""),
+		parser.NewNameType("", "\"github.com/go-kit/kit/log\""),
+		parser.NewNameType("", "\"github.com/go-kit/kit/sd\""),
+		parser.NewNameType("ketcd", "\"github.com/go-kit/kit/sd/etcdv3\""),
+		parser.NewNameType("", "\"github.com/go-kit/kit/sd/lb\"\n"),
+		parser.NewNameType(tt.alias, fmt.Sprintf("\"%s\"", thriftImport)),
+		parser.NewNameType("", fmt.Sprintf("\"%s\"", endpointsImport)),
+		parser.NewNameType("", fmt.Sprintf("\"%s\"", serviceImport)),
+	}
+	return tt, nil
+}
+
+// thriftTransportFile returns the path of the thrift transport file, `file_name` or `client_file_name`.
+func thriftTransportFile(name, file string) (string, error) {
+	te := template.NewEngine()
+	path, err := te.ExecuteString(viper.GetString("thrifttransport.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return "", err
+	}
+	fname, err := te.ExecuteString(viper.GetString("thrifttransport."+file), map[string]string{"ServiceName": name})
+	if err != nil {
+		return "", err
+	}
+	return path + fs.Get().FilePathSeparator() + fname, nil
+}
+
+func (sg *ThriftInitGenerator) Generate(name string) (err error) {
+	defaultFs := fs.Get()
+	iface, err := LoadServiceInterfaceFromFile(name)
 	if err != nil {
 		return err
 	}
-	if !b {
-		return errors.New(fmt.Sprintf("Service %s was not found", name))
+	if yes, err := IsThriftCompiled(name); err != nil {
+		return err
+	} else if !yes {
+		return errors.New("Could not find the compiled thrift of the service")
 	}
-	p := parser.NewFileParser()
-	s, err := defaultFs.ReadFile(sfile)
+	sfile, err := thriftTransportFile(name, "file_name")
 	if err != nil {
 		return err
 	}
-	f, err := p.Parse([]byte(s))
+	exist, err := defaultFs.Exists(sfile)
 	if err != nil {
 		return err
 	}
-	var iface *parser.Interface
-	for _, v := range f.Interfaces {
-		if v.Name == iname {
-			iface = &v
-		}
+	// If the transport was generated before, go to update
+	if exist {
+		logrus.Infof("exist thrift transport file found: %v ", sfile)
+		return NewThriftUpdateGenerator().Generate(name)
+	}
+	logrus.Info("Init thrift transport for service ", name)
+	tt, err := loadThriftTransport(name)
+	if err != nil {
+		return err
 	}
-	if iface == nil {
-		return errors.New(fmt.Sprintf("Could not find the service interface in `%s`", sfile))
+	if err = defaultFs.MkdirAll(sfile[:strings.LastIndex(sfile, defaultFs.FilePathSeparator())]); err != nil {
+		return err
 	}
-	toKeep := []parser.Method{}
+	handler := parser.NewFile()
+	handler.Package = fmt.Sprintf("%stransport", name)
+	handler.Imports = tt.imports
+	thriftStruct := parser.NewStruct("thriftServer", []parser.NamedTypeValue{})
+	handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
+		"NewThriftServer",
+		`NewThriftServer makes a set of endpoints available as a thrift server.`,
+		parser.NamedTypeValue{},
+		`s := &thriftServer{}`,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("endpoints", fmt.Sprintf("%sendpoint.Set", name)),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", fmt.Sprintf("%s.%s", tt.alias, tt.service)),
+		},
+	), parser.NewMethodWithComment(
+		"NewThriftClient",
+		`NewThriftClient returns the service calling the thrift client through a set of endpoints.`,
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`set := %sendpoint.Set{}`, name),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("client", fmt.Sprintf("%s.%s", tt.alias, tt.service)),
+			tracingParam(name + "endpoint."),
+			parser.NewNameType("logger", "log.Logger"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", tt.iface),
+		},
+	))
 	for _, v := range iface.Methods {
-		isOk := false
-		for _, p := range v.Parameters {
-			if p.Type == "context.Context" {
-				isOk = true
-				break
-			}
-		}
-		if string(v.Name[0]) == strings.ToLower(string(v.Name[0])) {
-			logrus.Warnf("The method '%s' is private and will be ignored", v.Name)
-			continue
-		}
-		if len(v.Results) == 0 {
-			logrus.Warnf("The method '%s' does not have any return value and will be ignored", v.Name)
-			continue
-		}
-		if !isOk {
-			logrus.Warnf("The method '%s' does not have a context and will be ignored", v.Name)
+		if err = tt.addMethod(&handler, &thriftStruct, v); err != nil {
+			return err
 		}
-		if isOk {
-			toKeep = append(toKeep, v)
-		}
-
 	}
-	iface.Methods = toKeep
-	if len(iface.Methods) == 0 {
-		return errors.New("The service has no method please implement the interface methods")
+	handler.Methods[0].Body += `
+	return s`
+	handler.Methods[1].Body += `
+	return set`
+	handler.Structs = append(handler.Structs, thriftStruct)
+	handler.Methods = append(handler.Methods, tt.tm.helpers...)
+	if err = defaultFs.WriteFile(sfile, handler.String(), false); err != nil {
+		return err
 	}
-	path, err = te.ExecuteString(viper.GetString("transport.path"), map[string]string{
-		"ServiceName":   name,
-		"TransportType": "thrift",
-	})
+	return generateEndpointsTracing(name)
+}
+
+func (sg *ThriftInitGenerator) GenerateEndpointClient(name string) (err error) {
+	defaultFs := fs.Get()
+	iface, err := LoadServiceInterfaceFromFile(name)
 	if err != nil {
 		return err
 	}
-	sfile = path + defaultFs.FilePathSeparator() + "gen-go" + defaultFs.FilePathSeparator() +
-		utils.ToLowerSnakeCase(name) + defaultFs.FilePathSeparator() +
-		utils.ToLowerSnakeCase(name) + ".go"
-	b, err = defaultFs.Exists(sfile)
+	sfile, err := thriftTransportFile(name, "client_file_name")
 	if err != nil {
 		return err
 	}
-	if !b {
-		return errors.New("Could not find the compiled thrift of the service")
-	}
-	gosrc := utils.GetGOPATH() + "/src/"
-	gosrc = strings.Replace(gosrc, "\\", "/", -1)
-	pwd, err := os.Getwd()
+	exist, err := defaultFs.Exists(sfile)
 	if err != nil {
 		return err
 	}
-	if viper.GetString("gk_folder") != "" {
-		pwd += "/" + viper.GetString("gk_folder")
-	}
-	pwd = strings.Replace(pwd, "\\", "/", -1)
-	projectPath := strings.Replace(pwd, gosrc, "", 1)
-	thriftImport := projectPath + "/" + path + "/" + "gen-go" +
-		"/" + utils.ToLowerSnakeCase(name)
-	thriftImport = strings.Replace(thriftImport, "\\", "/", -1)
-	enpointsPath, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{
-		"ServiceName": name,
-	})
+	// If the client was generated before, go to update
+	if exist {
+		return NewThriftUpdateGenerator().UpdateEndpointClient(name)
+	}
+	logrus.Info("Init client of thrift endpoint for service ", name)
+	tt, err := loadThriftTransport(name)
 	if err != nil {
 		return err
 	}
-	enpointsPath = strings.Replace(enpointsPath, "\\", "/", -1)
-	endpointsImport := projectPath + "/" + enpointsPath
 	handler := parser.NewFile()
-	handler.Package = "thrift"
-	handler.Imports = []parser.NamedTypeValue{
-		parser.NewNameType("", "\"context\""),
-		parser.NewNameType("", "\"errors\""),
-		parser.NewNameType("", "\"github.com/go-kit/kit/endpoint\""),
-		parser.NewNameType(
-			fmt.Sprintf("thrift%s", utils.ToUpperFirstCamelCase(name)),
-			fmt.Sprintf("\"%s\"", thriftImport),
-		),
-		parser.NewNameType("", fmt.Sprintf("\"%s\"", endpointsImport)),
-	}
-	thriftStruct := parser.NewStruct("thriftServer", []parser.NamedTypeValue{
-		parser.NewNameType("ctx", "context.Context"),
-	})
+	handler.Package = fmt.Sprintf("%stransport", name)
+	handler.Imports = tt.imports
 	handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
-		"MakeThriftHandler",
-		`MakeThriftHandler makes a set of endpoints available as a thrift server.`,
+		"NewThriftEndpointClientSet",
+		`NewThriftEndpointClientSet makes a set of endpoints available for a thrift client,
+		the instances of the service are discovered in etcd.`,
 		parser.NamedTypeValue{},
-		`req = &thriftServer{
-				ctx:    ctx,`,
+		fmt.Sprintf(`
+		var instancer *ketcd.Instancer
+		if instancer, err = ketcd.NewInstancer(etcdClient, svcName, logger); err != nil {
+			return set, err
+		}
+		set = %sendpoint.Set{}
+		`, name),
 		[]parser.NamedTypeValue{
-			parser.NewNameType("ctx", "context.Context"),
-			parser.NewNameType("endpoints", "endpoints.Endpoints"),
+			parser.NewNameType("svcName", "string"),
+			parser.NewNameType("retryMax", "int"),
+			parser.NewNameType("retryTimeout", "time.Duration"),
+			parser.NewNameType("logger", "log.Logger"),
+			parser.NewNameType("etcdClient", "ketcd.Client"),
+			tracingParam(name + "endpoint."),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("set", fmt.Sprintf("%sendpoint.Set", name)),
+			parser.NewNameType("err", "error"),
+		},
+	), parser.NewMethod(
+		"thriftFactory",
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`
+		return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+			socket, err := thrift.NewTSocket(instance)
+			if err != nil {
+				return nil, nil, err
+			}
+			transport, err := thrift.NewTBufferedTransportFactory(8192).GetTransport(socket)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err = transport.Open(); err != nil {
+				return nil, nil, err
+			}
+			client := %s.New%sClientFactory(transport, thrift.NewTBinaryProtocolFactoryDefault())
+			service := NewThriftClient(client, tracer, logger)
+			return makeEndpoint(service), transport, nil
+		}`, tt.alias, tt.service),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("makeEndpoint", fmt.Sprintf("func(%s) endpoint.Endpoint", tt.iface)),
+			tracingParam(name + "endpoint."),
+			parser.NewNameType("logger", "log.Logger"),
 		},
 		[]parser.NamedTypeValue{
-			parser.NewNameType("req", fmt.Sprintf("thrift%s.%sService",
-				utils.ToUpperFirstCamelCase(name), utils.ToUpperFirstCamelCase(name))),
+			parser.NewNameType("", "sd.Factory"),
 		},
 	))
 	for _, v := range iface.Methods {
-		thriftStruct.Vars = append(thriftStruct.Vars, parser.NewNameType(
-			utils.ToLowerFirstCamelCase(v.Name),
-			"endpoint.Endpoint",
-		))
-		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
+		handler.Methods[0].Body += thriftEndpointClientBlock(name, v)
+	}
+	handler.Methods[0].Body += `
+	return set, nil`
+	if err = defaultFs.WriteFile(sfile, handler.String(), false); err != nil {
+		return err
+	}
+	if err = generateEndpointsResilience(name, iface); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
+}
+
+// addMethod adds the codecs of the method, its thrift server method and the endpoints
+// of the server and of the client.
+func (tt *thriftTransport) addMethod(handler *parser.File, thriftStruct *parser.Struct, v parser.Method) error {
+	lowerName := utils.ToLowerFirstCamelCase(v.Name)
+	thriftStruct.Vars = append(thriftStruct.Vars, parser.NewNameType(lowerName, "endpoint.Endpoint"))
+	codecs, err := thriftCodecs(tt.tm, tt.name, v)
+	if err != nil {
+		return err
+	}
+	handler.Methods = append(handler.Methods, codecs...)
+	handler.Methods = append(handler.Methods, parser.NewMethod(
+		thriftGoName(v.Name),
+		parser.NewNameType("s", "*thriftServer"),
+		fmt.Sprintf(
+			`request, err := DecodeThrift%sRequest(req)
+				if err != nil {
+					return nil, err
+				}
+				response, err := s.%s(ctx, request)
+				if err != nil {
+					return nil, err
+				}
+				r, err := EncodeThrift%sResponse(response)
+				if err != nil {
+					return nil, err
+				}
+				return &r, nil`,
+			v.Name, lowerName, v.Name,
+		),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("ctx", "context.Context"),
+			parser.NewNameType("req", fmt.Sprintf("*%s.%s", tt.alias, thriftGoName(v.Name+"Request"))),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("rep", fmt.Sprintf("*%s.%s", tt.alias, thriftGoName(v.Name+"Reply"))),
+			parser.NewNameType("err", "error"),
+		},
+	))
+	handler.Methods[0].Body += "\n" + fmt.Sprintf(`s.%s = endpoints.%sEndpoint`, lowerName, v.Name)
+	handler.Methods[1].Body += "\n" + fmt.Sprintf(`
+		{
+			ep := func(ctx context.Context, request interface{}) (interface{}, error) {
+				req, err := EncodeThrift%sRequest(request)
+				if err != nil {
+					return nil, err
+				}
+				rep, err := client.%s(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				return DecodeThrift%sResponse(rep)
+			}
+			set.%sEndpoint = %sendpoint.TraceMiddleware(tracer, "%s")(ep)
+		}`, v.Name, thriftGoName(v.Name), v.Name, v.Name, tt.name, lowerName)
+	return nil
+}
+
+// thriftEndpointClientBlock balances the endpoint of the method over the discovered instances.
+func thriftEndpointClientBlock(name string, v parser.Method) string {
+	return "\n" + fmt.Sprintf(`
+		{
+			factory := thriftFactory(%sendpoint.Make%sEndpoint, tracer, logger)
+			endpointer := sd.NewEndpointer(instancer, factory, logger)
+			balancer := lb.NewRoundRobin(endpointer)
+			retry := lb.Retry(retryMax, retryTimeout, balancer)
+			%s
+		}`, name, utils.ToUpperFirstCamelCase(v.Name), clientEndpoint(name, v))
+}
+
+// thriftCodecs returns the functions converting the thrift request and reply of the method
+// to the endpoint request and response, in the server and in the client direction.
+func thriftCodecs(tm *thriftMapper, name string, v parser.Method) ([]parser.Method, error) {
+	ep := fmt.Sprintf("%sendpoint", name)
+	request := tm.alias + "." + thriftGoName(v.Name+"Request")
+	reply := tm.alias + "." + thriftGoName(v.Name+"Reply")
+	params, paramTypes, err := tm.thriftFields(v.Name, v.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	results, resultTypes, err := tm.thriftFields(v.Name, v.Results)
+	if err != nil {
+		return nil, err
+	}
+	decodeReq, encodeReq := "", ""
+	for i, f := range params {
+		decodeReq += fmt.Sprintf("\n%s: %s,", f.Name, paramTypes[i].From("r."+thriftGoName(f.Name)))
+		encodeReq += fmt.Sprintf("\n%s: %s,", thriftGoName(f.Name), paramTypes[i].To("r."+f.Name))
+	}
+	encodeRes, decodeRes, encodeErr, decodeErr := "", "", "", ""
+	for i, f := range results {
+		if isErrorResult(v.Results[len(v.Results)-len(results)+i]) {
+			encodeErr += fmt.Sprintf(`
+			if r.%s != nil {
+				rep.%s = r.%s.Error()
+			}`, f.Name, thriftGoName(f.Name), f.Name)
+			decodeErr += fmt.Sprintf(`
+			if rep.%s != "" {
+				response.%s = errors.New(rep.%s)
+			}`, thriftGoName(f.Name), f.Name, thriftGoName(f.Name))
+			continue
+		}
+		encodeRes += fmt.Sprintf("\n%s: %s,", thriftGoName(f.Name), resultTypes[i].To("r."+f.Name))
+		decodeRes += fmt.Sprintf("\n%s: %s,", f.Name, resultTypes[i].From("rep."+thriftGoName(f.Name)))
+	}
+	encodeReqBody := fmt.Sprintf(`r := request.(%s.%sReq)
+		req = &%s{%s
+		}
+		return req, nil`, ep, v.Name, request, encodeReq)
+	if len(params) == 0 {
+		encodeReqBody = fmt.Sprintf(`return &%s{}, nil`, request)
+	}
+	return []parser.Method{
+		parser.NewMethodWithComment(
 			"DecodeThrift"+v.Name+"Request",
 			fmt.Sprintf(
 				`DecodeThrift%sRequest is a func that converts a
-				thrift request to a user-domain request. Primarily useful in a server.
-				TODO: Do not forget to implement the decoder.`,
+				thrift request to a user-domain request. Primarily useful in a server.`,
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`err = errors.New("'%s' Decoder is not impelement")
-			return req, err`, v.Name),
+			fmt.Sprintf(`req = %s.%sReq{%s
+			}
+			return req, nil`, ep, v.Name, decodeReq),
 			[]parser.NamedTypeValue{
-				parser.NewNameType("r", fmt.Sprintf("*thrift%s.%sRequest",
-					utils.ToUpperFirstCamelCase(name), utils.ToUpperFirstCamelCase(v.Name))),
+				parser.NewNameType("r", "*"+request),
 			},
 			[]parser.NamedTypeValue{
-				parser.NewNameType("req", fmt.Sprintf("endpoints.%sRequest",
-					utils.ToUpperFirstCamelCase(v.Name))),
+				parser.NewNameType("req", fmt.Sprintf("%s.%sReq", ep, v.Name)),
 				parser.NewNameType("err", "error"),
 			},
-		))
-		handler.Methods = append(handler.Methods, parser.NewMethodWithComment(
+		),
+		parser.NewMethodWithComment(
 			"EncodeThrift"+v.Name+"Response",
 			fmt.Sprintf(
 				`EncodeThrift%sResponse is a func that converts a
-					user-domain response to a thrift reply. Primarily useful in a server.
-					TODO: Do not forget to implement the encoder.`,
+				user-domain response to a thrift reply. Primarily useful in a server.`,
 				v.Name,
 			),
 			parser.NamedTypeValue{},
-			fmt.Sprintf(`err = errors.New("'%s' Encoder is not impelement")
-			return rep, err`, v.Name),
+			fmt.Sprintf(`r := reply.(%s.%sRes)
+			rep = %s{%s
+			}%s
+			return rep, nil`, ep, v.Name, reply, encodeRes, encodeErr),
 			[]parser.NamedTypeValue{
 				parser.NewNameType("reply", "interface{}"),
 			},
 			[]parser.NamedTypeValue{
-				parser.NewNameType("rep", fmt.Sprintf("thrift%s.%sReply",
-					utils.ToUpperFirstCamelCase(name), utils.ToUpperFirstCamelCase(v.Name))),
+				parser.NewNameType("rep", reply),
 				parser.NewNameType("err", "error"),
 			},
-		))
-		handler.Methods = append(handler.Methods, parser.NewMethod(
-			v.Name,
-			parser.NewNameType("s", "*thriftServer"),
+		),
+		parser.NewMethodWithComment(
+			"EncodeThrift"+v.Name+"Request",
 			fmt.Sprintf(
-				`request,err:=DecodeThrift%sRequest(req)
-					if err != nil {
-						return nil, err
-					}
-					response, err := s.%s(s.ctx, request)
-					if err != nil {
-						return nil, err
-					}
-					r,err := EncodeThrift%sResponse(response)
-					rep = &r
-					return rep, err`,
-				utils.ToUpperFirstCamelCase(v.Name),
-				utils.ToLowerFirstCamelCase(v.Name),
-				utils.ToUpperFirstCamelCase(v.Name),
+				`EncodeThrift%sRequest is a func that converts a
+				user-domain request to a thrift request. Primarily useful in a client.`,
+				v.Name,
 			),
+			parser.NamedTypeValue{},
+			encodeReqBody,
 			[]parser.NamedTypeValue{
-				parser.NewNameType("req", fmt.Sprintf("*thrift%s.%sRequest", utils.ToUpperFirstCamelCase(name), utils.ToUpperFirstCamelCase(v.Name))),
+				parser.NewNameType("request", "interface{}"),
 			},
 			[]parser.NamedTypeValue{
-				parser.NewNameType("rep", fmt.Sprintf("*thrift%s.%sReply", utils.ToUpperFirstCamelCase(name), utils.ToUpperFirstCamelCase(v.Name))),
+				parser.NewNameType("req", "*"+request),
 				parser.NewNameType("err", "error"),
 			},
-		))
-		handler.Methods[0].Body += "\n" + fmt.Sprintf(`%s :  endpoints.%sEndpoint,`,
-			utils.ToLowerFirstCamelCase(v.Name), utils.ToUpperFirstCamelCase(v.Name))
+		),
+		parser.NewMethodWithComment(
+			"DecodeThrift"+v.Name+"Response",
+			fmt.Sprintf(
+				`DecodeThrift%sResponse is a func that converts a
+				thrift reply to a user-domain response. Primarily useful in a client.`,
+				v.Name,
+			),
+			parser.NamedTypeValue{},
+			fmt.Sprintf(`response = %s.%sRes{%s
+			}%s
+			return response, nil`, ep, v.Name, decodeRes, decodeErr),
+			[]parser.NamedTypeValue{
+				parser.NewNameType("rep", "*"+reply),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("response", fmt.Sprintf("%s.%sRes", ep, v.Name)),
+				parser.NewNameType("err", "error"),
+			},
+		),
+	}, nil
+}
+
+// thriftScalarTypes maps the go scalars to the thrift type and the go type the thrift compiler
+// generates for it, thrift has no unsigned integers so those are widened.
+var thriftScalarTypes = map[string][2]string{
+	"string":  {"string", "string"},
+	"bool":    {"bool", "bool"},
+	"[]byte":  {"binary", "[]byte"},
+	"int8":    {"i8", "int8"},
+	"int16":   {"i16", "int16"},
+	"int32":   {"i32", "int32"},
+	"rune":    {"i32", "int32"},
+	"int64":   {"i64", "int64"},
+	"int":     {"i64", "int64"},
+	"uint8":   {"i16", "int16"},
+	"byte":    {"i16", "int16"},
+	"uint16":  {"i32", "int32"},
+	"uint32":  {"i64", "int64"},
+	"uint":    {"i64", "int64"},
+	"uint64":  {"i64", "int64"},
+	"float64": {"double", "float64"},
+	"float32": {"double", "float64"},
+}
+
+// thriftCommonInitialisms are upper cased by the thrift go compiler when they are a whole word.
+var thriftCommonInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true,
+	"QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SSH": true,
+	"TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XSRF": true, "XSS": true,
+}
+
+// thriftGoName returns the go name the thrift compiler generates for a thrift identifier.
+func thriftGoName(name string) string {
+	n := utils.ToUpperFirst(name)
+	if thriftCommonInitialisms[strings.ToUpper(n)] {
+		n = strings.ToUpper(n)
 	}
-	handler.Methods[0].Body += `
+	if strings.HasPrefix(n, "New") || strings.HasSuffix(n, "Args") || strings.HasSuffix(n, "Result") {
+		n += "_"
 	}
-	return req`
-	handler.Structs = append(handler.Structs, thriftStruct)
-	fname, err = te.ExecuteString(viper.GetString("transport.file_name"), map[string]string{
-		"ServiceName":   name,
-		"TransportType": "thrift",
-	})
-	if err != nil {
-		return err
+	return n
+}
+
+// thriftType is the thrift representation of a go type of the service.
+type thriftType struct {
+	// IDL is the type used in the thrift file.
+	IDL string
+	// Go is the type generated by the thrift compiler.
+	Go string
+	// Optional is set for pointers to scalars, thrift declares them as optional fields.
+	Optional bool
+	// To and From return the expressions converting a value to and from thrift.
+	To   func(expr string) string
+	From func(expr string) string
+}
+
+func (t *thriftType) identity() bool {
+	return t.To("v") == "v" && t.From("v") == "v"
+}
+
+func thriftIdentity(expr string) string {
+	return expr
+}
+
+// thriftMapper maps the types of the service to thrift, the structures of the service are
+// declared in the thrift model the first time they are used and get a pair of
+// toThrift<Type>/fromThrift<Type> helpers.
+type thriftMapper struct {
+	st *ServiceTypes
+	// alias is the import alias of the compiled thrift package.
+	alias   string
+	model   *parser.Thrift
+	helpers []parser.Method
+	done    map[string]bool
+}
+
+func newThriftMapper(st *ServiceTypes, alias string, model *parser.Thrift) *thriftMapper {
+	return &thriftMapper{st: st, alias: alias, model: model, done: map[string]bool{}}
+}
+
+func (tm *thriftMapper) mapType(goType string) (*thriftType, error) {
+	qualified := tm.st.Qualify(goType)
+	switch goType {
+	case "time.Time":
+		tm.declareTimestamp()
+		return &thriftType{
+			IDL:  thriftTimestamp,
+			Go:   "*" + tm.alias + "." + thriftTimestamp,
+			To:   func(e string) string { return "toThriftTime(" + e + ")" },
+			From: func(e string) string { return "fromThriftTime(" + e + ")" },
+		}, nil
+	case "time.Duration":
+		return &thriftType{
+			IDL:  "i64",
+			Go:   "int64",
+			To:   func(e string) string { return "int64(" + e + ")" },
+			From: func(e string) string { return "time.Duration(" + e + ")" },
+		}, nil
 	}
-	sfile = path + defaultFs.FilePathSeparator() + fname
-	err = defaultFs.WriteFile(sfile, handler.String(), false)
-	if err != nil {
-		return err
+	if s, ok := thriftScalarTypes[goType]; ok {
+		t := &thriftType{IDL: s[0], Go: s[1], To: thriftIdentity, From: thriftIdentity}
+		if s[1] != goType {
+			t.To = func(e string) string { return s[1] + "(" + e + ")" }
+			t.From = func(e string) string { return goType + "(" + e + ")" }
+		}
+		return t, nil
+	}
+	if underlying, ok := tm.st.Types[goType]; ok {
+		if underlying == "struct" {
+			if err := tm.declareStruct(goType); err != nil {
+				return nil, err
+			}
+			return &thriftType{
+				IDL:  goType,
+				Go:   "*" + tm.alias + "." + thriftGoName(goType),
+				To:   func(e string) string { return "toThrift" + utils.ToUpperFirst(goType) + "(" + e + ")" },
+				From: func(e string) string { return "fromThrift" + utils.ToUpperFirst(goType) + "(" + e + ")" },
+			}, nil
+		}
+		t, err := tm.mapType(underlying)
+		if err != nil {
+			return nil, err
+		}
+		if _, scalar := thriftScalarTypes[underlying]; scalar || underlying == "time.Duration" {
+			return &thriftType{
+				IDL:  t.IDL,
+				Go:   t.Go,
+				To:   func(e string) string { return t.Go + "(" + e + ")" },
+				From: func(e string) string { return qualified + "(" + e + ")" },
+			}, nil
+		}
+		return &thriftType{
+			IDL:  t.IDL,
+			Go:   t.Go,
+			To:   func(e string) string { return t.To(tm.st.Qualify(underlying) + "(" + e + ")") },
+			From: func(e string) string { return qualified + "(" + t.From(e) + ")" },
+		}, nil
+	}
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		elem := goType[1:]
+		t, err := tm.mapType(elem)
+		if err != nil {
+			return nil, err
+		}
+		if t.Optional || t.IDL == "binary" || strings.HasPrefix(t.IDL, "list<") || strings.HasPrefix(t.IDL, "map<") {
+			return nil, fmt.Errorf("the type `%s` can not be mapped to thrift", goType)
+		}
+		q := tm.st.Qualify(elem)
+		if _, isStruct := tm.st.Structs[elem]; isStruct || elem == "time.Time" {
+			return &thriftType{
+				IDL: t.IDL,
+				Go:  t.Go,
+				To: func(e string) string {
+					return fmt.Sprintf(`func(in *%s) %s {
+						if in == nil {
+							return nil
+						}
+						return %s
+					}(%s)`, q, t.Go, t.To("*in"), e)
+				},
+				From: func(e string) string {
+					return fmt.Sprintf(`func(in %s) *%s {
+						if in == nil {
+							return nil
+						}
+						out := %s
+						return &out
+					}(%s)`, t.Go, q, t.From("in"), e)
+				},
+			}, nil
+		}
+		p := &thriftType{IDL: t.IDL, Go: "*" + t.Go, Optional: true, To: thriftIdentity, From: thriftIdentity}
+		if !t.identity() {
+			p.To = func(e string) string {
+				return fmt.Sprintf(`func(in *%s) *%s {
+					if in == nil {
+						return nil
+					}
+					out := %s
+					return &out
+				}(%s)`, q, t.Go, t.To("(*in)"), e)
+			}
+			p.From = func(e string) string {
+				return fmt.Sprintf(`func(in *%s) *%s {
+					if in == nil {
+						return nil
+					}
+					out := %s
+					return &out
+				}(%s)`, t.Go, q, t.From("(*in)"), e)
+			}
+		}
+		return p, nil
+	case strings.HasPrefix(goType, "[]"):
+		elem := goType[2:]
+		t, err := tm.mapType(elem)
+		if err != nil {
+			return nil, err
+		}
+		if t.Optional {
+			return nil, fmt.Errorf("the type `%s` can not be mapped to thrift, use values in lists", goType)
+		}
+		l := &thriftType{IDL: "list<" + t.IDL + ">", Go: "[]" + t.Go, To: thriftIdentity, From: thriftIdentity}
+		if !t.identity() {
+			l.To = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for i, v := range in {
+						out[i] = %s
+					}
+					return out
+				}(%s)`, qualified, l.Go, l.Go, t.To("v"), e)
+			}
+			l.From = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for i, v := range in {
+						out[i] = %s
+					}
+					return out
+				}(%s)`, l.Go, qualified, qualified, t.From("v"), e)
+			}
+		}
+		return l, nil
+	case strings.HasPrefix(goType, "map["):
+		key, value := splitMapType(goType)
+		k, err := tm.mapType(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, scalar := thriftScalarTypes[key]; !scalar || k.IDL == "binary" {
+			return nil, fmt.Errorf("the type `%s` can not be mapped to thrift, map keys have to be scalars", goType)
+		}
+		v, err := tm.mapType(value)
+		if err != nil {
+			return nil, err
+		}
+		if v.Optional {
+			return nil, fmt.Errorf("the type `%s` can not be mapped to thrift, use values in maps", goType)
+		}
+		m := &thriftType{
+			IDL:  "map<" + k.IDL + ", " + v.IDL + ">",
+			Go:   "map[" + k.Go + "]" + v.Go,
+			To:   thriftIdentity,
+			From: thriftIdentity,
+		}
+		if !k.identity() || !v.identity() {
+			m.To = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for k, v := range in {
+						out[%s] = %s
+					}
+					return out
+				}(%s)`, qualified, m.Go, m.Go, k.To("k"), v.To("v"), e)
+			}
+			m.From = func(e string) string {
+				return fmt.Sprintf(`func(in %s) %s {
+					out := make(%s, len(in))
+					for k, v := range in {
+						out[%s] = %s
+					}
+					return out
+				}(%s)`, m.Go, qualified, qualified, k.From("k"), v.From("v"), e)
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("the type `%s` can not be mapped to thrift", goType)
+}
+
+// declareStruct declares the thrift struct of a service structure and its conversion helpers,
+// the structures it depends on are declared first as thrift needs types to be defined before use.
+func (tm *thriftMapper) declareStruct(name string) error {
+	if tm.done[name] {
+		return nil
+	}
+	tm.done[name] = true
+	ts := parser.ThriftStruct{Kind: "struct", Name: name}
+	toList, fromList := "", ""
+	for _, f := range tm.st.Structs[name] {
+		if f.Name == "" || f.Name[:1] == strings.ToLower(f.Name[:1]) {
+			logrus.Warnf("The field `%s %s` of `%s` is not exported and will be ignored", f.Name, f.Type, name)
+			continue
+		}
+		t, err := tm.mapType(f.Type)
+		if err != nil {
+			return fmt.Errorf("field `%s` of `%s`: %s", f.Name, name, err)
+		}
+		field := parser.ThriftField{ID: len(ts.Fields) + 1, Name: f.Name, Type: t.IDL}
+		if t.Optional || strings.HasPrefix(f.Type, "*") {
+			field.Requiredness = "optional"
+		}
+		ts.Fields = append(ts.Fields, field)
+		toList += fmt.Sprintf("\n%s: %s,", thriftGoName(f.Name), t.To("in."+f.Name))
+		fromList += fmt.Sprintf("\n%s: %s,", f.Name, t.From("in."+thriftGoName(f.Name)))
+	}
+	if tm.model != nil && tm.model.Struct(name) == nil {
+		tm.model.Structs = append(tm.model.Structs, ts)
+	}
+	goName := tm.alias + "." + thriftGoName(name)
+	tm.helpers = append(tm.helpers, parser.NewMethodWithComment(
+		"toThrift"+utils.ToUpperFirst(name),
+		fmt.Sprintf(`toThrift%s converts a %s to its thrift struct.`, utils.ToUpperFirst(name), name),
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`return &%s{%s
+		}`, goName, toList),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("in", tm.st.Qualify(name)),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "*"+goName),
+		},
+	), parser.NewMethodWithComment(
+		"fromThrift"+utils.ToUpperFirst(name),
+		fmt.Sprintf(`fromThrift%s converts a thrift struct to a %s, nil gives the zero value.`,
+			utils.ToUpperFirst(name), name),
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`if in == nil {
+			return out
+		}
+		return %s{%s
+		}`, tm.st.Qualify(name), fromList),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("in", "*"+goName),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("out", tm.st.Qualify(name)),
+		},
+	))
+	return nil
+}
+
+// thriftTimestamp is the thrift struct of time.Time, the seconds and the nanoseconds of the
+// instant are kept apart so any time, the zero one included, survives the round trip.
+const thriftTimestamp = "Timestamp"
+
+// declareTimestamp declares the thrift struct of time.Time and its conversion helpers.
+func (tm *thriftMapper) declareTimestamp() {
+	if tm.done["time.Time"] {
+		return
+	}
+	tm.done["time.Time"] = true
+	if tm.model != nil && tm.model.Struct(thriftTimestamp) == nil {
+		tm.model.Structs = append(tm.model.Structs, parser.ThriftStruct{
+			Kind: "struct",
+			Name: thriftTimestamp,
+			Fields: []parser.ThriftField{
+				{ID: 1, Name: "Seconds", Type: "i64"},
+				{ID: 2, Name: "Nanos", Type: "i32"},
+			},
+		})
+	}
+	goName := tm.alias + "." + thriftTimestamp
+	tm.helpers = append(tm.helpers, parser.NewMethodWithComment(
+		"toThriftTime",
+		"toThriftTime converts a time.Time to its thrift struct.",
+		parser.NamedTypeValue{},
+		fmt.Sprintf(`return &%s{Seconds: in.Unix(), Nanos: int32(in.Nanosecond())}`, goName),
+		[]parser.NamedTypeValue{
+			parser.NewNameType("in", "time.Time"),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "*"+goName),
+		},
+	), parser.NewMethodWithComment(
+		"fromThriftTime",
+		"fromThriftTime converts a thrift struct to a time.Time in UTC, nil gives the zero time.",
+		parser.NamedTypeValue{},
+		`if in == nil {
+			return time.Time{}
+		}
+		return time.Unix(in.Seconds, int64(in.Nanos)).UTC()`,
+		[]parser.NamedTypeValue{
+			parser.NewNameType("in", "*"+goName),
+		},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "time.Time"),
+		},
+	))
+}
+
+// splitMapType returns the key and the value type of a `map[K]V` type.
+func splitMapType(goType string) (key, value string) {
+	depth := 0
+	for i := len("map["); i < len(goType); i++ {
+		switch goType[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				return goType[len("map["):i], goType[i+1:]
+			}
+			depth--
+		}
+	}
+	return goType, ""
+}
+
+// thriftFields maps the parameters or the results of a method to the fields of its thrift
+// request or reply, the errors become strings.
+func (tm *thriftMapper) thriftFields(method string, list []parser.NamedTypeValue) (fields []parser.ThriftField, types []*thriftType, err error) {
+	for _, p := range list {
+		if p.Type == "context.Context" {
+			continue
+		}
+		field := parser.ThriftField{ID: len(fields) + 1, Name: utils.ToUpperFirstCamelCase(p.Name)}
+		var t *thriftType
+		if isErrorResult(p) {
+			t = &thriftType{IDL: "string", Go: "string"}
+		} else if t, err = tm.mapType(p.Type); err != nil {
+			return nil, nil, fmt.Errorf("`%s %s` of method `%s`: %s", p.Name, p.Type, method, err)
+		}
+		field.Type = t.IDL
+		if t.Optional || strings.HasPrefix(p.Type, "*") {
+			field.Requiredness = "optional"
+		}
+		fields = append(fields, field)
+		types = append(types, t)
+	}
+	return fields, types, nil
+}
+
+// TransferToThriftModel adds the methods of the interface that the thrift service does not
+// declare yet, with their request, reply and the structures they use.
+func TransferToThriftModel(t *parser.Thrift, name string, iface *parser.Interface, st *ServiceTypes) error {
+	tm := newThriftMapper(st, "", t)
+	svc := t.Service(utils.ToUpperFirstCamelCase(name) + "Service")
+	for _, v := range iface.Methods {
+		if svc.Function(v.Name) != nil {
+			continue
+		}
+		params, _, err := tm.thriftFields(v.Name, v.Parameters)
+		if err != nil {
+			return err
+		}
+		results, _, err := tm.thriftFields(v.Name, v.Results)
+		if err != nil {
+			return err
+		}
+		for _, s := range []parser.ThriftStruct{
+			{Kind: "struct", Name: v.Name + "Request", Fields: params},
+			{Kind: "struct", Name: v.Name + "Reply", Fields: results},
+		} {
+			if t.Struct(s.Name) == nil {
+				t.Structs = append(t.Structs, s)
+			}
+		}
+		svc.Functions = append(svc.Functions, parser.ThriftFunction{
+			Name:       v.Name,
+			ReturnType: v.Name + "Reply",
+			Params:     []parser.ThriftField{{ID: 1, Name: "req", Type: v.Name + "Request"}},
+		})
 	}
-	logrus.Warn("---------------------------------------------------------------------------------------")
-	logrus.Warn("The generator does not implement the Decoding and Encoding of the thrift request/response")
-	logrus.Warn("Before using the service don't forget to implement those.")
-	logrus.Warn("---------------------------------------------------------------------------------------")
 	return nil
 }
@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	"github.com/liuchamp/gk/utils"
+	"strings"
+)
+
+type ThriftUpdateGenerator struct {
+}
+
+func NewThriftUpdateGenerator() *ThriftUpdateGenerator {
+	return &ThriftUpdateGenerator{}
+}
+
+// Generate adds the methods the thrift file and the thrift transport do not have yet.
+func (sg *ThriftUpdateGenerator) Generate(name string) (err error) {
+	logrus.Info("Updating thrift transport for service ", name)
+	defaultFs := fs.Get()
+	iface, err := LoadServiceInterfaceFromFile(name)
+	if err != nil {
+		return err
+	}
+	if err = NewServiceInitGenerator().generateThriftTransport(name, iface); err != nil {
+		return err
+	}
+	if yes, err := IsThriftCompiled(name); err != nil {
+		return err
+	} else if !yes {
+		return errors.New("Could not find the compiled thrift of the service")
+	}
+	sfile, err := thriftTransportFile(name, "file_name")
+	if err != nil {
+		return err
+	}
+	fileContent, err := defaultFs.ReadFile(sfile)
+	if err != nil {
+		return err
+	}
+	handler, err := parser.NewFileParser().Parse([]byte(fileContent))
+	if err != nil {
+		return err
+	}
+	migrateTracing(handler, name+"endpoint.")
+	var thriftServer *parser.Struct
+	for k, v := range handler.Structs {
+		if v.Name == "thriftServer" {
+			thriftServer = &handler.Structs[k]
+			break
+		}
+	}
+	if thriftServer == nil {
+		err = errors.New("Could not find thriftServer")
+		logrus.Error(err)
+		return err
+	}
+	// the parser tags the fields again, the endpoints of the server are not exported
+	for k := range thriftServer.Vars {
+		thriftServer.Vars[k].Tag = ""
+	}
+	// addMethod appends to the bodies of the constructors, they are moved first for it
+	server, client := methodIndex(handler, "NewThriftServer"), methodIndex(handler, "NewThriftClient")
+	if server < 0 || client < 0 {
+		return errors.New("Could not find NewThriftServer and NewThriftClient")
+	}
+	constructors := []parser.Method{handler.Methods[server], handler.Methods[client]}
+	rest := []parser.Method{}
+	for k, v := range handler.Methods {
+		if k != server && k != client {
+			rest = append(rest, v)
+		}
+	}
+	handler.Methods = append(constructors, rest...)
+	handler.Methods[0].Body = trimReturn(handler.Methods[0].Body, "return s")
+	handler.Methods[1].Body = trimReturn(handler.Methods[1].Body, "return set")
+
+	tt, err := loadThriftTransport(name)
+	if err != nil {
+		return err
+	}
+	for _, v := range iface.Methods {
+		var isExist bool
+		for _, vv := range thriftServer.Vars {
+			if vv.Name == utils.ToLowerFirstCamelCase(v.Name) {
+				isExist = true
+				break
+			}
+		}
+		if isExist {
+			continue
+		}
+		if err = tt.addMethod(handler, thriftServer, v); err != nil {
+			return err
+		}
+	}
+	handler.Methods[0].Body += `
+	return s`
+	handler.Methods[1].Body += `
+	return set`
+	for _, v := range tt.tm.helpers {
+		if methodIndex(handler, v.Name) < 0 {
+			handler.Methods = append(handler.Methods, v)
+		}
+	}
+	if err = defaultFs.WriteFile(sfile, handler.String(), false); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
+}
+
+// UpdateEndpointClient balances the endpoints of the new methods in NewThriftEndpointClientSet.
+func (sg *ThriftUpdateGenerator) UpdateEndpointClient(name string) (err error) {
+	defaultFs := fs.Get()
+	iface, err := LoadServiceInterfaceFromFile(name)
+	if err != nil {
+		return err
+	}
+	sfile, err := thriftTransportFile(name, "client_file_name")
+	if err != nil {
+		return err
+	}
+	fileContent, err := defaultFs.ReadFile(sfile)
+	if err != nil {
+		return err
+	}
+	handler, err := parser.NewFileParser().Parse([]byte(fileContent))
+	if err != nil {
+		return err
+	}
+	migrateTracing(handler, name+"endpoint.")
+	k := methodIndex(handler, "NewThriftEndpointClientSet")
+	if k < 0 {
+		return errors.New("Could not find NewThriftEndpointClientSet")
+	}
+	body := trimReturn(handler.Methods[k].Body, "return set, nil")
+	for _, v := range iface.Methods {
+		if strings.Contains(body, "Make"+utils.ToUpperFirstCamelCase(v.Name)+"Endpoint") {
+			continue
+		}
+		body += thriftEndpointClientBlock(name, v)
+	}
+	handler.Methods[k].Body = wrapClientEndpoints(body, name, iface) + `
+	return set, nil`
+	if err = defaultFs.WriteFile(sfile, handler.String(), false); err != nil {
+		return err
+	}
+	if err = generateEndpointsResilience(name, iface); err != nil {
+		return err
+	}
+	return generateEndpointsTracing(name)
+}
+
+func methodIndex(f *parser.File, name string) int {
+	for k, v := range f.Methods {
+		if v.Name == name && v.Struct.Type == "" {
+			return k
+		}
+	}
+	return -1
+}
+
+// trimReturn removes the final return statement of a body.
+func trimReturn(body, ret string) string {
+	return strings.TrimRight(strings.TrimSuffix(strings.TrimRight(body, " \t\n"), ret), " \t\n")
+}
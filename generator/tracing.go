@@ -0,0 +1,358 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+	template "github.com/liuchamp/gk/templates"
+)
+
+// tracingModes are the values of `tracing`.
+var tracingModes = []string{"none", "zipkin", "opentelemetry"}
+
+// tracingMode is the tracing of the generated code, `tracing` of gk.json: the spans of the
+// endpoints, the propagation of the transports and the exporters of main.
+func tracingMode() (string, error) {
+	mode := viper.GetString("tracing")
+	for _, v := range tracingModes {
+		if v == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("The tracing `%s` is not supported, use %s", mode, strings.Join(tracingModes, ", "))
+}
+
+// tracingParam is the tracer parameter of the constructors, ep is the endpoints package
+// qualifier, empty in the endpoints package.
+func tracingParam(ep string) parser.NamedTypeValue {
+	return parser.NewNameType("tracer", ep+"Tracer")
+}
+
+// tracingClientEndpoint is the statement recording the spans of the client endpoint of the method.
+func tracingClientEndpoint(name, method string) string {
+	return fmt.Sprintf("ep = %sendpoint.TraceMiddleware(tracer, %q)(ep)", name, method)
+}
+
+// generateEndpointsTracing writes the tracer of the endpoints, `tracing.go` next to the
+// middleware: the Tracer the constructors take, TraceMiddleware and NewNopTracer. It follows
+// gk.json, it is generated again by each update.
+func generateEndpointsTracing(name string) error {
+	mode, err := tracingMode()
+	if err != nil {
+		return err
+	}
+	logrus.Info("Generating endpoints tracing...")
+	te := template.NewEngine()
+	defaultFs := fs.Get()
+	path, err := te.ExecuteString(viper.GetString("endpoints.path"), map[string]string{"ServiceName": name})
+	if err != nil {
+		return err
+	}
+	endpointsImport, err := ProjectImport(path)
+	if err != nil {
+		return err
+	}
+	file := parser.NewFile()
+	file.Package = fmt.Sprintf("%sendpoint", name)
+	file.Imports = []parser.NamedTypeValue{parser.NewNameType("", `"github.com/go-kit/kit/endpoint"`)}
+	var nop, trace string
+	comment := `TraceMiddleware returns an endpoint middleware recording a span of each invocation
+		named after the method, with its error.`
+	switch mode {
+	case "none":
+		file.AliasType = []parser.NamedTypeValue{parser.NewNameTypeWithComment("Tracer", "= struct{}",
+			"Tracer is the tracer of the constructors, the tracing is off.")}
+		nop = "return Tracer{}"
+		trace = "return func(next endpoint.Endpoint) endpoint.Endpoint { return next }"
+		comment = "TraceMiddleware returns an endpoint middleware leaving the endpoints as they are, the tracing is off."
+	case "zipkin":
+		file.Imports = append(file.Imports,
+			parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
+			parser.NewNameType("", `"github.com/go-kit/kit/tracing/zipkin"`),
+		)
+		file.AliasType = []parser.NamedTypeValue{parser.NewNameTypeWithComment("Tracer", "= *stdzipkin.Tracer",
+			"Tracer is the tracer of the constructors, the spans are sent to zipkin.")}
+		nop = `tracer, _ := stdzipkin.NewTracer(nil, stdzipkin.WithNoopTracer(true))
+			return tracer`
+		trace = "return zipkin.TraceEndpoint(tracer, method)"
+	case "opentelemetry":
+		file.Imports = append([]parser.NamedTypeValue{parser.NewNameType("", "\"context\"\n")}, append(file.Imports,
+			parser.NewNameType("", `"go.opentelemetry.io/otel/codes"`),
+			parser.NewNameType("", `"go.opentelemetry.io/otel/trace"`),
+			parser.NewNameType("", `"go.opentelemetry.io/otel/trace/noop"`),
+		)...)
+		file.AliasType = []parser.NamedTypeValue{parser.NewNameTypeWithComment("Tracer", "= trace.TracerProvider",
+			"Tracer is the tracer of the constructors, the spans are recorded by OpenTelemetry.")}
+		file.Constants = []parser.NamedTypeValue{parser.NewNameTypeValue("instrumentationName", "", fmt.Sprintf("%q", endpointsImport))}
+		nop = "return noop.NewTracerProvider()"
+		trace = `t := tracer.Tracer(instrumentationName)
+			return func(next endpoint.Endpoint) endpoint.Endpoint {
+				return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+					ctx, span := t.Start(ctx, method)
+					defer func() {
+						failure := err
+						if f, ok := response.(Failer); ok && err == nil {
+							failure = f.Failed()
+						}
+						if failure != nil {
+							span.RecordError(failure)
+							span.SetStatus(codes.Error, failure.Error())
+						}
+						span.End()
+					}()
+					return next(ctx, request)
+				}
+			}`
+	}
+	file.Methods = []parser.Method{
+		parser.NewMethodWithComment(
+			"NewNopTracer",
+			`NewNopTracer returns a tracer recording nothing, for the tests and the clients
+			without tracing.`,
+			parser.NamedTypeValue{},
+			nop,
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{parser.NewNameType("", "Tracer")},
+		),
+		parser.NewMethodWithComment(
+			"TraceMiddleware",
+			comment,
+			parser.NamedTypeValue{},
+			trace,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("tracer", "Tracer"),
+				parser.NewNameType("method", "string"),
+			},
+			[]parser.NamedTypeValue{parser.NewNameType("", "endpoint.Middleware")},
+		),
+	}
+	return defaultFs.WriteFile(path+defaultFs.FilePathSeparator()+"tracing.go", file.String(), true)
+}
+
+// tracingTransportHelpers are the options of the servers and the clients of the transport,
+// `http` or `grpc`, carrying the traces across the calls, and the helpers of the gRPC streams.
+func tracingTransportHelpers(name, transport string) ([]parser.Method, error) {
+	mode, err := tracingMode()
+	if err != nil {
+		return nil, err
+	}
+	kit := map[string]string{"http": "httptransport", "grpc": "grpctransport"}[transport]
+	upper := strings.ToUpper(transport)
+	server, client := "return nil", "return nil"
+	comments := map[string][2]string{
+		"none":          {"have no tracing option, the tracing is off.", "have no tracing option, the tracing is off."},
+		"zipkin":        {"join the zipkin spans of the callers.", "send the zipkin spans of the calls."},
+		"opentelemetry": {"extract the OpenTelemetry trace context of the calls with the global propagator.", "inject the OpenTelemetry trace context in the calls with the global propagator."},
+	}[mode]
+	switch {
+	case mode == "zipkin":
+		server = fmt.Sprintf("return []%s.ServerOption{zipkin.%sServerTrace(tracer)}", kit, upper)
+		client = fmt.Sprintf("return []%s.ClientOption{zipkin.%sClientTrace(tracer)}", kit, upper)
+	case mode == "opentelemetry" && transport == "http":
+		server = `return []httptransport.ServerOption{
+				httptransport.ServerBefore(func(ctx context.Context, r *http.Request) context.Context {
+					return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+				}),
+			}`
+		client = `return []httptransport.ClientOption{
+				httptransport.ClientBefore(func(ctx context.Context, r *http.Request) context.Context {
+					otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+					return ctx
+				}),
+			}`
+	case mode == "opentelemetry" && transport == "grpc":
+		server = `return []grpctransport.ServerOption{
+				grpctransport.ServerBefore(func(ctx context.Context, md metadata.MD) context.Context {
+					carrier := propagation.MapCarrier{}
+					for k, v := range md {
+						if len(v) > 0 {
+							carrier[k] = v[0]
+						}
+					}
+					return otel.GetTextMapPropagator().Extract(ctx, carrier)
+				}),
+			}`
+		client = `return []grpctransport.ClientOption{
+				grpctransport.ClientBefore(func(ctx context.Context, md *metadata.MD) context.Context {
+					carrier := propagation.MapCarrier{}
+					otel.GetTextMapPropagator().Inject(ctx, carrier)
+					for k, v := range carrier {
+						md.Set(k, v)
+					}
+					return ctx
+				}),
+			}`
+	}
+	params := []parser.NamedTypeValue{tracingParam(name + "endpoint.")}
+	helpers := []parser.Method{
+		parser.NewMethodWithComment(
+			fmt.Sprintf("tracing%sServerOptions", upper),
+			fmt.Sprintf(`tracing%sServerOptions are the options of the servers, they %s`, upper, comments[0]),
+			parser.NamedTypeValue{},
+			server,
+			params,
+			[]parser.NamedTypeValue{parser.NewNameType("", fmt.Sprintf("[]%s.ServerOption", kit))},
+		),
+		parser.NewMethodWithComment(
+			fmt.Sprintf("tracing%sClientOptions", upper),
+			fmt.Sprintf(`tracing%sClientOptions are the options of the clients, they %s`, upper, comments[1]),
+			parser.NamedTypeValue{},
+			client,
+			params,
+			[]parser.NamedTypeValue{parser.NewNameType("", fmt.Sprintf("[]%s.ClientOption", kit))},
+		),
+	}
+	if transport == "grpc" {
+		helpers = append(helpers, tracingGRPCStreamHelpers(name, mode)...)
+	}
+	return helpers, nil
+}
+
+// tracingGRPCStreamHelpers carry the traces across the streams, they bypass the grpctransport
+// servers and clients so they do not have the options.
+func tracingGRPCStreamHelpers(name, mode string) []parser.Method {
+	server, client := "return ctx, func() {}", "return ctx"
+	switch mode {
+	case "zipkin":
+		server = `span := tracer.StartSpan(method, stdzipkin.Kind(model.Server), stdzipkin.Parent(tracer.Extract(b3.ExtractGRPC(&md))))
+			return stdzipkin.NewContext(ctx, span), span.Finish`
+		client = `if span := stdzipkin.SpanFromContext(ctx); span != nil {
+				b3.InjectGRPC(md)(span.Context())
+			}
+			return ctx`
+	case "opentelemetry":
+		server = `carrier := propagation.MapCarrier{}
+			for k, v := range md {
+				if len(v) > 0 {
+					carrier[k] = v[0]
+				}
+			}
+			return otel.GetTextMapPropagator().Extract(ctx, carrier), func() {}`
+		client = `carrier := propagation.MapCarrier{}
+			otel.GetTextMapPropagator().Inject(ctx, carrier)
+			for k, v := range carrier {
+				md.Set(k, v)
+			}
+			return ctx`
+	}
+	return []parser.Method{
+		parser.NewMethodWithComment(
+			"tracingGRPCStreamServer",
+			`tracingGRPCStreamServer joins the trace of the metadata of a stream like the server
+			options, the returned function ends the span of the handler.`,
+			parser.NamedTypeValue{},
+			server,
+			[]parser.NamedTypeValue{
+				tracingParam(name + "endpoint."),
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("md", "metadata.MD"),
+				parser.NewNameType("method", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "context.Context"),
+				parser.NewNameType("", "func()"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"tracingGRPCStreamClient",
+			`tracingGRPCStreamClient sends the trace of the context in the metadata of a stream
+			like the client options.`,
+			parser.NamedTypeValue{},
+			client,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("ctx", "context.Context"),
+				parser.NewNameType("md", "*metadata.MD"),
+			},
+			[]parser.NamedTypeValue{parser.NewNameType("", "context.Context")},
+		),
+	}
+}
+
+// tracingTransportImports are the imports of the tracing helpers of the transport.
+func tracingTransportImports(transport string) ([]parser.NamedTypeValue, error) {
+	mode, err := tracingMode()
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case "zipkin":
+		imports := []parser.NamedTypeValue{parser.NewNameType("", `"github.com/go-kit/kit/tracing/zipkin"`)}
+		if transport == "grpc" {
+			imports = append(imports,
+				parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
+				parser.NewNameType("", `"github.com/openzipkin/zipkin-go/model"`),
+				parser.NewNameType("", `"github.com/openzipkin/zipkin-go/propagation/b3"`),
+			)
+		}
+		return imports, nil
+	case "opentelemetry":
+		imports := []parser.NamedTypeValue{
+			parser.NewNameType("", `"context"`),
+			parser.NewNameType("", `"go.opentelemetry.io/otel"`),
+			parser.NewNameType("", `"go.opentelemetry.io/otel/propagation"`),
+		}
+		if transport == "grpc" {
+			return append(imports, parser.NewNameType("", `"google.golang.org/grpc/metadata"`)), nil
+		}
+		return append(imports, parser.NewNameType("", `"net/http"`)), nil
+	}
+	return nil, nil
+}
+
+// tracingLegacyImports are the tracing packages of the files generated before `tracing`, the
+// helpers import again the ones they use.
+var tracingLegacyImports = map[string]bool{
+	`"github.com/openzipkin/zipkin-go"`:                true,
+	`"github.com/openzipkin/zipkin-go/model"`:          true,
+	`"github.com/openzipkin/zipkin-go/propagation/b3"`: true,
+	`"github.com/opentracing/opentracing-go"`:          true,
+	`"github.com/go-kit/kit/tracing/opentracing"`:      true,
+	`"github.com/go-kit/kit/tracing/zipkin"`:           true,
+	`"go.opentelemetry.io/otel"`:                       true,
+	`"go.opentelemetry.io/otel/propagation"`:           true,
+}
+
+var (
+	zipkinOptionsRegexp   = regexp.MustCompile(`(?s)zipkin(?:Server|Client) := zipkin\.(HTTP|GRPC)(Server|Client)Trace\(zipkinTracer\)\s*options := \[\](\w+)\.(?:Server|Client)Option\{(.*?)[\s,]*zipkin(?:Server|Client),?\s*\}`)
+	zipkinTracerRegexp    = regexp.MustCompile(`\bzipkinTracer\b`)
+	opentracingLineRegexp = regexp.MustCompile(`(?m)^[ \t]*//.*\b(?:opentracing|otTracer)\b.*\n`)
+)
+
+// migrateTracing switches the constructors generated with the zipkin tracer to the Tracer of
+// the endpoints and their options to the tracing helpers, ep is the endpoints package
+// qualifier, empty in the endpoints package. The opentracing leftovers are dropped.
+func migrateTracing(file *parser.File, ep string) {
+	for k := range file.Methods {
+		m := &file.Methods[k]
+		for i, p := range m.Parameters {
+			if p.Name == "zipkinTracer" {
+				m.Parameters[i] = tracingParam(ep)
+			}
+		}
+		m.Body = zipkinOptionsRegexp.ReplaceAllStringFunc(m.Body, func(s string) string {
+			g := zipkinOptionsRegexp.FindStringSubmatch(s)
+			options := strings.TrimRight(strings.TrimSpace(g[4]), ", \t\n")
+			if options == "" {
+				return fmt.Sprintf("options := tracing%s%sOptions(tracer)", g[1], g[2])
+			}
+			return fmt.Sprintf("options := append([]%s.%sOption{%s}, tracing%s%sOptions(tracer)...)",
+				g[3], g[2], options, g[1], g[2])
+		})
+		m.Body = zipkinTracerRegexp.ReplaceAllString(m.Body, "tracer")
+		m.Body = strings.ReplaceAll(m.Body, "zipkin.TraceEndpoint(", ep+"TraceMiddleware(")
+		m.Body = opentracingLineRegexp.ReplaceAllString(m.Body, "")
+	}
+	var kept []parser.NamedTypeValue
+	for _, v := range file.Imports {
+		if !tracingLegacyImports[strings.TrimSpace(v.Type)] {
+			kept = append(kept, v)
+		}
+	}
+	file.Imports = kept
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/liuchamp/gk/parser"
+)
+
+func TestTracingMode(t *testing.T) {
+	testProject(t, nil)
+	if mode, err := tracingMode(); err != nil || mode != "zipkin" {
+		t.Errorf("the tracing of gk.json is %s %v, want zipkin", mode, err)
+	}
+	for _, v := range []string{"none", "opentelemetry"} {
+		viper.Set("tracing", v)
+		if mode, err := tracingMode(); err != nil || mode != v {
+			t.Errorf("got %s %v, want %s", mode, err, v)
+		}
+	}
+	viper.Set("tracing", "jaeger")
+	if _, err := tracingMode(); err == nil || err.Error() != "The tracing `jaeger` is not supported, use none, zipkin, opentelemetry" {
+		t.Errorf("got %v, want the unsupported tracing", err)
+	}
+	for _, v := range []string{"http", "grpc"} {
+		if _, err := tracingTransportHelpers("acc", v); err == nil {
+			t.Errorf("the %s helpers have no error", v)
+		}
+	}
+}
+
+func TestTracingInit(t *testing.T) {
+	testHTTPService(t, map[string]interface{}{"tracing": "none"})
+	tracing := testRead(t, "acc/pkg/accendpoint/tracing.go")
+	assertContains(t, tracing, `type Tracer = struct{}`)
+	assertContains(t, tracing, `
+		func TraceMiddleware(tracer Tracer, method string) endpoint.Middleware {
+			return func(next endpoint.Endpoint) endpoint.Endpoint { return next }
+		}`)
+	http := testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, http, `
+		func tracingHTTPServerOptions(tracer accendpoint.Tracer) []httptransport.ServerOption {
+			return nil
+		}`)
+	main := testRead(t, "acc/main.go")
+	assertContains(t, main, `tracer := accendpoint.NewNopTracer()`)
+	assertNotContains(t, main, `zipkin`)
+	assertNotContains(t, main, `otel`)
+
+	testHTTPService(t, map[string]interface{}{})
+	tracing = testRead(t, "acc/pkg/accendpoint/tracing.go")
+	assertContains(t, tracing, `type Tracer = *stdzipkin.Tracer`)
+	assertContains(t, tracing, `tracer, _ := stdzipkin.NewTracer(nil, stdzipkin.WithNoopTracer(true))`)
+	assertContains(t, tracing, `return zipkin.TraceEndpoint(tracer, method)`)
+	http = testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, http, `return []httptransport.ServerOption{zipkin.HTTPServerTrace(tracer)}`)
+	assertContains(t, http, `return []httptransport.ClientOption{zipkin.HTTPClientTrace(tracer)}`)
+	main = testRead(t, "acc/main.go")
+	assertContains(t, main, `ZipkinAddr = flag.String("zipkin-addr",`)
+	assertContains(t, main, `var tracer *stdzipkin.Tracer`)
+	assertNotContains(t, main, `otel`)
+
+	testHTTPService(t, map[string]interface{}{"tracing": "opentelemetry"})
+	tracing = testRead(t, "acc/pkg/accendpoint/tracing.go")
+	assertContains(t, tracing, `type Tracer = trace.TracerProvider`)
+	assertContains(t, tracing, `return noop.NewTracerProvider()`)
+	assertContains(t, tracing, `
+		ctx, span := t.Start(ctx, method)
+		defer func() {
+			failure := err
+			if f, ok := response.(Failer); ok && err == nil {
+				failure = f.Failed()
+			}`)
+	assertNotContains(t, tracing, `zipkin`)
+	http = testRead(t, "acc/pkg/acctransport/http.go")
+	assertContains(t, http, `return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))`)
+	assertContains(t, http, `otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))`)
+	assertNotContains(t, http, `zipkin`)
+	main = testRead(t, "acc/main.go")
+	assertContains(t, main, `OtlpEndpoint = flag.String("otlp-endpoint",`)
+	assertContains(t, main, `tracer = sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(res))`)
+	assertContains(t, main, `otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))`)
+	assertContains(t, main, `func otelExporters(ctx context.Context) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {`)
+	assertNotContains(t, main, `zipkin`)
+}
+
+func TestMigrateTracing(t *testing.T) {
+	file := parser.NewFile()
+	file.Imports = []parser.NamedTypeValue{
+		parser.NewNameType("", `"context"`),
+		parser.NewNameType("stdopentracing", `"github.com/opentracing/opentracing-go"`),
+		parser.NewNameType("", `"github.com/go-kit/kit/tracing/zipkin"`),
+		parser.NewNameType("stdzipkin", `"github.com/openzipkin/zipkin-go"`),
+	}
+	file.Methods = []parser.Method{
+		parser.NewMethod(
+			"NewHTTPHandler",
+			parser.NamedTypeValue{},
+			`zipkinServer := zipkin.HTTPServerTrace(zipkinTracer)
+			options := []httptransport.ServerOption{
+				httptransport.ServerErrorEncoder(errorEncoder),
+				zipkinServer,
+			}
+			// options = append(options, opentracing.HTTPToContext(otTracer, "Get", logger))
+			return options`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("endpoints", "accendpoint.Set"),
+				parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer"),
+			},
+			nil,
+		),
+		parser.NewMethod(
+			"NewGRPCClient",
+			parser.NamedTypeValue{},
+			`zipkinClient := zipkin.GRPCClientTrace(zipkinTracer)
+			options := []grpctransport.ClientOption{zipkinClient}
+			ep = zipkin.TraceEndpoint(zipkinTracer, "Get")(ep)`,
+			[]parser.NamedTypeValue{parser.NewNameType("zipkinTracer", "*stdzipkin.Tracer")},
+			nil,
+		),
+	}
+	migrateTracing(&file, "accendpoint.")
+
+	handler := file.Methods[0]
+	if p := handler.Parameters[1]; p.Name != "tracer" || p.Type != "accendpoint.Tracer" {
+		t.Errorf("the tracer parameter is %s %s", p.Name, p.Type)
+	}
+	assertContains(t, handler.Body, `options := append([]httptransport.ServerOption{httptransport.ServerErrorEncoder(errorEncoder)}, tracingHTTPServerOptions(tracer)...)`)
+	assertNotContains(t, handler.Body, `opentracing`)
+	client := file.Methods[1]
+	assertContains(t, client.Body, `options := tracingGRPCClientOptions(tracer)`)
+	assertContains(t, client.Body, `ep = accendpoint.TraceMiddleware(tracer, "Get")(ep)`)
+	assertNotContains(t, client.Body, `zipkin`)
+	if len(file.Imports) != 1 || file.Imports[0].Type != `"context"` {
+		t.Errorf("the imports are %v, want the tracing ones dropped", file.Imports)
+	}
+}
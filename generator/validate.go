@@ -0,0 +1,363 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/liuchamp/gk/parser"
+	"github.com/liuchamp/gk/utils"
+)
+
+// validation builds the body of the Validate method of a request from the
+// `gk:validate <param> <rules>` directives of the method and the `validate` tags of the
+// fields of the service structures. The rules are comma separated, like the tags of
+// go-playground/validator: required, omitempty, min=, max=, len=, oneof=, email and url.
+type validation struct {
+	st     *ServiceTypes
+	method string
+	checks []string
+}
+
+// kind is how the rules apply to a type: string, number, duration, bool, collection,
+// time, struct or the empty string when no rule applies.
+func (v *validation) kind(typeName string) string {
+	typeName = strings.TrimPrefix(typeName, v.st.Package+".")
+	switch {
+	case typeName == "string":
+		return "string"
+	case typeName == "bool":
+		return "bool"
+	case typeName == "time.Time":
+		return "time"
+	case typeName == "time.Duration":
+		return "duration"
+	case strings.HasPrefix(typeName, "[") || strings.HasPrefix(typeName, "map["):
+		return "collection"
+	}
+	if _, ok := openAPIFormats[typeName]; ok {
+		return "number"
+	}
+	if underlying, ok := v.st.Types[typeName]; ok {
+		if underlying == "struct" {
+			return "struct"
+		}
+		return v.kind(underlying)
+	}
+	return ""
+}
+
+// violation is the statement recording a violation of the field.
+func violation(field, description string) string {
+	return fmt.Sprintf("violations = append(violations, FieldViolation{Field: %q, Description: %q})", field, description)
+}
+
+// value is the limit of a rule for the kind, a duration is given in Go syntax.
+func (v *validation) value(kind, param string) (string, bool) {
+	if kind == "duration" {
+		d, err := time.ParseDuration(param)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("time.Duration(%d)", int64(d)), true
+	}
+	if _, err := strconv.ParseFloat(param, 64); err != nil {
+		return "", false
+	}
+	return param, true
+}
+
+// field adds the checks of the rules of a field, expr is the value of the field and field its
+// name in the JSON of the request.
+func (v *validation) field(expr, field, typeName, rules string, seen map[string]bool) {
+	var list []string
+	omitempty := false
+	for _, r := range strings.Split(rules, ",") {
+		if r = strings.TrimSpace(r); r == "omitempty" {
+			omitempty = true
+		} else if r != "" {
+			list = append(list, r)
+		}
+	}
+	if strings.HasPrefix(typeName, "*") {
+		var checks []string
+		for k, r := range list {
+			if r == "required" {
+				checks = append(checks, fmt.Sprintf("if %s == nil {\n%s\n}", expr, violation(field, "is required")))
+				list = append(list[:k:k], list[k+1:]...)
+				break
+			}
+		}
+		inner := &validation{st: v.st, method: v.method}
+		inner.field("(*"+expr+")", field, typeName[1:], strings.Join(list, ","), seen)
+		if len(inner.checks) > 0 {
+			checks = append(checks, fmt.Sprintf("if %s != nil {\n%s\n}", expr, strings.Join(inner.checks, "\n")))
+		}
+		v.checks = append(v.checks, checks...)
+		return
+	}
+	kind := v.kind(typeName)
+	var checks []string
+	for _, r := range list {
+		name, param := r, ""
+		if i := strings.Index(r, "="); i != -1 {
+			name, param = r[:i], r[i+1:]
+		}
+		check, description := "", ""
+		switch {
+		case name == "required":
+			description = "is required"
+			switch kind {
+			case "string":
+				check = expr + ` == ""`
+			case "number", "duration":
+				check = expr + " == 0"
+			case "bool":
+				check = "!" + expr
+			case "collection":
+				check = "len(" + expr + ") == 0"
+			case "time":
+				check = expr + ".IsZero()"
+			}
+		case name == "min" || name == "max" || name == "len":
+			op := map[string]string{"min": "<", "max": ">", "len": "!="}[name]
+			limit, ok := v.value(kind, param)
+			if !ok {
+				break
+			}
+			switch kind {
+			case "string":
+				check = fmt.Sprintf("utf8.RuneCountInString(%s) %s %s", expr, op, limit)
+				description = map[string]string{"min": "must be at least %s characters long", "max": "must be at most %s characters long", "len": "must be %s characters long"}[name]
+			case "collection":
+				check = fmt.Sprintf("len(%s) %s %s", expr, op, limit)
+				description = map[string]string{"min": "must have at least %s items", "max": "must have at most %s items", "len": "must have %s items"}[name]
+			case "number", "duration":
+				if name == "len" {
+					break
+				}
+				check = fmt.Sprintf("%s %s %s", expr, op, limit)
+				description = map[string]string{"min": "must be at least %s", "max": "must be at most %s"}[name]
+			}
+			description = fmt.Sprintf(description, param)
+		case name == "oneof" && (kind == "string" || kind == "number"):
+			var values, conds []string
+			for _, w := range strings.Fields(param) {
+				value := strconv.Quote(w)
+				if kind == "number" {
+					if _, ok := v.value(kind, w); !ok {
+						conds = nil
+						break
+					}
+					value = w
+				}
+				values = append(values, w)
+				conds = append(conds, fmt.Sprintf("%s != %s", expr, value))
+			}
+			if len(conds) > 0 {
+				check = strings.Join(conds, " && ")
+				description = "must be one of " + strings.Join(values, ", ")
+			}
+		case name == "email" && kind == "string":
+			check, description = fmt.Sprintf("!isEmail(%s)", expr), "must be an email address"
+		case name == "url" && kind == "string":
+			check, description = fmt.Sprintf("!isURL(%s)", expr), "must be a URL"
+		}
+		if check == "" {
+			logrus.Warnf("The validation rule '%s' of '%s' in '%s' is not supported and is ignored", r, field, v.method)
+			continue
+		}
+		checks = append(checks, fmt.Sprintf("if %s {\n%s\n}", check, violation(field, description)))
+	}
+	if kind == "struct" {
+		checks = append(checks, v.structFields(expr, field, strings.TrimPrefix(typeName, v.st.Package+"."), seen)...)
+	}
+	if len(checks) == 0 {
+		return
+	}
+	if omitempty {
+		zero := map[string]string{
+			"string":     expr + ` != ""`,
+			"number":     expr + " != 0",
+			"duration":   expr + " != 0",
+			"bool":       expr,
+			"collection": "len(" + expr + ") != 0",
+			"time":       "!" + expr + ".IsZero()",
+		}[kind]
+		if zero != "" {
+			checks = []string{fmt.Sprintf("if %s {\n%s\n}", zero, strings.Join(checks, "\n"))}
+		}
+	}
+	v.checks = append(v.checks, checks...)
+}
+
+// structFields are the checks of the `validate` tags of the fields of a service structure.
+func (v *validation) structFields(expr, field, typeName string, seen map[string]bool) []string {
+	if seen[typeName] {
+		return nil
+	}
+	seen[typeName] = true
+	defer delete(seen, typeName)
+	inner := &validation{st: v.st, method: v.method}
+	for _, f := range v.st.Structs[typeName] {
+		tag := reflect.StructTag(strings.Trim(f.Tag, "`"))
+		rules, ok := tag.Lookup("validate")
+		if f.Name == "" || (!ok && v.kind(strings.TrimPrefix(f.Type, "*")) != "struct") {
+			continue
+		}
+		name := f.Name
+		if j := strings.Split(tag.Get("json"), ",")[0]; j != "" && j != "-" {
+			name = j
+		}
+		inner.field(expr+"."+f.Name, field+"."+name, f.Type, rules, seen)
+	}
+	return inner.checks
+}
+
+// validateMethod is the Validate method of the request of the method.
+func validateMethod(m parser.Method, st *ServiceTypes) parser.Method {
+	rules := map[string]string{}
+	for _, d := range m.Directives("validate") {
+		if len(d) > 1 {
+			rules[d[0]] = strings.Join(d[1:], " ")
+		}
+	}
+	v := &validation{st: st, method: m.Name}
+	for _, p := range m.Parameters {
+		if p.Type == "context.Context" {
+			continue
+		}
+		r, ok := rules[p.Name]
+		delete(rules, p.Name)
+		if !ok && v.kind(p.Type) != "struct" && v.kind(strings.TrimPrefix(p.Type, "*")) != "struct" {
+			continue
+		}
+		v.field("r."+utils.ToUpperFirstCamelCase(p.Name), utils.ToLowerSnakeCase(p.Name), p.Type, r, map[string]bool{})
+	}
+	for p := range rules {
+		logrus.Warnf("The validated parameter '%s' is not a parameter of '%s'", p, m.Name)
+	}
+	body := "return nil"
+	if len(v.checks) > 0 {
+		body = fmt.Sprintf(`var violations []FieldViolation
+			%s
+			if len(violations) > 0 {
+				return &ValidationError{Violations: violations}
+			}
+			return nil`, strings.Join(v.checks, "\n"))
+	}
+	validate := parser.NewMethodWithComment(
+		"Validate",
+		fmt.Sprintf(`Validate checks the %sReq against the gk:validate rules of %s and the validate tags
+			of the service structures, it is generated again by gk update.`, m.Name, m.Name),
+		parser.NewNameType("r", m.Name+"Req"),
+		body,
+		[]parser.NamedTypeValue{},
+		[]parser.NamedTypeValue{
+			parser.NewNameType("", "error"),
+		},
+	)
+	// the methods of structures end their comment with a line break
+	validate.Comment = strings.TrimSuffix(validate.Comment, "\n")
+	return validate
+}
+
+// validateStructs are the errors of the invalid requests.
+func validateStructs() []parser.Struct {
+	return []parser.Struct{
+		parser.NewStructWithComment(
+			"FieldViolation",
+			`FieldViolation is a field of a request breaking a validation rule.`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("Field", "string"),
+				parser.NewNameType("Description", "string"),
+			}),
+		parser.NewStructWithComment(
+			"ValidationError",
+			`ValidationError is the error of an invalid request, it is an invalid argument error
+			of the service.`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("Violations", "[]FieldViolation"),
+			}),
+	}
+}
+
+// validateMethods are the methods of ValidationError, the middleware validating the requests
+// and the checks of the rules.
+func validateMethods(st *ServiceTypes) []parser.Method {
+	return []parser.Method{
+		parser.NewMethod(
+			"Error",
+			parser.NewNameType("e", "*ValidationError"),
+			fmt.Sprintf(`msgs := make([]string, 0, len(e.Violations))
+			for _, v := range e.Violations {
+				msgs = append(msgs, v.Field+" "+v.Description)
+			}
+			return strings.Join(msgs, ", ") + ": " + %s.ErrInvalidArgument.Error()`, st.Package),
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "string"),
+			},
+		),
+		parser.NewMethod(
+			"Unwrap",
+			parser.NewNameType("e", "*ValidationError"),
+			fmt.Sprintf(`return %s.ErrInvalidArgument`, st.Package),
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "error"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"ValidatingMiddleware",
+			`ValidatingMiddleware returns an endpoint middleware that checks the requests with
+			their Validate method, the invalid requests do not reach the service.`,
+			parser.NamedTypeValue{},
+			`
+			return func(next endpoint.Endpoint) endpoint.Endpoint {
+				return func(ctx context.Context, request interface{}) (interface{}, error) {
+					if v, ok := request.(interface{ Validate() error }); ok {
+						if err := v.Validate(); err != nil {
+							return nil, err
+						}
+					}
+					return next(ctx, request)
+				}
+			}`,
+			[]parser.NamedTypeValue{},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "endpoint.Middleware"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"isEmail",
+			`isEmail reports whether s is a bare email address.`,
+			parser.NamedTypeValue{},
+			`a, err := mail.ParseAddress(s)
+			return err == nil && a.Address == s`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("s", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "bool"),
+			},
+		),
+		parser.NewMethodWithComment(
+			"isURL",
+			`isURL reports whether s is an absolute URL.`,
+			parser.NamedTypeValue{},
+			`u, err := url.ParseRequestURI(s)
+			return err == nil && u.Scheme != "" && u.Host != ""`,
+			[]parser.NamedTypeValue{
+				parser.NewNameType("s", "string"),
+			},
+			[]parser.NamedTypeValue{
+				parser.NewNameType("", "bool"),
+			},
+		),
+	}
+}
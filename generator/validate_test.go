@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/liuchamp/gk/fs"
+	"github.com/liuchamp/gk/parser"
+)
+
+func TestValidateMethod(t *testing.T) {
+	st := &ServiceTypes{
+		Package: "accservice",
+		Types:   map[string]string{"Item": "struct", "Owner": "struct", "Level": "int"},
+		Structs: map[string][]parser.NamedTypeValue{
+			"Item": {
+				{Name: "Name", Type: "string", Tag: "`json:\"name\" validate:\"required,max=64\"`"},
+				{Name: "Email", Type: "string", Tag: "`json:\"email\" validate:\"omitempty,email\"`"},
+				{Name: "Owner", Type: "*Owner"},
+				{Name: "Note", Type: "string"},
+			},
+			// the cycles are validated once
+			"Owner": {
+				{Name: "Id", Type: "int64", Tag: "`validate:\"min=1\"`"},
+				{Name: "Boss", Type: "*Owner"},
+			},
+		},
+	}
+	for _, c := range []struct {
+		typeName, rules string
+		want            []string
+	}{
+		{"string", "required,min=2", []string{
+			`if r.P == "" { violations = append(violations, FieldViolation{Field: "p", Description: "is required"}) }`,
+			`if utf8.RuneCountInString(r.P) < 2 { violations = append(violations, FieldViolation{Field: "p", Description: "must be at least 2 characters long"}) }`,
+		}},
+		{"string", "oneof=asc desc", []string{`if r.P != "asc" && r.P != "desc" {`, `"must be one of asc, desc"`}},
+		{"string", "omitempty,url", []string{`if r.P != "" { if !isURL(r.P) {`}},
+		{"int", "min=1,max=100", []string{`if r.P < 1 {`, `if r.P > 100 {`, `"must be at most 100"`}},
+		{"Level", "oneof=1 2 3", []string{`if r.P != 1 && r.P != 2 && r.P != 3 {`}},
+		{"time.Duration", "max=1m", []string{`if r.P > time.Duration(60000000000) {`, `"must be at most 1m"`}},
+		{"time.Time", "required", []string{`if r.P.IsZero() {`}},
+		{"[]string", "len=2", []string{`if len(r.P) != 2 {`, `"must have 2 items"`}},
+		{"map[string]int", "omitempty,max=3", []string{`if len(r.P) != 0 { if len(r.P) > 3 {`}},
+		{"*string", "required,email", []string{`if r.P == nil {`, `if r.P != nil { if !isEmail((*r.P)) {`}},
+		// the tags of the structures
+		{"Item", "", []string{
+			`if r.P.Name == "" { violations = append(violations, FieldViolation{Field: "p.name", Description: "is required"}) }`,
+			`if r.P.Email != "" { if !isEmail(r.P.Email) {`,
+			`if r.P.Owner != nil { if (*r.P.Owner).Id < 1 { violations = append(violations, FieldViolation{Field: "p.Owner.Id"`,
+		}},
+		// the rules that do not apply are ignored
+		{"bool", "min=1", []string{`return nil`}},
+		{"string", "len=abc", []string{`return nil`}},
+	} {
+		m := parser.NewMethodWithComment("Find", "gk:validate p "+c.rules, parser.NamedTypeValue{}, "",
+			[]parser.NamedTypeValue{parser.NewNameType("ctx", "context.Context"), parser.NewNameType("p", c.typeName)},
+			[]parser.NamedTypeValue{parser.NewNameType("err", "error")},
+		)
+		v := validateMethod(m, st)
+		if v.Struct.Type != "FindReq" {
+			t.Errorf("Validate is a method of %s", v.Struct.Type)
+		}
+		assertContains(t, v.Body, c.want...)
+	}
+
+	// a cycle of structures is walked once
+	m := parser.NewMethod("Find", parser.NamedTypeValue{}, "",
+		[]parser.NamedTypeValue{parser.NewNameType("o", "Owner")},
+		[]parser.NamedTypeValue{parser.NewNameType("err", "error")},
+	)
+	v := validateMethod(m, st)
+	assertContains(t, v.Body, `if r.O.Id < 1 {`)
+	assertNotContains(t, v.Body, `(*r.O.Boss).Id`)
+}
+
+func TestValidateInit(t *testing.T) {
+	testProject(t, map[string]interface{}{"gk_transport": "grpc"})
+	testService(t, "acc", `// gk:validate id min=1
+	Get(ctx context.Context, id int64) (a *Account, err error)`, testAccTypes)
+	if err := fs.Get().WriteFile("acc/accpb/acc.pb.go", "package accpb\n", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewServiceInitGenerator().Generate("acc"); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, testRead(t, "acc/pkg/accendpoint/set.go"),
+		`func (r GetReq) Validate() error {`,
+		`if r.Id < 1 {`,
+	)
+	assertContains(t, testRead(t, "acc/pkg/accendpoint/middleware.go"),
+		`type ValidationError struct {`,
+		`return accservice.ErrInvalidArgument`,
+		`func ValidatingMiddleware() endpoint.Middleware {`,
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/sirupsen/logrus"
 	template "github.com/liuchamp/gk/templates"
 	"go/format"
+	"strings"
 )
 
 type Method struct {
@@ -33,6 +34,31 @@ func NewMethodWithComment(name string, comment string, str NamedTypeValue, body
 	return m
 }
 
+// Directives returns the words of the `gk:<name>` lines of the method comment.
+func (m Method) Directives(name string) [][]string {
+	return directives(strings.Split(m.Comment, "\n"), name)
+}
+
+// HTTPRules are the routes of the `gk:http <METHOD> <path> [body=<field>]` lines of the
+// method comment.
+func (m Method) HTTPRules() []ProtoHTTPRule {
+	return httpDirectives(strings.Split(m.Comment, "\n"))
+}
+
+// Description is the text of the method comment without the comment markers and the `gk:`
+// directives.
+func (m Method) Description() string {
+	var lines []string
+	for _, v := range strings.Split(m.Comment, "\n") {
+		line := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(v), "//"))
+		if strings.HasPrefix(line, "gk:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
 func (m *Method) String() string {
 	str := ""
 	if m.Struct.Name != "" {
@@ -8,6 +8,7 @@ import (
 	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"strings"
 )
 
@@ -67,7 +68,7 @@ func (fp *FileParser) Parse(src []byte) (*File, error) {
 			case token.VAR:
 				f.Vars = fp.parseVars(dec.Specs)
 			case token.TYPE:
-				fp.parseType(dec.Specs, &f)
+				fp.parseType(dec.Specs, dec.Doc, &f)
 			default:
 				logrus.Info("Skipping unknown Token Type")
 			}
@@ -76,7 +77,9 @@ func (fp *FileParser) Parse(src []byte) (*File, error) {
 	//fmt.Println(f.String())
 	return &f, nil
 }
-func (fp *FileParser) parseType(ds []ast.Spec, f *File) {
+// parseType reads the types of the declaration, the doc of a declaration of one type is the
+// comment of the type.
+func (fp *FileParser) parseType(ds []ast.Spec, doc *ast.CommentGroup, f *File) {
 	for _, sp := range ds {
 		tsp, ok := sp.(*ast.TypeSpec)
 		if !ok {
@@ -89,11 +92,20 @@ func (fp *FileParser) parseType(ds []ast.Spec, f *File) {
 			mth := fp.parseFieldListAsMethods(ift.Methods)
 			intr := NewInterface(tsp.Name.Name, mth)
 			intr.Methods = mth
+			comment := tsp.Doc
+			if comment == nil && len(ds) == 1 {
+				comment = doc
+			}
+			if comment != nil {
+				intr.Comment = prepareComments(strings.TrimSpace(comment.Text()))
+			}
 			f.Interfaces = append(f.Interfaces, intr)
 		case *ast.StructType:
 			st := tsp.Type.(*ast.StructType)
 			str := NewStruct(tsp.Name.Name, fp.parseFieldListAsNamedTypes(st.Fields))
 			f.Structs = append(f.Structs, str)
+		case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.ArrayType, *ast.MapType:
+			f.AliasType = append(f.AliasType, NewNameType(tsp.Name.Name, fp.getTypeFromExp(tsp.Type)))
 		default:
 			logrus.Info("Skipping unknown type - ", fmt.Sprintf("%v", tsp.Name.Name))
 		}
@@ -224,31 +236,23 @@ func (fp *FileParser) getTypeFromExp(e ast.Expr) string {
 		key := fp.getTypeFromExp(k.Key)
 		value := fp.getTypeFromExp(k.Value)
 		tp = "map[" + key + "]" + value
+	case *ast.ChanType:
+		switch k.Dir {
+		case ast.RECV:
+			tp = "<-chan " + fp.getTypeFromExp(k.Value)
+		case ast.SEND:
+			tp = "chan<- " + fp.getTypeFromExp(k.Value)
+		default:
+			tp = "chan " + fp.getTypeFromExp(k.Value)
+		}
 	case *ast.InterfaceType:
 		tp = "interface{}"
+	case *ast.Ellipsis:
+		tp = "..." + fp.getTypeFromExp(k.Elt)
 	case *ast.FuncType:
-		var (
-			paramList  string
-			resultList string
-		)
-		for _, v := range k.Params.List {
-			t := v.Type.(*ast.SelectorExpr)
-			p := t.X.(*ast.Ident)
-			paramList += fmt.Sprintf("%s.%s,", p.Name, t.Sel.Name)
-		}
-		for _, v := range k.Results.List {
-			t := v.Type.(*ast.SelectorExpr)
-			p := t.X.(*ast.Ident)
-			resultList += fmt.Sprintf("%s.%s,", p.Name, t.Sel.Name)
-		}
-		paramList = strings.TrimSpace(paramList)
-		paramList = strings.TrimRight(paramList, ",")
-		resultList = strings.TrimSpace(resultList)
-		resultList = strings.TrimRight(resultList, ",")
-		if len(k.Results.List) > 0 {
-			tp = fmt.Sprintf("func(%s) (%s)", paramList, resultList)
-		} else {
-			tp = fmt.Sprintf("func(%s) %s", paramList, resultList)
+		tp = "func(" + fp.getTypesFromFieldList(k.Params) + ")"
+		if results := fp.getTypesFromFieldList(k.Results); results != "" {
+			tp += " (" + results + ")"
 		}
 	default:
 		logrus.Info("Type Expresion not supported", fmt.Sprintf("%#v", k))
@@ -256,6 +260,24 @@ func (fp *FileParser) getTypeFromExp(e ast.Expr) string {
 	}
 	return tp
 }
+// getTypesFromFieldList is the comma separated types of the parameters or the results of a
+// func type, the list is nil when the func has none.
+func (fp *FileParser) getTypesFromFieldList(list *ast.FieldList) string {
+	if list == nil {
+		return ""
+	}
+	var out []string
+	for _, v := range list.List {
+		tp := fp.getTypeFromExp(v.Type)
+		if tp == "" {
+			tp = types.ExprString(v.Type)
+		}
+		for i := 0; i < len(v.Names) || i == 0; i++ {
+			out = append(out, tp)
+		}
+	}
+	return strings.Join(out, ", ")
+}
 func (fp *FileParser) parseFieldListAsMethods(list *ast.FieldList) []Method {
 	mth := []Method{}
 	if list != nil {
@@ -265,6 +287,9 @@ func (fp *FileParser) parseFieldListAsMethods(list *ast.FieldList) []Method {
 				m := Method{
 					Name: p.Names[0].Name,
 				}
+				if p.Doc != nil {
+					m.Comment = prepareComments(strings.TrimSpace(p.Doc.Text()))
+				}
 				m.Parameters = fp.parseFieldListAsNamedTypes(t.Params)
 				m.Results = fp.parseFieldListAsNamedTypes(t.Results)
 				mth = append(mth, m)
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -21,3 +22,118 @@ type MyService interface {
 	fmt.Println(v.String())
 
 }
+
+func TestChanTypes(t *testing.T) {
+	p := NewFileParser()
+	v, err := p.Parse([]byte(`package service
+type MyService interface {
+	Chat(ctx context.Context, in <-chan string, ack chan<- bool) (out chan *Message, err error)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := v.Interfaces[0].Methods[0]
+	if m.Parameters[1].Type != "<-chan string" || m.Parameters[2].Type != "chan<- bool" || m.Results[0].Type != "chan *Message" {
+		t.Fatalf("the channel types are wrong: %v %v", m.Parameters, m.Results)
+	}
+}
+
+func TestMethodDirectives(t *testing.T) {
+	p := NewFileParser()
+	v, err := p.Parse([]byte(`package service
+type MyService interface {
+	// GetUser finds a user.
+	// gk:http GET /users/{id}
+	// gk:header token Authorization
+	GetUser(ctx context.Context, id int64, token string) (u *User, err error)
+	Ping(ctx context.Context) (err error)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := v.Interfaces[0].Methods[0]
+	if rules := m.HTTPRules(); len(rules) != 1 || rules[0].Method != "GET" || rules[0].Path != "/users/{id}" {
+		t.Errorf("unexpected rules %v", rules)
+	}
+	if d := m.Directives("header"); len(d) != 1 || strings.Join(d[0], " ") != "token Authorization" {
+		t.Errorf("unexpected header directives %v", d)
+	}
+	if d := m.Description(); d != "GetUser finds a user." {
+		t.Errorf("unexpected description %q", d)
+	}
+	if rules := v.Interfaces[0].Methods[1].HTTPRules(); len(rules) != 0 {
+		t.Errorf("unexpected rules %v", rules)
+	}
+}
+
+func TestFuncTypes(t *testing.T) {
+	p := NewFileParser()
+	// the stream helpers of a generated grpc.go
+	v, err := p.Parse([]byte(`package acctransport
+
+// tracingGRPCStreamServer joins the trace of the metadata of a stream like the server
+// options, the returned function ends the span of the handler.
+func tracingGRPCStreamServer(tracer accendpoint.Tracer, ctx context.Context, md metadata.MD, method string) (context.Context, func()) {
+	return ctx, func() {}
+}
+
+func authGRPCToContext(ctx context.Context, md metadata.MD) context.Context {
+	return ctx
+}
+
+func chain(before func(context.Context, metadata.MD) context.Context, after func(ctx context.Context, err error), opts ...func(*grpc.Server)) {
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Methods[0].Results[1].Type; got != "func()" {
+		t.Errorf("unexpected result type %q", got)
+	}
+	params := v.Methods[2].Parameters
+	for k, want := range []string{
+		"func(context.Context, metadata.MD) (context.Context)",
+		"func(context.Context, error)",
+		"...func(*grpc.Server)",
+	} {
+		if params[k].Type != want {
+			t.Errorf("unexpected parameter type %q, want %q", params[k].Type, want)
+		}
+	}
+	if _, err := p.Parse([]byte(v.String())); err != nil {
+		t.Errorf("the file does not parse back: %s", err)
+	}
+}
+
+func TestInterfaceComment(t *testing.T) {
+	p := NewFileParser()
+	v, err := p.Parse([]byte(`package service
+// MyService finds the users.
+// gk:auth apikey
+type MyService interface {
+	GetUser(ctx context.Context, id int64) (u *User, err error)
+}
+type (
+	// Other is not the only type of its declaration.
+	Other interface{}
+	Last interface{}
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := v.Interfaces[0].Comment; c != "// MyService finds the users.\n// gk:auth apikey\n" {
+		t.Errorf("the comment of MyService is %q", c)
+	}
+	if d := (Method{Comment: v.Interfaces[0].Comment}).Directives("auth"); len(d) != 1 || d[0][0] != "apikey" {
+		t.Errorf("unexpected directives %v", d)
+	}
+	if c := v.Interfaces[1].Comment; c != "// Other is not the only type of its declaration.\n" {
+		t.Errorf("the comment of Other is %q", c)
+	}
+	if c := v.Interfaces[2].Comment; c != "" {
+		t.Errorf("the comment of Last is %q", c)
+	}
+}
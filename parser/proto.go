@@ -3,64 +3,223 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"strings"
+
 	"github.com/emicklei/proto"
+	template "github.com/liuchamp/gk/templates"
+	"github.com/sirupsen/logrus"
 )
 
 type Proto struct {
+	Syntax      string
 	PackageName string
 	Imports     []*proto.Import
-	Options     []*proto.Option
-	ServiceName string
-	Methods     []Method
-	Messages    []Struct
+	Options     []ProtoOption
+	Services    []ProtoService
+	Messages    []ProtoMessage
+	Enums       []ProtoEnum
+}
+
+// ProtoOption is an option statement, Value keeps the literal as written in the source.
+type ProtoOption struct {
+	Name  string
+	Value string
+}
+
+type ProtoService struct {
+	Name    string
+	Comment []string
+	Options []ProtoOption
+	RPCs    []ProtoRPC
+}
+
+type ProtoRPC struct {
+	Name           string
+	Comment        []string
+	RequestType    string
+	StreamsRequest bool
+	ReturnsType    string
+	StreamsReturns bool
+	Options        []ProtoOption
+	// HTTPRules are the REST routes of the rpc, from its `google.api.http` option or, without
+	// one, from the `gk:http` directives of its comment.
+	HTTPRules []ProtoHTTPRule
+}
+
+// ProtoHTTPRule maps a REST route onto an rpc. Body is the request field filled from the
+// JSON body, `*` for the whole request, the path variables and the query fill the others.
+type ProtoHTTPRule struct {
+	Method       string
+	Path         string
+	Body         string
+	ResponseBody string
+}
+
+type ProtoMessage struct {
+	Name     string
+	Comment  []string
+	IsExtend bool
+	Options  []ProtoOption
+	Reserved []ProtoReserved
+	// Extensions are the numbers of a proto2 message left to the extensions, they keep the
+	// FieldNames of ProtoReserved empty.
+	Extensions []ProtoReserved
+	Fields     []ProtoField
+	Oneofs     []ProtoOneof
+	Enums      []ProtoEnum
+	Messages   []ProtoMessage
+}
+
+type ProtoField struct {
+	Name          string
+	Comment       []string
+	InlineComment string
+	Type          string
+	KeyType       string
+	Sequence      int
+	Repeated      bool
+	Optional      bool
+	Required      bool
+	Options       []ProtoOption
+}
+
+type ProtoOneof struct {
+	Name    string
+	Comment []string
+	Options []ProtoOption
+	Fields  []ProtoField
+}
+
+type ProtoEnum struct {
+	Name     string
+	Comment  []string
+	Options  []ProtoOption
+	Reserved []ProtoReserved
+	Values   []ProtoEnumValue
+}
+
+type ProtoEnumValue struct {
+	Name    string
+	Comment []string
+	Number  int
+	Options []ProtoOption
+}
+
+type ProtoReserved struct {
+	Ranges     []proto.Range
+	FieldNames []string
 }
 
 func NewProto() *Proto {
-	return &Proto{}
+	return &Proto{Syntax: "proto3"}
 }
 
-func (p *Proto) handleService() func(s *proto.Service) {
-	return func(s *proto.Service) {
-		p.ServiceName = s.Name
+// GoPackage returns the value of the go_package file option, if any.
+func (p *Proto) GoPackage() string {
+	for _, v := range p.Options {
+		if v.Name == "go_package" {
+			return strings.Trim(v.Value, `"'`)
+		}
 	}
+	return ""
 }
 
-func (p *Proto) handleMessage() func(s *proto.Message) {
-	return func(s *proto.Message) {
-		fields := []NamedTypeValue{}
-		for _, v := range s.Elements {
-			var field NamedTypeValue
-			switch v.(type) {
-			case *proto.NormalField:
-				n := v.(*proto.NormalField)
-				seq := fmt.Sprintf("%v", n.Sequence)
-				if n.Repeated {
-					field = NewNameTypeValue(n.Name, "repeated "+n.Type, seq)
-				} else {
-					field = NewNameTypeValue(n.Name, n.Type, seq)
-				}
-				field.Options = n.Options
-			case *proto.MapField:
-				n := v.(*proto.MapField)
-				seq := fmt.Sprintf("%v", n.Sequence)
-				typeName := fmt.Sprintf("map<%v, %v>", n.KeyType, n.Type)
-				field = NewNameTypeValue(n.Name, typeName, seq)
-				field.Options = n.Options
-			}
-			fields = append(fields, field)
+// Service returns the service with the given name, adding an empty one when it does not exist yet.
+func (p *Proto) Service(name string) *ProtoService {
+	for k, v := range p.Services {
+		if v.Name == name {
+			return &p.Services[k]
 		}
-		message := Struct{Name: s.Name, Vars: fields}
-		p.Messages = append(p.Messages, message)
 	}
+	p.Services = append(p.Services, ProtoService{Name: name})
+	return &p.Services[len(p.Services)-1]
 }
 
-func (p *Proto) handleRPC() func(s *proto.RPC) {
-	return func(s *proto.RPC) {
-		reqParam := []NamedTypeValue{NewNameType("", s.RequestType)}
-		resParam := []NamedTypeValue{NewNameType("", s.ReturnsType)}
-		method := Method{Name: s.Name, Parameters: reqParam, Results: resParam}
-		p.Methods = append(p.Methods, method)
+// Message returns the top level message with the given name or nil.
+func (p *Proto) Message(name string) *ProtoMessage {
+	for k, v := range p.Messages {
+		if v.Name == name {
+			return &p.Messages[k]
+		}
 	}
+	return nil
+}
+
+// Render is the source of the proto.
+func (p *Proto) Render() (string, error) {
+	s, err := template.NewEngine().Execute("proto.pb", p)
+	if err != nil {
+		return "", err
+	}
+	return formatProto(s), nil
+}
+
+// String is the source of the proto, Render tells why it is empty.
+func (p *Proto) String() string {
+	s, err := p.Render()
+	if err != nil {
+		logrus.Error(err)
+	}
+	return s
+}
+
+func (s *ProtoService) RPC(name string) *ProtoRPC {
+	for k, v := range s.RPCs {
+		if v.Name == name {
+			return &s.RPCs[k]
+		}
+	}
+	return nil
+}
+
+func (m *ProtoMessage) Field(name string) *ProtoField {
+	for k, v := range m.Fields {
+		if v.Name == name {
+			return &m.Fields[k]
+		}
+	}
+	return nil
+}
+
+// MatchField returns the field named like a go field or parameter, case and underscores are ignored.
+func (m *ProtoMessage) MatchField(name string) *ProtoField {
+	return findProtoField(m.Fields, name)
+}
+
+// MatchOneofField returns the oneof member named like a go field or parameter and its oneof.
+func (m *ProtoMessage) MatchOneofField(name string) (*ProtoOneof, *ProtoField) {
+	for k := range m.Oneofs {
+		if f := findProtoField(m.Oneofs[k].Fields, name); f != nil {
+			return &m.Oneofs[k], f
+		}
+	}
+	return nil, nil
+}
+
+// FullType is the type of the field as it is declared, including its label.
+func (f ProtoField) FullType() string {
+	switch {
+	case f.KeyType != "":
+		return fmt.Sprintf("map<%s, %s>", f.KeyType, f.Type)
+	case f.Repeated:
+		return "repeated " + f.Type
+	case f.Optional:
+		return "optional " + f.Type
+	case f.Required:
+		return "required " + f.Type
+	}
+	return f.Type
+}
+
+func (r ProtoReserved) String() string {
+	var list []string
+	for _, v := range r.Ranges {
+		list = append(list, v.SourceRepresentation())
+	}
+	for _, v := range r.FieldNames {
+		list = append(list, fmt.Sprintf("%q", v))
+	}
+	return strings.Join(list, ", ")
 }
 
 type ProtoParser struct{}
@@ -70,32 +229,306 @@ func NewProtoParser() *ProtoParser {
 }
 
 func (pp *ProtoParser) Parse(src []byte) (*Proto, error) {
-	p := NewProto()
+	p := &Proto{}
 	reader := bytes.NewReader(src)
 	parser := proto.NewParser(reader)
 	definition, err := parser.Parse()
 	if err != nil {
 		return nil, err
 	}
-
-	proto.Walk(definition,
-		proto.WithService(p.handleService()),
-		proto.WithMessage(p.handleMessage()),
-		proto.WithRPC(p.handleRPC()),
-	)
+	if err = checkProtoNodes(definition.Elements); err != nil {
+		return nil, err
+	}
 	for _, v := range definition.Elements {
-		switch v.(type) {
+		switch e := v.(type) {
+		case *proto.Syntax:
+			p.Syntax = e.Value
 		case *proto.Package:
-			pack := v.(*proto.Package)
-			p.PackageName = pack.Name
+			p.PackageName = e.Name
 		case *proto.Import:
-			imp := v.(*proto.Import)
-			p.Imports = append(p.Imports, imp)
+			p.Imports = append(p.Imports, e)
 		case *proto.Option:
-			opt := v.(*proto.Option)
-			p.Options = append(p.Options, opt)
+			p.Options = append(p.Options, newProtoOption(e))
+		case *proto.Service:
+			p.Services = append(p.Services, newProtoService(e))
+		case *proto.Message:
+			p.Messages = append(p.Messages, newProtoMessage(e))
+		case *proto.Enum:
+			p.Enums = append(p.Enums, newProtoEnum(e))
 		}
 	}
-
 	return p, nil
 }
+
+// checkProtoNodes refuses the nodes the model can not render back, they would be lost when the
+// proto is written again.
+func checkProtoNodes(elements []proto.Visitee) error {
+	for _, v := range elements {
+		switch e := v.(type) {
+		case *proto.Group:
+			return fmt.Errorf("line %d: the group `%s` is not supported, declare it as a nested message", e.Position.Line, e.Name)
+		case *proto.Message:
+			if err := checkProtoNodes(e.Elements); err != nil {
+				return err
+			}
+		case *proto.Oneof:
+			if err := checkProtoNodes(e.Elements); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newProtoService(s *proto.Service) ProtoService {
+	svc := ProtoService{Name: s.Name, Comment: commentLines(s.Comment)}
+	for _, v := range s.Elements {
+		switch e := v.(type) {
+		case *proto.Option:
+			svc.Options = append(svc.Options, newProtoOption(e))
+		case *proto.RPC:
+			rpc := ProtoRPC{
+				Name:           e.Name,
+				Comment:        commentLines(e.Comment),
+				RequestType:    e.RequestType,
+				StreamsRequest: e.StreamsRequest,
+				ReturnsType:    e.ReturnsType,
+				StreamsReturns: e.StreamsReturns,
+			}
+			for _, o := range e.Elements {
+				if opt, ok := o.(*proto.Option); ok {
+					rpc.Options = append(rpc.Options, newProtoOption(opt))
+					if opt.Name == "(google.api.http)" {
+						rpc.HTTPRules = append(rpc.HTTPRules, newProtoHTTPRules(opt.Constant.OrderedMap)...)
+					}
+				}
+			}
+			if len(rpc.HTTPRules) == 0 {
+				rpc.HTTPRules = httpDirectives(rpc.Comment)
+			}
+			svc.RPCs = append(svc.RPCs, rpc)
+		}
+	}
+	return svc
+}
+
+// newProtoHTTPRules reads a `google.api.http` rule and its additional bindings.
+func newProtoHTTPRules(m proto.LiteralMap) []ProtoHTTPRule {
+	rule := ProtoHTTPRule{}
+	var more []ProtoHTTPRule
+	for _, v := range m {
+		switch v.Name {
+		case "get", "put", "post", "delete", "patch":
+			rule.Method, rule.Path = strings.ToUpper(v.Name), v.Source
+		case "custom":
+			kind, _ := v.OrderedMap.Get("kind")
+			path, _ := v.OrderedMap.Get("path")
+			rule.Method, rule.Path = strings.ToUpper(kind.Source), path.Source
+		case "body":
+			rule.Body = v.Source
+		case "response_body":
+			rule.ResponseBody = v.Source
+		case "additional_bindings":
+			if v.OrderedMap != nil {
+				more = append(more, newProtoHTTPRules(v.OrderedMap)...)
+			}
+			for _, b := range v.Array {
+				more = append(more, newProtoHTTPRules(b.OrderedMap)...)
+			}
+		}
+	}
+	if rule.Path == "" {
+		return more
+	}
+	return append([]ProtoHTTPRule{rule}, more...)
+}
+
+// httpDirectives reads the `gk:http <METHOD> <path> [body=<field>]` lines of a comment.
+func httpDirectives(comment []string) (rules []ProtoHTTPRule) {
+	for _, words := range directives(comment, "http") {
+		if len(words) < 2 {
+			continue
+		}
+		rule := ProtoHTTPRule{Method: strings.ToUpper(words[0]), Path: words[1]}
+		for _, w := range words[2:] {
+			switch {
+			case strings.HasPrefix(w, "body="):
+				rule.Body = strings.TrimPrefix(w, "body=")
+			case strings.HasPrefix(w, "response_body="):
+				rule.ResponseBody = strings.TrimPrefix(w, "response_body=")
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func newProtoMessage(m *proto.Message) ProtoMessage {
+	msg := ProtoMessage{Name: m.Name, IsExtend: m.IsExtend, Comment: commentLines(m.Comment)}
+	for _, v := range m.Elements {
+		switch e := v.(type) {
+		case *proto.Option:
+			msg.Options = append(msg.Options, newProtoOption(e))
+		case *proto.Reserved:
+			msg.Reserved = append(msg.Reserved, ProtoReserved{Ranges: e.Ranges, FieldNames: e.FieldNames})
+		case *proto.Extensions:
+			msg.Extensions = append(msg.Extensions, ProtoReserved{Ranges: e.Ranges})
+		case *proto.NormalField:
+			f := newProtoField(e.Field)
+			f.Repeated, f.Optional, f.Required = e.Repeated, e.Optional, e.Required
+			msg.Fields = append(msg.Fields, f)
+		case *proto.MapField:
+			f := newProtoField(e.Field)
+			f.KeyType = e.KeyType
+			msg.Fields = append(msg.Fields, f)
+		case *proto.Oneof:
+			oneof := ProtoOneof{Name: e.Name, Comment: commentLines(e.Comment)}
+			for _, o := range e.Elements {
+				switch oe := o.(type) {
+				case *proto.Option:
+					oneof.Options = append(oneof.Options, newProtoOption(oe))
+				case *proto.OneOfField:
+					oneof.Fields = append(oneof.Fields, newProtoField(oe.Field))
+				}
+			}
+			msg.Oneofs = append(msg.Oneofs, oneof)
+		case *proto.Enum:
+			msg.Enums = append(msg.Enums, newProtoEnum(e))
+		case *proto.Message:
+			msg.Messages = append(msg.Messages, newProtoMessage(e))
+		}
+	}
+	return msg
+}
+
+func newProtoField(f *proto.Field) ProtoField {
+	field := ProtoField{
+		Name:     f.Name,
+		Comment:  commentLines(f.Comment),
+		Type:     f.Type,
+		Sequence: f.Sequence,
+	}
+	if f.InlineComment != nil {
+		field.InlineComment = strings.Join(f.InlineComment.Lines, " ")
+	}
+	for _, o := range f.Options {
+		field.Options = append(field.Options, newProtoOption(o))
+	}
+	return field
+}
+
+func newProtoEnum(e *proto.Enum) ProtoEnum {
+	enum := ProtoEnum{Name: e.Name, Comment: commentLines(e.Comment)}
+	for _, v := range e.Elements {
+		switch ee := v.(type) {
+		case *proto.Option:
+			enum.Options = append(enum.Options, newProtoOption(ee))
+		case *proto.Reserved:
+			enum.Reserved = append(enum.Reserved, ProtoReserved{Ranges: ee.Ranges, FieldNames: ee.FieldNames})
+		case *proto.EnumField:
+			value := ProtoEnumValue{Name: ee.Name, Comment: commentLines(ee.Comment), Number: ee.Integer}
+			for _, o := range ee.Elements {
+				if opt, ok := o.(*proto.Option); ok {
+					value.Options = append(value.Options, newProtoOption(opt))
+				}
+			}
+			if len(value.Options) == 0 && ee.ValueOption != nil {
+				value.Options = append(value.Options, newProtoOption(ee.ValueOption))
+			}
+			enum.Values = append(enum.Values, value)
+		}
+	}
+	return enum
+}
+
+func newProtoOption(o *proto.Option) ProtoOption {
+	return ProtoOption{Name: o.Name, Value: literalSource(o.Constant)}
+}
+
+// literalSource renders a literal back to proto source, including arrays and aggregates.
+func literalSource(l proto.Literal) string {
+	if l.Array != nil {
+		var list []string
+		for _, v := range l.Array {
+			list = append(list, literalSource(*v))
+		}
+		return "[" + strings.Join(list, ", ") + "]"
+	}
+	if l.OrderedMap != nil {
+		var list []string
+		for _, v := range l.OrderedMap {
+			sep := ": "
+			if !v.PrintsColon && v.Literal.OrderedMap != nil {
+				sep = " "
+			}
+			list = append(list, v.Name+sep+literalSource(*v.Literal))
+		}
+		return "{ " + strings.Join(list, " ") + " }"
+	}
+	return l.SourceRepresentation()
+}
+
+func commentLines(c *proto.Comment) []string {
+	if c == nil {
+		return nil
+	}
+	return c.Lines
+}
+
+// formatProto indents the rendered proto source by block depth and drops repeated blank lines.
+func formatProto(src string) string {
+	var (
+		out   []string
+		depth int
+		blank bool
+	)
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if !blank && len(out) > 0 && !strings.HasSuffix(out[len(out)-1], "{") {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		opens, closes := countBraces(line)
+		if strings.HasPrefix(line, "}") && depth > 0 {
+			depth--
+			closes--
+		}
+		if len(out) > 0 && out[len(out)-1] == "" && strings.HasPrefix(line, "}") {
+			out = out[:len(out)-1]
+		}
+		out = append(out, strings.Repeat("    ", depth)+line)
+		depth += opens - closes
+		if depth < 0 {
+			depth = 0
+		}
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+func countBraces(line string) (opens, closes int) {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '/' && strings.HasPrefix(line[i:], "//"):
+			return
+		case c == '{':
+			opens++
+		case c == '}':
+			closes++
+		}
+	}
+	return
+}
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/proto"
+)
+
+// ProtoLock keeps the field numbers handed out to the messages gk manages, so a message
+// keeps its numbers when the go types it comes from change and a number is never used twice.
+type ProtoLock map[string]*ProtoMessageLock
+
+// ProtoMessageLock are the numbers of the fields of a message and of the fields it removed.
+type ProtoMessageLock struct {
+	Fields   map[string]int `json:"fields"`
+	Reserved map[string]int `json:"reserved,omitempty"`
+}
+
+// ProtoScalarGoTypes maps the protobuf scalar types to the go types protoc-gen-go uses.
+var ProtoScalarGoTypes = map[string]string{
+	"double":   "float64",
+	"float":    "float32",
+	"int32":    "int32",
+	"int64":    "int64",
+	"uint32":   "uint32",
+	"uint64":   "uint64",
+	"sint32":   "int32",
+	"sint64":   "int64",
+	"fixed32":  "uint32",
+	"fixed64":  "uint64",
+	"sfixed32": "int32",
+	"sfixed64": "int64",
+	"bool":     "bool",
+	"string":   "string",
+	"bytes":    "[]byte",
+}
+
+// protoReservedRange is the range protobuf keeps for its own implementation.
+var protoReservedRange = proto.Range{From: 19000, To: 19999}
+
+func NewProtoLock() ProtoLock {
+	return ProtoLock{}
+}
+
+// ParseProtoLock reads a lock written by ProtoLock.String.
+func ParseProtoLock(src []byte) (ProtoLock, error) {
+	l := NewProtoLock()
+	if err := json.Unmarshal(src, &l); err != nil {
+		return nil, fmt.Errorf("the proto lock is invalid: %s", err)
+	}
+	return l, nil
+}
+
+func (l ProtoLock) String() string {
+	b, _ := json.MarshalIndent(l, "", "  ")
+	return string(b) + "\n"
+}
+
+func (l ProtoLock) message(name string) *ProtoMessageLock {
+	ml, ok := l[name]
+	if !ok || ml == nil {
+		ml = &ProtoMessageLock{}
+		l[name] = ml
+	}
+	if ml.Fields == nil {
+		ml.Fields = map[string]int{}
+	}
+	if ml.Reserved == nil {
+		ml.Reserved = map[string]int{}
+	}
+	return ml
+}
+
+// Sync makes the fields of the message match the wanted fields without renumbering:
+// fields already declared keep their number and declaration, new fields get a number never
+// used by the message and removed fields are reserved. Names are matched ignoring case and
+// underscores so `user_id` stays the field of `UserId`.
+func (l ProtoLock) Sync(msg *ProtoMessage, fields []ProtoField) error {
+	ml := l.message(msg.Name)
+	declared := append([]ProtoField{}, msg.Fields...)
+	for _, o := range msg.Oneofs {
+		declared = append(declared, o.Fields...)
+	}
+	for _, f := range declared {
+		if n, ok := ml.Fields[f.Name]; ok && n != f.Sequence {
+			return fmt.Errorf("the field `%s` of `%s` is number %d but the lock gave it number %d, field numbers can not change", f.Name, msg.Name, f.Sequence, n)
+		}
+		for name, n := range ml.Fields {
+			if n == f.Sequence && name != f.Name {
+				return fmt.Errorf("the field `%s` of `%s` reuses the number %d of `%s`", f.Name, msg.Name, n, name)
+			}
+		}
+		for name, n := range ml.Reserved {
+			if n == f.Sequence {
+				return fmt.Errorf("the field `%s` of `%s` reuses the number %d of the removed field `%s`", f.Name, msg.Name, n, name)
+			}
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, f := range fields {
+		if d := findProtoField(declared, f.Name); d != nil {
+			if protoTypeChanged(*d, f) {
+				return fmt.Errorf("the type of the field `%s` of `%s` changed from `%s` to `%s`, its number %d can not change type: rename the field so it gets a new number", d.Name, msg.Name, protoFieldType(*d), protoFieldType(f), d.Sequence)
+			}
+			matched[d.Name] = true
+			continue
+		}
+		if n, ok := ml.Reserved[f.Name]; ok {
+			return fmt.Errorf("the field `%s` of `%s` was removed and its number %d is reserved, use another name", f.Name, msg.Name, n)
+		}
+		if msg.reservesName(f.Name) {
+			return fmt.Errorf("the name of the field `%s` of `%s` is reserved, use another name", f.Name, msg.Name)
+		}
+		f.Sequence = l.nextNumber(msg)
+		msg.Fields = append(msg.Fields, f)
+		matched[f.Name] = true
+	}
+
+	kept := []ProtoField{}
+	for _, f := range msg.Fields {
+		if matched[f.Name] {
+			kept = append(kept, f)
+			continue
+		}
+		msg.Reserved = append(msg.Reserved,
+			ProtoReserved{Ranges: []proto.Range{{From: f.Sequence, To: f.Sequence}}},
+			ProtoReserved{FieldNames: []string{f.Name}},
+		)
+		delete(ml.Fields, f.Name)
+		ml.Reserved[f.Name] = f.Sequence
+	}
+	msg.Fields = kept
+	for _, f := range msg.Fields {
+		ml.Fields[f.Name] = f.Sequence
+	}
+	for _, o := range msg.Oneofs {
+		for _, f := range o.Fields {
+			ml.Fields[f.Name] = f.Sequence
+		}
+	}
+	return nil
+}
+
+// nextNumber is the number following every number the message or the lock ever used.
+func (l ProtoLock) nextNumber(msg *ProtoMessage) int {
+	used := []int{0}
+	for _, f := range msg.Fields {
+		used = append(used, f.Sequence)
+	}
+	for _, o := range msg.Oneofs {
+		for _, f := range o.Fields {
+			used = append(used, f.Sequence)
+		}
+	}
+	for _, r := range append(append([]ProtoReserved{}, msg.Reserved...), msg.Extensions...) {
+		for _, v := range r.Ranges {
+			if !v.Max {
+				used = append(used, v.To)
+			}
+		}
+	}
+	ml := l.message(msg.Name)
+	for _, n := range ml.Fields {
+		used = append(used, n)
+	}
+	for _, n := range ml.Reserved {
+		used = append(used, n)
+	}
+	sort.Ints(used)
+	next := used[len(used)-1] + 1
+	if next >= protoReservedRange.From && next <= protoReservedRange.To {
+		next = protoReservedRange.To + 1
+	}
+	return next
+}
+
+func (m *ProtoMessage) reservesName(name string) bool {
+	for _, r := range m.Reserved {
+		for _, v := range r.FieldNames {
+			if v == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// protoTypeChanged tells whether the wanted field can not keep the declared one: the scalars
+// are compared by the go type they decode to so `sint32` still holds an `int32`, the messages
+// and enums are named after their package and are left alone.
+func protoTypeChanged(declared, wanted ProtoField) bool {
+	if declared.Repeated != wanted.Repeated || (declared.KeyType == "") != (wanted.KeyType == "") {
+		return true
+	}
+	return protoScalarChanged(declared.KeyType, wanted.KeyType) || protoScalarChanged(declared.Type, wanted.Type)
+}
+
+func protoScalarChanged(declared, wanted string) bool {
+	d, dok := ProtoScalarGoTypes[declared]
+	w, wok := ProtoScalarGoTypes[wanted]
+	return (dok || wok) && d != w
+}
+
+func protoFieldType(f ProtoField) string {
+	switch {
+	case f.KeyType != "":
+		return "map<" + f.KeyType + ", " + f.Type + ">"
+	case f.Repeated:
+		return "repeated " + f.Type
+	}
+	return f.Type
+}
+
+func findProtoField(list []ProtoField, name string) *ProtoField {
+	key := strings.ToLower(strings.Replace(name, "_", "", -1))
+	for k, v := range list {
+		if strings.ToLower(strings.Replace(v.Name, "_", "", -1)) == key {
+			return &list[k]
+		}
+	}
+	return nil
+}
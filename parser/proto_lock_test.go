@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProtoLockSync(t *testing.T) {
+	lock := NewProtoLock()
+	msg := &ProtoMessage{Name: "GetReq"}
+	if err := lock.Sync(msg, []ProtoField{{Name: "Id", Type: "int64"}, {Name: "Name", Type: "string"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a parameter inserted before the others and one removed
+	err := lock.Sync(msg, []ProtoField{{Name: "Tag", Type: "string"}, {Name: "Name", Type: "string"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers := map[string]int{}
+	for _, f := range msg.Fields {
+		numbers[f.Name] = f.Sequence
+	}
+	if numbers["Name"] != 2 || numbers["Tag"] != 3 || len(numbers) != 2 {
+		t.Fatalf("the fields were renumbered: %v", numbers)
+	}
+	if len(msg.Reserved) != 2 || msg.Reserved[0].String() != "1" || msg.Reserved[1].String() != `"Id"` {
+		t.Fatalf("the removed field is not reserved: %v", msg.Reserved)
+	}
+
+	// the lock survives a round trip and still refuses the removed field
+	lock, err = ParseProtoLock([]byte(lock.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = lock.Sync(msg, []ProtoField{{Name: "Id", Type: "int64"}})
+	if err == nil || !strings.Contains(err.Error(), "number 1 is reserved") {
+		t.Fatalf("the removed field was added again: %v", err)
+	}
+
+	// a hand edited proto reusing a removed number
+	msg.Fields = append(msg.Fields, ProtoField{Name: "Other", Type: "string", Sequence: 1})
+	err = lock.Sync(msg, []ProtoField{{Name: "Other", Type: "string"}})
+	if err == nil || !strings.Contains(err.Error(), "reuses the number 1") {
+		t.Fatalf("the number reuse was accepted: %v", err)
+	}
+
+	// snake case fields of an imported proto match the go names
+	imported := &ProtoMessage{Name: "FindReq", Fields: []ProtoField{{Name: "user_id", Type: "int64", Sequence: 4}}}
+	if err := lock.Sync(imported, []ProtoField{{Name: "UserId", Type: "int64"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(imported.Fields) != 1 || imported.Fields[0].Name != "user_id" || len(imported.Reserved) != 0 {
+		t.Fatalf("the imported field was replaced: %v", imported)
+	}
+
+	// a field changing its type can not keep its number
+	typed := &ProtoMessage{Name: "TagReq"}
+	if err := lock.Sync(typed, []ProtoField{{Name: "Id", Type: "string"}, {Name: "Ids", Type: "sint64", Repeated: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lock.Sync(typed, []ProtoField{{Name: "Id", Type: "string"}, {Name: "Ids", Type: "int64", Repeated: true}}); err != nil {
+		t.Fatalf("the scalars holding the same go type were refused: %v", err)
+	}
+	err = lock.Sync(typed, []ProtoField{{Name: "Id", Type: "int64"}, {Name: "Ids", Type: "sint64", Repeated: true}})
+	if err == nil || !strings.Contains(err.Error(), "field `Id` of `TagReq` changed from `string` to `int64`") {
+		t.Fatalf("the type change was accepted: %v", err)
+	}
+	err = lock.Sync(typed, []ProtoField{{Name: "Id", Type: "string"}, {Name: "Ids", Type: "int64"}})
+	if err == nil || !strings.Contains(err.Error(), "changed from `repeated sint64` to `int64`") {
+		t.Fatalf("the repeated change was accepted: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 )
 
@@ -33,10 +34,214 @@ func TestProtoParse(t *testing.T) {
 	}
 
 	fmt.Printf("PackageName %#v \n", p.PackageName)
-	fmt.Printf("ServiceName %#v \n", p.ServiceName)
-	fmt.Printf("Methods %#v \n", p.Methods)
+	fmt.Printf("Services %#v \n", p.Services)
 	fmt.Printf("Message %#v \n", p.Messages)
 	fmt.Printf("Imports %#v \n", p.Imports)
 	fmt.Printf("Options %#v \n", p.Options)
 
 }
+
+func TestProtoRoundTrip(t *testing.T) {
+	pp := NewProtoParser()
+	file := `syntax = "proto3";
+
+package user.v1;
+
+import "google/api/annotations.proto";
+
+option go_package = "github.com/x/user/v1;userpb";
+
+// User service
+service User {
+    rpc Get (GetReq) returns (GetRes) {
+        option (google.api.http) = { get: "/users/{id}" };
+    }
+    rpc Watch (WatchReq) returns (stream Event) {}
+}
+
+service Admin {
+    rpc Ban (stream BanReq) returns (BanRes) {}
+}
+
+message GetReq {
+    option deprecated = true;
+    reserved 2, 9 to 11;
+    reserved "old";
+    // the user id
+    int64 id = 1 [(gogoproto.jsontag) = "id", (gogoproto.moretags) = 'xorm:"id"'];
+    map<string, Profile> profiles = 3;
+    oneof key {
+        string email = 4;
+        string phone = 5;
+    }
+    enum Kind {
+        KIND_UNKNOWN = 0;
+        KIND_ADMIN = 1;
+    }
+    message Profile {
+        repeated string tags = 1;
+    }
+}
+
+enum Status {
+    STATUS_UNKNOWN = 0;
+    STATUS_ACTIVE = 1 [deprecated = true];
+}
+`
+	p, err := pp.Parse([]byte(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.GoPackage() != "github.com/x/user/v1;userpb" {
+		t.Errorf("unexpected go_package %q", p.GoPackage())
+	}
+	if len(p.Services) != 2 || !p.Services[0].RPCs[1].StreamsReturns || !p.Services[1].RPCs[0].StreamsRequest {
+		t.Errorf("services or streaming flags not parsed: %#v", p.Services)
+	}
+	out := p.String()
+	for _, want := range []string{
+		`syntax = "proto3";`,
+		`option go_package = "github.com/x/user/v1;userpb";`,
+		`option (google.api.http) = { get: "/users/{id}" };`,
+		`rpc Watch (WatchReq) returns (stream Event) {}`,
+		`rpc Ban (stream BanReq) returns (BanRes) {}`,
+		`option deprecated = true;`,
+		`reserved 2, 9 to 11;`,
+		`reserved "old";`,
+		`// the user id`,
+		`int64 id = 1 [(gogoproto.jsontag) = "id", (gogoproto.moretags) = 'xorm:"id"'];`,
+		`map<string, Profile> profiles = 3;`,
+		`oneof key {`,
+		`KIND_ADMIN = 1;`,
+		`repeated string tags = 1;`,
+		`STATUS_ACTIVE = 1 [deprecated = true];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	again, err := pp.Parse([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != out {
+		t.Errorf("rendering is not stable:\n%s\n---\n%s", out, again.String())
+	}
+}
+
+func TestProtoProto2RoundTrip(t *testing.T) {
+	pp := NewProtoParser()
+	file := `syntax = "proto2";
+
+package acc;
+
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.EnumValueOptions {
+    optional string label = 50001;
+}
+
+message Acc {
+    extensions 100 to 199;
+    extensions 1000 to max;
+    optional int64 id = 1;
+    extend Other {
+        optional int32 bar = 126;
+    }
+}
+
+enum Kind {
+    option allow_alias = true;
+    KIND_UNKNOWN = 0 [(label) = "unknown", deprecated = true];
+    KIND_A = 1 [(label) = "a"];
+    KIND_B = 1;
+}
+`
+	p, err := pp.Parse([]byte(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := p.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`extend google.protobuf.EnumValueOptions {`,
+		`extensions 100 to 199;`,
+		`extensions 1000 to max;`,
+		`extend Other {`,
+		`KIND_UNKNOWN = 0 [(label) = "unknown", deprecated = true];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	again, err := pp.Parse([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != out {
+		t.Errorf("rendering is not stable:\n%s\n---\n%s", out, again.String())
+	}
+
+	// the new fields are not numbered in the extensions
+	lock := NewProtoLock()
+	msg := p.Message("Acc")
+	if err := lock.Sync(msg, []ProtoField{{Name: "id", Type: "int64"}, {Name: "Name", Type: "string"}}); err != nil {
+		t.Fatal(err)
+	}
+	if f := msg.Field("Name"); f == nil || f.Sequence != 200 {
+		t.Errorf("the new field is numbered in the extensions: %#v", msg.Fields)
+	}
+
+	// the groups can not be rendered back
+	_, err = pp.Parse([]byte(`syntax = "proto2";
+message Acc {
+    optional group Result = 1 {
+        optional string url = 2;
+    }
+}`))
+	if err == nil || !strings.Contains(err.Error(), "the group `Result` is not supported") {
+		t.Errorf("the group was accepted: %v", err)
+	}
+}
+
+func TestProtoHTTPRules(t *testing.T) {
+	pp := NewProtoParser()
+	file := `syntax = "proto3";
+
+service User {
+    rpc Get (GetReq) returns (GetRes) {
+        option (google.api.http) = {
+            get: "/v1/users/{id}"
+            additional_bindings { get: "/v1/me" }
+        };
+    }
+    rpc Update (UpdateReq) returns (UpdateRes) {
+        option (google.api.http) = { patch: "/v1/users/{user.id}" body: "user" };
+    }
+    // gk:http POST /v1/users body=*
+    rpc Create (CreateReq) returns (CreateRes) {}
+    rpc Ping (PingReq) returns (PingRes) {}
+}
+`
+	p, err := pp.Parse([]byte(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := p.Services[0]
+	for _, v := range []struct {
+		rpc   string
+		rules []ProtoHTTPRule
+	}{
+		{"Get", []ProtoHTTPRule{{Method: "GET", Path: "/v1/users/{id}"}, {Method: "GET", Path: "/v1/me"}}},
+		{"Update", []ProtoHTTPRule{{Method: "PATCH", Path: "/v1/users/{user.id}", Body: "user"}}},
+		{"Create", []ProtoHTTPRule{{Method: "POST", Path: "/v1/users", Body: "*"}}},
+		{"Ping", nil},
+	} {
+		rules := svc.RPC(v.rpc).HTTPRules
+		if fmt.Sprint(rules) != fmt.Sprint(v.rules) {
+			t.Errorf("%s: expected the rules %v, got %v", v.rpc, v.rules, rules)
+		}
+	}
+}
@@ -2,8 +2,6 @@ package parser
 
 import (
 	"strings"
-
-	"github.com/emicklei/proto"
 )
 
 type ParsedSrc interface {
@@ -17,7 +15,6 @@ type NamedTypeValue struct {
 	HasValue bool
 	Comment  string
 	Tag      string
-	Options  []*proto.Option
 }
 
 func NewNameType(name string, tp string) NamedTypeValue {
@@ -27,6 +24,16 @@ func NewNameType(name string, tp string) NamedTypeValue {
 		HasValue: false,
 	}
 }
+
+// NewNameTypeWithComment returns the named type with its doc comment, the aliases of a file
+// are rendered with it.
+func NewNameTypeWithComment(name string, tp string, comment string) NamedTypeValue {
+	return NamedTypeValue{
+		Name:    name,
+		Type:    tp,
+		Comment: prepareComments(comment),
+	}
+}
 func NewNameTypeValue(name string, tp string, vl string) NamedTypeValue {
 	return NamedTypeValue{
 		Name:     name,
@@ -44,3 +51,15 @@ func prepareComments(comment string) string {
 	}
 	return comment
 }
+
+// directives returns the words following `gk:<name>` on each line of a comment, the comment
+// markers are ignored.
+func directives(comment []string, name string) (list [][]string) {
+	for _, v := range comment {
+		words := strings.Fields(strings.TrimPrefix(strings.TrimSpace(v), "//"))
+		if len(words) > 0 && words[0] == "gk:"+name {
+			list = append(list, words[1:])
+		}
+	}
+	return list
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/sirupsen/logrus"
 	template "github.com/liuchamp/gk/templates"
 	"github.com/liuchamp/gk/utils"
+	"go/ast"
 	"go/format"
 )
 
@@ -17,7 +18,8 @@ type Struct struct {
 func NewStruct(name string, vars []NamedTypeValue) Struct {
 	for k, v := range vars {
 		vars[k].Comment = utils.ToLowerSnakeCase(v.Name)
-		if v.Tag == "" {
+		// the unexported fields are not encoded, a tag on them is dead
+		if v.Tag == "" && ast.IsExported(v.Name) {
 			vars[k].Tag = fmt.Sprintf("`json:\"%s\"`", utils.ToLowerSnakeCase(v.Name))
 		}
 	}
@@ -0,0 +1,554 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	template "github.com/liuchamp/gk/templates"
+	"github.com/sirupsen/logrus"
+)
+
+type Thrift struct {
+	Namespaces []ThriftNamespace
+	Includes   []string
+	Typedefs   []ThriftTypedef
+	Consts     []ThriftConst
+	Enums      []ThriftEnum
+	Structs    []ThriftStruct
+	Services   []ThriftService
+}
+
+type ThriftNamespace struct {
+	Scope string
+	Name  string
+}
+
+type ThriftTypedef struct {
+	Comment     []string
+	Type        string
+	Name        string
+	Annotations string
+}
+
+// ThriftConst is a constant definition, Value keeps the literal as written in the source.
+type ThriftConst struct {
+	Comment []string
+	Type    string
+	Name    string
+	Value   string
+}
+
+type ThriftEnum struct {
+	Name        string
+	Comment     []string
+	Values      []ThriftEnumValue
+	Annotations string
+}
+
+type ThriftEnumValue struct {
+	Name        string
+	Comment     []string
+	Value       int
+	HasValue    bool
+	Annotations string
+}
+
+// ThriftStruct is a struct, union or exception, Kind keeps which one.
+type ThriftStruct struct {
+	Kind        string
+	Name        string
+	Comment     []string
+	Fields      []ThriftField
+	Annotations string
+}
+
+type ThriftService struct {
+	Name        string
+	Extends     string
+	Comment     []string
+	Functions   []ThriftFunction
+	Annotations string
+}
+
+type ThriftFunction struct {
+	Name        string
+	Comment     []string
+	Oneway      bool
+	ReturnType  string
+	Params      []ThriftField
+	Throws      []ThriftField
+	Annotations string
+}
+
+type ThriftField struct {
+	ID           int
+	Name         string
+	Comment      []string
+	Requiredness string
+	Type         string
+	Default      string
+	// Annotations keeps the annotations without the parentheses e.g. `go.tag = "json:\"id\""`
+	Annotations string
+}
+
+func NewThrift() *Thrift {
+	return &Thrift{}
+}
+
+// Service returns the service with the given name, adding an empty one when it does not exist yet.
+func (t *Thrift) Service(name string) *ThriftService {
+	for k, v := range t.Services {
+		if v.Name == name {
+			return &t.Services[k]
+		}
+	}
+	t.Services = append(t.Services, ThriftService{Name: name})
+	return &t.Services[len(t.Services)-1]
+}
+
+// Struct returns the struct, union or exception with the given name or nil.
+func (t *Thrift) Struct(name string) *ThriftStruct {
+	for k, v := range t.Structs {
+		if v.Name == name {
+			return &t.Structs[k]
+		}
+	}
+	return nil
+}
+
+func (t *Thrift) String() string {
+	s, err := template.NewEngine().Execute("svc.thrift", t)
+	if err != nil {
+		logrus.Panic(err)
+	}
+	return strings.TrimLeft(s, "\n")
+}
+
+func (s *ThriftService) Function(name string) *ThriftFunction {
+	for k, v := range s.Functions {
+		if v.Name == name {
+			return &s.Functions[k]
+		}
+	}
+	return nil
+}
+
+func (s *ThriftStruct) Field(name string) *ThriftField {
+	for k, v := range s.Fields {
+		if v.Name == name {
+			return &s.Fields[k]
+		}
+	}
+	return nil
+}
+
+func (f ThriftField) String() string {
+	s := fmt.Sprintf("%d: ", f.ID)
+	if f.Requiredness != "" {
+		s += f.Requiredness + " "
+	}
+	s += f.Type + " " + f.Name
+	if f.Default != "" {
+		s += " = " + f.Default
+	}
+	if f.Annotations != "" {
+		s += " (" + f.Annotations + ")"
+	}
+	return s
+}
+
+type ThriftParser struct {
+	tokens  []thriftToken
+	pos     int
+	comment []string
+}
+
+type thriftToken struct {
+	value string
+	line  int
+	// comment is set for the comment tokens, value then keeps the text without the markers
+	comment bool
+}
+
+func NewThriftParser() *ThriftParser {
+	return &ThriftParser{}
+}
+
+func (tp *ThriftParser) Parse(src []byte) (t *Thrift, err error) {
+	tp.tokens, err = tokenizeThrift(string(src))
+	if err != nil {
+		return nil, err
+	}
+	tp.pos = 0
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(thriftSyntaxError); ok {
+				t, err = nil, e
+				return
+			}
+			panic(r)
+		}
+	}()
+	t = NewThrift()
+	for !tp.eof() {
+		comment := tp.comments()
+		if tp.eof() {
+			break
+		}
+		switch tok := tp.next(); tok {
+		case "namespace":
+			t.Namespaces = append(t.Namespaces, ThriftNamespace{Scope: tp.next(), Name: tp.next()})
+		case "include", "cpp_include":
+			include := tp.next()
+			if tok == "include" {
+				t.Includes = append(t.Includes, strings.Trim(include, `"'`))
+			}
+		case "typedef":
+			td := ThriftTypedef{Comment: comment, Type: tp.fieldType()}
+			td.Name = tp.next()
+			td.Annotations = tp.annotations()
+			t.Typedefs = append(t.Typedefs, td)
+		case "const":
+			c := ThriftConst{Comment: comment, Type: tp.fieldType()}
+			c.Name = tp.next()
+			tp.expect("=")
+			c.Value = tp.constValue()
+			t.Consts = append(t.Consts, c)
+		case "enum":
+			t.Enums = append(t.Enums, tp.enum(comment))
+		case "senum":
+			tp.fail("senum is not supported")
+		case "struct", "union", "exception":
+			s := ThriftStruct{Kind: tok, Name: tp.next(), Comment: comment}
+			s.Fields = tp.fields("{", "}")
+			s.Annotations = tp.annotations()
+			t.Structs = append(t.Structs, s)
+		case "service":
+			t.Services = append(t.Services, tp.service(comment))
+		default:
+			tp.pos--
+			tp.fail(fmt.Sprintf("unexpected `%s`", tok))
+		}
+		tp.separator()
+	}
+	return t, nil
+}
+
+func (tp *ThriftParser) enum(comment []string) ThriftEnum {
+	e := ThriftEnum{Name: tp.next(), Comment: comment}
+	tp.expect("{")
+	next := 0
+	for {
+		valueComment := tp.comments()
+		if tp.peek() == "}" {
+			tp.next()
+			break
+		}
+		v := ThriftEnumValue{Name: tp.next(), Comment: valueComment, Value: next}
+		if tp.peek() == "=" {
+			tp.next()
+			n, err := strconv.ParseInt(tp.next(), 0, 64)
+			if err != nil {
+				tp.pos--
+				tp.fail("invalid enum value")
+			}
+			v.Value, v.HasValue = int(n), true
+		}
+		v.Annotations = tp.annotations()
+		tp.separator()
+		next = v.Value + 1
+		e.Values = append(e.Values, v)
+	}
+	e.Annotations = tp.annotations()
+	return e
+}
+
+func (tp *ThriftParser) service(comment []string) ThriftService {
+	s := ThriftService{Name: tp.next(), Comment: comment}
+	if tp.peek() == "extends" {
+		tp.next()
+		s.Extends = tp.next()
+	}
+	tp.expect("{")
+	for {
+		fnComment := tp.comments()
+		if tp.peek() == "}" {
+			tp.next()
+			break
+		}
+		fn := ThriftFunction{Comment: fnComment}
+		if tp.peek() == "oneway" {
+			tp.next()
+			fn.Oneway = true
+		}
+		fn.ReturnType = tp.fieldType()
+		fn.Name = tp.next()
+		fn.Params = tp.fields("(", ")")
+		if tp.peek() == "throws" {
+			tp.next()
+			fn.Throws = tp.fields("(", ")")
+		}
+		fn.Annotations = tp.annotations()
+		tp.separator()
+		s.Functions = append(s.Functions, fn)
+	}
+	s.Annotations = tp.annotations()
+	return s
+}
+
+func (tp *ThriftParser) fields(open, close string) (fields []ThriftField) {
+	tp.expect(open)
+	for {
+		comment := tp.comments()
+		if tp.peek() == close {
+			tp.next()
+			return fields
+		}
+		f := ThriftField{Comment: comment}
+		if id, err := strconv.Atoi(tp.peek()); err == nil {
+			tp.next()
+			tp.expect(":")
+			f.ID = id
+		} else {
+			// fields without id are numbered backwards from -1, as thrift does
+			f.ID = -1 - len(fields)
+		}
+		if p := tp.peek(); p == "required" || p == "optional" {
+			f.Requiredness = tp.next()
+		}
+		f.Type = tp.fieldType()
+		f.Name = tp.next()
+		if tp.peek() == "=" {
+			tp.next()
+			f.Default = tp.constValue()
+		}
+		f.Annotations = tp.annotations()
+		tp.separator()
+		fields = append(fields, f)
+	}
+}
+
+// fieldType reads a type, container types are returned in their canonical form e.g. `map<string, i32>`.
+func (tp *ThriftParser) fieldType() string {
+	tok := tp.next()
+	switch tok {
+	case "list", "set":
+		tp.expect("<")
+		elem := tp.fieldType()
+		tp.expect(">")
+		tp.annotations()
+		return tok + "<" + elem + ">"
+	case "map":
+		tp.expect("<")
+		key := tp.fieldType()
+		tp.expect(",")
+		value := tp.fieldType()
+		tp.expect(">")
+		tp.annotations()
+		return "map<" + key + ", " + value + ">"
+	}
+	if !isThriftIdentifier(tok) {
+		tp.pos--
+		tp.fail(fmt.Sprintf("expected a type, found `%s`", tok))
+	}
+	tp.annotations()
+	return tok
+}
+
+// constValue reads a literal, lists and maps are kept as written with normalized spacing.
+func (tp *ThriftParser) constValue() string {
+	tok := tp.next()
+	switch tok {
+	case "[", "{":
+		close := "]"
+		if tok == "{" {
+			close = "}"
+		}
+		var list []string
+		for tp.peek() != close {
+			v := tp.constValue()
+			if tok == "{" {
+				tp.expect(":")
+				v += ": " + tp.constValue()
+			}
+			list = append(list, v)
+			tp.separator()
+		}
+		tp.next()
+		return tok + strings.Join(list, ", ") + close
+	}
+	return tok
+}
+
+// annotations reads the `( key = "value", ... )` annotations thrift allows after types and definitions.
+func (tp *ThriftParser) annotations() string {
+	if tp.peek() != "(" {
+		return ""
+	}
+	tp.next()
+	var list []string
+	for tp.peek() != ")" {
+		a := tp.next()
+		if tp.peek() == "=" {
+			tp.next()
+			a += " = " + tp.next()
+		}
+		list = append(list, a)
+		tp.separator()
+	}
+	tp.next()
+	return strings.Join(list, ", ")
+}
+
+func (tp *ThriftParser) separator() {
+	if p := tp.peek(); p == "," || p == ";" {
+		tp.next()
+	}
+}
+
+// comments consumes the comments before the next definition and returns their lines.
+func (tp *ThriftParser) comments() (lines []string) {
+	for tp.pos < len(tp.tokens) && tp.tokens[tp.pos].comment {
+		lines = append(lines, strings.Split(tp.tokens[tp.pos].value, "\n")...)
+		tp.pos++
+	}
+	return lines
+}
+
+// lookahead returns the index of the next token that is not a comment.
+func (tp *ThriftParser) lookahead() int {
+	i := tp.pos
+	for i < len(tp.tokens) && tp.tokens[i].comment {
+		i++
+	}
+	return i
+}
+
+func (tp *ThriftParser) eof() bool {
+	return tp.lookahead() >= len(tp.tokens)
+}
+
+func (tp *ThriftParser) peek() string {
+	if tp.eof() {
+		return ""
+	}
+	return tp.tokens[tp.lookahead()].value
+}
+
+func (tp *ThriftParser) next() string {
+	if tp.eof() {
+		tp.fail("unexpected end of file")
+	}
+	tp.pos = tp.lookahead() + 1
+	return tp.tokens[tp.pos-1].value
+}
+
+func (tp *ThriftParser) expect(tok string) {
+	if v := tp.next(); v != tok {
+		tp.pos--
+		tp.fail(fmt.Sprintf("expected `%s`, found `%s`", tok, v))
+	}
+}
+
+type thriftSyntaxError struct {
+	line int
+	msg  string
+}
+
+func (e thriftSyntaxError) Error() string {
+	return fmt.Sprintf("thrift:%d: %s", e.line, e.msg)
+}
+
+func (tp *ThriftParser) fail(msg string) {
+	line := 0
+	if tp.pos < len(tp.tokens) {
+		line = tp.tokens[tp.pos].line
+	} else if len(tp.tokens) > 0 {
+		line = tp.tokens[len(tp.tokens)-1].line
+	}
+	panic(thriftSyntaxError{line: line, msg: msg})
+}
+
+func isThriftIdentifier(s string) bool {
+	if s == "" || !(unicode.IsLetter(rune(s[0])) || s[0] == '_') {
+		return false
+	}
+	for _, c := range s {
+		if !(unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenizeThrift(src string) (tokens []thriftToken, err error) {
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			text := strings.TrimPrefix(strings.TrimPrefix(src[i:i+end], "#"), "//")
+			tokens = append(tokens, thriftToken{value: text, line: line, comment: true})
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, thriftSyntaxError{line: line, msg: "unterminated comment"}
+			}
+			text := src[i+2 : i+2+end]
+			var lines []string
+			for _, l := range strings.Split(text, "\n") {
+				l = strings.TrimSpace(l)
+				l = strings.TrimPrefix(strings.TrimPrefix(l, "*"), " ")
+				lines = append(lines, l)
+			}
+			for len(lines) > 0 && lines[0] == "" {
+				lines = lines[1:]
+			}
+			for len(lines) > 0 && lines[len(lines)-1] == "" {
+				lines = lines[:len(lines)-1]
+			}
+			if len(lines) > 0 {
+				tokens = append(tokens, thriftToken{value: " " + strings.Join(lines, "\n "), line: line, comment: true})
+			}
+			line += strings.Count(text, "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, thriftSyntaxError{line: line, msg: "unterminated string"}
+			}
+			tokens = append(tokens, thriftToken{value: src[i : end+1], line: line})
+			i = end + 1
+		case strings.ContainsRune("{}()<>,;:=[]", rune(c)):
+			tokens = append(tokens, thriftToken{value: string(c), line: line})
+			i++
+		default:
+			end := i
+			for end < len(src) && !strings.ContainsRune(" \t\r\n{}()<>,;:=[]\"'#", rune(src[end])) &&
+				!strings.HasPrefix(src[end:], "//") && !strings.HasPrefix(src[end:], "/*") {
+				end++
+			}
+			tokens = append(tokens, thriftToken{value: src[i:end], line: line})
+			i = end
+		}
+	}
+	return tokens, nil
+}
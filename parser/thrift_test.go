@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThriftRoundTrip(t *testing.T) {
+	tp := NewThriftParser()
+	file := `namespace go user
+include "shared.thrift"
+
+typedef i64 UserId (go.type = "int64")
+const list<string> ROLES = ["admin", "user"]
+
+/**
+ * The user status
+ */
+enum Status {
+    UNKNOWN,
+    ACTIVE = 3 (deprecated = "true");
+    BANNED (label = "banned")
+} (final = "true")
+
+# the user
+struct User {
+    // the user id
+    1: required UserId id,
+    2: optional string name = "anonymous";
+    3: map<string, list<i32>> scores
+    4: binary avatar (go.tag = "json:\"avatar\"")
+} (go.name = "Account")
+
+exception NotFound {
+    1: string message
+}
+
+service UserService extends shared.Base {
+    // Get a user
+    User Get(1: UserId id) throws (1: NotFound nf),
+    oneway void Ping() (priority = "low")
+} (version = "2")
+`
+	th, err := tp.Parse([]byte(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(th.Enums) != 1 || th.Enums[0].Values[2].Value != 4 {
+		t.Errorf("enum values not numbered: %#v", th.Enums)
+	}
+	if s := th.Struct("User"); s == nil || s.Field("id").Requiredness != "required" || s.Field("scores").Type != "map<string, list<i32>>" {
+		t.Errorf("struct not parsed: %#v", s)
+	}
+	fn := th.Services[0].Function("Get")
+	if fn == nil || fn.ReturnType != "User" || len(fn.Throws) != 1 || !th.Services[0].Functions[1].Oneway {
+		t.Errorf("service not parsed: %#v", th.Services)
+	}
+	out := th.String()
+	for _, want := range []string{
+		`namespace go user`,
+		`include "shared.thrift"`,
+		`typedef i64 UserId (go.type = "int64")`,
+		`const list<string> ROLES = ["admin", "user"]`,
+		`// The user status`,
+		`ACTIVE = 3 (deprecated = "true"),`,
+		`BANNED (label = "banned"),`,
+		`} (final = "true")`,
+		`} (go.name = "Account")`,
+		`// the user id`,
+		`1: required UserId id`,
+		`2: optional string name = "anonymous"`,
+		`4: binary avatar (go.tag = "json:\"avatar\"")`,
+		`exception NotFound {`,
+		`service UserService extends shared.Base {`,
+		`User Get(1: UserId id) throws (1: NotFound nf)`,
+		`oneway void Ping() (priority = "low")`,
+		`} (version = "2")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	again, err := tp.Parse([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != out {
+		t.Errorf("rendering is not stable:\n%s\n---\n%s", out, again.String())
+	}
+	if _, err := tp.Parse([]byte("struct A {\n 1: string\n}")); err == nil || !strings.Contains(err.Error(), "thrift:3") {
+		t.Errorf("expected a syntax error with line, got %v", err)
+	}
+}
@@ -1,5 +1,6 @@
 // Package template Code generated by go-bindata. (@generated) DO NOT EDIT.
 // sources:
+// tmpl/errors.tmpl
 // tmpl/file.tmpl
 // tmpl/gk.json.tmpl
 // tmpl/main_api.tmpl
@@ -15,12 +16,14 @@
 // tmpl/partials/interface.tmpl
 // tmpl/partials/interface_func.tmpl
 // tmpl/partials/interface_stub.tmpl
+// tmpl/partials/proto_comment.tmpl
+// tmpl/partials/proto_enum.tmpl
+// tmpl/partials/proto_field.tmpl
+// tmpl/partials/proto_message.tmpl
 // tmpl/partials/struct.tmpl
 // tmpl/partials/struct_function.tmpl
 // tmpl/partials/vars.tmpl
 // tmpl/proto.pb.tmpl
-// tmpl/proto_compile.bat.tmpl
-// tmpl/proto_compile.sh.tmpl
 // tmpl/svc.thrift.tmpl
 // tmpl/thrift_compile.bat.tmpl
 // tmpl/thrift_compile.sh.tmpl
@@ -100,6 +103,26 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _tmplErrorsTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x56\x5b\x6f\xe3\x36\x13\x7d\xb6\x7e\xc5\x44\xc0\x06\x36\x3e\x41\xf9\xba\xd9\x6e\x5b\x17\x7a\x08\xb2\x0e\x9a\x97\x24\xc8\x66\x9f\x8a\xa2\xa0\xa5\x91\x4d\x44\x22\x0d\x92\x8a\x63\x78\xf5\xdf\x8b\xe1\x45\xd6\xc5\x45\x5b\x3f\xc9\x9c\x33\x17\x9e\x39\x33\xd2\x8e\xe5\xaf\x6c\x83\x70\x3c\xa6\x4f\xee\xb1\x6d\xa3\x88\xd7\x3b\xa9\x0c\xcc\xa3\x59\x9c\x4b\x61\xf0\xdd\xc4\xd1\x2c\x46\xa5\xa4\xd2\xf4\x54\xd6\xf6\x40\x61\x59\x61\x6e\x1f\xb5\x51\x5c\x6c\x74\x1c\x2d\xa2\xe8\xea\x0a\x6e\x65\x81\xc0\x35\x98\x2d\xc2\x2b\x17\x05\xc8\x12\x98\x00\x1b\x81\x9e\xe9\x5c\xa3\x7a\xe3\x39\xa6\xf0\xb2\x45\x78\x63\x55\x83\x1a\x98\x42\x6b\xdb\x3c\x3f\xdd\x82\x36\xcc\x34\x1a\x72\x59\xa0\x4e\xe8\x98\x22\x1b\xc5\x84\xa6\xea\x34\x98\x46\x09\x3a\xae\x81\x0b\x23\xe9\x89\x2b\x90\x7b\x31\x70\x4c\x23\x73\xd8\xa1\x2b\xa8\xe1\xc2\x5c\x7f\x8c\xa2\x5c\x0a\x6d\x6f\x47\xa7\xb7\x4c\xe4\x58\x61\x01\xa7\x9f\x05\x67\xf0\x83\x03\x7c\x13\xaf\x82\xa2\x4e\x01\x1f\x1d\xe0\x5e\xbc\xb1\x8a\x17\x37\x6a\xd3\xd4\x28\x4c\x0f\x70\xed\x00\x5f\x90\x15\x15\x17\xb8\x7a\xcf\x11\x0b\x9b\xca\x03\x3e\x39\xc0\x83\x34\x77\xb2\x11\x67\x6a\xf8\xd1\x01\x6e\x2a\x85\xac\x38\xac\xde\xb9\x36\x7a\x00\xf8\xec\x00\x4f\xa8\x6a\xae\x35\x97\xe2\x0b\x0a\xde\x4f\xf1\x93\x03\x3c\xa3\x96\x8d\xca\x71\xf5\xbe\x65\x8d\x36\x84\xf0\x80\x9f\x1d\xe0\x8e\xf1\x0a\x8b\x27\x85\xb9\x14\x05\x37\x5c\x8a\x00\xf8\xc5\xd7\xb0\x96\xca\x0c\x78\xea\x88\xfa\xbf\x43\x3c\x36\xe6\xb1\x7c\x66\x62\x83\x13\x44\xc7\x25\xaf\x77\x15\x12\x4d\x21\x52\x40\x74\x64\x1a\x54\x82\x55\x67\xb2\x5c\x87\x18\xec\x8d\xf1\x8a\xad\x2b\x1c\x23\x3e\x75\x88\xc6\x6c\x51\x18\x9e\x33\x9f\x27\x20\x3e\x7b\x81\xbe\x58\x01\x0a\xc3\x05\x56\x4e\x96\x7a\xac\xcb\xe8\x8d\x29\x12\xc9\x4a\xa9\x69\x7f\x32\xef\x94\x3e\xe0\x7e\x1e\x0b\x69\xa0\x24\x40\xbc\xb0\xf8\xa9\x22\x86\x78\xee\xec\xc0\x3c\xc0\xbb\x4d\xeb\x1e\xba\x35\x43\xbb\xf7\x9a\x76\x76\xe8\xa5\xbc\x1d\x30\x00\x4e\xe9\x46\x44\x4e\xd2\x05\x7b\xbc\xf0\xbc\xad\xc8\x7e\xcb\x0c\xab\xe4\x06\x2a\xab\x46\xe2\xcc\xb9\xd9\xc7\xde\x80\x6a\x14\x05\xec\xb9\xd9\xfa\xd9\xa4\x81\xb4\x83\x0c\x79\xc5\x51\xf4\x46\x98\x42\x93\x15\x98\x28\x2c\xa0\x46\xad\x69\x2f\xad\x59\xfe\xda\x8d\x37\x68\x56\x87\x5c\x29\xdc\x14\x45\x3f\xf7\xb0\x7b\xb0\x45\xe5\x5b\x38\x28\x39\x83\xdf\xff\xb0\x07\x2b\x61\xd4\xe1\x18\xcd\x8e\xa4\x8c\x25\xf4\x37\x41\x42\x2e\x4b\xf0\x9b\x2f\x0d\xc7\x6d\x32\x40\x8f\x87\x7a\xe4\x35\x36\x8f\xbc\x83\xa2\xbc\x57\x4f\x63\x23\xe0\x48\x4a\x27\xfc\xc8\x30\x72\x1b\x49\xe9\xe4\x36\x32\x8c\xdc\x26\x5a\x3a\x39\x4e\x4c\xd3\x8c\x41\x2d\x83\x6c\xe1\xb0\x4d\xa2\xf6\x24\x21\x4b\x3f\x70\x3d\x79\x2b\xe4\xae\x51\x29\xc1\xac\x7d\x34\xa6\x50\x33\x93\x6f\xd1\xeb\xca\x6b\xe1\x5e\x27\x20\x15\x05\x2f\xa5\x02\x06\xb4\xf2\x0b\x8f\x67\xe2\xe0\xde\x2e\x21\x03\x19\x93\x2e\xcc\xfa\x60\x0f\x60\xbf\x45\x01\x0f\xb8\x87\x75\xc3\xab\x42\x4f\x95\x45\xd1\x2d\xb2\x54\xb2\xf6\x82\xf6\x2a\x5d\x46\x57\x57\x7d\x2a\xa6\xab\xd4\x33\x72\xf9\xd5\x30\x83\x96\x80\x63\x9b\x50\xba\x25\x94\x8d\xc8\xe7\xb5\xde\x80\x7b\x85\x2e\x7c\xd9\x47\x50\x68\xa7\xa3\xef\x53\xeb\x4d\x0b\x6d\xeb\x5e\x69\x3d\x1e\xb5\x51\x4d\x6e\xe0\xe8\xd6\x9f\xad\xc1\x4e\x38\xb8\x60\xd1\x8c\x2e\xf6\x37\x99\xa8\x2b\x64\x81\x79\x3f\xe4\xc2\x11\x34\x47\xa5\x1c\x6c\x01\x6b\x29\x2b\x4a\xc1\x4b\x40\x5a\x10\x90\x65\x20\xb8\x3d\x9a\xf9\x5a\xbb\x76\x90\x5f\x02\x98\xae\x94\x5a\x44\xb3\x36\x9a\x51\x5f\x7e\x25\x3b\x5c\x58\x2f\xf7\xdc\xed\x9b\x6f\x62\xaf\xd8\x8e\xbc\x16\x36\x1e\x2f\xc1\x7f\x5c\xa4\x2f\x87\x1d\x3e\x96\xce\x94\x65\x93\xe3\x74\x15\x7c\x42\x11\x46\x35\x18\xcd\x28\x69\x1b\x85\xb3\x92\x55\x1a\xfb\xfa\xb3\x3c\x39\xa3\xeb\xb5\xdd\x3e\x5e\x21\x25\x57\xda\x00\x5a\x6a\x87\xb2\x74\xac\x70\xb1\x39\xe9\x23\x09\xdf\x3b\xe1\x3b\xc1\x2a\x49\x48\x81\x50\x48\xfa\xfe\xb0\xe4\x76\x49\xfb\x8c\xd2\x7f\x38\x3a\x72\xfe\x4c\x00\x61\x99\x81\xb2\xaf\xcf\xc1\xce\xf2\x84\x60\xda\xb5\x64\x78\x61\x4c\x5d\xc3\x87\x57\xee\x95\xd4\xbf\xf8\x9d\x92\xf5\xe4\xf2\xe7\x26\xb0\x5b\xdc\x67\x17\xf3\xd2\x2f\x5b\x37\x9a\x14\xdd\xc5\xe8\x9c\x3c\x2e\x01\x6a\x2c\x4d\xa3\xa5\xa5\x67\x01\x56\xd0\x98\x49\xe0\x26\x01\xac\x34\xf6\xa8\xef\xcf\xaf\x2c\xed\xe4\xf9\x3a\x52\xb8\x37\x5d\xe9\xa4\xbe\x2e\x6c\x28\x7b\xcb\x34\x08\x09\xba\xc9\xfd\x7a\xe8\x77\x20\xdc\x7e\x9e\x87\x39\x49\xe0\xcc\xec\xfd\xfb\x96\x58\x2a\x2f\x32\xc7\xd1\xf7\xef\x7e\x32\x2e\x4e\x93\x31\xa3\x17\x02\x17\x41\x92\xe4\x45\x09\xb3\xcc\x4d\x47\x6a\xc3\xce\xc7\x0d\x5d\x29\x75\xc2\xfb\x6f\xeb\xf4\x37\xa6\xbf\x36\x65\xc9\xdf\x69\x88\x13\x88\x97\x10\xff\x6f\x10\x64\x18\xa5\xac\x8d\x33\x94\xf3\xf8\x83\x5e\xc2\x87\x7d\x9c\x74\xb1\x5e\x14\xaf\xff\x21\xd8\x69\x7e\xbd\xb0\xfe\x13\x27\x99\xe7\xe4\xf2\xf2\x0c\x27\xdd\x3d\xe9\x3b\xa3\xd6\x9b\xc5\x58\xbc\x82\x57\x51\x1b\xfd\x35\x00\x69\xe8\x6e\x49\x9d\x0c\x00\x00"
+
+func tmplErrorsTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_tmplErrorsTmpl,
+		"tmpl/errors.tmpl",
+	)
+}
+
+func tmplErrorsTmpl() (*asset, error) {
+	bytes, err := tmplErrorsTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tmpl/errors.tmpl", size: 3229, mode: os.FileMode(438), modTime: time.Unix(1792368647, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _tmplFileTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x41\x6b\x84\x30\x10\x85\xef\x0b\xfb\x1f\x86\xe0\xa1\x85\x22\x3d\x17\x7a\x28\x7b\xda\x43\x4b\xa1\xa5\xd7\x65\x70\x47\x1b\xaa\xd1\x26\xb3\xc2\x32\xf8\xdf\x4b\x8c\xb6\x51\x54\x7a\x8b\xf3\x5e\xbe\xf7\x18\x23\xa2\x73\x30\x04\xe9\xa1\xae\x2a\x32\x0c\x4a\x75\x9d\xc8\xf8\xd9\x75\xfb\x9d\x08\x99\xb3\x3f\xec\x77\x0d\x66\x5f\x58\x10\x88\xa4\xaf\xe1\x18\xe6\x3d\xa5\x60\xb8\x29\xc9\x40\x7a\xac\x9a\xda\xb2\xbb\x85\xfb\x70\x9d\xa9\x6a\x4a\x64\x02\xa5\x83\xa2\x7e\x3d\x33\xfe\x94\x73\xa8\x8d\x63\x34\x8b\xa4\x6c\xd4\x54\xe4\xdb\xa4\x7d\xa0\x5d\x02\xb5\x68\x3d\xc3\xab\x9b\xd7\x9f\x4a\x8d\xee\xfd\xda\xd0\x02\x03\xbd\x76\xe2\x6b\x43\x2a\x32\x6e\xe2\x8e\x86\xc9\xe6\x98\xd1\x5f\x27\x8b\xa6\x20\x48\xf4\x5d\xd2\xc2\xc3\x63\x6c\xf1\x7f\x24\x5a\xe2\x38\x57\x90\xb4\x93\x90\xd5\xb4\x37\xb6\x97\x8c\xd7\xa3\x06\x7d\x9a\xe3\xfa\xe1\xff\x43\x9e\x89\x3f\xeb\xf3\x3c\x24\x4a\x19\x0c\x41\xd5\x39\xd0\x37\x24\xed\x90\x9d\xbe\x60\x45\xfd\xe3\x9b\xae\x36\xbf\x98\x2c\x54\x10\xa1\xd2\xd1\x5c\x0f\x25\x4f\xde\xc6\xba\x36\x6b\x6d\xa3\xd2\x3f\x01\x00\x00\xff\xff\x19\x28\x3f\x96\xf2\x02\x00\x00"
 
 func tmplFileTmplBytes() ([]byte, error) {
@@ -120,7 +143,7 @@ func tmplFileTmpl() (*asset, error) {
 	return a, nil
 }
 
-var _tmplGkJsonTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x92\xcf\x6a\xc3\x30\x0c\xc6\xef\x85\xbe\x43\xf0\x79\xf4\x01\x7a\xdd\x7d\x97\x3c\x40\xaa\x38\x4a\x6a\x1a\xff\xc1\x56\x57\x86\xf1\xbb\x0f\xa7\x71\xea\x78\x1b\x1d\x8c\x91\xa3\xa5\x4f\xd2\xf7\xb3\xe4\xf7\xbb\xaa\x62\x0e\xed\xbb\xe0\xc8\x8e\xd3\xb3\xaa\x98\x01\x3a\xb3\x23\xf3\xfe\xe4\x3d\xe9\x5a\xc1\x05\x5f\xc1\x61\x75\xa8\xef\xca\x37\x90\x18\xc2\x29\x04\xef\x7b\x31\x62\x8d\x06\x2c\x90\xb6\x21\x98\xcb\xf0\x25\xf6\xbc\x4d\x32\xf0\x32\xcf\x8f\x0d\x1a\x05\x12\xd9\x31\x99\x3b\x0c\x7a\x49\x0b\x45\x68\x7b\xe0\x8b\xa6\x2e\xea\x1d\xd9\x2b\xa7\x94\x6d\xc1\x09\x9e\x24\x51\x11\x26\x1d\x93\xa2\xeb\x46\xbc\x81\xcd\xc8\xe7\x92\x47\x2a\xce\xcd\x6a\x50\x75\x46\x0b\x45\x6e\xbb\xcf\x4a\x16\x7e\xf8\x2d\x2a\x1c\x9f\x89\x0c\x59\x50\xce\x68\x4b\xdb\xb9\x7e\x58\xf8\xce\x76\x34\x99\x6f\x98\xd0\x51\x53\x0a\x9a\x18\x2d\xe8\x06\x6b\x78\x49\xb7\x35\xdb\x8a\x2c\x1a\x5c\xc8\x18\x1f\x05\xaa\x35\x59\x14\xdc\xc3\x05\x9a\x69\xff\xce\xf3\xbc\xc4\xb4\xf9\x4c\x2e\xbb\x74\x22\xbf\x9f\x3a\x6f\x6d\x85\x2d\x41\xa8\x72\x55\x40\x78\x83\x8f\x7f\xeb\xdf\x61\x0f\xd7\x91\x9a\xec\x1c\xa6\xb3\x61\xfb\x5d\xf8\x0c\x00\x00\xff\xff\x40\x23\xff\x04\xe8\x04\x00\x00"
+var _tmplGkJsonTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcc\x56\xdf\x8f\x1b\x35\x10\x7e\xbf\xbf\x22\x9a\xe7\x6d\x94\x03\x95\x87\x7d\x3b\x8e\x4a\x3d\x2a\x50\x45\x5a\x01\x8a\x4e\xdb\x89\x77\xb2\x71\xe3\x5d\x1b\x7b\x36\xc7\x11\xed\xff\x8e\x6c\xaf\xf7\x47\x2e\x40\x01\xc1\xf5\x6d\x33\xf3\xcd\x37\xe3\x6f\xc6\x13\x9f\xae\x16\x0b\x70\x64\x8f\x52\x10\xe4\xfe\xd7\x62\x01\x06\x79\x0f\x39\x9c\x4e\x1f\x4e\x27\xd6\xeb\x06\x0f\x74\x8b\x8e\x16\xcb\x75\x04\x7e\x8f\x35\x75\xdd\x87\xae\x3b\x9d\x76\x52\xd1\x9a\x0c\x5a\x64\x6d\xbb\xce\x1c\xaa\x27\xb6\xbf\xa6\x49\xf9\xb3\x98\xde\xc7\x17\x0d\xd6\x04\x79\x2a\x6d\x59\xe9\xe4\x95\x0d\x93\xdd\xa1\x18\x20\xeb\x79\xb4\x63\xdb\x0a\x4e\xce\x2d\x3a\x29\xce\x10\x64\xad\xb6\xae\x98\xa6\x89\x26\x9f\xe5\x6a\xb1\xe8\x3c\x0e\x6a\x59\x96\x8a\x1e\xd0\x8e\xba\xf4\xe0\xd1\x33\x0b\xa0\xa6\x34\x5a\x36\xec\x9e\x4d\xc7\x54\xc1\x65\x21\x79\x22\xe2\xf4\x70\x1b\xc0\x96\xf7\x90\x41\x4d\x6c\xa5\x70\x90\x81\xd2\x55\x25\x9b\x0a\x32\x60\x8b\x22\x7e\x1d\x51\xc9\x12\x99\xe0\xbe\xe7\xb0\xc8\x54\x28\x59\x4b\x86\xfc\x7a\xb5\xea\xad\x2c\x6b\xd2\x2d\x43\x0e\xd7\x2b\x97\xd2\x09\x69\x45\x2b\x79\x6b\x09\x0f\x64\x93\x3e\xbe\x42\x94\xaa\xb5\xe4\x20\x7f\x99\x25\x5b\x8d\xbf\x16\x96\x7e\x69\xc9\x79\x29\xaf\x07\x7b\x68\xfc\x11\x15\xe4\x30\x30\xcf\x12\x7e\xb9\x72\x10\xac\x5d\x3a\x25\xf1\x5e\x97\xbe\x1f\xdd\xd0\xa5\x74\xa0\x1c\x7e\x93\xe6\x20\x9b\x40\x14\x15\x48\x6d\x73\x62\x4f\x41\xb3\x8f\x0f\x83\x94\xfe\x73\x2c\x1b\x55\xa5\xad\xe4\x7d\x0d\x39\xbc\x5e\x7f\xf1\xf2\xab\xb1\x9c\x03\x3d\x42\x0e\xd4\x1c\xf3\x6f\x7f\x7c\x57\xbc\x79\xf5\xf3\xbc\x24\x34\xb2\x08\x90\x81\x6b\x4f\x58\x7a\x4d\xe0\xa7\x17\x37\x6f\xef\x5e\xbc\xa1\xc7\x19\x99\xeb\xd9\x6e\xde\xde\x79\xb6\xf5\x9c\x2e\x8c\xf7\x84\xac\x75\x64\x53\xc4\xd7\x37\xeb\xbb\xdb\xe2\xe6\xfd\xbb\xd7\xc5\xfb\xf5\xab\x1f\x52\xe4\xa0\xc4\x9e\xd9\xb0\xc5\xc6\x19\x6d\xf9\xd9\x66\x76\xac\xe0\xc2\xd0\xfa\x12\x27\x53\xcb\xe4\xb8\x38\xf7\x17\xde\x3a\x01\x09\x25\xa9\x79\x0a\x8b\xe6\x09\xce\xea\x96\xc9\xf6\xde\x64\xa4\xe6\x48\x4a\x1b\x1f\xc4\x54\x1b\x85\x4c\xe7\xae\x62\x70\xe4\x9b\x5e\xf6\x53\xda\x0c\x42\x97\x14\xaf\x4a\x4b\x49\xc4\xe5\xad\x2e\xe3\x51\xa1\xcb\xce\x03\x6a\x57\x3d\xc1\x7f\xe7\xaa\x3f\x82\x97\xc8\xf8\x04\xff\x0d\x32\xf6\x01\x01\x9f\xee\xa7\x2f\x46\x38\x7f\xbf\x3f\x3a\xdd\x40\x06\xc6\x6a\xd6\xdb\x76\x07\xf7\xc3\x0c\x54\xd6\x88\xb3\x19\x78\xe6\x09\x98\xf5\xdf\x97\x97\x5a\x76\xa9\xb1\xde\x3f\x6f\x2c\x54\xc8\xf4\x80\x8f\x73\x58\xb4\x0d\x98\xb8\xed\xfd\x52\x64\xe4\x36\xee\x12\x7f\x0f\x95\x3f\x38\xdb\x96\x82\xc1\xd2\x4e\x91\x60\xa9\x9b\x89\x31\x2c\x21\x41\x86\x03\xc1\x06\x2c\x09\x7d\x24\xfb\x08\x19\xf4\x3b\xab\x90\xe5\x64\x97\x8e\x52\xf3\xde\xca\x1d\x7f\xc6\x62\xc7\x02\xff\x4c\xee\x88\x18\x05\x3f\x3b\xdb\xbf\x3e\xd2\x27\x94\x1f\x13\x0d\x89\xcd\xf6\x7f\x48\x6a\xb6\x51\x91\x70\x7f\x04\xe4\xe9\xa3\x1f\x08\xa1\xda\xd2\xff\x83\x6d\xee\x23\x0a\xc5\x01\x2b\xfa\xa4\x62\x12\xb3\xac\x7d\x4b\x46\x0e\x6d\xfc\xd8\xa5\x87\x04\x54\xba\x38\x63\x5d\xde\x85\x88\x40\x32\xaa\xb1\x93\xa4\xca\x62\x08\xde\x8c\xc3\xa7\x0d\x35\x68\x64\x22\xfc\xe7\x6a\x79\x92\xec\xea\x7c\x4f\xf7\xf4\xcb\xb8\x68\x82\xfb\x48\xd6\x85\xab\x03\xd7\xcb\xd5\x72\xd5\x5b\xfd\x73\x8e\xec\x70\xd0\x05\xb4\x26\xbc\x29\xf2\x1d\x2a\x47\x43\xb9\xa2\x2e\xff\x76\xa9\x17\xca\xaa\x51\x36\xb3\x39\xed\x17\xc1\x7f\xc2\x5d\xd2\x0e\x5b\xc5\xc5\xe4\x86\xc7\xbf\x96\xab\xee\xf7\x01\x00\x1f\xb1\x1b\xcb\x65\x0b\x00\x00"
 
 func tmplGkJsonTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -135,12 +158,12 @@ func tmplGkJsonTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/gk.json.tmpl", size: 1256, mode: os.FileMode(438), modTime: time.Unix(1596615722, 0)}
+	info := bindataFileInfo{name: "tmpl/gk.json.tmpl", size: 2917, mode: os.FileMode(438), modTime: time.Unix(1792369312, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplMain_apiTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x58\xcd\x8e\xdb\xc8\x11\x3e\x93\x4f\xd1\xdb\x40\x00\x52\xa0\x48\x8d\xb3\x6b\x6c\x26\x3b\x07\x67\x2c\xdb\x13\x68\x34\xc2\x48\x86\x81\xbd\x18\x3d\x64\x89\x6a\x98\xec\x66\x9a\x4d\x69\xc6\xf6\x00\xb9\x04\xf9\x01\x7c\x4c\x4e\x1b\x04\x7b\x0f\x92\x53\x2e\x01\x92\xa7\xc9\xae\x77\xdf\x22\xa8\x6a\x52\x7f\x33\x96\xbd\x06\x46\x6c\x56\x57\x57\x57\x57\x7d\xfd\x55\xd1\x95\x48\x5f\x89\x1c\x58\x29\xa4\xf2\x7d\x59\x56\xda\x58\x16\xf8\x1e\x9f\x17\x22\xe7\xf8\x2c\x2d\x3e\x14\xd8\x64\x61\x6d\xb5\x3d\x4e\xaa\xca\xe8\x39\x4a\x74\xed\x7e\x93\x5a\xe6\x4a\x14\xf8\x52\xdf\xd4\xa9\x28\x68\x68\x65\x09\xdc\xf7\x3d\x9e\x4b\xbb\x68\xae\xe2\x54\x97\x49\xae\x8d\x2c\x0a\x91\x94\xcd\x35\xf7\xbd\xda\x66\xaf\x65\xf5\x4a\x2a\xb6\xad\xa3\x2b\x50\x4e\x9c\xb8\x47\x3f\xd7\xdc\xf7\xdc\x18\x1d\xf8\xa8\x76\x62\x00\x0f\x04\x66\xed\xfb\xd6\x82\xca\xe8\x12\xec\x02\x9a\x3a\x49\x0b\x09\xca\xbe\xcc\x75\x21\x54\xbe\x3d\x81\x43\xb7\x74\xdf\xfd\xfe\x2b\x69\x13\xfc\x2b\x74\xce\x0f\x4d\x26\x05\x2c\xa1\xe0\x7e\xe8\xfb\x4b\x61\x30\xb6\x49\xf2\xfe\x8f\x7f\xf8\xfe\x9b\xbf\xfb\xde\x53\x69\x9f\x89\x7a\xc1\x18\x3b\x61\x7c\xac\x59\xf7\x3e\x31\x7a\x29\x33\xc8\xb8\xef\xfd\xaa\x91\x45\x36\x93\x25\xb4\x2a\x9b\xf7\x8d\x52\xd8\x59\xce\xb7\xec\x61\x02\xe3\xa9\x35\x52\xe5\x01\x6f\x0d\xf3\xa8\xdb\x22\x62\x28\x63\x0b\xd4\x4e\x75\x06\x4c\xcf\x99\x5d\xc8\x9a\x89\xaa\x62\x41\x0e\x0a\x8c\xb0\xc0\xb4\x62\x57\xb8\x61\xc8\x43\xdf\xbb\xda\x72\x65\xc7\xfa\xda\x27\x1e\x6d\xfc\x8b\x98\x93\x33\x4c\xff\x27\x98\x0f\x37\xe1\xf9\xfe\x9b\x77\xdf\xfd\xe9\xdb\x1f\x7f\xf7\xee\xfd\x7f\xfe\xe1\x7b\xcf\xac\xad\x1e\x65\x99\xb9\x73\x28\x4c\x4c\x5f\x64\x99\xe1\x11\xe3\xc7\x47\x83\xc1\x60\x80\x23\xd4\x85\xba\x66\x73\x6d\xd8\xb3\xd9\x6c\xc2\x82\x5f\x4f\x2f\xc6\x21\xab\xc1\x2c\xc1\xe0\x41\x1e\xc3\x55\x93\x93\xc9\x3d\x8b\x19\x4e\xec\x9a\x3c\xc2\x11\x2d\x60\x42\x65\xac\x04\x6b\x64\x5a\xb3\x42\xd6\x16\x14\x13\x6e\x2f\x34\x3a\xd2\xf9\x08\x33\x7d\xc7\xcd\x42\xe7\x7d\x87\x81\x88\xb9\x1d\x70\x30\x05\xcb\x0a\x9d\x33\x9a\x61\x01\xc9\xdf\x4a\x35\xd7\x6f\x57\xc2\xa8\xb7\x60\x8c\x36\x14\xf5\xe9\x32\x1d\x8b\x12\xee\xe6\xb4\x5e\xa6\x7d\x25\x28\xe8\x3c\x17\x16\x56\xe2\x06\x87\x76\x01\x0c\xc5\xeb\x90\xe3\xb9\x65\x0a\x4c\xaa\xf5\x30\x93\x75\xaa\x97\x60\x6e\x70\x83\x24\xf9\xf1\xf7\xef\x7e\xf8\xe7\x9f\x7d\xef\x12\xac\xb9\x39\x17\xd7\x8c\x6d\x76\x3b\x53\x36\xe0\x06\x27\xfa\xa5\xb8\xe6\x11\x3b\x8a\x18\xaf\xc0\xf4\x0d\xfc\xa6\x81\xda\x32\x9c\x93\x50\x33\xab\x59\x26\xe7\x73\x30\xa0\x2c\x93\xaa\xb6\x42\xa5\x40\x91\x21\xb3\x08\x09\xdd\xd8\xce\xec\xe3\xc6\x08\x2b\xb5\xea\x6c\x5b\x37\xcd\x23\xf6\xf3\x41\x0f\x5f\xe2\x29\xa4\x5a\x65\x7b\x9b\xb5\x6a\x11\x93\x2a\x2d\x9a\x4c\xaa\xbc\xdb\xdf\x9d\xc4\x21\xe7\x7f\xff\xfd\xeb\x0f\xff\xfa\x0b\x85\xee\x31\x94\xfa\xbe\xc0\x65\x50\x6a\x97\x0f\xf7\xec\x22\x43\x91\x43\xe4\xe0\xc4\x16\x64\x92\xe4\xbb\xbf\xfd\xfb\xfd\xb7\xbf\xed\x4c\x7f\x4d\x14\x73\x1f\x86\x5a\xf2\xe9\x40\x84\x20\x3d\x4e\x5a\x4a\x3a\xfe\xc5\xe7\x47\x47\x89\xa8\x64\xb2\x7c\x90\xd4\x95\x50\x35\xaa\x0c\x95\xb8\x2a\x80\x39\x93\xcc\x1a\x91\xe2\xb9\x96\x52\x30\xd1\x09\x09\xc7\xa7\xba\x28\x20\xb5\xda\x30\x50\x59\xa5\xa5\xb2\x11\xfb\x98\x79\xbc\x57\xfe\xbc\x51\x29\x31\x7c\x10\xb2\x37\xbe\x47\xee\x4e\x84\xa9\x21\x08\x7d\x3c\x1a\x1b\xe9\x3c\xc7\x3d\x33\x8d\x5a\xb1\xef\x15\x3a\xcf\xc1\xb0\xe3\x13\x77\x3f\x47\xf4\x1a\x84\xdd\x44\x3c\xd2\x79\xc0\xcb\x9a\x90\xbc\x80\xa2\xa0\x18\xe6\xf7\x90\xcc\xd5\x7d\xd4\x10\xfa\x5e\x06\x73\x30\xec\x1e\x6b\xb9\xd6\xd9\xd5\x0d\xfc\x44\x7b\x74\x8a\x33\x65\xc1\x98\xa6\x42\x5a\x53\x59\x01\x26\xf6\x3d\x30\x26\xc5\x63\x94\xe2\x15\x04\xe9\x42\x28\xe6\x6e\x96\xef\xe5\x9a\x61\x5c\x5c\x48\xbc\x3d\x25\x5d\xc7\x53\x2a\x65\xa1\xef\x79\xae\xa8\xc5\x63\x6d\xe5\xfc\x26\x48\x23\xd6\xd6\xb6\x78\x7a\xf6\xf4\x6c\x3c\xdb\x79\x9f\x0d\x2f\xcf\x71\x0d\xed\xfb\x55\x9f\xcd\x4b\x1b\x0f\x71\xc7\x79\xc0\x7f\x86\xd9\xfe\xaa\x9f\x86\xbe\x77\x4b\x91\x47\xc2\x73\x99\x9b\x19\x91\x82\x61\xbd\x75\x21\x8c\x9d\xc4\xf7\xd0\xb9\x96\x18\xc9\x2a\xdb\xfc\xa3\x93\xa0\x78\xa1\x6b\x3b\xc1\xc2\xdd\x5e\x5c\x5e\xe8\x54\x14\x28\x3d\xfe\x72\xc0\x51\xa3\x85\xf7\x9a\x48\x7a\x2d\xab\xe0\x5c\x53\xc3\x58\xeb\xaa\x75\xe1\x84\x05\xbd\x6d\x70\x9f\x30\x8e\xf8\xf7\xbc\xae\x94\x76\x9b\x6c\xca\x70\x3c\x86\xd5\x65\x3b\xbb\xbd\x18\x97\xe1\x9f\xcb\x75\xb7\x3e\x3e\x2d\x34\x21\xcf\xf3\x5e\x0f\x27\x11\x7b\x89\x91\xdf\x9c\x7b\x0c\xab\x61\x8b\xee\x60\xcb\xeb\x88\x75\x87\xa4\x8c\x88\xb2\x2a\x1c\x3e\x77\x56\x9e\xeb\xac\x29\xf4\xd4\xcd\x06\x47\x03\xda\x64\x2b\xc0\x11\xc6\x8c\xed\x2d\x72\x53\x41\xe7\x5f\xb4\x35\xfb\x42\xda\xc5\x08\x63\xb9\x76\xe9\xf5\x70\x12\xee\x6b\x6c\xa2\x17\xec\xc4\xf2\x8e\x62\xe7\x58\xeb\x7e\x88\xee\xc9\x39\xf9\xf4\xd9\x09\x53\xb2\x20\x28\xee\xdc\x31\x30\xc8\x22\x60\x28\x98\x9e\xae\xe3\xe1\xb5\xb4\xc1\x11\xbe\xdd\xba\xd5\x9f\xed\xe6\xef\x8e\x05\x4b\x72\xbc\x4f\x2e\x33\x54\x23\x6e\x2a\xba\x61\x63\x61\xe5\x92\x46\xcf\x2f\x47\x3c\x62\x7b\xc9\xbb\xf5\x7d\xfa\x4b\x12\x36\x25\x26\x64\x46\x37\x16\xea\xd8\xf7\x28\xf6\x65\x73\x4d\xb9\x47\x21\xb1\xc3\x1b\xdf\xf7\xbc\x24\x79\x43\xbf\x04\xdb\xa6\x06\x83\xa5\x0e\x9f\x1d\x6b\xc5\x53\xb0\x4e\x43\xce\x3b\x85\x2e\x33\xf8\x6a\x8d\x50\x35\x26\x63\x1b\x0c\xa7\xd4\xa2\x4d\xc1\x06\x88\xdd\xe7\x35\x26\xaa\xd7\x15\xac\x6e\x38\xeb\xca\x83\x0b\x40\xc4\x76\xae\x57\xf8\xcb\xfd\x50\x27\x89\xe7\x55\x42\xc9\x34\x00\x63\xdc\x3d\x0d\x28\x2b\x49\x42\xe1\x4d\x12\xcf\xc4\x13\x61\x17\x13\x03\x73\x79\x1d\xf0\x44\x54\x55\x82\x3e\xf2\x30\x7e\xe6\x48\x26\xa0\x2b\x80\xec\x5f\x6d\x69\x49\xa7\x15\x31\x91\xa6\x50\xd7\xa7\x5a\x59\xa3\x8b\xe0\xce\xf1\x90\xd8\x3b\x43\xeb\x50\x68\xdb\xc1\x75\x17\xbc\xee\x54\x61\xd8\xba\x78\xbb\xce\x0e\x95\x87\x8d\xdd\x7d\x72\xdb\x83\x83\xd3\xa2\xc6\x06\x17\xe2\xb3\xad\x56\xbd\xae\xd7\xda\xa6\x30\x3a\xdf\x88\xfa\x9d\x47\x2a\x23\x20\x04\x6b\xc5\x88\x99\x35\x9d\x25\x09\x73\x8d\x92\x6b\x8e\x88\x7f\xef\x73\x04\xa1\x53\xe8\x9c\xae\x44\xd0\x65\x6a\xe3\xd8\xa6\x4d\x42\x2f\x4a\xd4\xee\x58\x86\x36\x3f\x6f\xae\x89\x3d\xca\x36\x03\x01\x4f\x48\xdd\x7d\x8e\x24\xdc\x15\xc5\x2e\x3d\x4f\x70\x77\x9a\x89\xcf\x54\x06\xd7\xe1\x81\xa5\x69\x99\x15\x52\xc1\x87\x2d\x9c\x3a\x85\x43\x36\xf0\x47\x16\x07\x6c\x4c\x9c\xc2\x21\x1b\xf5\x4d\x79\xa5\x8b\x0f\x9b\x98\xd2\xfc\x21\x0b\x74\xe9\x3f\x6c\x80\x00\xb5\xbf\xbe\x6d\x6d\x79\xc4\xba\xaf\x9e\x35\xc4\x43\x4c\xef\x0e\x8c\x3a\xc0\xac\x5b\xe9\x8f\x22\x66\xad\x19\xb1\x72\x0d\x99\x97\xec\x64\xa7\x09\xb0\x60\x4a\xa9\x84\x85\x8c\x4a\x25\x1a\x0c\xfd\xdb\xb6\x83\xd9\xbd\x4a\x8b\x9d\xc3\x85\x3b\x6f\x08\x36\x03\xb6\x31\xea\x6e\x04\x08\x8e\x2b\x27\xbf\x84\xba\xd2\xaa\x86\x17\x46\x12\xf3\x1b\xd6\x6b\xe5\xd4\x6e\x3a\xd0\x8a\xa2\xd0\xab\x0b\x23\x73\xa9\x10\x8b\x26\x7e\x06\x22\x03\x13\x3f\x05\x1b\x70\x27\xe7\x2d\x93\x6f\xab\x52\xe5\x74\x74\xbc\x23\x66\xbc\xc7\x5b\xf6\x5e\xb5\xa6\x82\x10\x29\x31\xe0\x8f\xe8\x80\xfd\xf6\x84\xfd\x47\xb8\xac\xdf\xee\x10\x6d\x1b\xa7\x74\x7c\xd2\xea\x73\xb0\x0b\x9d\x51\x9b\xf9\x74\x38\x8b\x26\xcf\x67\xd1\xe4\x62\x3a\x8b\x1e\x0f\x47\xc3\xd9\x30\xba\x98\xcc\xce\x2e\xc6\x53\xf2\xff\x93\xec\x39\x15\xb2\xf7\xa8\xb1\x0b\x6d\xe4\x6b\xea\xe3\x39\xb9\xb4\x88\x29\xd9\xc8\x2a\xc1\xaa\xa5\x86\x4d\xfe\x76\x5a\x49\x62\x00\xf7\x82\x41\xc2\x5a\xd1\xb2\xc3\x66\x82\xda\x4d\xf7\x51\x85\x94\x81\x83\x98\xdc\x38\x53\x73\x8d\x1c\x50\xaf\xa4\x4d\x17\xac\xb7\xfe\xf6\x7a\xe3\x7b\xa9\xa8\xa1\x23\x90\x63\x87\x5a\x37\xb7\x63\x81\xd0\x88\x26\x9c\x3a\x7e\x78\x1d\xd0\xee\xf6\x73\xca\xf8\x81\x76\x40\xf9\x85\x30\x6a\xa3\x4c\x4d\xda\x01\xed\xb6\xe8\x20\x95\x77\xf4\xe8\xd8\x71\x0c\xab\x91\xce\xe7\xa5\x6d\x23\x86\x2d\xa9\xcd\x74\x63\xc3\x6d\x45\x32\x34\x86\xd5\x13\x59\x60\x0d\xee\xd8\xb4\xdb\x2b\xdc\x33\xba\x4b\xb9\xf5\x32\xc5\x3b\xdc\xf6\x82\x4e\x17\x4b\xe8\xd4\x8a\xb2\xea\x58\x1a\x05\x35\x0a\x9e\x68\x53\x0a\x1b\xb4\x74\x4e\xdf\x68\xf4\x9f\x01\x08\xf1\xf6\xb2\x91\x70\xac\x57\x41\x18\x9f\xa9\x80\xde\x9e\xc8\x6b\xc8\xbe\xd6\x0a\x02\x7e\x3a\x9d\xf1\x88\x3d\x1c\xf4\x1e\x0e\x7a\x5f\x22\xfb\xdc\x46\x8c\x3f\x18\x0c\x1e\xf6\x07\x47\xfd\xc1\x83\xd9\xd1\x17\xc7\x83\xcf\x8f\x07\x5f\xc4\x03\xfa\xc7\x0f\x3b\x6f\x11\x84\xdb\x0e\x1f\x56\xc7\x06\x9d\x0a\x32\x4e\x3d\x86\xb9\x68\x0a\x7b\x4a\xb2\x70\xcd\x15\x4e\xd9\xbf\xf5\xff\x1f\x00\x00\xff\xff\x13\x28\x74\x06\x16\x13\x00\x00"
+var _tmplMain_apiTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xac\x39\x4b\x6f\xe3\xc8\xd1\x67\xf2\x57\xf4\x12\xf8\x00\xd2\xa0\x48\x79\x5f\xdf\xc6\x59\x1f\x66\x3c\x9e\x19\x27\x1e\x59\xb0\xb4\x58\x60\x2f\x8b\x36\x59\xa2\x1a\x26\xbb\xb9\xcd\x96\x25\x8f\x56\x40\x2e\x41\x1e\xc0\x1e\x93\xd3\x06\xc1\xde\x83\xe4\x94\x4b\x80\xe4\xd7\x64\x5f\xff\x22\xa8\x6a\x52\x24\x65\x5b\xe3\xd9\xc4\x80\xa5\xee\xae\xea\x7a\x75\x55\x75\x55\xab\xe4\xc9\x35\xcf\x80\x15\x5c\x48\xd7\x15\x45\xa9\xb4\x61\xbe\xbb\x5e\x0f\x98\x98\x31\xf8\x82\x45\x53\xcd\x13\x21\x33\xe6\xa9\x12\xa4\x81\x1c\x0a\x30\xfa\xd6\xdb\x6c\x5c\xc7\x4b\x94\x34\xb0\x32\x1e\xe1\x83\x4c\x69\x71\x96\xf3\xcc\xc3\xef\xc2\xe0\x97\x04\x13\xcf\x8d\x29\xbb\xe3\xb8\x2c\xb5\x9a\xe1\x8a\xaa\xec\x67\x5c\x89\x4c\xf2\x1c\x27\xd5\x6d\x95\xf0\x9c\x86\x46\x14\xe0\xb9\xae\xe3\x65\xc2\xcc\x17\x57\x51\xa2\x8a\x38\x53\x5a\xe4\x39\x8f\x8b\xc5\xca\xbb\x57\xce\xd7\xa2\xbc\x16\x92\x04\xac\x4c\x6a\x67\xac\x4b\x01\x15\xb1\xcb\xb1\xfd\x1a\x64\xca\x73\x1d\x3b\x46\xf1\xde\x88\x1d\x6b\x40\x43\x81\xae\x35\x6b\xd5\x7f\xa4\xe1\x32\x15\xf5\x56\x23\xa1\x62\x65\x20\xf7\xf6\xc0\x62\x6e\x8c\x16\x57\x0b\x03\x7b\xb1\x60\x65\x45\xab\x62\x65\xf2\x92\x3e\x10\x41\x24\x9d\x61\xa6\xcb\xe4\x6d\x89\x18\xcd\x13\x68\x47\x6f\x41\xa2\x32\xa9\x5a\x98\xfa\xcb\x0a\xf0\x53\x76\x12\xdb\xbd\x1b\x4b\xad\x4a\x9e\x71\x23\x94\xf4\x5c\xa7\x4a\xaf\x2d\x33\xf6\xf0\x8e\x2a\xbd\x8e\x1f\x21\x11\xa2\x69\xa8\xd4\x42\x93\x04\x55\x7a\x4d\xc2\xbc\x81\x70\x2d\x30\x39\x47\x5e\x81\x3d\xf8\xd6\xb1\x4a\xad\x0a\x30\x73\x58\x54\x71\x92\x0b\x90\xe6\xf3\x4c\xe5\x5c\x66\x5d\x00\x0e\x77\x5d\x6c\x37\x1c\x06\xd7\xc2\xc4\xf8\x9f\xab\xcc\xdb\x07\x8c\x73\xb8\x41\x17\x0b\x5c\xf7\x86\x6b\xe6\xbb\x4e\x1c\x7f\xff\xbb\xdf\x7e\xf7\xf5\x5f\x5c\xe7\x85\x30\x2f\x79\x35\x67\x8c\x1d\x33\x6f\xa4\x58\x33\x1f\x6b\x75\x23\x52\x48\x3d\xd7\x79\xba\x10\x79\x3a\x15\x05\xd4\x28\xed\xbc\x45\x0a\x1a\xca\x59\x87\x1e\x26\x84\x68\x62\xb4\x90\x99\xef\xd5\x84\xbd\xb0\x61\x11\x32\x5c\x63\x73\xc4\x4e\x54\x0a\x4c\xcd\x98\x99\x8b\x8a\xf1\xb2\x64\x7e\x06\x12\x34\x37\xc0\x94\x64\x57\xc8\x30\xf0\x02\xd7\xb9\xea\x88\xd2\xa3\xbe\x95\xc9\x0b\x5b\xf9\x42\x66\xd7\x19\xa6\x93\x47\x90\x0f\x5a\xf3\x7c\xf7\xf5\x57\xdf\xfe\xfe\x9b\x1f\x7f\xfd\xd5\xf7\xff\xfc\xab\xeb\xbc\x34\xa6\x7c\x92\xa6\xfa\x8e\x52\x78\x44\x03\x9e\xa6\xda\x0b\x99\x77\x74\x38\x1c\x0e\x87\x38\x42\x5c\xa8\x2a\x36\x53\x9a\xbd\x9c\x4e\xc7\xcc\xff\xc5\xe4\x62\x14\xb0\x0a\xf4\x0d\x68\x54\xe4\x19\x5c\x2d\x32\x22\xb9\x43\x31\x45\x40\x9f\xe4\x21\x8e\x68\x03\xe3\x32\x65\xd6\x6d\x2b\x96\x8b\xca\x80\x64\xdc\xf2\x42\xa2\xe7\x2a\x3b\xc7\x93\xbe\x23\x66\xae\xb2\x81\xf5\x81\x90\x59\x0e\x38\x98\x80\x61\xb9\xca\x18\x41\x98\x4f\xeb\x5f\x0a\x39\x53\x5f\x2e\xb9\x96\x5f\x82\xd6\x4a\x93\xd5\x27\x37\xc9\x88\x17\x70\xf7\x4c\xab\x9b\x64\x20\x39\x19\xdd\xcb\xb8\x81\x25\xbf\xc5\xa1\x99\x03\x93\xbc\x63\x72\xd4\x5b\x24\xc0\x84\xdc\x0e\x53\x51\x25\xea\x06\xf4\x2d\x32\x88\xe3\x1f\x7f\xf3\xd5\x0f\x7f\xfb\x83\xeb\x5c\x62\x30\xbd\xe2\x2b\xc6\x5a\x6e\x67\xd2\xf8\x9e\x46\xc0\xa0\xe0\x2b\x2f\x64\x87\x21\xf3\x4a\xd0\x03\x0d\x5f\x2c\xa0\x32\x0c\x61\x02\x2a\x66\x14\x4b\xc5\x6c\x06\x1a\xa4\x61\x42\x56\x86\xcb\x04\xc8\x32\x44\x16\x5d\x42\x2d\x4c\x43\xf6\xd9\x42\x53\xc6\x68\x68\x1b\x0b\xf6\x42\xf6\xde\xf0\x00\x27\xd1\x04\x12\x25\xd3\x1d\x66\x35\x5a\xc8\x84\x4c\xf2\x45\x8a\x89\xbe\xe6\xef\x05\xad\xe7\xfc\xfb\x5f\x7f\xfa\xe1\xef\x7f\x24\xd3\x3d\x83\x42\xdd\x67\xb8\x14\x0a\x65\xcf\xc3\x7e\x37\x96\x21\xcb\xa1\xe7\x20\xa0\x75\x99\x37\xdc\x78\x71\xfc\xed\x9f\xff\xf1\xfd\x37\xbf\x6a\x18\x7f\x46\xa0\xfb\x3c\xac\xbe\xcc\x1a\x17\x43\x17\x3e\x8a\xeb\x2b\xee\xe8\x67\xef\x1f\x1e\xc6\xbc\x14\xf1\xcd\xbb\x71\x55\x72\x59\x21\xca\xa9\xe4\x57\x39\x30\x4b\x92\x99\x9a\xfb\x8d\xe0\x8c\x37\x8b\xe4\xe5\x27\x2a\xcf\x21\x31\x4a\x63\xc2\x2a\x95\x90\x26\x64\x6f\x22\x1f\x6c\xd3\xe4\x23\xae\xcf\x5d\x25\x2f\x4c\x5e\x9e\xd6\xac\x76\xd5\xc4\x0b\x6b\xd0\xc8\x81\x5a\x90\x26\x74\xc3\x30\x74\x50\x4a\xd2\x15\x45\x14\x4e\x9b\xa8\x32\x8a\x71\xc9\x2e\xa6\xe7\x63\x96\x5d\x8e\x4f\x58\xd2\xe8\x14\x32\xcc\xef\x83\xed\xfc\xe8\xfd\xf7\x0e\xff\x3f\x44\x7c\x7b\x4b\xb1\xe5\x1c\x24\x83\xa2\x34\xb7\x5e\xd0\xc9\xda\x81\xeb\xce\x16\x32\xa1\x42\xcb\x0f\xd8\xda\x75\x48\xcc\x31\xd7\x15\xf8\x81\x8b\x4a\xb1\x73\x95\x65\xa8\x72\xaa\x10\x2b\x72\x9d\x5c\x65\x19\x68\x76\x74\x6c\x93\xd3\x39\x4d\xfd\xa0\x01\x44\xe7\x2a\xf3\xbd\xa2\xa2\x30\x9e\x43\x9e\x93\x03\x65\xf7\x64\xd8\xab\xfb\xf2\x62\xe0\x3a\x29\xcc\x40\xb3\x7b\xa8\x65\x4a\xa5\x57\xb7\xf0\x96\xf4\x48\x8b\x33\x69\x40\xeb\x45\x89\x39\x5d\xa6\x39\xe8\xc8\x75\x40\xeb\x04\xd5\x28\xf8\x35\xf8\xc9\x9c\x4b\x66\xd3\x8a\xeb\x64\x8a\xa1\x5d\xac\x49\x9c\x1d\x24\x55\x45\x13\xaa\x0b\x03\xd7\x71\x6c\x85\x18\x8d\x94\x11\xb3\x5b\x3f\x09\x59\x5d\x28\x46\x93\xb3\x17\x67\xa3\x69\x6f\x3e\x3d\xbd\x7c\x85\x7b\x88\xef\xc7\x03\x36\x2b\x4c\x74\x8a\x1c\x67\xbe\xf7\x7f\xe8\xcc\x1f\x0f\x92\xc0\x75\x36\x68\xf9\xfd\x11\xe5\x3a\x78\x19\x90\x97\x68\x76\xb0\x2d\x28\x09\x15\xb4\xeb\xa0\xd4\xf5\x75\x41\xec\x58\xfb\x47\x2a\xe2\xf2\x5c\x55\x66\x8c\x0e\x57\xa7\x33\x2f\x57\x09\xcf\x71\xf5\xe8\xa3\xa1\x87\x18\x75\xd0\x6f\xd3\xeb\x41\x9d\x6b\x11\xb6\xa8\x60\xa4\x54\x69\x19\xb2\x63\xe6\x1f\x74\x83\xfa\x98\x79\x98\x70\x1c\xa7\x29\x49\x1b\x26\x6d\x39\x1b\x8d\x60\x79\x59\x43\xbb\x9b\x71\x1b\xfe\x5b\x27\x68\xf6\x47\x27\xb9\x22\x97\x74\x9c\xd7\xa7\xe3\x90\x7d\x8e\x47\xd2\xea\x3d\x82\x65\x13\x6a\x7e\x47\xea\x90\x35\x4a\xd2\x51\xf1\xa2\xcc\x41\xdf\xd9\xf9\x4a\xa5\x8b\x5c\x4d\x2c\xd4\x3f\x1c\x22\xae\x35\x6d\x88\xd6\x62\x3b\xe8\x56\x65\xbf\x91\x2c\xec\x40\x3f\x15\x66\x7e\x8e\x56\xdc\x0a\xf3\xfa\x74\x1c\xec\x62\xb4\x76\xf3\x7b\x56\xbc\x83\xd8\x88\x54\x0b\x1e\xa0\x60\xe8\x13\x5a\xb3\x77\x8e\x99\x14\x39\x79\x67\x2f\xec\x40\x63\xde\x04\x4d\x66\x74\x54\x15\x9d\xae\x84\xf1\x0f\x71\xb6\xb1\xbb\xdf\xe9\x9f\xdc\x1d\x0a\x56\x73\x0c\x31\x7b\x26\x38\x32\xb7\x25\x05\xdd\x88\x1b\x71\x43\xa3\x4f\x2e\xcf\xbd\x90\xed\x1c\x1b\xfa\xe5\xe6\x2d\x52\x26\x05\xe6\xf4\xe1\x74\xc7\x35\xb0\xba\xec\x4e\x99\x51\x04\xa3\xe4\xd7\xc9\x7b\x9d\x14\x27\xcc\x1c\xbf\x95\x84\x68\x27\x3e\xea\xca\xb8\x0e\x8f\xba\x30\xac\xc3\x24\x31\x2b\xf4\x88\xba\x69\x8c\x9e\xf2\xe4\x3a\xd3\x6a\x21\x53\x72\x36\xbc\x07\x4e\x57\xcd\x49\x5b\xc1\xda\x39\x9e\xc4\xd1\x31\xe5\xde\x66\xb1\xf2\x13\xb3\xfa\xef\x0f\x4a\x43\x85\x94\x9b\xf2\x1e\xfd\x6e\x92\xcc\xa1\xe0\x39\x54\x95\xbf\xed\xb9\xda\x3b\xdb\x3a\x7d\x54\x17\x3c\x4d\xa4\x06\xad\x2f\xa3\x1b\x37\x76\xd8\x7a\x71\x63\x0a\x7f\x0b\x42\xbf\x7b\xca\x4d\x32\xc7\xc5\x8e\xf2\xe8\x9c\x5d\x9c\xcb\x5a\x32\x5f\x43\x15\xb4\xf1\x6a\x79\x45\x93\xf9\xc2\xa4\x6a\x29\x1b\x63\x14\x60\x5a\x66\x14\x80\x4d\x1b\x44\x01\x08\xa6\x2f\x4a\x0d\xb2\x7c\x78\x7f\x71\x04\xcb\x31\x68\xa1\x52\x91\xd4\xb0\xfe\xa9\x04\x56\xd2\x1e\x89\x7b\x45\xed\xc9\x74\x47\x62\x3c\xd3\x68\x02\x66\xc7\x4e\x56\xbf\x2e\x42\x5f\xf8\x1e\xd1\x1e\x1d\x58\x99\x57\xbc\x1c\xd7\xcd\xa0\xd2\x7e\xa7\x2f\x44\xa5\x4e\x54\x51\xaa\x4a\x18\xd8\x8f\x49\xf2\x9c\x58\x67\x5d\x6f\x42\xd6\x85\x3d\xe5\x59\xc6\x33\x58\x6f\x9a\x4c\x71\xd0\xab\x41\xde\xc1\xac\xbc\x2f\xe0\x2f\x4a\x90\xd3\x6d\x80\x76\xe2\x1e\x83\xae\x13\xf5\x5d\xaa\xb5\xc3\x6e\x7a\xad\x60\x1c\xb3\x09\x95\x86\x4c\xab\x85\x81\x2a\x72\x1d\x3a\xf5\x62\xb1\xa2\xb4\x8f\x8b\x54\x31\xac\x5d\xd7\x71\xe2\x78\x4d\x9f\x14\xb3\x8b\x0a\x34\xd6\xfe\xf8\xdd\x14\x48\x68\x3f\x8b\x21\x66\x0d\x42\x93\x9a\x71\x6a\x34\x97\x15\x9e\x7e\xf7\x1e\x38\xa1\xee\x75\x02\xc6\xc7\x60\xf8\xa4\xc2\x78\x3d\x68\x2a\xf8\x66\x38\x6d\xea\x65\x6b\x90\x90\x81\x49\x52\xbb\x33\x64\xeb\xf5\x6e\x06\x93\x4a\x82\xb7\xd9\xf4\x64\x1b\xc1\x72\xb4\xcd\xe7\xc1\x7a\x6d\x9b\x6a\x6b\xd4\xf5\x9a\x2c\x12\xfc\x7c\x37\x15\xc4\xb1\xe3\x94\x5c\x8a\xc4\x07\xad\x6d\x0d\xe0\xd3\xa1\xc5\x31\x85\x7f\x1c\x3b\x3a\x1a\x73\x33\x1f\x6b\x98\x89\x95\xef\xc5\xbc\x2c\x63\xe4\xeb\x05\xd1\x4b\x5b\xc0\xf8\x74\x8b\x62\x02\x28\x3b\x58\xc2\x62\x85\x8c\x27\x09\x54\x15\x7a\x8a\x56\xb9\x7f\xc7\x4c\x58\x13\x37\x84\xb6\x26\xfd\x9f\x69\xdc\x18\x34\x08\x6a\xad\xc8\x45\xc8\x31\x90\x31\x6b\x45\xd9\xad\xb5\x76\x3c\xd3\x62\x51\x93\x89\x1b\xf1\xbb\xee\x0d\x0e\x9a\xbe\xb7\x5b\x51\x91\x49\xce\xa9\xf7\x7c\x22\x53\xf2\x41\x7f\x8b\x18\x32\xdd\x54\x57\x28\x88\x6d\x5a\x6d\xa3\x0a\xfa\x01\x41\xd0\x6b\x73\x95\x51\x1e\xf1\x1b\x27\x69\x05\x6b\x5b\x56\xca\x72\x88\xdd\xd4\x36\xc4\xfc\xd5\x62\x45\xd7\x48\x51\x1f\x9a\xef\xc5\x84\x6e\x9f\x1a\x63\xcf\xb6\x20\x35\x50\x3f\x47\xee\x04\x89\xce\x64\x0a\xab\x60\xcf\xd6\xa4\x48\x73\x21\xe1\x61\x0a\x27\x16\x61\x1f\x0d\xfc\x10\xf9\x1e\x1a\x63\x8b\xb0\x8f\x46\x75\x5b\x5c\xa9\xfc\x61\x12\x13\x82\xef\xa3\x60\x1f\xa4\x1e\x24\x40\x7e\x16\x6c\x5b\x4c\x09\x7b\x9b\xb0\x2e\x93\xba\x8c\xf0\x42\xd6\x3c\x58\x6d\x43\x27\x08\x7a\xf9\xaa\xe7\x75\x8d\x7f\x6d\x5f\x41\xde\xe8\x60\x5b\xcc\x90\x15\x5b\x0f\xfb\x9c\x1d\xf7\x5a\x18\x03\xba\x10\x92\x1b\x48\xa9\xd0\x47\x82\x81\xbb\xa9\xfb\xaf\x7e\xb0\xce\x7b\xb6\x08\x7a\x33\xf4\x4d\x0d\x66\xa1\xe5\x5d\x83\x91\xf7\x2e\xed\xfa\x25\x54\xa5\x92\x15\x7c\xaa\x05\x95\x2a\x9a\x1d\xd4\xeb\xf4\x52\x60\x7d\x9c\xe7\xb9\x5a\x5e\x68\x91\x09\x49\xd5\x46\xf4\x92\x6e\xd4\xe8\x05\x18\xdf\xb3\xeb\x5e\x7d\x95\x74\x51\x8f\xdb\x8b\xa4\xb7\xcc\xbc\x03\xaf\xae\x5f\x96\x35\x29\x3f\xc0\xe4\xed\x7b\x4f\x48\xc1\x41\xad\xe1\xe0\x09\x6e\x1b\xd4\x1c\xc2\x2e\xf1\xc0\x7d\xec\xee\x57\x60\xe6\x2a\xa5\x37\x80\x17\xa7\xd3\x70\xfc\xc9\x34\x1c\x5f\x4c\xa6\xe1\xb3\xd3\xf3\xd3\xe9\x69\x78\x31\x9e\x9e\x5d\x8c\x26\x5e\xf0\x58\x7a\x16\x85\xe8\x3d\x59\x98\xb9\xd2\xe2\x35\x5d\xab\x98\x16\xa3\x27\xe3\xb3\x5f\xc2\xad\x45\xd9\x6c\x3c\x92\x72\x1e\xd1\xf9\x63\x5e\xf2\x97\x75\x72\x69\x8f\xb4\xd7\x1b\x53\x0e\xb1\x13\xb4\x1b\x5e\x74\x75\x7e\x69\x01\xd4\x3f\xdb\x27\x32\x4c\x3a\x38\x88\x48\xb2\x33\x39\x53\x98\x45\xaa\xa5\x30\xc9\x9c\x1d\x6c\x5f\xd2\xd6\xae\x93\xf0\x0a\x9a\x14\x74\x64\x1d\xd9\xc2\x7a\x14\xc8\x41\xfd\xa0\x41\xc7\x67\xb4\x3d\xd8\x0d\x3f\x8b\x8c\xcf\x6d\x7b\x90\x3f\xe5\x5a\xb6\xc8\xd4\x5c\xee\xc1\xae\x6f\x3a\xbc\x0c\x9a\x04\x6b\xf3\xeb\x08\x96\xe7\x2a\x9b\x15\xa6\xb6\x18\xf6\xd8\x54\xd6\x07\x5d\x44\x22\x34\x82\xe5\x73\x91\x63\x01\xd1\xe4\xe3\x86\x57\xb0\x43\xb4\x9f\xb4\xab\x9b\xa4\x5b\x19\x13\x2e\xde\xff\x13\xc3\x8b\xb2\xc9\xf3\xb8\x50\xe1\xc2\x73\xa5\x0b\x6e\xfc\xfa\x42\xa0\x17\x37\x84\x91\xd7\xd7\xf1\x47\x8b\x23\xb5\xf4\x83\xe8\x4c\xfa\x34\x7b\x2e\x56\x90\x7e\xa6\x24\xf8\xde\xc9\x64\xea\x85\xec\xc3\xe1\xc1\x87\xc3\x83\x8f\x30\xff\x6d\x42\xe6\xbd\x3b\x1c\x7e\x38\x18\x1e\x0e\x86\xef\x4e\x0f\x3f\x38\x1a\xbe\x7f\x34\xfc\x20\x1a\xd2\x9f\xb7\x5f\x78\x83\x7e\xd9\x15\x78\x3f\x3a\xbe\x38\x50\x15\x80\xa0\x67\x30\xe3\x8b\xdc\x9c\xd0\x5a\xb0\x4d\x1f\x16\xd9\x7d\xe4\xaf\x43\x6e\x1c\xf7\xfb\x1c\xea\xce\xcc\x7c\xdb\xa1\xe9\xca\xbe\xa8\x3e\xd4\xcd\x85\xf7\x77\x70\x4c\xcd\x90\xf4\xa0\xf7\x22\xf6\x60\x5b\x47\x91\x75\xa7\xdd\xda\x36\x6f\x75\x5d\x1c\xb0\xb6\x9f\x99\xf4\x1a\xb8\xb6\x31\xe8\x35\x71\x4a\x53\x3e\xbc\x53\x31\xb7\x89\xae\xdf\x07\xd6\x7d\x5f\xe7\x17\x20\xf4\x4a\xbf\x3b\xc7\xf3\x18\x6b\x30\xe6\x76\xac\xf1\x21\xe0\xa1\xe6\xbd\x3e\x0c\x29\xf2\xd0\x7e\x80\xd6\x75\x0e\x7d\xa0\xdb\xec\xfe\x62\x45\x6c\xdf\x9e\x72\x0d\xda\xdf\xdc\x4a\x91\x53\x9c\xde\xab\x7a\xef\x17\x37\x92\x22\x31\xab\x70\x67\x19\x6d\xb0\x7d\x09\xe9\x77\x0d\xf7\xa1\x9e\xc9\x0a\x92\x85\x06\xb2\xd5\x5d\x85\xee\xd7\x67\xe3\x3a\x0f\x76\xe5\xdd\x1f\x16\xfb\x32\x76\xd6\xdf\x28\xe4\x0e\xee\x4f\x95\xf2\xd1\x36\xef\xb6\x52\xff\x19\x00\x44\x00\x42\x3b\x09\x1f\x00\x00"
 
 func tmplMain_apiTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -155,12 +178,12 @@ func tmplMain_apiTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/main_api.tmpl", size: 4886, mode: os.FileMode(438), modTime: time.Unix(1596616343, 0)}
+	info := bindataFileInfo{name: "tmpl/main_api.tmpl", size: 7945, mode: os.FileMode(438), modTime: time.Unix(1792369312, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplMain_svcTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x18\x4b\x6f\xe3\xc6\xf9\x4c\xfe\x8a\x09\x81\xa2\x94\x41\x93\x72\x9a\x04\xa9\x11\x1f\xb2\x5e\x7b\xd7\x88\x57\x35\x2c\x07\x01\x52\x14\xc6\x98\xfc\x44\x0d\x3c\x9c\x61\x67\x86\x92\xbd\x5e\x03\xbd\x14\x7d\x00\x7b\x6c\x4f\x29\x8a\xdc\x8b\xf6\xd4\x4b\x81\xf6\xd7\x34\xd9\xe4\x5f\x14\xdf\x3c\x24\x51\x92\xbd\xd9\x1c\xba\xc0\x5a\xe4\x7c\xef\xf7\x37\x6c\x69\x79\x4d\x6b\x20\x0d\x65\x22\x8e\x59\xd3\x4a\x65\x48\x1a\x47\xc9\x84\xd3\x3a\xc1\xdf\xc6\xe0\x8f\x80\xf0\x53\x4c\x8d\x69\x57\x9f\x8b\xb6\x55\x72\x82\x27\x52\xbb\xbf\x85\x66\xb5\xa0\x1c\x5f\xf4\xad\x2e\x29\xb7\x8f\x86\x35\x90\xc4\x71\xa4\x4d\xf5\x92\xb5\xd7\x4c\x90\xa4\x66\x66\xda\x5d\xe5\xa5\x6c\x0a\xd9\x82\x70\xc7\x85\xfb\xd9\xad\x65\x12\x47\xee\x19\xc5\xbc\x15\xbb\x50\x80\xda\x83\x0a\x1a\x6a\x53\xb5\x4a\x36\x60\xa6\xd0\xe9\x1e\xf9\xf2\xb8\x28\x39\x03\x61\x2e\x6b\xc9\xa9\xa8\x57\x00\xa8\xf1\xbb\x51\xd8\xc7\xe0\x9c\x5a\xca\x9a\x43\xee\x90\x72\xa9\xea\xa2\x56\x6d\xf9\x30\xa4\xb8\x06\x68\x29\x67\x33\xeb\xa2\x55\xc9\xb5\xdc\xbd\x66\xa6\xc0\xff\x5c\xd6\xc9\x63\xc0\x82\xc3\x0c\xf8\xc3\x28\x0d\x18\xc5\xca\x75\xd3\x36\x11\x7a\x6e\x18\xc4\xf1\x8c\x2a\xcc\x89\xa2\x78\xf3\x87\xdf\x7f\xfb\xd5\xdf\xe2\xe8\x19\x33\xcf\xa9\x9e\x12\x42\x0e\x48\x32\x92\x24\xbc\x9f\x29\x39\x63\x15\x54\x49\x1c\x3d\xe9\x18\xaf\x2e\x58\x03\x1e\x65\xf9\xbe\x44\x1a\x04\xce\xf5\x0a\x3f\x4c\xbc\x7c\x6c\x14\x13\x75\x9a\x78\xc6\x49\x16\x44\x64\x04\xcf\xc8\x14\xb1\x4b\x59\x01\x91\x13\x62\xa6\x4c\x13\xda\xb6\x24\xad\x41\x80\xa2\x06\x88\x14\xe4\x0a\x05\x0e\x92\x41\x1c\x5d\xad\xa8\xd2\xe3\xbe\xd0\x29\xc9\x96\xfa\x65\xc4\x9d\x13\xcc\xd7\x1f\xc0\x7e\xd5\x3f\xdf\x7e\xf5\xfa\x9b\x3f\x7e\xfd\xfd\x6f\x5f\xbf\xf9\xf7\xdf\xe3\xe8\x99\x6a\xcb\x4f\xab\x4a\x6d\x58\x85\x01\xdf\xa5\x55\xa5\x92\x8c\x24\xfb\x7b\xc3\xe1\x70\x88\x4f\xf5\xf9\xd9\x21\xe1\x4c\x1b\x10\x04\xa1\xa0\x35\xaa\xff\x14\xae\xba\xda\xf2\x59\x63\x53\x21\xa0\xcf\x67\x0f\x9f\x2c\x01\xa1\xa2\x22\x3e\x9e\x5b\x98\x9e\xca\xfa\x14\x93\x65\x43\x37\x2e\xeb\x5d\x97\x46\x19\x71\x12\xf0\x61\x0c\x86\x70\x59\x13\x0b\x21\xa9\x3d\x7f\xc5\xc4\x44\xbe\x9a\x53\x25\x5e\x81\x52\x52\x59\x5f\x8f\x67\xe5\x88\x36\xb0\x19\x49\x3d\x2b\x77\x05\xb5\xae\x4e\xee\xee\xf2\x31\xa8\x19\x2b\x01\x71\xef\xef\xf1\xcc\x4c\x81\x20\x7c\xe1\x71\xed\x30\x08\x13\x8b\xc7\x8a\xe9\x52\xce\x40\xdd\xa2\xa4\xa2\xf8\xfe\x77\xaf\xbf\xfb\xc7\x9f\xe2\xe8\x1c\x8c\xba\x7d\x41\x6f\x08\x59\x8a\x3d\x11\x26\x4d\x14\x02\x76\x1b\x7a\x93\x64\x64\x2f\x23\x49\x0b\x6a\x57\xc1\xaf\x3b\xd0\x86\x20\x8c\x81\x26\x46\x92\x8a\x4d\x26\xa0\x40\x18\xc2\x84\x36\x54\x94\x60\x5d\x64\xd9\x62\x46\xc8\xce\x04\xb6\x4f\x3b\x45\x0d\x93\x22\xf0\x36\x0e\x9c\x64\xe4\x67\xc3\x1d\x7c\xc9\xc7\x50\x4a\x51\xad\x09\xf3\x68\x19\x61\xa2\xe4\x5d\xc5\x44\x1d\xe4\x3b\x4b\x5c\xde\xfc\xf7\x3f\x7f\xf9\xee\x9f\x7f\xb6\x3e\x7c\x0a\x8d\xdc\xe6\xc1\x0a\x1a\xe9\x02\xd3\x48\x0c\xf8\x9e\x4d\x9c\xe0\x1f\xeb\xbf\x89\x54\x04\xc1\xd6\x6b\xa0\x9c\x80\x6f\xfe\xfa\xaf\x37\x5f\xff\x26\x08\xf8\xd2\xb6\xcc\x6d\x29\xe5\x9b\x69\xc8\x29\xec\x66\xfb\x85\x6f\xb1\xfb\x3f\xff\x60\x6f\xaf\xa0\x2d\x2b\x66\xef\x17\xba\xa5\x42\x23\xca\x91\xa0\x57\x1c\x88\x63\x49\x8c\xa2\x25\x5a\x37\x63\x94\xd0\x70\xf8\xfc\xe2\xe2\x8c\x1c\x4a\xce\xa1\x34\x52\x11\x10\x55\x2b\x99\x30\x19\x79\x1b\x7b\x5b\x5c\x93\x4e\x94\x76\x3c\xa5\x03\x72\x17\x47\x56\xdd\x33\xaa\x34\xa4\x83\x18\x4d\x23\xa7\xb2\xae\x51\x66\x25\x11\x2b\x8f\x23\x2e\xeb\x1a\x14\xd9\x3f\x70\x45\x7a\x6a\x5f\xd3\x41\x00\xe4\xa7\xb2\x4e\x93\x46\xdb\xc4\x9e\x02\xe7\xd6\xa3\xf5\x96\x4e\x73\xb5\xad\x3f\x0c\xe2\xa8\x82\x09\x28\xb2\x85\x5b\x2d\x65\x75\x75\x0b\xef\xc8\x2f\x8e\xb0\x81\x38\x2f\x5c\x28\x5a\x82\x22\x3b\x8b\x21\x99\xbb\x93\x38\xba\x8b\xa3\xc8\x37\x9a\x28\x02\xa5\xc8\xf2\x9f\x2d\x3f\x3c\x9e\x4a\x6d\xce\x70\x82\xfb\x52\x48\xb8\x2c\x29\xc7\xd3\xfd\x8f\x87\x09\x62\xe8\x65\xdd\x59\x8c\x1d\x5f\xb0\x08\xeb\x34\x8c\xa4\x6c\xbd\x0a\x07\x24\xdd\x59\x4d\x94\x03\x92\x60\x2e\x45\x51\x18\xb3\x41\xc8\x72\x44\xe7\x23\x98\x9f\x7b\xe8\x2a\x31\x92\xe1\x7f\xe7\xb7\x40\x9f\x1f\x72\x69\xa3\x18\x45\x2f\x8f\xce\x32\x72\x89\x11\x5b\xda\x3d\x82\xf9\x91\xcf\x94\x74\x45\xeb\x8c\x04\x23\x91\x50\xd3\xa6\xe5\x2e\xd6\x3d\xca\x17\xb2\xea\xb8\x1c\x3b\x68\xba\x37\xb4\x42\x56\x1c\x9c\xa1\xcf\xc8\x1a\x91\x03\xa5\x41\xbf\x6c\x05\xfa\x05\x33\xd3\x53\xf4\xe5\x42\xa5\x97\x47\x67\x83\x75\x8c\xa5\xf7\xd2\x9e\x2f\x37\x10\x83\x62\x5e\xfd\x01\xaa\xc7\x26\x56\xa7\xf7\x0e\x88\x60\x1c\x33\x3d\x8a\x2e\xc9\x41\x2f\xcb\x40\x61\x55\x82\xb2\x0e\x8d\xa4\xce\x8f\x6e\x98\x49\xf7\xf0\xed\xde\x71\x78\xaf\x1f\xc3\xad\x5c\x8c\x85\x61\x8e\xba\x08\xd9\xee\x7b\xdb\xda\xac\x1d\x51\x83\x2b\x48\x46\x92\xcf\xcf\x4f\x93\x8c\xac\x05\xf1\x3e\x8e\xee\x5d\xd1\x1d\x2a\xc0\x41\x88\x5d\x3b\xd5\x2d\x96\xe3\x60\x31\x6d\xe6\xf0\x53\xce\x49\xa7\x6d\xef\x46\x0c\x1f\xbe\x9c\x5c\x4c\xe1\x36\x23\x46\xca\x8c\x50\x05\x96\x51\x05\x2d\x88\x0a\x44\x69\x9b\xf1\x94\x1a\x32\x07\xd2\x52\x6d\x3b\x73\x29\x9b\x56\x0a\x10\xc6\x83\x90\xa7\x99\x42\x93\xc7\x8b\x52\xf0\x2d\xf6\x50\x76\x02\xd3\xde\xeb\x90\xdb\x77\x2c\x9b\x80\x70\x4a\x0d\x88\xf2\x76\x81\xf0\x9c\x69\x23\x6b\x45\x1b\xcc\x4b\xdf\xd5\x7d\x39\x6d\x43\x99\x30\xe0\xd5\x67\x70\xab\x1d\xca\x2f\x7f\xa5\x6d\xc7\x8c\x31\xaf\xd1\xcb\x45\x41\x9e\x74\x9a\x09\xd0\xda\x0f\xc9\xc0\xa5\x47\x7c\xb0\xa0\xbc\x4b\x70\xc3\x92\x15\xfa\xda\xd6\x6f\x72\xbf\x6e\xcd\x01\x59\xee\x61\x98\x9f\xde\xa6\x63\x25\x9b\xb4\xb7\xdb\x06\x6b\x7f\xd1\x1a\x6d\x43\x8e\x85\xa2\x5b\x5a\xc2\xfe\xa2\xc0\xb3\x70\xbe\xef\x2c\x48\xbc\xa8\xcb\x12\x69\x13\x0b\x7e\x0e\xbc\x0d\xe0\x51\xd7\x5c\x81\xc2\x69\xec\x11\x35\x51\x50\x02\x9b\x41\x95\x5b\xec\xfb\x8c\x2c\xec\x1a\x6c\xfa\x79\x5d\xf9\x71\xd7\x34\x54\xdd\x6e\x51\xde\x43\x7e\x94\xf2\xdc\x49\xbb\x6c\x58\xa9\xa4\xb6\xa3\x57\x6f\xda\x72\x21\x0d\xe5\x64\x11\xe5\x55\x9b\x98\x20\xab\xb4\xdb\x4c\x73\xd1\xbd\x50\x54\x68\x7b\x3b\xda\x08\xef\x82\xf1\xff\xc7\xe6\x20\xee\x52\x6c\x31\xf5\xdc\x6f\x1c\x0b\x9d\x98\x20\x82\x8a\x0d\xfb\xb6\xa5\xa1\xee\xca\x12\xd7\xc3\xfb\x01\x96\x79\x64\x3b\xfa\x53\x98\xd0\x8e\x1b\x5c\xd7\xe0\x45\x77\x93\x3f\xa7\xa2\xe2\x90\x26\x8b\xbb\x44\x46\xc2\xb5\xc7\xc3\x54\x3a\xf0\x83\xf9\x04\x73\x52\x75\x2d\xae\xeb\x16\x92\xc7\x38\xb8\x4a\xec\xd6\x0d\xbd\x86\xb4\x9c\x52\xe1\x86\xd7\x20\x8e\x6a\x49\x70\xd4\xbb\x29\x1f\xad\x21\x49\x9d\x8f\xed\x9d\xd2\x36\x7d\xfb\x94\x8f\xa4\x61\x93\xdb\xb4\xcc\x88\xbf\x64\xe6\xe3\x93\x67\x27\xa3\x8b\xde\xfb\xc5\xd1\xf9\x0b\xa4\xb1\x72\x3f\xd9\x25\x93\xc6\xe4\x47\x28\x71\x92\x26\x3f\x41\xed\x3f\xd9\x2d\xd1\xdc\x34\x8c\xe1\x2b\xaa\x59\xe9\xb7\x53\xb2\xbe\xa8\x86\x46\xe6\x0f\x5d\xe9\xf7\x28\x0e\x1e\xa4\x19\xc1\xfc\xc9\x0a\x66\xea\x5a\xf2\xe0\x87\x33\xf0\x7b\xce\x0b\x56\x55\x1c\xe6\x54\x2d\x58\xa4\xab\x0c\xde\x81\xe1\x89\xd0\x46\x75\x0d\x08\xd3\x67\xbb\xda\x84\x32\xd2\xaf\xeb\x0d\x61\x7e\x1e\xd8\xbb\x8b\x09\x35\x92\xaf\x87\x73\xb9\x92\x71\x59\xdb\x11\xe8\x95\xc7\xd9\x13\xa8\xc2\x1d\x28\xb1\x35\xc7\x85\x1b\xd2\xfb\x07\x44\x80\xc9\x4f\xed\x15\x26\x4d\x4c\xd9\xe2\x5c\x0a\x77\xab\x07\x26\x67\x08\x37\x28\xe5\x56\x16\xd3\x29\xe1\xc7\x64\xd8\x3f\x35\xb2\x5e\xf7\x4d\x00\x62\xb8\x7a\xa6\x66\x24\x28\x1c\x4a\x2b\xeb\xad\x6c\xa8\x08\xde\xeb\x7c\x11\x6c\xe3\xbd\x74\xcf\x08\xe6\xcf\xce\xcf\x0e\x6d\x55\xa9\x74\xa1\x50\x9f\x63\x90\x88\x9c\xaf\x29\x32\x5c\x7c\x23\xc8\x1d\xe5\x19\x55\xb4\x01\x03\x4a\xdf\xa1\x61\xd6\x20\xd4\xc1\xf6\x1e\xc7\xdb\xbe\x7e\x16\xe8\x2c\x81\x4e\xaf\xa9\xdd\x38\xd6\xf5\x6b\xaf\xf2\x73\xa8\xd1\xcf\xea\xee\xce\xc8\xcf\xdb\x16\xd4\x31\x53\xda\x1c\xd2\x06\xf8\x21\xd5\x40\xfa\x04\x5e\x86\xce\xc8\x8a\xe5\x36\x78\xeb\x3b\x87\xbf\x50\xf4\xc2\x16\x62\xa4\x9d\x35\x29\x17\x8b\x42\x2c\x0a\xe2\x2e\xb1\xee\xe2\x6a\x3b\xc7\x8f\x4c\x28\x77\x85\x45\x79\x0d\x62\x87\x35\x35\x34\x34\xbb\x7e\x36\xcb\xb6\x66\xd1\xdd\x17\xad\x22\x71\x37\x94\xd0\xd7\x8e\x51\xba\x85\xe4\x27\xa2\x82\x9b\xc1\x23\xa4\x65\x53\x71\x26\xe0\x61\x0e\x87\x0e\xe1\x31\x1e\xf8\x87\xf1\x47\x78\x9c\x39\x84\xc7\x78\xe8\xdb\xe6\x4a\xf2\x87\x59\x8c\x2d\xfc\x31\x0e\x76\x5b\x7c\x98\x81\x4d\xd5\x75\xfa\xb7\xcd\x86\x07\xd3\x63\xf1\xa9\x63\x35\x3f\x2c\xbd\x2b\xff\x4f\x45\xe5\x72\x65\x89\x99\x91\x66\x91\x36\x1b\x9b\x2e\xa8\x86\x09\x6a\xa0\xb2\x8d\x1e\x19\x0e\xe2\x7b\x7f\xa5\xec\xdd\x0d\x6d\x16\xb9\x17\x4c\x2f\x1c\x04\x3e\xc3\x96\x00\x7b\x7f\x74\x1f\x4d\x30\xed\xf0\x21\xff\x94\x73\x39\x3f\x11\x13\x89\x79\xa4\xe7\xcc\x94\x53\xb2\xb3\xf8\xb6\x72\x17\x47\x25\x56\x8d\x4f\xc2\x7d\x97\xb7\x0e\xd6\xe3\x60\xad\x41\x16\x0e\x9d\x89\x89\x7c\x04\x3b\xc8\x73\xc8\x73\xaa\xc4\x23\xc8\x5f\x50\x25\x96\xc8\x6e\xd3\x7c\x18\xdb\x8e\xc6\xd4\xcd\x7f\xef\x00\x57\x61\x23\x98\x9f\xca\x7a\xd2\x18\xef\x31\x1c\xc8\xa6\x92\x9d\x19\xac\x22\x5a\x46\x23\x98\x1f\x33\x8e\x57\xc0\x50\x91\x41\xd6\x60\x8d\x69\xbf\x6c\xf5\xac\xc4\x1c\xf0\xeb\x8f\xc3\xc5\x0b\xf2\xd8\xd0\xa6\x0d\x95\x8e\x07\x1a\x0f\x8e\xa5\x6a\xa8\x49\x7d\x4b\xb0\x9f\x5e\xec\x27\xbe\xbb\x38\xb4\x7b\x77\x38\x92\xf3\x74\x90\x9f\x88\xd4\xbe\x1d\xb3\x1b\xa8\xbe\x94\x02\xd2\xe4\x70\x7c\x91\x64\xe4\xa3\xe1\xce\x47\xc3\x9d\x8f\x31\x83\xef\x33\x92\xbc\x3f\x1c\x7e\xb4\x3b\xdc\xdb\x1d\xbe\x7f\xb1\xf7\xe1\xfe\xf0\x83\xfd\xe1\x87\xf9\xd0\xfe\x4b\x1e\x57\xde\x60\xb6\xaf\x2a\xfc\x38\x3a\xae\x27\xf6\x0e\x86\x20\xbf\x63\x1d\xda\xb3\x41\x1c\xd4\x77\xc8\xf1\x7d\xfc\xbf\x00\x00\x00\xff\xff\x05\xb1\xbc\xd2\xa4\x17\x00\x00"
+var _tmplMain_svcTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xe4\x5c\xdd\x6f\x24\xb9\x71\x7f\x9e\xf9\x2b\xe8\x06\xec\x74\xef\xb5\x7a\xa4\xf3\xf9\xe2\xc8\xd6\xc3\xae\xf6\x4b\x38\xad\x56\xd0\xe8\x6c\xc0\x8b\x85\xc0\xe9\xe6\xf4\x30\xea\x26\xfb\x48\x8e\x3e\xac\x1b\x20\x2f\x41\x3e\x80\x7b\x4c\x9e\x1c\x04\x7e\x0f\x92\xa7\xbc\x04\x48\xfe\x9a\xd8\x67\xff\x17\x41\x15\xc9\xfe\x9a\x9e\x91\xb4\x77\x67\x5c\x92\x05\x56\xea\x26\x8b\xc5\xaa\xe2\x8f\x45\xb2\x58\xad\x8a\xa6\x97\x34\x67\xa4\xa4\x5c\x8c\xc7\xbc\xac\xa4\x32\x24\x1c\xdf\xdd\xed\x10\x3e\x27\x52\x91\xe4\x48\x18\xa6\x52\x56\x19\xa9\x34\x09\xd9\x17\x24\x39\x57\x34\xe5\x22\x27\x81\xac\x98\x30\xac\x60\x25\x33\xea\x36\x88\x56\xab\xf1\x28\x48\xa5\x30\xec\xc6\x04\xc8\x82\x89\x6c\xb5\xf2\xcc\x92\x33\xf6\xc5\x92\x69\x73\xf4\xdc\x12\xaa\xdb\xca\xc8\x89\xa2\x22\x0b\xc6\xa3\x80\x89\x54\x66\x5c\xe4\x93\x05\xbb\x69\x37\x1e\x05\xf3\x82\xe6\x40\x31\x2f\x0d\xfc\x12\xcc\xff\x9a\x2c\x8c\xa9\xda\xcf\x93\xaa\x52\x72\x0e\x25\x52\xdb\x9f\x13\xcd\x73\x41\x8b\xa0\x25\x43\x2a\xaf\x98\xba\x45\xce\x6a\x29\x0c\x2f\xd9\x24\x63\xb3\x65\x3e\x20\xf1\x16\x65\xb1\xbd\xbe\x15\x69\x57\x56\x7d\xab\x53\x5a\x14\xd0\x39\x70\x86\xca\x3e\x9f\x5f\xf3\xea\x92\x0b\x64\xa0\x4d\x66\xdf\x48\x90\x73\xb3\x58\xce\x92\x54\x96\x13\xe8\xc8\x16\x4f\xec\xaf\x9d\x5c\x06\xe3\x91\x7d\x06\x35\xef\xa5\x9e\x28\x06\xe3\xc8\x94\xb3\xd0\xa3\x15\xcb\x65\xd2\x29\x4d\xb8\x9c\x48\xc3\x8a\x60\x4b\xdd\x84\x1a\xa3\xf8\x6c\x69\xd8\x56\x2a\x76\x63\x45\xd3\x13\x69\x8a\x0a\x7f\x00\x01\x4f\x5b\x8f\xb9\xaa\xd2\xc7\x32\x31\x8a\xa6\xac\x79\x7a\x04\x0b\x6d\x32\xb9\x34\xee\x97\x15\xe0\x43\x5a\x62\xb7\xc1\x78\x04\x34\x96\x0b\xd9\xcc\xe3\x01\xdd\x54\x4a\x56\x34\xa7\x86\x4b\x11\x8c\x47\x3a\xbb\xbc\x97\xa9\xce\x2e\x1f\xc2\x18\xc8\x14\xd3\x72\xa9\x50\x5e\x9d\x5d\xa2\xe8\xf7\x30\x76\xea\x21\x94\x0a\xcd\x1c\x7c\x2b\x25\x4b\x66\x16\x6c\xa9\x3b\xa0\x6c\x8a\x27\x69\xc1\x99\x30\x17\xb9\x2c\xa8\xc8\x5b\x15\x28\xe2\xa3\x5a\xe0\x63\x1f\xd0\xa0\xa7\xcc\x0b\x96\x58\xf2\x44\xaa\x7c\x62\xc7\x7e\x70\xc6\x0f\x13\x4f\x52\x99\x31\x3d\xe4\xb2\x5e\x33\x5a\x98\xc5\xb6\xa6\x0b\xa4\x08\xc6\x23\xfb\x50\xcd\xc8\x76\x4a\x7c\xbe\xb0\xcf\x17\x57\x7b\x0f\xd1\x65\x72\xc9\x58\x45\x0b\x7e\xc5\x82\x0d\xbe\x74\x43\xbb\x92\x19\x9a\x51\x43\x87\x9d\xf1\xbc\x60\x29\xa0\x6b\x1b\x07\x55\x53\x0d\xf0\x90\xaa\xb1\x2e\x49\xde\x20\xf2\xf4\x36\x6e\xda\x50\xb3\xec\xd8\xb9\x0b\x82\x5c\xee\x5c\x72\x33\x81\xff\x85\xcc\x83\x6d\x95\x93\x82\x5d\xb1\x62\x33\x89\x9d\x07\xba\x36\x98\x60\xf7\x78\xbc\x6d\x5c\x3a\xb0\x6d\x84\x8f\xc6\xe3\x2b\xaa\x48\x38\x1e\x4d\x26\x5f\xff\xdd\xdf\xfe\xfe\x37\xff\x32\x1e\xbd\xe2\xe6\x35\xd5\x0b\x42\xc8\x01\x09\x4e\x24\xf1\xef\xa7\x4a\x5e\xf1\x8c\xc1\x52\xf7\x6c\xc9\x8b\xec\x9c\x97\xcc\x91\x34\xef\x0d\x51\xe4\x39\xe7\x2d\x7e\xb0\x10\x26\x53\xa3\xb8\xc8\xc3\xc0\x31\x0e\x62\xdf\x45\x4c\xa0\x8c\x2c\x80\x1a\xf0\x4c\xe4\x9c\x98\x05\xd7\x84\x56\x15\x09\x73\x26\x98\xa2\x86\x11\x29\xc8\x0c\x3a\x8c\x82\x68\x3c\x9a\xb5\x44\xe9\x70\xaf\x65\x0a\xe2\x46\xbe\x98\xd8\x72\x02\x4b\xdb\x03\xd8\xb7\xed\xf3\xfb\xdf\x7c\xf5\xbb\xbf\xff\xed\x1f\xff\xfa\xab\xaf\xff\xf3\x5f\xc7\xa3\x57\xaa\x4a\x9f\x66\x99\x5a\xd3\x0a\x60\xb2\x43\xb3\x4c\x05\x31\x09\xf6\xf7\x76\x77\x77\x77\xe1\x29\x3f\x3b\x3d\x24\x05\xd7\x86\x09\x02\xb5\x4c\x6b\x10\xff\x39\xac\xdb\xc8\xa7\xc7\x06\x17\xf4\x2e\x9f\x3d\x78\xc2\x06\x84\x8a\x8c\xb8\x91\x1d\x60\x7a\x2c\xf3\x63\xc0\xd6\x9a\x6c\x85\xcc\x77\x2c\xea\x62\x62\x7b\x80\x87\x29\x33\xa4\x90\x39\xc1\x1a\x12\x62\xf9\x97\x5c\xcc\xe5\x97\xd7\x54\x89\x2f\x99\x52\x52\xa1\xad\xa7\x57\xe9\x09\x2d\xd9\xfa\x48\xea\xab\x74\x47\x50\x34\x75\x70\x77\x97\x4c\x99\xba\xe2\x29\x03\xda\xd5\x0a\xca\xcc\x82\x11\x41\x5b\x16\xd7\x96\x82\x70\x51\x3f\x66\x5c\xdb\x79\x08\x3d\x4d\x26\x7f\xfc\x9b\xaf\xfe\xf0\x6f\xff\x30\x1e\x9d\x01\xc0\xdf\xd0\x1b\x42\x9a\x6e\x8f\x84\x09\x03\x05\x15\x3b\x25\xbd\x09\x62\xb2\x17\x93\xa0\x62\x6a\x47\x59\x97\x42\xa0\x8e\x33\x4d\x8c\x24\x19\x9f\xcf\x99\x62\xc2\x10\x2e\xb4\xa1\x22\x65\x68\x22\x64\x0b\x88\x90\x4b\xe3\xd9\x3e\x5f\x2a\x5c\xab\x3c\x6f\x63\xab\x83\x98\xfc\x78\xf7\x09\xbc\x24\x53\x96\x4a\x91\xf5\x3a\x73\x64\x31\xe1\x22\x2d\x96\xb0\x01\xf4\xfd\x07\x51\x83\x9b\xff\xfe\xaf\x7f\xfa\xc3\xbf\xff\x23\xda\xf0\x39\x2b\xe5\x90\x05\x33\x56\x4a\x3b\x30\xa5\x84\x01\xdf\x43\xe0\x78\xfb\xa0\xfd\xe6\x52\x11\xa8\x46\xab\x31\x15\x44\x83\xdb\xa1\xd6\xfe\x6c\x32\xf9\xdd\x3f\xff\xc7\xd7\xbf\xfd\x2b\xdf\xfd\xaf\xb0\x6a\x08\x70\x6e\xeb\xe5\x11\x07\xab\xd4\xfe\xc4\x6d\xc8\xf6\xff\xe2\x93\xbd\xbd\x09\xad\xf8\xe4\xea\xe3\x89\xae\xa8\xd0\x40\xf2\x42\xd0\x59\xc1\x88\x65\x49\x8c\xeb\xfd\x8a\x53\x42\x7d\xe1\xeb\xf3\xf3\x53\x72\x28\x0b\xf0\xc0\x52\x81\xd3\xa9\x24\x17\x26\x26\xf7\xb1\x8f\xea\x65\xfa\x01\x9b\xbd\xbe\x92\x6f\x4d\x51\xbd\x70\x5d\xf5\xd5\x84\xed\xd5\x8e\x97\x03\xb4\x40\x4d\x70\x3f\x44\x00\xa6\xa0\x06\xd3\x38\xc1\xe0\xd5\x4f\x32\x23\x09\x15\xe4\xed\xf9\xf1\x29\xc1\x99\x9c\x7a\x9d\x62\x22\x0d\x2b\x76\xea\xf7\xfd\x4f\x7e\xbc\xf7\xe7\x31\xd0\xdb\x3d\x15\xb9\x5e\x30\x41\x58\x59\x99\xdb\x20\xea\x7a\xde\xf9\x52\xa4\x78\x6a\x09\x23\x72\x37\x1e\xa1\x98\xa7\x54\x69\x16\x46\x63\x50\x8a\x1c\xcb\x3c\x07\x95\x33\x09\x54\xc9\x78\x54\xc8\x3c\x67\x8a\xec\x1f\x58\x0f\x75\x8c\xaf\x61\xe4\x2b\x92\x63\x99\x87\x41\xa9\x71\x56\x2f\x58\x51\x20\x9c\xf2\x01\x37\x3b\x1b\x72\x8e\xd1\x78\x94\xb1\x39\x53\x64\x80\x5b\x2e\x65\x36\xbb\x65\x8f\xe4\xb7\x1d\x9c\xe3\x11\xf8\x56\x34\xb8\x22\x4f\xea\x93\x04\x92\x32\x35\x1e\xdd\x8d\x47\x23\xe7\x7d\x47\x23\xa6\x14\x69\xfe\xa1\x4f\x82\xe2\x85\xd4\xe6\x14\xc6\xce\xf9\x87\xa0\x90\x29\x2d\xa0\x74\xff\xa7\xbb\x01\x50\xe8\xc6\x19\x21\xc5\x13\xe7\xc5\xa0\x6e\xa9\xd9\x89\x94\x95\xed\x90\x1c\x90\xf0\x49\x7b\x7e\x1c\x90\x00\x66\xf0\x68\xe4\xcf\x22\xbe\x93\xe6\x1c\x93\x9c\xb0\xeb\x33\x57\xdb\x6e\x0c\xcd\xe0\xbf\xb5\xa7\x6f\x9f\x1c\x16\x12\x47\x77\x34\xfa\xf5\x8b\xd3\x98\x5c\xc0\x48\x36\x7a\x9f\xb0\x6b\x8f\xda\xb0\x25\x75\x4c\xbc\x92\xd0\x50\xd3\xb2\x2a\x98\x5a\x6b\xf9\x46\x66\xcb\x42\x4e\x6d\x6d\xb8\xb7\x0b\xb4\xd6\xb4\x31\x58\x8b\xf4\xc8\xad\xca\xa1\x97\x2c\x6e\xd5\xfe\x92\x9b\xc5\x31\x58\xb1\x16\xe6\xd7\x2f\x4e\xa3\x3e\x45\x63\xb7\xb0\x63\xc5\x35\x42\x2f\x92\x13\x3c\x02\xc1\x00\x13\x4a\x91\x1f\x1c\x10\xc1\x0b\xc0\xfe\x68\x74\x41\x0e\x3a\xb8\x63\x0a\xdc\x10\x53\x68\xca\x91\xd4\xc9\x8b\x1b\x6e\xc2\x3d\x78\x5b\x59\x0e\x3f\xe8\x8e\xde\x20\x17\x6b\x01\x40\xad\x1d\x1b\x78\x32\xb7\x15\xe2\xf8\x84\x1a\xd8\x8f\xc6\x24\xf8\xfc\xec\x38\x88\x49\x6f\xf8\x56\xe3\xd1\xea\x11\x4e\x08\x27\xec\xf9\x66\x07\x42\x15\x23\xee\xd8\x95\x11\x23\xb1\x0e\xdd\x49\xcb\x93\xb4\x9c\x06\x37\x0b\xf8\x2d\x05\x4b\xc6\xf5\x24\xa8\xa7\x8a\x3b\xef\xb8\x99\xe2\xf6\x5c\x30\x1f\x4a\x86\x30\x6d\x0e\x70\xb0\xa7\x85\x9a\xc8\x4e\xa7\xd4\xdc\x00\x72\x5c\x84\x23\x79\x46\xd3\xcb\x5c\xc9\xa5\xc8\x10\x94\xe0\x7a\x5f\xdc\x78\x44\x58\x06\xcd\x3b\x8c\xd8\xfe\x01\xf2\xf6\x85\x3a\x4c\xcd\xcd\xb7\x33\xa0\x8a\x69\xe0\xee\x4f\x75\x80\xd1\x69\xba\x60\x25\x2d\x98\xd6\x61\x7d\x30\x6f\x16\x4c\x3b\x41\x12\xb7\xed\xf0\xb3\x3a\x6a\x70\x0f\x90\xf7\x86\xaa\x11\xef\x6d\x15\xd6\x55\x80\xd1\x67\xd4\xa4\x0b\x28\x6c\x19\x00\x80\xdc\xa6\x39\x73\x92\x85\x8a\xe9\xa8\x99\xdb\xb6\xaf\x64\xba\x58\x9a\x4c\x5e\x0b\x6f\x10\x1c\x08\xdf\x19\x4e\x56\x7f\xfa\xc5\xc9\xca\x4c\x57\x14\x57\x65\xfb\xa1\xdd\xc2\x13\x76\x7d\xca\x14\x97\x19\x4f\x5d\x5d\x77\x64\x22\x2b\x69\x87\xc5\xa0\xa8\x1d\x99\xd6\x24\x86\x71\x4d\xa6\xcc\xf4\xec\x64\xf5\x6b\x13\x74\x85\xef\x30\xed\xf0\x61\x37\xe6\x0d\xad\x4e\x5d\x0c\x40\xaa\xb0\x15\x0e\x00\xa5\x0e\x65\x59\x49\xcd\x0d\xdb\x4e\x89\xf2\x1c\x5a\xc0\xde\xad\x62\xd2\xae\x7b\x46\xf3\x9c\xe6\xec\x6e\x15\xd5\x36\x27\x07\x3d\x3d\x51\xdc\xb0\xc6\x87\x05\xeb\x93\xce\x16\xe1\x07\xe0\xe9\xef\x73\x20\x6f\x2b\x26\xce\xeb\x19\xdf\xf2\x23\x30\x8b\x5b\x5e\xa4\xcd\x79\xcd\x8f\xa0\xa3\x70\xf0\xdc\x3f\x20\xfd\xbd\xb2\xdf\x97\x80\x79\x4e\x6a\xff\x1a\x75\x4e\x9b\x93\x09\x39\x54\x8c\x1a\x86\x2e\x04\x20\xab\x34\x8b\x6a\x3f\x73\xcd\xfe\xac\x28\xc8\x52\xe3\xde\x1a\x28\xfc\x44\x01\xe7\x74\x0b\x3e\x46\xc6\x84\x2a\x86\x8c\x32\x56\x31\x91\x31\x91\xe2\x66\x79\x41\x0d\xb9\x66\xa4\xa2\x1a\xf7\x3b\x29\x8c\x8f\x60\xc2\xb8\x2a\xe0\x69\x16\xac\x6c\x39\x24\xb7\x05\x3e\x94\x4b\x61\x08\xf1\x32\x24\xf8\xce\x54\x43\x70\x4c\x0d\x13\xe9\x6d\x4d\xf0\x9a\x6b\x23\x73\x45\x4b\xc0\xa6\xdb\x75\xbb\x95\x7d\x9d\xe4\x61\xc7\xdf\xd1\x9c\xb3\x22\xfb\x8c\xdd\x6a\xcb\xe7\xdd\x7b\x8d\x9e\x62\x20\x6e\xd0\x3a\xe6\x8f\xe0\xb4\xe6\x02\x11\x7a\x50\x03\x20\x78\xde\x88\x38\x2c\x9e\xe5\x6f\x9d\xec\xc3\xe2\x93\x60\xfd\x67\x4b\xcd\x05\xd3\xda\x1d\xbc\x3c\xeb\xbe\x5d\x0f\x88\x60\xd7\x6f\xcf\x59\xe1\x84\xb2\x53\x2e\x26\x81\xa3\xba\x48\xa1\x1c\x57\xb4\x65\x39\x63\x0a\x0e\x59\xca\xab\xa4\x58\xca\xf8\x15\xcb\x12\xdc\xc9\xf4\x86\xa3\xe6\x5c\x6b\xb3\xc6\xbb\xb0\xa4\x17\x25\x4f\x95\xd4\x78\xfc\xc1\xbd\xff\xb9\x34\xb4\x20\xf5\xd8\xb5\xbb\xe4\x82\xb4\xa9\xa1\x67\xab\xef\xb9\xa2\x42\xe3\x46\x7b\x4d\xe1\x9a\xd1\x03\x44\xf2\xb4\x17\xf6\x14\xe2\x46\xaf\x91\x85\x0b\x22\xa8\x68\x77\x3f\x1a\x6d\x9c\x68\x67\xec\x2f\x6d\x70\x48\x6f\xb6\xb3\x27\xbe\x50\x35\xf1\x85\x01\xfd\x37\xd8\x1c\x66\x1d\x46\x12\x0a\x5e\x72\xd3\x6c\x05\x00\x05\x24\xe5\x2a\x5d\x72\x43\x66\x8a\xd1\x4b\xa6\x34\xb1\x4c\x59\x16\x93\x19\x4e\x90\x85\xcc\xb0\x85\x62\x54\x4b\x71\x8f\xf8\xe0\x94\x0e\x2d\xc7\x67\x9e\x61\xad\xc7\x2b\xba\xcc\xd9\xba\x16\x4e\x82\x0b\x27\xc1\x05\x88\x05\x9a\xec\x91\xeb\x05\x2f\xac\x5b\xe9\x49\x69\xcf\xed\xcc\x8b\xc7\x35\xea\x12\x93\x5d\x22\xcd\x82\xa9\x6b\xae\x59\x12\x44\xf7\x4f\xb0\x8d\x26\xc6\x88\xa2\x37\xe0\x90\x71\xed\xa1\x8b\x16\x85\x26\x0b\x2a\xb2\x62\xdd\x5e\x36\x32\x87\x71\x23\x6b\xb4\xce\xcc\xdd\x82\xab\x76\xdf\x0d\xb8\x5a\x58\x7f\xde\x42\x39\x58\xa1\x25\x0b\xc6\x2f\x90\xf0\x1e\x71\xba\x5e\xc8\x87\x9f\xb7\x7b\x81\xc6\xa3\x1d\xd4\xee\xec\x2e\xb0\x7d\x80\x5c\x78\x08\x0a\x56\xeb\xfe\xa2\x89\xf5\xd9\x75\x16\x6d\xfd\x52\xc9\x32\xec\xc4\xbb\xbd\x97\x7b\x5b\x19\x8d\x8b\x1f\x80\x4b\x57\x34\x65\xfb\xf5\x7e\x2a\xf6\xe5\xfb\xd6\xad\xf6\x9d\x0e\x54\xbf\x66\x45\xe5\xab\xb7\xba\x20\xa0\x5e\xc5\xa4\xd6\x6b\xd0\x25\x75\x85\x9f\x2e\xcb\x92\xaa\xdb\x01\xe1\x5d\xcd\x07\x09\x3f\xec\xd5\xfa\xba\x3c\xc6\xc7\xad\xab\xf6\x18\x9f\xf7\xa7\xd0\xb9\xe3\x36\xfb\xaa\xde\xeb\x44\x9d\x7e\x43\x30\xd4\xcb\x34\x85\xc0\xe3\xea\xe1\x8e\xf6\xbb\x04\xe8\x16\x6f\xfd\x00\xb0\x7e\xbb\xbe\x7b\x8b\xd5\x2c\x49\xb0\xfa\x00\xf7\xde\xb5\x1e\x7a\xf9\x01\xdb\x61\xf9\x87\x59\x6e\x78\x85\xe8\x5b\xef\x9b\xae\x17\x9b\x8c\xb3\x7a\xd0\x42\xf2\x5d\x42\x68\x70\x35\xda\x0c\x9e\x47\xae\x4d\x5b\x40\x01\x04\x16\x12\xbd\xc5\xab\xab\x6d\xbd\x86\x0d\xe8\x5b\xd7\x7d\xb0\xc6\x03\x6b\x60\x5f\xf7\x6f\xbe\x22\x22\xcb\x67\xcb\xf4\x92\x19\x0d\x5c\xbb\x4a\x3c\x67\x73\x57\xf7\x00\x63\xf5\x96\x56\x7c\x1a\xad\x1e\x78\x6c\xc0\x20\xde\x73\x36\xa7\xcb\xc2\xc0\x1c\x64\x6f\x96\x37\xc9\x6b\x1c\xc5\x30\xa8\xaf\xe0\x62\xe2\x2f\x6e\x5d\x9d\x0a\xa3\xb5\x53\x19\x66\x99\xa8\x65\x65\x1c\x0a\x54\x32\x1e\x31\xa5\x52\x38\xe8\x95\xf4\x92\x85\xe9\x82\x0a\x1b\xb9\x8c\xc6\xa3\x5c\x12\x88\xff\xda\xd0\xef\xa8\x47\x24\x75\x32\xc5\xac\x0f\x8c\xca\xe0\x53\x72\x22\x0d\x9f\xdf\x86\x69\x4c\x5c\x86\x46\x32\x3d\x7a\x75\x74\x72\xde\x79\x3f\x7f\x71\xf6\x06\xda\x60\xbf\x3f\xdf\x21\xf3\xd2\x24\x2f\xa0\xc7\x79\x18\xfc\x10\xf4\xf8\xf9\x4e\x1a\x8d\x47\x2b\x8c\x30\xc3\x29\x6e\x46\x35\x4f\x9d\xf3\x59\x3b\x8e\xfa\xa3\xa3\x2b\xb4\x21\xa4\x4e\x8b\x83\x8d\x6d\x4e\xd8\xf5\xb3\x16\x65\x68\x0f\xd5\xd1\xc3\x19\xb8\xe0\xf7\x1b\x9e\x65\x05\xbb\xa6\xaa\x66\x11\xb6\x19\x3c\x82\xe1\x91\xd0\x46\x2d\x4b\x26\x4c\x97\x6d\x7b\xf3\x14\x93\xee\x7e\x64\xad\x33\x37\xd6\x08\x78\xe3\xd7\xf6\x64\x8c\xf3\xf5\xb8\x8e\xce\x17\x32\xc7\x40\x8c\x13\x19\x82\x05\x9e\xd6\xdf\x05\xc2\x16\xd5\x3b\x5c\x7d\x5f\x2c\xa0\x23\x45\x4c\x9a\xce\xe2\x7a\xc5\x8e\x49\x1d\xa9\x81\x6a\x07\xd3\x21\xc6\x8d\xd8\xb0\x7a\x9c\x9d\x1e\x4e\xf1\x2e\x29\xac\xa5\xf1\xac\xda\x1d\x45\xe3\xd1\x25\x05\x6e\xf5\x45\x7e\x62\x9b\x9d\x52\x45\x71\x27\xad\xef\x56\xb6\x6b\x5b\x0e\xb4\xf0\x86\x1b\x1a\xdb\x81\x73\x6b\xc9\x67\x9e\x05\xb6\xd5\xe1\x25\x8d\xe2\xda\xe5\xb7\x73\xb5\x6a\xbf\x9f\x1c\x2e\x28\x17\x9f\x0b\xaa\x6e\x5b\xf5\x36\xc7\x4b\x51\x91\xb3\x81\x76\xb0\xaa\xae\x56\x6b\x6d\xea\x6c\x26\x58\x82\xdd\x85\xe3\x6a\xd5\x28\x7a\x77\xd7\x8e\xf7\x92\xc0\xbb\x80\xd5\xaa\xbd\x00\x59\xd3\x78\x37\x78\x77\x87\x6e\xc0\x69\xe1\x3c\xd0\x28\x8a\x3b\xd2\x4f\x8d\x62\xb4\x7c\xa4\xf8\xeb\x8d\xfe\xa4\xf2\xf7\x62\x1b\x3d\x20\x55\xb3\xe4\x8c\xe5\x5c\x1b\xe8\xd5\xc8\xcf\xab\x8a\xa9\x97\x5c\x69\x73\x48\x4b\x56\x1c\x52\xcd\x48\xb7\x81\xc3\x41\x03\x92\x98\xb4\xb0\x1a\x0d\xa4\xac\xb8\x98\xba\xcd\x35\xa9\xaf\x8a\xa9\xd0\xd7\x4c\xd9\xcd\x5a\xa5\xe4\x8c\xe9\x98\x70\x43\xb4\x91\x95\xbb\x5a\x16\x39\xa1\x9a\x68\x29\x05\xa1\x96\xce\xb7\xd5\x8b\xa5\xd1\x04\x02\x9f\x89\x4f\x78\x69\x00\x6b\xdf\x5b\x90\x8d\x9a\xa4\x98\x5a\xd7\xd7\xad\x46\x1d\x5d\xda\xdc\xa2\x2e\x73\x08\x88\x4e\xad\x60\x53\x5c\x06\x43\xb8\x27\x6f\x38\xf1\xd4\xde\x94\xd7\x9d\xd9\xaa\xc3\x05\x4b\x2f\xcf\x98\xae\xa4\xd0\xec\x62\xfa\xe2\xec\x17\x47\x27\xaf\xa2\x7b\x53\x61\x9a\x94\x97\x5a\xe8\x96\xa0\xed\xf6\xbd\x65\xa8\x10\x75\x98\x5f\x30\x93\x1c\x43\x53\x11\x06\x26\xad\x20\xb2\xe9\x53\x1e\x36\x04\xfc\xfd\x9a\xc3\x14\xde\xcc\x29\x66\x96\x4a\xb8\xe8\x3e\x84\x55\x1b\x8c\xda\xd0\xaa\xbb\x69\xee\xb0\xf5\x3c\x1a\x69\xad\x9b\x09\x0b\x51\x2f\x5b\x93\x09\xb1\x49\x10\x36\xf1\x01\xd7\xd9\x9e\x1a\x0f\x75\xc4\x36\x05\x02\x3a\x2e\x71\xf8\xdd\x8d\x9e\xdf\x08\xe0\xa5\x48\xd9\x6c\x07\x90\xdc\x66\x68\x4e\x02\x7b\x87\xed\x2a\xd5\x4b\xe8\x1d\x6b\x92\x23\x91\xb1\x9b\x68\x4b\xd3\xb4\xcc\x0a\x2e\xd8\x66\x0e\x87\x96\x60\x1b\x0f\xf8\xc1\x8b\x2d\x3c\x4e\x2d\xc1\x36\x1e\xfa\xb6\x9c\xc9\x62\x33\x8b\x29\xd6\x6f\xe3\x60\x33\xea\x36\x32\xc0\xf8\x75\x14\x3d\x34\x82\x5b\x7e\xc8\xc6\x6b\x2d\x62\xef\x61\x55\x67\xd6\xb4\x71\x85\x9c\x2c\xac\x9f\x8a\xcc\x42\xab\xa1\x8c\x49\x59\xa3\xac\xcf\xd6\x30\x55\x72\x41\x0d\xcb\x70\x17\x05\x0c\x87\x1c\x56\x77\xd2\xfb\x2b\x96\xee\x94\x6b\xb0\xfd\x0a\x0c\x34\x5f\x16\x53\x23\xab\x30\x1a\xaf\x5c\x4e\x40\xe7\x72\x1f\x31\x6c\x5f\xc8\x9d\xdd\xb4\x39\x7c\x37\x15\x98\x00\x60\x53\x7e\x00\xf4\xf0\x90\x3c\x2d\x0a\x79\x7d\x24\xe6\x12\x50\xac\xaf\xb9\x49\x17\xe4\x49\x9d\x19\x74\x37\x1e\xa5\xe0\xa0\xdd\x14\xd8\xb7\xb3\xc6\xd6\x75\x38\xa0\x71\xc2\xc8\x93\x43\x5a\xd0\x16\x6a\xdf\x9f\x25\x86\xf4\xa1\x2d\xc4\xbf\xa4\x4a\x34\xc4\x36\x9a\xb5\x99\x1a\xb7\xb1\x21\x6e\xc1\xfc\x04\xb7\xf3\xfb\x84\x5d\x1f\xcb\x7c\x5e\x1a\x67\x31\xd8\x3c\xe3\x2d\x6a\xd4\x26\x44\x46\x27\xec\xfa\x25\x2f\xc0\x1d\x7a\x7f\xe0\xfb\x8a\x7a\x4c\xbb\x4e\x43\x5f\xa5\xed\x3b\x46\xa4\x85\x0c\x87\xa9\xa1\x65\xe5\xfd\x0c\x14\x68\x28\x78\x29\x55\x49\x4d\xe8\x1c\x12\x26\x0e\x41\x1d\xba\x26\xeb\x15\x6d\xe1\x89\xbc\x0e\xa3\xe4\x48\x84\xf8\xf6\x92\xdf\xb0\xec\x57\x52\xb0\x30\x38\x9c\x9e\x07\x31\xf9\x74\xf7\xc9\xa7\xbb\x4f\x7e\x0a\xf3\x6f\x15\x93\xe0\xe3\xdd\xdd\x4f\x77\x76\xf7\x76\x76\x3f\x3e\xdf\xfb\xc9\xfe\xee\x27\xfb\xbb\x3f\x49\x76\xf1\x5f\xb0\x5d\x78\x03\xd3\xa8\x2d\xf0\x76\x72\x38\x4a\xe0\x8d\x17\x54\xb9\x93\xd1\x21\x96\x45\x63\x2f\xbe\x25\x1e\x3f\x30\x19\x7b\x3c\x99\x74\x6f\x8d\xf1\x32\xdc\x2c\xea\x0b\x71\xa5\xfd\x41\x72\xf8\xf2\x3c\x1e\xbe\x30\x27\x72\x0e\xac\x77\x3a\x29\x3d\x1b\x6f\xd1\x71\x66\xad\x5d\x5e\xd7\x57\xe1\xee\x86\x31\x22\xcd\xcd\xf0\xb4\x73\x1d\xde\x5c\xb1\x76\xae\xc4\xa5\xc2\x35\x67\xed\x4e\xf1\xa0\xbe\x53\xec\xde\xaa\xbb\xe5\xb5\x95\x70\x8d\x1b\xfc\xf6\x3b\x8c\xc7\xa9\x62\xc6\xdc\x9e\x2a\x48\xbf\xd8\x94\x32\xe1\x06\x43\xf0\x22\xb6\x3f\xec\xca\xbb\xb2\x57\xa1\x43\x77\xf7\xed\x04\x71\xec\xf6\xf1\x9c\x5d\xd5\xf6\x54\x01\xc1\x0b\x9c\xa7\x83\xaa\x77\x12\xdc\x51\x8a\xd4\xdc\xc4\xbd\x62\xb0\x41\x9d\x7f\xd2\xbd\x53\x1d\x22\x3d\x12\x9a\xa5\x4b\xc5\xd0\x56\xeb\x0a\x0d\xeb\xb3\x1a\x8f\x36\xe6\x38\xb4\xf3\xf8\xbb\x32\xb6\xca\xef\x15\xb2\x47\xfb\xa1\x52\x3e\xd8\xe6\xcd\x5c\x7b\xea\x13\x26\x30\xbb\x95\x89\xcc\xee\x82\x0b\x3a\x63\x05\xb9\xa2\xc5\x12\xf6\xcc\x98\x52\x08\x73\x0d\x0b\x48\x45\xb9\x6a\xe6\x5a\x9d\x72\xa1\x5b\x73\xa7\x61\x8b\x29\x19\x9a\xbc\x7b\x5f\xd3\x25\x9f\xb1\xdb\x5f\x00\xa3\xd8\x76\x83\xcf\xba\x0e\xdd\x44\x83\xb4\xa0\xb8\xe5\x74\x80\x3d\xea\x77\xfb\x05\x13\x96\x79\xd4\x7a\x7c\x3f\x1e\xcd\xa5\x22\x1c\x46\x67\xf7\x67\x84\x7f\xb4\x47\x7e\x4e\xa0\xba\xd5\x55\xf4\x33\xc2\xc9\x47\x07\xe4\x63\xb4\x66\xcd\x15\xd5\xb7\x6c\x62\xb2\x96\x48\xd2\x6a\xff\x8e\xbf\xef\x88\xfe\x8e\x7f\xb4\xf7\x3e\x8a\xda\x43\x80\x5c\x5a\x66\x76\xd1\x45\xc2\x35\xa1\xfd\xcb\x63\xc8\xc8\x05\x87\xe8\x52\x07\xdb\xe9\x03\x24\xb5\x24\xf1\xda\xa0\x00\x63\xaa\x18\xc1\xd8\x73\x6b\x04\x20\xd9\xa0\xd3\x23\xc4\x32\x52\x83\xeb\xb8\x2b\x69\x25\xfd\xbc\x2c\x24\x35\x9f\x7e\x52\xdf\x62\x5b\x5b\x90\xc1\x11\xa8\x77\x1d\x43\x57\x73\x6b\x99\x44\x16\x34\x70\x50\x29\x2a\xe2\x07\xb6\xaf\x78\x23\x54\x3d\xa3\x90\x5b\x4f\xae\xd0\xb2\x6a\x75\x01\x93\xe4\x39\xd3\xa9\xe2\x15\x26\xe2\x42\x2f\xc3\x73\x05\x1a\xf9\xed\xa2\xcd\x29\x6a\x06\xa9\x65\xa6\x3b\x27\xc7\xbe\xb7\xf8\xaa\x56\x37\x4c\xdb\x84\x11\xb1\xab\x60\x0b\xb8\x49\x92\x6c\x53\x30\xf1\x00\xeb\xcd\x0b\x57\xd1\x41\x52\xb3\x72\xa6\x1b\xfb\x7f\x9a\x65\x61\xc6\x0a\x43\xc9\xdc\x5a\x29\xb2\xdd\x38\xc1\x13\xa8\x1f\xca\xdb\x8a\x09\xb6\x5a\xb3\xe3\xba\x48\x49\x92\x44\x51\x0b\xbc\x75\xa8\xb8\x0b\xdf\xa6\x58\xb1\x54\xaa\x8d\x18\x5e\x78\xba\x16\x3a\x9b\xb6\x0d\x3e\x6b\xba\x01\x84\xb6\x12\x41\x3c\x46\x1f\x86\xd2\xde\x35\xee\xe3\x70\xda\x48\xd9\x16\x6f\x18\xab\x4d\x47\xdf\x21\x5a\xeb\x4e\xee\x6a\x69\xf6\x1b\xfb\xb6\x30\xbb\xe8\x92\x3f\x0c\xb5\x5d\x75\x37\xe1\x76\xb1\x15\xb7\x8b\x2d\x32\xbc\x9d\x61\x36\x7a\x68\x57\x91\x36\x7a\x17\x49\x03\x92\x33\x04\xd3\x06\x08\x5f\xd9\x75\x63\x33\x84\x17\xc3\x10\xc6\x4b\xae\x2e\x7c\x6d\xd1\x76\xe8\xe6\x40\x83\x31\x23\x08\x67\xda\xa5\xd1\xfa\x5f\xb7\xa7\xec\x2e\x80\x98\x76\xff\x34\xcb\x5a\x40\xb7\xbd\x34\x20\x47\x8e\x64\x00\xe1\x48\x58\xa3\x7b\x10\xda\xa3\xd2\xe0\xe7\x0d\x4f\xe0\x63\xcc\xe4\xcd\xd2\xb0\x9b\xf1\xc8\x89\x53\xd2\xea\x5d\xd3\xe4\x39\xd7\x86\x8b\xd4\xbc\x77\x46\xee\xcf\x8a\x56\xc6\xc7\xe3\x66\x84\x55\xc7\xeb\x31\x3c\x13\x2c\xf3\xef\x70\x16\x60\x07\x77\x28\xc1\xbe\x1f\xa2\xd2\xdc\xec\x93\x1f\x35\x86\x81\x0c\x40\x6b\x9a\xfd\x7b\x6c\x73\xb7\x6a\xcd\x9b\xbc\xe9\xe0\x61\x73\xa6\x31\xc8\xa6\xf9\x92\x6f\x9d\x2f\xf9\x86\xbe\xa7\xcc\x0c\xcc\x93\x3c\x29\xcd\x4d\x72\x2c\xd3\xcb\xb0\x4e\xc2\xb7\x65\x9f\x8b\xc2\x95\xe6\x89\xc5\xb4\x6d\x1e\x6d\xe0\x3f\xbc\x8a\x3c\x8a\x7f\x9e\x58\x03\xb7\x6c\x8b\x31\x31\x13\xe6\xcd\x24\x4c\x5e\x7c\xb1\xe4\x57\xb4\x60\x70\x3e\x79\x4f\x3e\xb2\x8b\xd0\x26\xa9\xda\x92\xf7\x04\x7b\x7c\x5f\x07\x16\x00\xd0\x18\x41\xf2\x0d\x3d\x4b\xde\xf5\x2c\x43\xb1\xd5\xfa\xeb\x4b\x70\x0d\x3e\xea\xde\xbf\x59\x20\x30\xee\x2e\x26\x4d\x05\x4f\xeb\x33\xed\x12\x08\xfd\x9d\x20\x5c\x90\x1a\x69\xef\x0a\x05\x2d\xec\x09\xd2\xef\xb0\x37\xb1\x0e\xd7\x02\x3e\x91\xbd\x5b\x41\x42\x1b\x4b\x6a\x4b\x72\x57\xc3\x10\xd8\x0e\x1d\x73\xf1\x9e\x0b\x44\x61\x6a\x4e\x53\x06\xb3\x0a\x22\x3b\xe4\xc9\x3a\xdb\xb9\x8c\xbd\xf0\xad\x4e\x7d\xcc\x9e\x84\x8a\xe9\xaa\xcb\x89\x29\xd5\x3a\x19\x3b\xb8\xb5\xc2\xb3\xe0\x1a\x94\x4d\xf6\x46\x7d\xc3\xe8\x67\xa4\x7b\xfe\xb4\xd1\x37\x0c\xd9\xd8\xb0\x8f\xbb\xfa\x73\x9f\xa4\xf8\x2b\x60\x10\x39\x79\xb9\x2c\x8a\x37\x58\x02\xdf\x65\x81\xe5\x83\x98\x60\xd0\xc6\xd0\xf4\x32\x88\x9d\xaf\xb3\x9f\xb5\x25\x53\x28\x0c\x23\x3c\x52\xdb\x8f\x4a\x0e\xdc\xc5\xb4\xbf\x27\xc5\x0f\x69\x13\x3f\x40\x9e\xe7\x3e\xf9\xe1\x15\xf0\xc5\x76\x70\x0e\x5e\xe1\xd9\xd9\xaf\x8e\x2e\x36\x89\x87\x44\xc5\xbe\x40\xd7\xd6\x85\xcb\xda\x4d\xce\x46\xbc\x68\xa4\x7c\x14\x60\xd6\x98\x6f\x44\x8c\xa5\xbc\x17\x32\x5a\x5d\x75\xc7\x54\x6b\xd7\x1e\x5b\x5a\x2e\x1d\xcc\x74\x19\xaf\x81\xc6\x56\x37\xa8\xf9\xff\x8b\x11\xad\xae\xc0\x9c\x0e\x22\xdb\xff\xc4\x84\x05\x90\x7b\xff\x8c\xdd\xc2\x4e\xc7\xc5\xc5\xf0\xab\x68\x72\xc9\x6e\x3d\x6c\x1c\x1d\xe1\x99\x4e\xc6\xa9\x14\xda\xf8\x22\xdb\xf4\x80\x04\x37\xfe\x9b\xc5\x1d\x9e\x05\xc8\xfc\x1a\xbf\x16\x70\x54\x24\xe7\x57\x0c\xb6\x52\x0d\x2b\x1f\x0c\xb0\xb9\x1d\xa9\x2c\x61\x47\xd5\x0a\xab\xd9\x03\x6c\x0d\xd5\x39\x17\x19\xe1\x86\x70\xe1\x37\x51\x5c\xb8\x46\xb5\xc8\x3e\xb6\x67\xbf\x91\x27\x39\x33\x1a\x9a\xcc\x68\x7a\xe9\x53\xd6\x17\xf8\x99\x43\xa3\x98\xbd\xff\x72\x98\xef\x88\x3c\x1c\xc2\xeb\x15\x00\x5e\xca\xcc\x7d\x6e\xe5\xe5\x48\x20\x77\xe6\xc8\x49\xe7\x08\xdd\xa7\x10\x65\x06\x1f\x11\x64\xc9\xa1\xac\x6e\x43\xbb\x93\x81\x48\x43\x99\x25\xaf\x98\x09\xdb\x46\x8d\x22\x88\xf3\xed\x22\x20\x67\x75\x0a\xc7\xbb\xf7\xb3\x5b\xc3\x62\xb2\xf7\x29\x0c\xff\x05\x74\x7c\x40\x14\x15\x59\x02\xdf\x6f\x84\x33\x28\x2d\xb3\x64\xda\x63\x06\x5b\xb3\x9b\xe4\x85\x00\x70\x9d\x4b\x17\x9e\x98\xb9\xf8\x83\xbf\x40\x83\x56\xaf\xed\x57\x20\xe8\x6c\x6a\x75\x4e\x21\x78\xd3\xe3\x37\x24\xf1\xbb\x5d\x0c\x69\x38\x50\xd6\xcd\x4f\xd8\xf5\x80\x31\x80\x45\x34\xee\xe1\x70\x6d\xe1\xdb\x0c\x1b\xbb\xf4\x21\x78\x6a\x8f\xb5\x81\x4b\xf8\x3d\x5a\xd2\xfa\xab\x99\xf7\x52\xbd\x99\xbc\x86\xc4\x68\xdd\xf9\xbb\xda\x75\xef\xbf\xd9\x68\xd6\xff\xaf\x19\x6c\xdd\xc7\x7f\xbf\x5c\x3a\xda\x89\xdc\x6d\x70\x78\x3f\xea\xe9\x71\xd7\xee\x75\x9f\x68\x1d\x93\x14\xb6\xfa\x5d\x9b\x6a\xed\x47\x37\x8c\xa2\x95\x37\x2c\x1e\xc2\x7a\xfc\xda\x47\xb1\xbe\x52\xe3\xd1\x00\x5c\x9a\x9d\xaa\x26\x4f\x7a\xcc\x22\x52\xf7\x3a\xe4\x4b\x9c\x7e\x3a\x49\xcd\xcd\xa0\x0b\x6f\xd2\x16\x01\x04\xee\x48\xb1\x36\x6d\x30\xb0\xa3\xfb\xf3\x04\xfd\xa3\xb4\x27\x6a\xac\xe4\xaa\xc9\xca\xdd\x9c\x50\x67\x75\xd9\xd0\x55\x58\xa7\xb7\xf6\x02\x59\x4d\xfa\xd0\x7a\xb0\xe0\x7f\xc1\x7c\x9c\xb1\x9c\x0b\x70\xbb\xcd\x75\x9a\xfd\x92\xb0\xaa\x4f\xb0\x03\x3b\xb3\x11\x18\xcc\xde\x86\xe0\x9a\x7e\x28\x33\x7b\x1e\xf5\x21\xe1\x56\x5a\xba\xb6\x97\x63\xdb\xb6\x13\xc0\x2d\x88\x71\x14\x22\x0c\xd2\xe1\x27\x8d\xde\xae\x8f\x6c\xef\x43\x29\xa8\xd0\x94\x8b\x94\x85\xa8\x64\xe4\xfe\xf4\x80\xb6\x97\x41\xce\xf4\xb5\xa6\x8d\xc7\x71\xc3\xb8\xee\x6f\x5a\x68\x73\x0e\xe6\xdb\x40\xda\xba\x53\xfa\x06\x50\xfb\xde\x39\xb2\x61\x7c\xf5\x90\xd5\xec\xe7\xfe\xef\x00\xab\x86\x54\xe3\xdb\xfe\x67\x00\x37\x14\xc0\x94\x66\x4d\x00\x00"
 
 func tmplMain_svcTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -175,12 +198,12 @@ func tmplMain_svcTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/main_svc.tmpl", size: 6052, mode: os.FileMode(438), modTime: time.Unix(1596617429, 0)}
+	info := bindataFileInfo{name: "tmpl/main_svc.tmpl", size: 19814, mode: os.FileMode(438), modTime: time.Unix(1792369583, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplPartialsAlias_typeTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xae\xce\x4c\x53\x48\x2f\x51\xd0\xc8\x49\xcd\x53\x50\xd1\x54\x30\xa8\xad\xe5\xaa\xae\x2e\x4a\xcc\x4b\x4f\x55\x50\xc9\xd4\x51\x29\x53\xb0\xb2\x55\x50\xa9\xad\xe5\x2a\xa9\x2c\x48\x55\xa8\xae\x56\x29\xd3\xf3\x4b\xcc\x4d\xad\xad\x85\xb0\x43\x2a\x0b\x52\xc1\x3a\x52\xf3\x52\x10\x34\x20\x00\x00\xff\xff\xa0\x3f\x01\xa9\x55\x00\x00\x00"
+var _tmplPartialsAlias_typeTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xaa\xae\xce\x4c\x53\x48\x2f\x51\xd0\xc8\x49\xcd\x53\x50\xd1\x54\x30\xa8\xad\xe5\xaa\xae\x2e\x4a\xcc\x4b\x4f\x55\x50\xc9\xd4\x51\x29\x53\xb0\xb2\x55\x50\x01\x0b\x66\xa6\x29\xe4\xa5\x2a\xa8\x94\xe9\x39\xe7\xe7\xe6\xa6\xe6\x95\x28\x28\x29\xd5\xd6\x56\x57\x23\x04\x40\xbc\xd4\xbc\x94\xda\xda\x92\xca\x82\x54\x05\xb0\x8c\x5f\x62\x6e\x6a\x6d\x2d\x84\x1d\x52\x59\x90\x0a\x36\x08\xac\x06\x46\x03\x06\x00\x4d\xb2\x22\x3e\x81\x00\x00\x00"
 
 func tmplPartialsAlias_typeTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -195,7 +218,7 @@ func tmplPartialsAlias_typeTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/alias_type.tmpl", size: 85, mode: os.FileMode(438), modTime: time.Unix(1561361816, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/alias_type.tmpl", size: 129, mode: os.FileMode(438), modTime: time.Unix(1792369357, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -220,7 +243,7 @@ func tmplPartialsConstantsTmpl() (*asset, error) {
 	return a, nil
 }
 
-var _tmplPartialsEndpoint_funcTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x91\x31\x6b\xc3\x30\x14\x84\xf7\x42\xff\xc3\x0d\x1e\x6c\x10\xa2\x73\x20\x53\xa0\x63\x87\x40\xbb\x16\xe1\xbe\x04\x81\x2a\xbb\x92\x6c\x02\x8f\xf7\xdf\x8b\x2c\xdb\xa4\xf6\x90\xa1\x5e\x8c\xc4\x71\xf7\xdd\xe9\xf9\x09\x00\x02\xa5\x21\x78\x5c\x06\xdf\xd6\x6d\xba\xa1\xed\x7c\xa2\x5b\xd2\xa7\xf2\x57\x00\xb3\xbd\xe0\x9a\x50\x3b\xf2\xd0\x67\xfa\x19\x28\x26\xfd\x61\x42\x6c\xf0\x22\x12\xca\x05\x33\xb9\x48\x22\x9f\xcc\xe4\xbf\x44\x60\x7d\xa2\x70\x31\x2d\xb1\x34\xa8\xef\x4e\x0a\x14\x42\x17\x1a\x70\x21\x58\xbe\xc7\x39\x38\x1c\x31\xc7\xe9\x9a\x79\xd5\xbc\x99\x6f\x12\x69\xb6\x76\x13\x07\x73\x30\xfe\x4a\xa8\xac\xaa\xc6\x6c\xa0\x4f\xc6\x39\xeb\xaf\xfa\x4c\x71\x70\x29\x66\x49\x35\xce\x1e\x13\x83\xef\x32\x84\x89\x09\x95\x45\xb5\xd5\x37\x22\x6a\xf5\x2e\x55\x0f\x47\xc4\xb1\xd5\xcc\xab\xb6\xb8\xe5\x41\xd5\x1e\xe0\xbe\x9a\x48\x6e\xa4\x1f\x20\xfc\x19\x63\x97\xbf\x29\x3e\xbf\xe8\xb4\x4f\xec\x3b\x1f\x69\x71\xc6\x8e\x65\xd7\x2e\x9b\xa6\xee\xbd\xef\x29\xbc\xda\x90\xf3\x17\xae\xc3\xbf\x67\x12\x05\x6f\x5d\x81\x95\xdf\x00\x00\x00\xff\xff\x32\x8c\x9b\xf7\x7e\x02\x00\x00"
+var _tmplPartialsEndpoint_funcTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x91\x31\x6b\xfb\x30\x10\xc5\xf7\xff\xa7\x78\x83\x06\x1b\x84\xf8\xcf\x81\x4e\x86\x8e\x1d\x02\xed\x5a\x84\x7b\x09\x02\x45\x4e\x25\xd9\x04\x8e\xfb\xee\x45\xb2\x63\x92\xb8\x90\xa1\x5e\x0e\x8b\xa7\x77\xbf\xf7\xf4\x0f\x00\x22\xe5\x31\x06\x1c\xc6\xd0\x37\x7d\xbe\xa0\x1f\x42\xa6\x4b\x36\xdd\x3c\x35\xc0\xec\x0e\x38\x66\x34\x9e\x02\xcc\x9e\xbe\x47\x4a\xd9\x7c\xd8\x98\x5a\xfc\x17\x89\xf3\x01\x33\xf9\x44\x22\x9f\xcc\x14\xbe\x44\xe0\x42\xa6\x78\xb0\x3d\xb1\xb4\x68\x6e\xfe\x34\x28\xc6\x21\xb6\xe0\x0a\x70\xfd\x9e\xaf\xc1\xee\x05\xcb\x36\xd3\x30\xaf\x9a\x37\x7b\x22\x91\xf6\xc1\xad\x52\x30\x47\x1b\x8e\x04\xe5\xb4\x9a\xca\x7d\xd3\x59\xef\x5d\x38\x9a\x3d\xa5\xd1\xe7\x54\x24\x6a\x5a\x2c\x2a\x42\x18\x0a\x83\x4d\x19\xca\x41\x3d\xea\x5b\x11\xbd\x7a\xd7\x51\x5c\xd3\xd4\x1b\xe6\x55\x3b\xbb\x95\x3a\xf5\x16\xe0\x36\x99\x48\x09\x64\x9e\x20\xdc\x75\xb1\xd9\x7f\x9f\x7b\x79\xce\xda\x4e\x3a\x0f\x21\xd1\xd5\x18\x1b\x14\xf5\x5b\x19\x79\x78\x3f\x9f\x29\xbe\xba\x98\x72\x67\x4f\xe4\x3b\x9b\x08\x2b\xdf\xee\xcf\x75\x89\x46\x70\xbe\x42\xcb\xcf\x00\xea\x96\x8b\x91\x82\x02\x00\x00"
 
 func tmplPartialsEndpoint_funcTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -235,7 +258,7 @@ func tmplPartialsEndpoint_funcTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/endpoint_func.tmpl", size: 638, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/endpoint_func.tmpl", size: 642, mode: os.FileMode(438), modTime: time.Unix(1792360439, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -340,7 +363,7 @@ func tmplPartialsImportsTmpl() (*asset, error) {
 	return a, nil
 }
 
-var _tmplPartialsInterfaceTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\xcc\x41\xca\xc2\x30\x10\xc5\xf1\x7d\xa1\x77\x78\x84\x2c\x3f\x72\x80\x0f\x5c\xb9\xd6\x2b\x48\x68\x5f\x34\x60\xa6\xa5\x8e\x05\x19\xe6\xee\x12\x41\x77\xef\x2d\x7e\x7f\xb3\x5a\x20\x44\x3a\x2e\xad\x51\x14\x21\xb8\x9b\x7d\x6f\xdf\x94\xd9\x5d\x5f\x2b\x61\x96\xce\xb9\xd1\x1d\x55\x94\x5b\xc9\x13\x61\xe3\x60\xb6\x65\xb9\x12\xb1\xfe\xc5\x1d\xff\x07\xa4\x13\xf5\xb6\xcc\x8f\xae\x95\x6d\xbd\x67\x25\xc2\xcf\x5c\xca\x53\xa6\x80\xb8\xbb\x77\xfc\xe9\x8f\x83\xbf\x03\x00\x00\xff\xff\x55\x08\xe2\x86\x8b\x00\x00\x00"
+var _tmplPartialsInterfaceTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x5c\x8d\x41\xca\xc2\x40\x14\x83\xf7\x3d\xc5\x63\x98\xe5\x4f\x0f\xf0\x83\x2b\xd7\x7a\x05\x19\xda\x8c\x0e\x38\xaf\xa5\x3e\x07\x24\xbc\xbb\x4b\x51\x2b\xb8\x4b\x42\xf2\x85\x2c\x59\x14\xd2\xef\xa7\x5a\xa1\x26\x21\xb8\x93\x1f\xbb\x6a\xe8\xe8\x6e\x8f\x19\x42\xf6\xc7\x54\xe1\x2e\x45\x0d\x4b\x4e\x03\x84\x1d\xb9\x24\x3d\x43\x62\xf9\x8b\x4d\xfe\x77\xd2\x1f\x60\x97\x69\xbc\xad\xe3\x17\x3d\xb6\x1f\xfe\x37\xd8\x1e\x48\x43\x9d\xaf\xc9\x20\x61\xc3\x9f\xf2\x5d\x87\x20\xb1\xb9\x77\xef\x5e\xe7\xcf\x01\x00\xb4\xca\x7d\xe5\xb4\x00\x00\x00"
 
 func tmplPartialsInterfaceTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -355,12 +378,12 @@ func tmplPartialsInterfaceTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/interface.tmpl", size: 139, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/interface.tmpl", size: 180, mode: os.FileMode(438), modTime: time.Unix(1792360393, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplPartialsInterface_funcTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xae\xd6\xf3\x4b\xcc\x4d\xad\xad\xd5\xa8\xae\x2e\x49\xcd\x2d\xc8\x49\x2c\x49\x55\x50\x4a\x2b\xcd\x4b\x8e\x2f\x48\x2c\x4a\xcc\x4d\x2d\x49\x2d\x2a\x56\x52\xd0\x0b\x80\x73\x6a\x6b\x35\x31\x94\x16\xa5\x16\x97\xe6\x94\x80\xd4\x05\x41\x58\xb5\xb5\x80\x00\x00\x00\xff\xff\xc1\x2e\xd4\xb5\x59\x00\x00\x00"
+var _tmplPartialsInterface_funcTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xaa\xae\xd6\xf3\x4b\xcc\x4d\xad\xad\xd5\xa8\xae\x2e\x49\xcd\x2d\xc8\x49\x2c\x49\x55\x50\x4a\x2b\xcd\x4b\x8e\x2f\x48\x2c\x4a\xcc\x4d\x2d\x49\x2d\x2a\x56\x52\xd0\x0b\x80\x73\x6a\x6b\x35\x31\x94\x16\xa5\x16\x97\xe6\x94\x80\xd4\x05\x41\x58\xb5\xb5\x80\x01\x00\xc1\x2e\xd4\xb5\x59\x00\x00\x00"
 
 func tmplPartialsInterface_funcTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -375,7 +398,7 @@ func tmplPartialsInterface_funcTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/interface_func.tmpl", size: 89, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/interface_func.tmpl", size: 89, mode: os.FileMode(438), modTime: time.Unix(1792360393, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -400,127 +423,167 @@ func tmplPartialsInterface_stubTmpl() (*asset, error) {
 	return a, nil
 }
 
-var _tmplPartialsStructTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\xcc\xb1\x0a\xc2\x30\x18\x04\xe0\x5d\xf0\x1d\x8e\x92\x51\xf2\x00\x82\x93\xbb\x93\xb8\x07\xfd\x2d\x1d\x12\x4b\xfa\x5b\x28\xc7\xbd\xbb\x10\x09\xdd\xbe\x83\xbb\x23\xa7\x37\x8a\x21\x5e\x3f\x39\x5b\x71\x0c\x83\x44\xf6\x28\x1d\x0f\xa4\x95\x97\xe4\xdb\x6c\x20\xe3\x2d\x65\x93\xb0\x78\xfd\x3e\x1d\x04\x59\x53\x19\x0d\x61\x3a\x85\x15\xe7\x0b\xe2\x23\xd5\xe5\x3f\x0c\x6b\xaf\x37\xdf\xb7\x79\x77\x1a\x1b\xdb\x37\xf4\x0b\x00\x00\xff\xff\x55\xe9\x71\x95\x88\x00\x00\x00"
+var _tmplPartialsProto_commentTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xaa\xae\x2e\x4a\xcc\x4b\x4f\x55\x50\xc9\xd4\x51\x29\x53\xb0\xb2\x55\x50\xa9\xad\xd5\xd7\xaf\xae\x56\x29\xab\xad\xe5\xaa\xae\x4e\xcd\x4b\xa9\xad\x05\x0c\x00\x72\x30\x69\x73\x24\x00\x00\x00"
 
-func tmplPartialsStructTmplBytes() ([]byte, error) {
+func tmplPartialsProto_commentTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_tmplPartialsStructTmpl,
-		"tmpl/partials/struct.tmpl",
+		_tmplPartialsProto_commentTmpl,
+		"tmpl/partials/proto_comment.tmpl",
 	)
 }
 
-func tmplPartialsStructTmpl() (*asset, error) {
-	bytes, err := tmplPartialsStructTmplBytes()
+func tmplPartialsProto_commentTmpl() (*asset, error) {
+	bytes, err := tmplPartialsProto_commentTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/struct.tmpl", size: 136, mode: os.FileMode(438), modTime: time.Unix(1566802589, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/proto_comment.tmpl", size: 36, mode: os.FileMode(438), modTime: time.Unix(1792359748, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplPartialsStruct_functionTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcf\xcd\x4a\xc7\x30\x10\x04\xf0\xbb\xe0\x3b\x0c\x39\xb5\x97\xe0\x33\xe8\xdd\x83\x7a\x2f\xa5\xdd\x48\x21\x49\x6b\xba\x39\x94\x65\xde\x5d\x62\xfd\x7f\x9d\x92\x19\x76\xf9\xb1\x66\x4b\x40\x16\xf8\xb7\x35\x25\xc9\x0a\xe7\x48\xb3\x4b\x24\x9f\x9f\xcc\x24\xcf\x64\xa8\x79\x42\x67\xe6\x3f\xb5\xd4\x49\xfd\xfb\x98\x84\xc4\xad\xf8\x3a\x36\x21\x7b\x98\xa9\xa4\x2d\x8e\x2a\x70\x4b\x56\x29\x61\x9c\x64\x68\xeb\x0e\x9e\x34\x9c\xe6\xaa\xe8\xe4\x07\xfe\x75\x9d\x0f\x38\xd7\xff\xb1\x2d\xb4\x8f\xc4\x5d\x4e\x7b\x09\xf8\x56\x74\x51\x32\xfc\x87\xec\x35\xea\xde\xe3\x85\x2c\xa2\xb5\xe4\x07\xac\x11\xc3\xd9\xbb\xeb\xf0\xdd\x05\xff\x0f\xf8\x1b\x00\x00\xff\xff\x25\x63\x5f\x34\xf5\x00\x00\x00"
+var _tmplPartialsProto_enumTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x74\x8f\xcd\x4a\xc4\x30\x10\xc7\xef\x7d\x8a\x61\x99\x63\xc8\x03\x28\x7b\xf2\xbe\x82\x82\x17\x11\x89\xee\xb8\x14\x37\x93\x92\xa6\x61\xcb\x30\xef\x2e\x69\xba\xd1\xa2\x9e\x32\xe1\xff\x31\xf3\x13\x49\xe4\x87\xb3\x4b\x04\xbb\x21\x86\x14\x5e\xdf\x83\xf7\xc4\x69\x07\xf6\xae\x4e\xaa\xc4\x93\x07\x11\x7b\x70\x9e\x54\x41\x3a\x91\xe8\xf8\x44\x80\xde\x20\xc3\xcd\x1e\xec\xfd\x90\xfa\xc0\xa3\x6a\x58\x06\x10\x41\xbe\xfa\xf7\xf5\xf7\xe4\xce\x13\xa9\xde\x76\x22\xc4\x47\xd5\xdf\x25\x0f\x34\x52\xcc\x74\x54\x8d\xeb\x54\x93\x8f\x29\xf6\x7c\xfa\x2b\xfa\x69\x30\x2f\xd1\xa5\x7c\x2c\xca\xbf\x3c\x98\xbf\x89\x44\x30\x6f\xce\xcb\xf6\x30\xf9\x37\x8a\x45\xea\x3f\x8a\xb7\x11\xc1\x73\x5b\x77\x31\x38\x97\x75\x3f\xe5\xea\xbf\xa8\x1a\x68\xd7\xe1\xbc\x29\x9f\xaf\xec\xab\xe1\x65\x7d\x1b\x8f\x76\x5f\x03\x00\x45\x10\xb3\x36\x88\x01\x00\x00"
 
-func tmplPartialsStruct_functionTmplBytes() ([]byte, error) {
+func tmplPartialsProto_enumTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_tmplPartialsStruct_functionTmpl,
-		"tmpl/partials/struct_function.tmpl",
+		_tmplPartialsProto_enumTmpl,
+		"tmpl/partials/proto_enum.tmpl",
 	)
 }
 
-func tmplPartialsStruct_functionTmpl() (*asset, error) {
-	bytes, err := tmplPartialsStruct_functionTmplBytes()
+func tmplPartialsProto_enumTmpl() (*asset, error) {
+	bytes, err := tmplPartialsProto_enumTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/struct_function.tmpl", size: 245, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/proto_enum.tmpl", size: 392, mode: os.FileMode(438), modTime: time.Unix(1792359748, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplPartialsVarsTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xae\xce\x4c\x53\x48\x2f\x51\xd0\xc8\x49\xcd\x53\x50\xd1\x54\x30\xa8\xad\x2d\x4b\x2c\x52\xd0\xa8\xae\x2e\x4a\xcc\x4b\x4f\x55\x50\xc9\xd4\x51\x29\x53\xb0\xb2\x55\x50\xa9\xad\xe5\xe5\xaa\xae\x56\x29\xd3\xf3\x4b\xcc\x4d\xad\xad\x55\x00\xb3\x43\x2a\x0b\x20\xec\xcc\x34\x05\x95\x32\x3d\x8f\xc4\xe2\xb0\xc4\x9c\xd2\xd4\xda\x5a\x5b\x88\x3c\x94\x57\x5d\x9d\x9a\x97\x02\xa7\x78\xb9\x34\xa1\x2c\x40\x00\x00\x00\xff\xff\x51\x62\x20\x15\x7e\x00\x00\x00"
+var _tmplPartialsProto_fieldTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x5c\x8e\x41\xcb\xc2\x30\x0c\x86\xff\x4a\x18\x3d\x8e\xee\xfe\x7d\xec\x24\x08\x5e\xf4\xa0\x78\x11\x91\x32\xa3\x0c\xda\xb4\xce\x0e\x56\x42\xfe\xbb\xb4\x1b\x32\x3c\xf5\xe1\xed\x93\x37\x61\x8e\xe8\x82\x35\x11\xa1\x0a\x83\x8f\xfe\xd6\x79\xe7\x90\x62\x05\x7a\x33\x93\x08\xb3\xde\x8e\xd6\x9e\x52\x40\x11\x60\xd6\x7b\xe3\x32\xb5\x99\x8f\xf8\x1a\x91\x3a\xcc\x5a\xff\x00\x7d\x08\xb1\xf7\xf4\x16\x81\x0b\xf3\x60\xe8\x89\xa0\xa6\x5a\x25\xf8\x6b\x57\x9f\xc5\x55\x93\x48\x0d\xcc\x48\xf7\x9c\xa8\xb4\x2e\x56\x49\x9f\x8d\x1d\x4b\x6f\x11\xae\xcb\xfb\x3f\xef\xd9\x91\xed\x09\xbf\x37\x42\xd3\x30\xff\x86\xcb\xc4\x67\x00\x97\xc1\x94\xbc\xe5\x00\x00\x00"
 
-func tmplPartialsVarsTmplBytes() ([]byte, error) {
+func tmplPartialsProto_fieldTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_tmplPartialsVarsTmpl,
-		"tmpl/partials/vars.tmpl",
+		_tmplPartialsProto_fieldTmpl,
+		"tmpl/partials/proto_field.tmpl",
 	)
 }
 
-func tmplPartialsVarsTmpl() (*asset, error) {
-	bytes, err := tmplPartialsVarsTmplBytes()
+func tmplPartialsProto_fieldTmpl() (*asset, error) {
+	bytes, err := tmplPartialsProto_fieldTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/partials/vars.tmpl", size: 126, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/proto_field.tmpl", size: 229, mode: os.FileMode(438), modTime: time.Unix(1792359748, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplProtoPbTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x91\x41\x6b\xfb\x30\x0c\xc5\xef\x85\x7e\x07\x51\x0c\xfd\x17\x42\x2e\xff\xdb\x4a\x4e\x85\xc1\x0e\xdd\xc6\x3a\x7a\x19\x3b\x98\x54\xeb\x42\x1b\x3b\xb3\xdc\xd0\x20\xf4\xdd\x47\xec\x2c\x75\xb7\x9e\x22\xc5\x4f\xef\xfd\x64\x53\x67\xbc\x3e\x43\x01\xb3\xc6\x59\x6f\xff\xcf\x96\xd3\xc9\x74\xd2\xe8\xf2\xa0\xf7\x08\xcc\xde\x6e\x8c\x3e\xe0\x4a\x13\x42\xfe\x1c\x7f\x3f\xea\x1a\x45\x82\x90\xd9\x69\xb3\x47\x50\x75\xa6\x0c\xdc\x15\x90\x3f\xd4\x8d\x75\x9e\x44\xaa\x50\xc0\x8c\x59\x99\xfc\xbe\x3a\xa2\x09\x63\x7d\x00\x33\x9a\x9d\xc8\x6d\x83\xa7\xc6\x57\xd6\x90\x88\x0d\x05\x84\xf9\x18\x09\x45\xec\x56\xd6\x90\xd7\xc6\xe7\x1b\x7b\x72\x65\x64\x49\x3c\x09\x5d\x5b\x95\x3d\x7d\xbe\x89\xe5\x30\xce\x49\x5e\x95\xa9\x36\xe4\xad\xd1\x7f\xda\x1d\x89\x00\x00\xb8\xa6\xec\x23\xda\x9f\xc0\x7f\x49\xf3\x82\x5f\x0b\x70\xe8\x4f\xce\xd0\xaf\x03\x5a\x00\xcb\xc8\x10\x20\x6e\xad\xb6\x46\x22\xbd\x47\xea\x15\x75\xac\xaf\xd6\x4b\xf9\x0e\x03\x9f\x32\xf9\x56\xbb\x01\x2f\xb2\xbd\x76\x4d\x50\x27\x9c\x45\xec\xb6\xfa\x78\x42\x11\xe6\xea\x03\x54\x7b\xb9\xca\xb7\xd1\xf6\x9c\xa9\x2e\xd8\x26\xa7\x51\x7e\x16\xc9\x60\xd8\x80\x59\x75\x17\xeb\x79\x68\xff\x5c\xfa\x7c\x10\xbf\x0f\xdf\xe5\xf8\x04\x72\xf5\x1c\xdf\x01\x00\x00\xff\xff\x19\x19\x06\x4e\x63\x02\x00\x00"
+var _tmplPartialsProto_messageTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x92\xc1\x4a\xf4\x40\x10\x84\xef\xfb\x14\x4d\x98\xe3\x92\x07\xf8\x7f\xf6\x24\x0a\x1e\x54\x50\xf0\x2a\xc1\xad\x84\x60\xa6\x3b\x64\x26\x61\x97\xa6\xdf\x5d\x92\x89\xbb\xa2\xc3\xee\xc1\x53\x2a\xf4\x7c\x45\x57\xcd\xa8\x46\xf8\xbe\xab\x22\xa8\xe8\x07\x89\xf2\xf6\x2e\xde\x83\x63\x41\xe5\x4d\x52\x66\xaa\x6d\x4d\xe5\x7d\xb8\x3d\x44\xf0\xde\x0c\xcb\x57\x15\x5d\x80\x99\x47\x08\x55\x03\xd5\x65\x46\xaa\xe5\x63\xe5\x31\xab\x8d\xea\x50\x71\x03\x72\x7e\xeb\x98\xfe\xed\xa8\x7c\xea\x63\x2b\x1c\xcc\x64\x11\xa4\xea\xf8\xeb\xfc\x2e\xfd\xbd\x56\xdd\x08\xb3\xff\x9b\xd5\xf1\xb7\xc9\x33\x02\x86\x09\x7b\xb3\x61\x55\x89\x7c\x89\x43\xcb\xcd\x45\x74\x89\x10\xd2\x0a\x38\xe9\x6b\xf8\xc7\xd6\x4d\x0b\x7e\xd7\xa2\xdb\x87\x79\xf2\xb3\xb6\x7a\x9e\x14\xe4\x26\xb3\x1c\x2e\x29\x3d\x43\xea\x2c\x7e\x6a\xdd\xc9\xb9\x77\x99\x8f\xcf\xbb\xc9\x85\x4a\x9d\xfc\xa9\xd4\xc3\xd6\x1d\x57\x9b\xeb\xe1\x8e\xe7\x70\xd9\x94\x48\x1d\xf3\xe8\xb3\x36\xe0\xd1\x17\xe4\x90\x87\xd3\x05\x3d\xa4\xd7\x94\xe5\xd7\x97\x56\x90\xe3\xef\x8b\x7c\x0e\x00\xa5\xaa\xe8\xab\xc4\x02\x00\x00"
 
-func tmplProtoPbTmplBytes() ([]byte, error) {
+func tmplPartialsProto_messageTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_tmplProtoPbTmpl,
-		"tmpl/proto.pb.tmpl",
+		_tmplPartialsProto_messageTmpl,
+		"tmpl/partials/proto_message.tmpl",
 	)
 }
 
-func tmplProtoPbTmpl() (*asset, error) {
-	bytes, err := tmplProtoPbTmplBytes()
+func tmplPartialsProto_messageTmpl() (*asset, error) {
+	bytes, err := tmplPartialsProto_messageTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/proto.pb.tmpl", size: 611, mode: os.FileMode(438), modTime: time.Unix(1575956779, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/proto_message.tmpl", size: 708, mode: os.FileMode(438), modTime: time.Unix(1792375750, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplProto_compileBatTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x8f\xb1\x6a\x03\x31\x0c\x86\xf7\x40\xde\x41\x2f\x60\x7b\xe8\x66\xc8\x5c\xba\x74\x29\x9d\x8b\xee\xa2\x2a\x06\x9f\x65\x2c\xb9\x14\x42\xde\xbd\x44\x3d\x28\x85\x2e\x5d\x8c\x11\xdf\xff\xc1\x97\x33\x3c\x35\x35\xac\x15\xfa\x10\x93\x87\x78\x3c\xe4\x0c\x17\xb3\xae\x39\x25\x2e\x76\x99\x4b\x5c\x65\x4b\x2c\xc2\x95\x92\x53\xcb\x7c\x4f\x83\x2a\xa1\x92\x3a\xff\xda\xcf\x68\xf4\xad\x58\xe1\x51\x60\x29\xed\x5c\x1a\x2b\x7c\x14\x74\x02\x58\x80\xc9\x20\x4c\xf8\x25\xad\xd8\xf8\x47\xea\x9f\xff\xf2\x6b\x60\x6a\x81\x7d\xe7\xdb\x17\x22\xc0\xaa\xbb\xe8\xaf\x96\xd1\x57\x7f\x02\x4b\xb2\x41\x94\x36\x54\xa3\x91\xe8\x13\xb7\x5e\xef\x51\xc7\xc3\x1e\x73\xbd\xc6\x67\xdc\xe8\x76\x8b\x7e\x80\x10\x58\xde\x64\xda\xa9\xd7\xc9\xa5\xe9\xe9\xee\xc9\xf1\x2b\x00\x00\xff\xff\x7e\x97\x17\x88\x49\x01\x00\x00"
+var _tmplPartialsStructTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x44\xcc\x31\x0a\xc2\x40\x14\x84\xe1\xde\x53\x0c\x61\x4b\xd9\x03\x08\x56\xf6\x56\x62\xbf\xe8\x33\xa4\xd8\x67\xd8\x3c\x03\x61\x98\xbb\x8b\x0b\xc1\xee\x1b\x18\x7e\x72\x7a\xc1\x0d\xf9\xf2\xae\xd5\x3c\x30\x0c\x12\xb9\xcf\x9f\xcd\x9f\x52\x6c\xb3\x81\xcc\xd7\x52\x4d\xc2\x12\xed\xf3\x08\x10\x64\x2b\x3e\x1a\xd2\x74\x4c\x2b\x4e\x67\xe4\x7b\x69\x8b\x74\x20\xd3\xba\xbf\xbb\x6f\xdb\xfc\x77\x19\x3b\x7b\x1a\xfa\x0e\x00\xbd\xd4\x67\xab\x85\x00\x00\x00"
 
-func tmplProto_compileBatTmplBytes() ([]byte, error) {
+func tmplPartialsStructTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_tmplProto_compileBatTmpl,
-		"tmpl/proto_compile.bat.tmpl",
+		_tmplPartialsStructTmpl,
+		"tmpl/partials/struct.tmpl",
 	)
 }
 
-func tmplProto_compileBatTmpl() (*asset, error) {
-	bytes, err := tmplProto_compileBatTmplBytes()
+func tmplPartialsStructTmpl() (*asset, error) {
+	bytes, err := tmplPartialsStructTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/proto_compile.bat.tmpl", size: 329, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/partials/struct.tmpl", size: 133, mode: os.FileMode(438), modTime: time.Unix(1792360377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplProto_compileShTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\x31\x6b\xf3\x30\x10\x86\xf7\x40\xfe\xc3\x7d\x64\xfd\xac\x1b\xba\x25\x64\x2e\x5d\xda\x21\x74\x2e\xb2\x72\x39\x8b\x4a\x3a\x23\x9d\xd2\x96\x90\xff\x5e\x24\x9b\x42\xa1\x8b\x7d\x88\xf7\x9e\xf7\x91\x76\xff\xb0\x96\x8c\xa3\x4f\x48\xe9\x0a\x65\xda\x6e\xb6\x9b\x1d\x3c\xa5\xa2\x36\x04\x98\xb3\xa8\x3c\xc0\x25\x4b\x84\x22\x35\x3b\x82\x68\xdd\xcb\x09\x24\x85\x2f\xd3\x92\x30\x66\xfa\x00\xbf\xe6\x6d\x55\x71\x92\x2e\x7d\x88\xf6\x9d\x20\xf8\x51\x45\x42\x8f\xb2\x57\x70\x41\x12\xc1\xa4\x3a\x97\x3d\x22\x7b\x9d\xea\x68\x9c\x44\x64\x11\x0e\x84\xbd\x71\xac\x97\xbe\x60\xb0\x71\x98\x92\x29\x13\x1c\xc0\x60\x63\x7b\xae\x99\xe0\x00\x1d\xbf\xfe\xd6\xfe\xed\x66\xd7\xf6\x5e\xe7\xb3\x55\x5a\xe4\x1d\x3c\x0a\x8c\x3e\x9d\x7d\xe2\x02\x57\x6f\x17\x13\x01\x26\x85\xa1\xc2\x2f\x83\x60\x13\xff\x18\xe0\xad\x4f\xff\x17\xcc\xc0\x94\x06\x96\xfb\x5a\x71\x22\x02\x1b\x8a\x74\xda\x5f\xb7\xc9\xb3\xeb\x9f\x81\x05\x35\x13\x61\xb4\x45\x29\x23\x7d\xda\x38\x07\x2a\xed\x9d\x57\xc1\xdb\xcd\x3c\xdb\x48\xf7\xbb\xe9\x07\x30\x0c\x2c\x6f\x52\xf5\x38\x87\xca\x3e\x95\x63\xe3\xec\xcd\x77\x00\x00\x00\xff\xff\x44\x50\x25\xa7\xab\x01\x00\x00"
+var _tmplPartialsStruct_functionTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcf\xcd\x4a\xc7\x30\x10\x04\xf0\xbb\xe0\x3b\x0c\x39\xb5\x97\xe0\x33\xe8\xdd\x83\x7a\x2f\xa5\xdd\x48\x21\x49\x6b\xba\x39\x94\x65\xde\x5d\x62\xfd\x7f\x9d\x92\x19\x76\xf9\xb1\x66\x4b\x40\x16\xf8\xb7\x35\x25\xc9\x0a\xe7\x48\xb3\x4b\x24\x9f\x9f\xcc\x24\xcf\x64\xa8\x79\x42\x67\xe6\x3f\xb5\xd4\x49\xfd\xfb\x98\x84\xc4\xad\xf8\x3a\x36\x21\x7b\x98\xa9\xa4\x2d\x8e\x2a\x70\x4b\x56\x29\x61\x9c\x64\x68\xeb\x0e\x9e\x34\x9c\xe6\xaa\xe8\xe4\x07\xfe\x75\x9d\x0f\x38\xd7\xff\xb1\x2d\xb4\x8f\xc4\x5d\x4e\x7b\x09\xf8\x56\x74\x51\x32\xfc\x87\xec\x35\xea\xde\xe3\x85\x2c\xa2\xb5\xe4\x07\xac\x11\xc3\xd9\xbb\xeb\xf0\xdd\x05\xff\x0f\xf8\x1b\x00\x00\xff\xff\x25\x63\x5f\x34\xf5\x00\x00\x00"
 
-func tmplProto_compileShTmplBytes() ([]byte, error) {
+func tmplPartialsStruct_functionTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_tmplProto_compileShTmpl,
-		"tmpl/proto_compile.sh.tmpl",
+		_tmplPartialsStruct_functionTmpl,
+		"tmpl/partials/struct_function.tmpl",
+	)
+}
+
+func tmplPartialsStruct_functionTmpl() (*asset, error) {
+	bytes, err := tmplPartialsStruct_functionTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tmpl/partials/struct_function.tmpl", size: 245, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _tmplPartialsVarsTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xae\xce\x4c\x53\x48\x2f\x51\xd0\xc8\x49\xcd\x53\x50\xd1\x54\x30\xa8\xad\x2d\x4b\x2c\x52\xd0\xa8\xae\x2e\x4a\xcc\x4b\x4f\x55\x50\xc9\xd4\x51\x29\x53\xb0\xb2\x55\x50\xa9\xad\xe5\xe5\xaa\xae\x56\x29\xd3\xf3\x4b\xcc\x4d\xad\xad\x55\x00\xb3\x43\x2a\x0b\x20\xec\xcc\x34\x05\x95\x32\x3d\x8f\xc4\xe2\xb0\xc4\x9c\xd2\xd4\xda\x5a\x5b\x88\x3c\x94\x57\x5d\x9d\x9a\x97\x02\xa7\x78\xb9\x34\xa1\x2c\x40\x00\x00\x00\xff\xff\x51\x62\x20\x15\x7e\x00\x00\x00"
+
+func tmplPartialsVarsTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_tmplPartialsVarsTmpl,
+		"tmpl/partials/vars.tmpl",
 	)
 }
 
-func tmplProto_compileShTmpl() (*asset, error) {
-	bytes, err := tmplProto_compileShTmplBytes()
+func tmplPartialsVarsTmpl() (*asset, error) {
+	bytes, err := tmplPartialsVarsTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tmpl/partials/vars.tmpl", size: 126, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _tmplProtoPbTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x92\xcd\x6a\xeb\x30\x10\x85\xf7\x7e\x8a\xc1\x68\x71\x2f\x04\x3f\x40\x43\x56\xa1\x85\x52\xda\x86\xa4\x74\x5b\x84\x3b\x0d\x26\x96\xac\x6a\x64\xd1\x30\xcc\xbb\x17\x4b\xb1\x03\xcd\xcf\x22\x2b\x8f\xe6\x30\xdf\x39\x1e\x89\xb9\xf9\x82\x6a\xb3\xb7\x41\xff\x88\x50\xfa\xc2\x02\x4a\xe6\xa9\x59\xce\x99\xd1\x7e\x8a\x14\x85\xd3\xf5\x4e\x6f\x11\x98\xab\x55\x2e\x5f\xb4\x41\x91\x79\x51\x30\x7b\x6d\xb7\x08\xca\xcc\x94\x85\xbb\x05\x54\x8f\xc6\x75\x3e\x90\x48\x93\x0a\x48\x56\xca\x56\x4f\xcd\x00\x63\x9e\x4a\x38\xf0\xcb\xd4\x7b\x68\x5a\xb4\x89\x5a\xce\x8b\xd1\xf9\x94\xfe\xea\x42\xd3\x59\x12\xe9\x52\x01\x69\x36\xa7\x81\x45\x3e\xbd\xeb\xb6\x4f\xe1\x4e\x28\xcd\x4c\x51\xa2\x6c\xd0\xc7\xa6\x46\x4a\x62\x40\xe3\x5a\x1d\x10\x4a\xe7\xbb\xd0\x7d\xd4\x9d\x31\x68\x43\x09\x8a\xaa\x65\xae\x45\x28\x4f\x0c\x0e\x34\xfa\xf1\x69\x3e\x45\xb7\x24\x9c\x30\xbb\x99\x8a\x07\xcc\x7a\xb5\x24\x91\x6b\xe9\xe2\x31\x9d\x77\xf5\x40\x8e\xa3\xcf\xbf\xbc\xf4\x58\x6d\x82\x47\x6d\x68\x8d\xdf\x3d\xd2\xf0\x1b\xe9\x0c\x93\xaf\x8a\xd5\x41\x7b\xdb\x3b\x14\xf9\x0f\x1e\x43\xef\x2d\x9d\x41\xa4\xfe\x05\x44\xd2\x46\xc4\x38\x39\x6d\xe2\xdc\xa2\xe2\x2d\x8b\x12\x66\x6c\x09\x45\x58\x8e\x97\x9b\x95\x2b\x6f\xe6\x19\x89\xf4\xf6\xc2\x6d\x9b\x2c\x96\xa0\xec\x55\xc8\xbd\xed\xcd\x79\x02\xda\xde\xfc\x19\xff\x1d\x00\x25\xd6\xe9\xed\x5f\x03\x00\x00"
+
+func tmplProtoPbTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_tmplProtoPbTmpl,
+		"tmpl/proto.pb.tmpl",
+	)
+}
+
+func tmplProtoPbTmpl() (*asset, error) {
+	bytes, err := tmplProtoPbTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/proto_compile.sh.tmpl", size: 427, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/proto.pb.tmpl", size: 863, mode: os.FileMode(438), modTime: time.Unix(1792359748, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _tmplSvcThriftTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xae\x2e\x4a\xcc\x4b\x4f\x55\x50\xc9\xd4\x51\x29\x53\xb0\xb2\x55\xd0\xf3\x4d\x2d\xc9\xc8\x4f\x29\xae\xad\x2d\x2e\x29\x2a\x4d\x2e\x51\xa8\xae\x56\x29\xd3\x0b\x4a\x2d\xc8\xa9\xac\xad\xad\xe6\xe5\xaa\xe5\xe5\x42\x95\x28\x2c\x4d\x2d\x2e\x81\x48\x55\x57\xa7\xe6\xa5\xd4\xd6\xf2\x72\xf1\x72\x15\xa7\x16\x95\x65\x26\xa7\x2a\x54\x57\xeb\xf9\x25\xe6\xa6\xd6\xd6\x06\xc3\x04\x78\xb9\xf0\xd8\x89\x62\x1b\x84\x03\xd1\xae\xa0\x61\x68\x85\x66\xa3\x42\x51\x6a\xa1\x26\xc8\x34\xb0\xa5\x60\x5b\x01\x01\x00\x00\xff\xff\xd6\xd0\xa5\x28\xce\x00\x00\x00"
+var _tmplSvcThriftTmpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x53\x4f\x6b\xdb\x30\x14\xbf\xf7\x53\x88\xa2\x43\x0b\xc6\xbd\x0f\x7a\x18\xa5\x63\x63\xb0\x8d\x35\xec\x2e\x9c\xa7\x4e\x2c\x7e\x36\x96\xec\x26\x3c\xde\x77\x1f\xfa\x17\x67\x8e\x3c\x92\x9e\xf2\x2c\xeb\xf7\xf7\xc5\x44\x83\xc2\x57\x10\xd2\x54\x12\xc5\x87\x47\x51\x7f\x53\x2d\xd8\x5e\x35\x60\x99\x31\xcf\x82\x48\x62\xfd\xd2\x74\x3d\x30\xc7\x07\x7f\x8f\xf9\x86\x08\x70\xcb\x7c\xce\xf3\x05\x9b\xdd\xb8\xf5\x2c\x26\x4e\xe2\xd6\xe3\x98\x6f\xd7\x31\x9b\x43\x0f\x5b\xd0\x36\xf0\xa6\x97\x8d\x7f\x23\xb1\x7e\xea\xda\x16\xd0\x31\x3f\x3c\x10\xc9\x66\x96\x76\x11\x14\x5d\x6d\x0e\x4b\x87\x44\x46\x7b\xfc\x47\xc4\xce\x29\x67\x3a\xb4\xcc\xe2\x8e\x68\x79\x76\x9f\xf8\xd6\xed\x3d\x75\x68\xdd\x75\xe6\x1a\x0f\x59\xb3\x26\x1e\xe3\xd3\x2f\xb5\x1b\x57\xba\x84\x20\xfc\x8c\x63\x5b\xd0\x85\x75\x5d\xc0\xb1\xf5\xe4\x90\xa5\x68\x06\xff\xa9\xe4\x94\xf0\x41\xd9\x32\x2f\x88\xa7\x99\x58\x08\x21\x96\xe4\xfe\x8c\x48\x4e\xff\x34\x3c\xd5\x9f\x95\x4d\x49\x62\xb0\x29\x07\x3b\xe6\x8a\xf7\xce\x37\x31\x95\x37\x51\x65\xc1\x04\x85\x02\x14\x2e\x5f\xa2\x0d\x5d\xbe\xb8\x61\x6c\x4a\x5b\xb4\xeb\x6d\x12\x49\x5b\x7f\x35\x7e\x14\x61\x2e\x96\xaa\x13\xcd\x27\x03\xbb\xed\x79\xa9\xfa\xa2\x52\xb5\x37\x68\xf0\x95\x79\x11\xde\x16\xc2\xdb\xab\xc3\xc3\x30\x99\xf0\x69\x5f\x91\xde\x46\xd0\x69\xf2\x6c\xe9\x79\xef\x00\x7d\x56\x01\x71\x8a\x97\x8e\xc7\x39\xdb\x4a\x51\x23\x36\xc9\xfa\x7b\xba\xf2\x16\x74\xfd\x1d\xe1\x4d\x1d\x98\xbb\xf0\x2b\xe6\x8d\xe9\xfa\x27\xb8\x71\xc0\xf9\xbb\xd3\xc9\xfd\xdd\x51\x6d\x5f\xc9\x3e\x09\xfe\x50\x83\x6a\x6d\x8e\xb6\x67\xae\x4e\xb8\xfa\xe3\x56\xd2\xd9\x7d\x96\xdf\xfc\x1e\xba\x37\x5f\x80\x0b\x83\x28\x72\xe7\x4b\x17\x73\x9f\x7c\x2f\xba\xb0\x77\xfd\xff\xbd\xbf\xff\x1f\x73\xf3\x77\x00\xf1\x34\x63\x4e\x10\x06\x00\x00"
 
 func tmplSvcThriftTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -535,7 +598,7 @@ func tmplSvcThriftTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/svc.thrift.tmpl", size: 206, mode: os.FileMode(438), modTime: time.Unix(1560931698, 0)}
+	info := bindataFileInfo{name: "tmpl/svc.thrift.tmpl", size: 1552, mode: os.FileMode(438), modTime: time.Unix(1792375847, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -632,6 +695,7 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"tmpl/errors.tmpl":                   tmplErrorsTmpl,
 	"tmpl/file.tmpl":                     tmplFileTmpl,
 	"tmpl/gk.json.tmpl":                  tmplGkJsonTmpl,
 	"tmpl/main_api.tmpl":                 tmplMain_apiTmpl,
@@ -647,12 +711,14 @@ var _bindata = map[string]func() (*asset, error){
 	"tmpl/partials/interface.tmpl":       tmplPartialsInterfaceTmpl,
 	"tmpl/partials/interface_func.tmpl":  tmplPartialsInterface_funcTmpl,
 	"tmpl/partials/interface_stub.tmpl":  tmplPartialsInterface_stubTmpl,
+	"tmpl/partials/proto_comment.tmpl":   tmplPartialsProto_commentTmpl,
+	"tmpl/partials/proto_enum.tmpl":      tmplPartialsProto_enumTmpl,
+	"tmpl/partials/proto_field.tmpl":     tmplPartialsProto_fieldTmpl,
+	"tmpl/partials/proto_message.tmpl":   tmplPartialsProto_messageTmpl,
 	"tmpl/partials/struct.tmpl":          tmplPartialsStructTmpl,
 	"tmpl/partials/struct_function.tmpl": tmplPartialsStruct_functionTmpl,
 	"tmpl/partials/vars.tmpl":            tmplPartialsVarsTmpl,
 	"tmpl/proto.pb.tmpl":                 tmplProtoPbTmpl,
-	"tmpl/proto_compile.bat.tmpl":        tmplProto_compileBatTmpl,
-	"tmpl/proto_compile.sh.tmpl":         tmplProto_compileShTmpl,
 	"tmpl/svc.thrift.tmpl":               tmplSvcThriftTmpl,
 	"tmpl/thrift_compile.bat.tmpl":       tmplThrift_compileBatTmpl,
 	"tmpl/thrift_compile.sh.tmpl":        tmplThrift_compileShTmpl,
@@ -700,6 +766,7 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"tmpl": &bintree{nil, map[string]*bintree{
+		"errors.tmpl":   &bintree{tmplErrorsTmpl, map[string]*bintree{}},
 		"file.tmpl":     &bintree{tmplFileTmpl, map[string]*bintree{}},
 		"gk.json.tmpl":  &bintree{tmplGkJsonTmpl, map[string]*bintree{}},
 		"main_api.tmpl": &bintree{tmplMain_apiTmpl, map[string]*bintree{}},
@@ -716,13 +783,15 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"interface.tmpl":       &bintree{tmplPartialsInterfaceTmpl, map[string]*bintree{}},
 			"interface_func.tmpl":  &bintree{tmplPartialsInterface_funcTmpl, map[string]*bintree{}},
 			"interface_stub.tmpl":  &bintree{tmplPartialsInterface_stubTmpl, map[string]*bintree{}},
+			"proto_comment.tmpl":   &bintree{tmplPartialsProto_commentTmpl, map[string]*bintree{}},
+			"proto_enum.tmpl":      &bintree{tmplPartialsProto_enumTmpl, map[string]*bintree{}},
+			"proto_field.tmpl":     &bintree{tmplPartialsProto_fieldTmpl, map[string]*bintree{}},
+			"proto_message.tmpl":   &bintree{tmplPartialsProto_messageTmpl, map[string]*bintree{}},
 			"struct.tmpl":          &bintree{tmplPartialsStructTmpl, map[string]*bintree{}},
 			"struct_function.tmpl": &bintree{tmplPartialsStruct_functionTmpl, map[string]*bintree{}},
 			"vars.tmpl":            &bintree{tmplPartialsVarsTmpl, map[string]*bintree{}},
 		}},
 		"proto.pb.tmpl":           &bintree{tmplProtoPbTmpl, map[string]*bintree{}},
-		"proto_compile.bat.tmpl":  &bintree{tmplProto_compileBatTmpl, map[string]*bintree{}},
-		"proto_compile.sh.tmpl":   &bintree{tmplProto_compileShTmpl, map[string]*bintree{}},
 		"svc.thrift.tmpl":         &bintree{tmplSvcThriftTmpl, map[string]*bintree{}},
 		"thrift_compile.bat.tmpl": &bintree{tmplThrift_compileBatTmpl, map[string]*bintree{}},
 		"thrift_compile.sh.tmpl":  &bintree{tmplThrift_compileShTmpl, map[string]*bintree{}},